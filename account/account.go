@@ -2,15 +2,17 @@ package account
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/contracts"
-	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typeddata"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
@@ -36,7 +38,7 @@ type AccountInterface interface {
 	SignInvokeTransaction(ctx context.Context, tx *rpc.InvokeTxnV1) error
 	SignDeployAccountTransaction(ctx context.Context, tx *rpc.DeployAccountTxn, precomputeAddress *felt.Felt) error
 	SignDeclareTransaction(ctx context.Context, tx *rpc.DeclareTxnV2) error
-	PrecomputeAccountAddress(salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) (*felt.Felt, error)
+	PrecomputeAccountAddress(salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) *felt.Felt
 	WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceiptWithBlockInfo, error)
 }
 
@@ -47,9 +49,16 @@ type Account struct {
 	provider       rpc.RpcProvider
 	ChainId        *felt.Felt
 	AccountAddress *felt.Felt
-	publicKey      string
 	CairoVersion   int
-	ks             Keystore
+	// signer signs with the key of the account
+	signer Signer
+	nonces nonceCache
+	// signatureLayout packs the signatures of the account's signers, nil means the StandardSignatureLayout
+	signatureLayout SignatureLayout
+	// specVersion is the spec version pinned with WithSpecVersion, empty means the version of the provider
+	specVersion string
+	// preflight validates the invoke v3 transactions with ValidateInvoke before broadcasting them, see WithPreflight
+	preflight bool
 }
 
 // NewAccount creates a new Account instance.
@@ -57,30 +66,35 @@ type Account struct {
 // Parameters:
 // - provider: is the provider of type rpc.RpcProvider
 // - accountAddress: is the account address of type *felt.Felt
-// - publicKey: is the public key of type string
-// - keystore: is the keystore of type Keystore
+// - signer: is the Signer of the account, e.g. a MemorySigner, or a KeystoreSigner for the key of a public key
+// in a Keystore
+// - cairoVersion: is the Cairo version of the account contract
+// - options: are the AccountOption applied to the account, e.g. WithSpecVersion
 // It returns:
 // - *Account: a pointer to newly created Account
 // - error: an error if any
-func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey string, keystore Keystore, cairoVersion int) (*Account, error) {
+func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, signer Signer, cairoVersion int, options ...AccountOption) (*Account, error) {
 	account := &Account{
 		provider:       provider,
 		AccountAddress: accountAddress,
-		publicKey:      publicKey,
-		ks:             keystore,
 		CairoVersion:   cairoVersion,
+		signer:         signer,
+	}
+	for _, option := range options {
+		option(account)
 	}
 
 	chainID, err := provider.ChainID(context.Background())
 	if err != nil {
 		return nil, err
 	}
-	account.ChainId = new(felt.Felt).SetBytes([]byte(chainID))
+	account.ChainId = rpc.ChainID(chainID).Felt()
 
 	return account, nil
 }
 
-// Sign signs the given felt message using the account's private key.
+// Sign signs the given felt message using the account's private key, the signature being packed with the
+// signature layout of the account (see SetSignatureLayout).
 //
 // Parameters:
 // - ctx: is the context used for the signing operation
@@ -89,17 +103,23 @@ func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey s
 // - []*felt.Felt: an array of signed felt messages
 // - error: an error, if any
 func (account *Account) Sign(ctx context.Context, msg *felt.Felt) ([]*felt.Felt, error) {
+	return account.SignWithSigners(ctx, msg)
+}
 
-	msgBig := utils.FeltToBigInt(msg)
-
-	s1, s2, err := account.ks.Sign(ctx, account.publicKey, msgBig)
+// SignTypedData signs the SNIP-12 revision 1 hash of the typed data message for the account.
+//
+// Parameters:
+// - ctx: is the context used for the signing operation
+// - td: is the typed data message to be signed
+// Returns:
+// - []*felt.Felt: the signature of the message hash
+// - error: an error if the message hash cannot be computed or signed
+func (account *Account) SignTypedData(ctx context.Context, td typeddata.TypedData) ([]*felt.Felt, error) {
+	hash, err := td.GetMessageHash(account.AccountAddress)
 	if err != nil {
 		return nil, err
 	}
-	s1Felt := utils.BigIntToFelt(s1)
-	s2Felt := utils.BigIntToFelt(s2)
-
-	return []*felt.Felt{s1Felt, s2Felt}, nil
+	return account.Sign(ctx, hash)
 }
 
 // SignInvokeTransaction signs and invokes a transaction.
@@ -166,6 +186,70 @@ func (account *Account) SignDeclareTransaction(ctx context.Context, tx *rpc.Decl
 	return nil
 }
 
+// BuildDeclareTxnV3 builds and signs a DeclareTxnV3 transaction declaring the Sierra class, ready to be sent
+// with AddDeclareTransaction. The class hash is calculated from the Sierra class and the compiled class hash
+// from its CASM compilation, so that they match the ones checked by the sequencer.
+//
+// Parameters:
+// - ctx: the context.Context
+// - sierraClass: the Sierra class to declare
+// - casmClass: the CASM compilation of the Sierra class
+// - nonce: the nonce of the account
+// - resourceBounds: the resource bounds of the transaction
+// Returns:
+// - *rpc.BroadcastDeclareTxnV3: the signed transaction
+// - error: an error if any
+func (account *Account) BuildDeclareTxnV3(ctx context.Context, sierraClass contracts.SierraClass, casmClass contracts.CasmClass, nonce *felt.Felt, resourceBounds rpc.ResourceBoundsMapping) (*rpc.BroadcastDeclareTxnV3, error) {
+	contractClass := sierraClass.ContractClass()
+	classHash, err := hash.ClassHash(contractClass)
+	if err != nil {
+		return nil, err
+	}
+	compiledClassHash, err := contracts.CompiledClassHash(casmClass)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := rpc.DeclareTxnV3{
+		Type:                  rpc.TransactionType_Declare,
+		SenderAddress:         account.AccountAddress,
+		CompiledClassHash:     compiledClassHash,
+		Version:               rpc.TransactionV3,
+		Nonce:                 nonce,
+		ClassHash:             classHash,
+		ResourceBounds:        resourceBounds,
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+	txHash, err := account.TransactionHashDeclare(tx)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := account.Sign(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.BroadcastDeclareTxnV3{
+		Type:                  tx.Type,
+		SenderAddress:         tx.SenderAddress,
+		CompiledClassHash:     tx.CompiledClassHash,
+		Version:               tx.Version,
+		Signature:             signature,
+		Nonce:                 tx.Nonce,
+		ContractClass:         &contractClass,
+		ResourceBounds:        tx.ResourceBounds,
+		Tip:                   tx.Tip,
+		PayMasterData:         tx.PayMasterData,
+		AccountDeploymentData: tx.AccountDeploymentData,
+		NonceDataMode:         tx.NonceDataMode,
+		FeeMode:               tx.FeeMode,
+	}, nil
+}
+
 // TransactionHashDeployAccount calculates the transaction hash for a deploy account transaction.
 //
 // Parameters:
@@ -181,7 +265,7 @@ func (account *Account) TransactionHashDeployAccount(tx rpc.DeployAccountType, c
 	case rpc.DeployAccountTxn:
 		calldata := []*felt.Felt{txn.ClassHash, txn.ContractAddressSalt}
 		calldata = append(calldata, txn.ConstructorCalldata...)
-		calldataHash := curve.PedersenArray(calldata...)
+		calldataHash := utils.ComputeHashOnElements(calldata)
 
 		versionFelt, err := new(felt.Felt).SetString(string(txn.Version))
 		if err != nil {
@@ -261,7 +345,7 @@ func (account *Account) TransactionHashInvoke(tx rpc.InvokeTxnType) (*felt.Felt,
 			return nil, ErrNotAllParametersSet
 		}
 
-		calldataHash := curve.PedersenArray(txn.Calldata...)
+		calldataHash := utils.ComputeHashOnElements(txn.Calldata)
 		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
 		if err != nil {
 			return nil, err
@@ -282,7 +366,7 @@ func (account *Account) TransactionHashInvoke(tx rpc.InvokeTxnType) (*felt.Felt,
 			return nil, ErrNotAllParametersSet
 		}
 
-		calldataHash := curve.PedersenArray(txn.Calldata...)
+		calldataHash := utils.ComputeHashOnElements(txn.Calldata)
 		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
 		if err != nil {
 			return nil, err
@@ -387,7 +471,7 @@ func (account *Account) TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Fel
 			return nil, ErrNotAllParametersSet
 		}
 
-		calldataHash := curve.PedersenArray(txn.ClassHash)
+		calldataHash := utils.ComputeHashOnElements([]*felt.Felt{txn.ClassHash})
 
 		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
 		if err != nil {
@@ -408,7 +492,7 @@ func (account *Account) TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Fel
 			return nil, ErrNotAllParametersSet
 		}
 
-		calldataHash := curve.PedersenArray(txn.ClassHash)
+		calldataHash := utils.ComputeHashOnElements([]*felt.Felt{txn.ClassHash})
 
 		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
 		if err != nil {
@@ -476,11 +560,8 @@ func (account *Account) TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Fel
 // - constructorCalldata: the constructor calldata
 // Returns:
 // - *felt.Felt: the precomputed address as a *felt.Felt
-// - error: an error if any
-func (account *Account) PrecomputeAccountAddress(salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) (*felt.Felt, error) {
-	result := contracts.PrecomputeAddress(&felt.Zero, salt, classHash, constructorCalldata)
-
-	return result, nil
+func (account *Account) PrecomputeAccountAddress(salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) *felt.Felt {
+	return contracts.PrecomputeAccountAddress(salt, classHash, constructorCalldata)
 }
 
 // WaitForTransactionReceipt waits for the transaction receipt of the given transaction hash to succeed or fail.
@@ -501,8 +582,7 @@ func (account *Account) WaitForTransactionReceipt(ctx context.Context, transacti
 		case <-t.C:
 			receiptWithBlockInfo, err := account.TransactionReceipt(ctx, transactionHash)
 			if err != nil {
-				rpcErr := err.(*rpc.RPCError)
-				if rpcErr.Code == rpc.ErrHashNotFound.Code && rpcErr.Message == rpc.ErrHashNotFound.Message {
+				if errors.Is(err, rpc.ErrHashNotFound) {
 					continue
 				} else {
 					return nil, err
@@ -514,6 +594,8 @@ func (account *Account) WaitForTransactionReceipt(ctx context.Context, transacti
 }
 
 // AddInvokeTransaction generates an invoke transaction and adds it to the account's provider.
+// If the nonce cache is enabled and the broadcast fails, the nonce of the transaction is released.
+// With WithPreflight, an invoke v3 transaction is first validated with ValidateInvoke and not broadcast if it fails.
 //
 // Parameters:
 // - ctx: the context.Context object for the transaction.
@@ -522,10 +604,22 @@ func (account *Account) WaitForTransactionReceipt(ctx context.Context, transacti
 // - *rpc.AddInvokeTransactionResponse: The response for the AddInvokeTransactionResponse
 // - error: an error if any.
 func (account *Account) AddInvokeTransaction(ctx context.Context, invokeTx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
-	return account.provider.AddInvokeTransaction(ctx, invokeTx)
+	if tx, ok := broadcastInvokeV3(invokeTx); ok && account.preflight {
+		if err := account.ValidateInvoke(ctx, tx); err != nil {
+			account.releaseNonce(tx.Nonce, err)
+			return nil, err
+		}
+	}
+	resp, err := account.provider.AddInvokeTransaction(ctx, invokeTx)
+	if err != nil {
+		account.releaseNonce(txnNonce(invokeTx), err)
+		return nil, err
+	}
+	return resp, nil
 }
 
 // AddDeclareTransaction adds a declare transaction to the account.
+// If the nonce cache is enabled and the broadcast fails, the nonce of the transaction is released.
 //
 // Parameters:
 // - ctx: The context.Context for the request.
@@ -534,7 +628,12 @@ func (account *Account) AddInvokeTransaction(ctx context.Context, invokeTx rpc.B
 // - *rpc.AddDeclareTransactionResponse: The response for adding a declare transaction
 // - error: an error, if any
 func (account *Account) AddDeclareTransaction(ctx context.Context, declareTransaction rpc.BroadcastDeclareTxnType) (*rpc.AddDeclareTransactionResponse, error) {
-	return account.provider.AddDeclareTransaction(ctx, declareTransaction)
+	resp, err := account.provider.AddDeclareTransaction(ctx, declareTransaction)
+	if err != nil {
+		account.releaseNonce(txnNonce(declareTransaction), err)
+		return nil, err
+	}
+	return resp, nil
 }
 
 // AddDeployAccountTransaction adds a deploy account transaction to the account.
@@ -624,6 +723,69 @@ func (account *Account) Call(ctx context.Context, call rpc.FunctionCall, blockId
 	return account.provider.Call(ctx, call, blockId)
 }
 
+// CallAndDecode performs a function call on a block and decodes its output into dest with the ABI of the class
+// of the called contract at the block, as contracts.DecodeReturn does.
+//
+// Parameters:
+// - ctx: The context.Context object for the function.
+// - call: The rpc.FunctionCall object representing the function call.
+// - blockID: The rpc.BlockID object representing the block ID.
+// - dest: a pointer to the value to decode the output into
+// Returns:
+// - error: contracts.ErrFunctionNotInABI if the ABI of the class has no function of the selector of the call,
+// or an error if the call fails or its output cannot be decoded into dest
+func (account *Account) CallAndDecode(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID, dest interface{}) error {
+	class, err := account.provider.ClassAt(ctx, blockID, call.ContractAddress)
+	if err != nil {
+		return err
+	}
+	var abi []byte
+	switch class := class.(type) {
+	case *rpc.ContractClass:
+		abi = []byte(class.ABI)
+	case *rpc.DeprecatedContractClass:
+		if abi, err = json.Marshal(class.ABI); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported class %T", class)
+	}
+	name, err := functionName(abi, call.EntryPointSelector)
+	if err != nil {
+		return err
+	}
+
+	output, err := account.provider.Call(ctx, call, blockID)
+	if err != nil {
+		return err
+	}
+	return contracts.DecodeReturn(abi, name, output, dest)
+}
+
+// functionName returns the name of the function of the selector in the ABI, declared at its top level or
+// inside one of its interfaces.
+func functionName(abi []byte, selector *felt.Felt) (string, error) {
+	var entries rpc.ABI
+	if err := json.Unmarshal(abi, &entries); err != nil {
+		return "", fmt.Errorf("invalid ABI: %w", err)
+	}
+	functions := []*rpc.FunctionABIEntry{}
+	for _, entry := range entries {
+		switch entry := entry.(type) {
+		case *rpc.FunctionABIEntry:
+			functions = append(functions, entry)
+		case *rpc.InterfaceABIEntry:
+			functions = append(functions, entry.Items...)
+		}
+	}
+	for _, function := range functions {
+		if selector != nil && utils.GetSelectorFromNameFelt(function.Name).Equal(selector) {
+			return function.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: selector %s", contracts.ErrFunctionNotInABI, selector)
+}
+
 // ChainID returns the chain ID associated with the account.
 //
 // Parameters:
@@ -817,8 +979,8 @@ func (account *Account) TransactionReceipt(ctx context.Context, transactionHash
 // - ctx: The context.Context object for the request.
 // - transactionHash: The transaction hash for which the transaction trace is to be retrieved.
 // Returns:
-// - rpc.TxnTrace: The rpc.TxnTrace object representing the transaction trace, and an error if any.
-func (account *Account) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (rpc.TxnTrace, error) {
+// - rpc.TransactionTrace: The rpc.TransactionTrace object representing the transaction trace, and an error if any.
+func (account *Account) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (rpc.TransactionTrace, error) {
 	return account.provider.TraceTransaction(ctx, transactionHash)
 }
 
@@ -7,16 +7,21 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/devnet"
 	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/keystore"
 	"github.com/NethermindEth/starknet.go/mocks"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typeddata"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/require"
@@ -164,7 +169,7 @@ func TestTransactionHashInvoke(t *testing.T) {
 			}
 
 			mockRpcProvider.EXPECT().ChainID(context.Background()).Return(test.ChainID, nil)
-			account, err := account.NewAccount(mockRpcProvider, test.AccountAddress, test.PubKey, ks, 0)
+			account, err := account.NewAccount(mockRpcProvider, test.AccountAddress, account.NewKeystoreSigner(ks, test.PubKey), 0)
 			require.NoError(t, err, "error returned from account.NewAccount()")
 			invokeTxn := rpc.BroadcastInvokev1Txn{
 				InvokeTxnV1: rpc.InvokeTxnV1{
@@ -249,7 +254,7 @@ func TestFmtCallData(t *testing.T) {
 
 	for _, test := range testSet {
 		mockRpcProvider.EXPECT().ChainID(context.Background()).Return(test.ChainID, nil)
-		acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, "pubkey", account.NewMemKeystore(), test.CairoVersion)
+		acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), "pubkey"), test.CairoVersion)
 		require.NoError(t, err)
 
 		fmtCallData, err := acnt.FmtCalldata([]rpc.FunctionCall{test.FnCall})
@@ -299,7 +304,7 @@ func TestChainIdMOCK(t *testing.T) {
 
 	for _, test := range testSet {
 		mockRpcProvider.EXPECT().ChainID(context.Background()).Return(test.ChainID, nil)
-		account, err := account.NewAccount(mockRpcProvider, &felt.Zero, "pubkey", account.NewMemKeystore(), 0)
+		account, err := account.NewAccount(mockRpcProvider, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), "pubkey"), 0)
 		require.NoError(t, err)
 		require.Equal(t, test.ExpectedID, account.ChainId.String())
 	}
@@ -340,7 +345,7 @@ func TestChainId(t *testing.T) {
 		client, err := rpc.NewProvider(base)
 		require.NoError(t, err, "Error in rpc.NewClient")
 
-		account, err := account.NewAccount(client, &felt.Zero, "pubkey", account.NewMemKeystore(), 0)
+		account, err := account.NewAccount(client, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), "pubkey"), 0)
 		require.NoError(t, err)
 		require.Equal(t, account.ChainId.String(), test.ExpectedID)
 	}
@@ -414,7 +419,7 @@ func TestSignMOCK(t *testing.T) {
 		ks.Put(test.Address.String(), privKeyBI)
 
 		mockRpcProvider.EXPECT().ChainID(context.Background()).Return(test.ChainId, nil)
-		account, err := account.NewAccount(mockRpcProvider, test.Address, test.Address.String(), ks, 0)
+		account, err := account.NewAccount(mockRpcProvider, test.Address, account.NewKeystoreSigner(ks, test.Address.String()), 0)
 		require.NoError(t, err, "error returned from account.NewAccount()")
 
 		sig, err := account.Sign(context.Background(), test.FeltToSign)
@@ -517,7 +522,7 @@ func TestAddInvoke(t *testing.T) {
 			ks.Put(test.PubKey.String(), fakePrivKeyBI)
 		}
 
-		acnt, err := account.NewAccount(client, test.AccountAddress, test.PubKey.String(), ks, 2)
+		acnt, err := account.NewAccount(client, test.AccountAddress, account.NewKeystoreSigner(ks, test.PubKey.String()), 2)
 		require.NoError(t, err)
 
 		test.InvokeTx.Calldata, err = acnt.FmtCalldata([]rpc.FunctionCall{test.FnCall})
@@ -571,7 +576,7 @@ func TestAddDeployAccountDevnet(t *testing.T) {
 	require.True(t, ok)
 	ks.Put(fakeUser.PublicKey, fakePrivKeyBI)
 
-	acnt, err := account.NewAccount(client, fakeUserAddr, fakeUser.PublicKey, ks, 0)
+	acnt, err := account.NewAccount(client, fakeUserAddr, account.NewKeystoreSigner(ks, fakeUser.PublicKey), 0)
 	require.NoError(t, err)
 
 	classHash := utils.TestHexToFelt(t, "0x061dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f") // preDeployed classhash
@@ -588,8 +593,9 @@ func TestAddDeployAccountDevnet(t *testing.T) {
 		ConstructorCalldata: []*felt.Felt{fakeUserPub},
 	}
 
-	precomputedAddress, err := acnt.PrecomputeAccountAddress(fakeUserPub, classHash, tx.ConstructorCalldata)
-	require.Nil(t, err)
+	precomputedAddress := acnt.PrecomputeAccountAddress(fakeUserPub, classHash, tx.ConstructorCalldata)
+	// the predeployed class of the devnet is the OpenZeppelin account v0.8.1
+	require.Equal(t, account.PrecomputeOZAddress(fakeUserPub, fakeUserPub), precomputedAddress)
 	require.NoError(t, acnt.SignDeployAccountTransaction(context.Background(), &tx, precomputedAddress))
 
 	_, err = devnet.Mint(precomputedAddress, new(big.Int).SetUint64(10000000000000000000))
@@ -598,6 +604,8 @@ func TestAddDeployAccountDevnet(t *testing.T) {
 	resp, err := acnt.AddDeployAccountTransaction(context.Background(), rpc.BroadcastDeployAccountTxn{DeployAccountTxn: tx})
 	require.Nil(t, err, "AddDeployAccountTransaction gave an Error")
 	require.NotNil(t, resp, "AddDeployAccountTransaction resp not nil")
+	// the address the node deploys the account at
+	require.Equal(t, precomputedAddress, resp.ContractAddress)
 }
 
 // TestTransactionHashDeclare tests the TransactionHashDeclare function.
@@ -625,7 +633,7 @@ func TestTransactionHashDeclare(t *testing.T) {
 	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
 	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
 
-	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, "", account.NewMemKeystore(), 0)
+	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 0)
 	require.NoError(t, err)
 
 	type testSetType struct {
@@ -687,7 +695,7 @@ func TestTransactionHashInvokeV3(t *testing.T) {
 	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
 	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
 
-	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, "", account.NewMemKeystore(), 0)
+	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 0)
 	require.NoError(t, err)
 
 	type testSetType struct {
@@ -823,7 +831,7 @@ func TestTransactionHashdeployAccount(t *testing.T) {
 	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
 	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
 
-	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, "", account.NewMemKeystore(), 0)
+	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 0)
 	require.NoError(t, err)
 
 	type testSetType struct {
@@ -974,7 +982,7 @@ func TestWaitForTransactionReceiptMOCK(t *testing.T) {
 	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
 
 	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
-	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, "", account.NewMemKeystore(), 0)
+	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 0)
 	require.NoError(t, err, "error returned from account.NewAccount()")
 
 	type testSetType struct {
@@ -1061,7 +1069,7 @@ func TestWaitForTransactionReceipt(t *testing.T) {
 	client, err := rpc.NewProvider(base)
 	require.NoError(t, err, "Error in rpc.NewClient")
 
-	acnt, err := account.NewAccount(client, &felt.Zero, "pubkey", account.NewMemKeystore(), 0)
+	acnt, err := account.NewAccount(client, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), "pubkey"), 0)
 	require.NoError(t, err, "error returned from account.NewAccount()")
 
 	type testSetType struct {
@@ -1125,7 +1133,7 @@ func TestAddDeclareTxn(t *testing.T) {
 	client, err := rpc.NewProvider(base)
 	require.NoError(t, err, "Error in rpc.NewClient")
 
-	acnt, err := account.NewAccount(client, AccountAddress, PubKey.String(), ks, 0)
+	acnt, err := account.NewAccount(client, AccountAddress, account.NewKeystoreSigner(ks, PubKey.String()), 0)
 	require.NoError(t, err)
 
 	// Class Hash
@@ -1145,7 +1153,8 @@ func TestAddDeclareTxn(t *testing.T) {
 	var casmClass contracts.CasmClass
 	err = json.Unmarshal(content2, &casmClass)
 	require.NoError(t, err)
-	compClassHash := hash.CompiledClassHash(casmClass)
+	compClassHash, err := hash.CompiledClassHash(casmClass)
+	require.NoError(t, err)
 
 	tx := rpc.DeclareTxnV2{
 		Nonce:   utils.TestHexToFelt(t, "0xd"),
@@ -1199,3 +1208,1141 @@ func newDevnet(t *testing.T, url string) (*devnet.DevNet, []devnet.TestAccount,
 	acnts, err := devnet.Accounts()
 	return devnet, acnts, err
 }
+
+// TestNonceCacheMOCK tests the nonce cache of the Account.
+//
+// It checks that concurrent reservations get distinct nonces from a single fetch,
+// that a failed broadcast rolls the cached nonce back, that a nonce error invalidates the cache and
+// that a nonce handed out before the cache was refetched is not given back.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestNonceCacheMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, accountAddress, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 2)
+	require.NoError(t, err)
+	acnt.EnableNonceCache()
+
+	ctx := context.Background()
+	mockRpcProvider.EXPECT().Nonce(ctx, rpc.WithBlockTag("pending"), accountAddress).Return(new(felt.Felt).SetUint64(5), nil).Times(1)
+
+	const reservations = 10
+	type reservation struct {
+		nonce *felt.Felt
+		err   error
+	}
+	reserved := make(chan reservation, reservations)
+	var wg sync.WaitGroup
+	for i := 0; i < reservations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := acnt.NextNonce(ctx)
+			reserved <- reservation{nonce: nonce, err: err}
+		}()
+	}
+	wg.Wait()
+	close(reserved)
+	seen := map[uint64]bool{}
+	for reservation := range reserved {
+		require.NoError(t, reservation.err)
+		seen[utils.FeltToBigInt(reservation.nonce).Uint64()] = true
+	}
+	for nonce := uint64(5); nonce < 5+reservations; nonce++ {
+		require.True(t, seen[nonce], "nonce %d was not reserved", nonce)
+	}
+
+	// a failed broadcast of the last reserved nonce rolls the cache back
+	lastTxn := rpc.BroadcastInvokev1Txn{InvokeTxnV1: rpc.InvokeTxnV1{Nonce: new(felt.Felt).SetUint64(14)}}
+	mockRpcProvider.EXPECT().AddInvokeTransaction(ctx, lastTxn).Return(nil, rpc.ErrDuplicateTx)
+	_, err = acnt.AddInvokeTransaction(ctx, lastTxn)
+	require.Error(t, err)
+	nonce, err := acnt.NextNonce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(14), utils.FeltToBigInt(nonce).Uint64())
+
+	// a nonce error invalidates the cache, which is then refetched from the pending block
+	mockRpcProvider.EXPECT().AddInvokeTransaction(ctx, lastTxn).Return(nil, rpc.ErrInvalidTransactionNonce)
+	_, err = acnt.AddInvokeTransaction(ctx, lastTxn)
+	require.Error(t, err)
+	mockRpcProvider.EXPECT().Nonce(ctx, rpc.WithBlockTag("pending"), accountAddress).Return(new(felt.Felt).SetUint64(20), nil).Times(1)
+	nonce, err = acnt.NextNonce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), utils.FeltToBigInt(nonce).Uint64())
+
+	// a nonce handed out before the refetch is not given back, the node already counted it
+	staleTxn := rpc.BroadcastInvokev1Txn{InvokeTxnV1: rpc.InvokeTxnV1{Nonce: new(felt.Felt).SetUint64(13)}}
+	mockRpcProvider.EXPECT().AddInvokeTransaction(ctx, staleTxn).Return(nil, rpc.ErrDuplicateTx)
+	_, err = acnt.AddInvokeTransaction(ctx, staleTxn)
+	require.Error(t, err)
+	nonce, err = acnt.NextNonce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(21), utils.FeltToBigInt(nonce).Uint64())
+}
+
+func TestBraavosSignatureLayoutMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	privKey, ok := new(big.Int).SetString("0x04818374f8071c3b4c3070ff7ce766e7b9352628df7b815ea4de26e0fadb5cc9", 0)
+	require.True(t, ok)
+	ks := account.NewMemKeystore()
+	ks.Put(address.String(), privKey)
+
+	ctx := context.Background()
+	mockRpcProvider.EXPECT().ChainID(ctx).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, address.String()), 2)
+	require.NoError(t, err)
+
+	braavosClassHash := utils.TestHexToFelt(t, "0x00816dd0297efc55dc1e7559020a3a825e81ef734b558f03c83325d4da7e6253")
+	mockRpcProvider.EXPECT().ClassHashAt(ctx, rpc.WithBlockTag("latest"), address).Return(braavosClassHash, nil)
+	layout, err := acnt.DetectSignatureLayout(ctx)
+	require.NoError(t, err)
+	require.IsType(t, account.BraavosSignatureLayout{}, layout)
+
+	msg := utils.TestHexToFelt(t, "0x4b2e6743b03a0412f8450dd1d337f37a0e946603c3e6fbf4ba2469703c1705b")
+	starkR := "0xfa671736285eb70057579532f0efb6fde09ecefe323755ffd126537234e9c5"
+	starkS := "0x27bf55daa78a3ccfb7a4ee6576a13adfc44af707c28588be8292b8476bb27ef"
+
+	// a standard Braavos signer only signs with its Stark key
+	sig, err := acnt.SignWithSigners(ctx, msg)
+	require.NoError(t, err)
+	require.Equal(t, []string{starkR, starkS}, feltStrings(sig))
+
+	// hardware signatures follow the Stark one as [signer_id, r.low, r.high, s.low, s.high]
+	hwR, ok := new(big.Int).SetString("0x11111111111111111111111111111111aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", 0)
+	require.True(t, ok)
+	hwS, ok := new(big.Int).SetString("0x22222222222222222222222222222222bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", 0)
+	require.True(t, ok)
+	hw := account.SignerSignature{Type: account.SignerTypeSecp256r1, SignerID: utils.TestHexToFelt(t, "0x1"), R: hwR, S: hwS}
+	sig, err = acnt.SignWithSigners(ctx, msg, hw)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		starkR,
+		starkS,
+		"0x1",
+		"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"0x11111111111111111111111111111111",
+		"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		"0x22222222222222222222222222222222",
+	}, feltStrings(sig))
+
+	decoded, err := layout.Unpack(sig)
+	require.NoError(t, err)
+	require.Len(t, decoded, 2)
+	require.Equal(t, account.SignerTypeStark, decoded[0].Type)
+	require.Equal(t, hw, decoded[1])
+
+	_, err = layout.Unpack(sig[:4])
+	require.ErrorIs(t, err, account.ErrInvalidSignatureLayout)
+
+	// Sign packs the signature with the layout of the account
+	sig, err = acnt.Sign(ctx, msg)
+	require.NoError(t, err)
+	require.Equal(t, []string{starkR, starkS}, feltStrings(sig))
+	acnt.SetSignatureLayout(countedSignatureLayout{})
+	sig, err = acnt.Sign(ctx, msg)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0x1", starkR, starkS}, feltStrings(sig))
+}
+
+// countedSignatureLayout is a SignatureLayout prefixing the [r, s] signatures with their number.
+type countedSignatureLayout struct{}
+
+func (countedSignatureLayout) Pack(signatures ...account.SignerSignature) ([]*felt.Felt, error) {
+	packed := []*felt.Felt{new(felt.Felt).SetUint64(uint64(len(signatures)))}
+	for _, sig := range signatures {
+		packed = append(packed, utils.BigIntToFelt(sig.R), utils.BigIntToFelt(sig.S))
+	}
+	return packed, nil
+}
+
+func (countedSignatureLayout) Unpack(signature []*felt.Felt) ([]account.SignerSignature, error) {
+	return nil, account.ErrInvalidSignatureLayout
+}
+
+func feltStrings(felts []*felt.Felt) []string {
+	strs := make([]string, len(felts))
+	for i, f := range felts {
+		strs[i] = f.String()
+	}
+	return strs
+}
+
+func TestSignTypedDataMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	privKey, ok := new(big.Int).SetString("0x04818374f8071c3b4c3070ff7ce766e7b9352628df7b815ea4de26e0fadb5cc9", 0)
+	require.True(t, ok)
+	ks := account.NewMemKeystore()
+	ks.Put(address.String(), privKey)
+
+	ctx := context.Background()
+	mockRpcProvider.EXPECT().ChainID(ctx).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, address.String()), 2)
+	require.NoError(t, err)
+
+	var td typeddata.TypedData
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"types": {
+			"StarknetDomain": [
+				{ "name": "name", "type": "shortstring" },
+				{ "name": "version", "type": "shortstring" },
+				{ "name": "chainId", "type": "shortstring" },
+				{ "name": "revision", "type": "shortstring" }
+			],
+			"Transfer": [
+				{ "name": "recipient", "type": "ContractAddress" },
+				{ "name": "amount", "type": "u128" }
+			]
+		},
+		"primaryType": "Transfer",
+		"domain": { "name": "Dapp", "version": "1", "chainId": "SN_SEPOLIA", "revision": "1" },
+		"message": { "recipient": "0x1234", "amount": "100" }
+	}`), &td))
+
+	sig, err := acnt.SignTypedData(ctx, td)
+	require.NoError(t, err)
+
+	hash, err := td.GetMessageHash(address)
+	require.NoError(t, err)
+	expected, err := acnt.Sign(ctx, hash)
+	require.NoError(t, err)
+	require.Equal(t, expected, sig)
+}
+
+// TestPrecomputeWalletAddresses tests the wallet address helpers against accounts deployed on chain: the
+// address of an Argent X or Braavos account is checked against the contract address of its DEPLOY_ACCOUNT
+// receipt, or against the hash of its DEPLOY_ACCOUNT transaction, which commits to the address. No account of
+// the OpenZeppelin class of PrecomputeOZAddress is deployed in the fixtures, so its calldata layout (the public
+// key alone) is checked against a Sepolia account with the same constructor, and PrecomputeOZAddress against
+// the address the devnet deploys an account of the class at in TestAddDeployAccountDevnet.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestPrecomputeWalletAddresses(t *testing.T) {
+	// Argent X account deployed on mainnet in block 588763 (tx 0x9b3d4c1bbdb926a382b7dd07a0ad0ecb6f1481d91a91ede403023db9afd94f)
+	argentSigner := utils.TestHexToFelt(t, "0x7e804e99010172c123186ec8ae5cf3ad2b76d2192567b1f9470dbf57bc7c56b")
+	require.Equal(t,
+		"0x3a89c0b226eb39bb3a30cdc65efb574cf5421a13a1536708153d269d6aa96df",
+		account.PrecomputeArgentAddress(argentSigner, &felt.Zero, argentSigner).String(),
+	)
+	// https://sepolia.voyager.online/tx/0x4bf28fb0142063f1b9725ae490c6949e6f1842c79b49f7cc674b7e3f5ad4875
+	argentSigner = utils.TestHexToFelt(t, "0x1a09f0001cc46f82b1a805d07c13e235248a44ed13d87f170d7d925e3c86082")
+	require.Equal(t,
+		"0x365633b6c2ca24b461747d2fe8e0c19a3637a954ee703a7ed0e5d1d9644ad1a",
+		account.PrecomputeArgentAddress(argentSigner, &felt.Zero, argentSigner).String(),
+	)
+
+	// Braavos account deployed on Sepolia in block 65204 (tx 0x26e30d2ed579c1ff575710d8ce29d9056e67ac08ab261a7221d384734d6ad5a)
+	braavosKey := utils.TestHexToFelt(t, "0x5ffef5f00daec09457836121dcb7a8bcae97080716a292d3d8ee899d0b2597e")
+	braavosAddress := account.PrecomputeBraavosAddress(braavosKey, braavosKey)
+	require.Equal(t, "0x1464ec80b9557ac41115c49e8ce2d1c203ec7030e702cfd10b704aa54fc4122", braavosAddress.String())
+	mockCtrl := gomock.NewController(t)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, &felt.Zero, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 0)
+	require.NoError(t, err)
+	hash, err := acnt.TransactionHashDeployAccount(rpc.DeployAccountTxn{
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV1,
+		Nonce:               &felt.Zero,
+		MaxFee:              utils.TestHexToFelt(t, "0x4865413596"),
+		ClassHash:           account.BraavosBaseClassHash,
+		ContractAddressSalt: braavosKey,
+		ConstructorCalldata: []*felt.Felt{braavosKey},
+	}, braavosAddress)
+	require.NoError(t, err)
+	require.Equal(t, "0x26e30d2ed579c1ff575710d8ce29d9056e67ac08ab261a7221d384734d6ad5a", hash.String())
+
+	// https://sepolia.voyager.online/tx/0x66d1d9d50d308a9eb16efedbad208b0672769a545a0b828d357757f444e9188, an
+	// account whose constructor takes the public key alone, as the OpenZeppelin accounts
+	ozKey := utils.TestHexToFelt(t, "0x960532cfba33384bbec41aa669727a9c51e995c87e101c86706aaf244f7e4e")
+	ozSalt := utils.TestHexToFelt(t, "0x15d621f9515c6197d3117eb1a25c7a4a669317be8f49831e03fcc00d855352e")
+	sepoliaClassHash := utils.TestHexToFelt(t, "0x1e60c8722677cfb7dd8dbea5be86c09265db02cdfe77113e77da7d44c017388")
+	require.Equal(t,
+		"0x5dd5faeddd4a9e01231f3bb9b95ec93426d08977b721c222e45fd98c5f353ff",
+		contracts.PrecomputeAccountAddress(ozSalt, sepoliaClassHash, []*felt.Felt{ozKey}).String(),
+	)
+	require.NotEqual(t, account.PrecomputeOZAddress(braavosKey, braavosKey), braavosAddress)
+}
+
+// TestSimulateConcurrentMOCK tests that SimulateConcurrent bounds the running simulations, keeps the order
+// of the transactions and reports the error of each failed simulation.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSimulateConcurrentMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(account.NewMemKeystore(), address.String()), 2)
+	require.NoError(t, err)
+
+	const concurrency = 3
+	var running, maxRunning atomic.Int32
+	failing := new(felt.Felt).SetUint64(4)
+	mockRpcProvider.EXPECT().SimulateTransactions(gomock.Any(), rpc.WithBlockTag("pending"), gomock.Len(1), nil).DoAndReturn(
+		func(ctx context.Context, blockID rpc.BlockID, txns []rpc.Transaction, flags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				seen := maxRunning.Load()
+				if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			nonce := txns[0].(rpc.BroadcastInvokev1Txn).Nonce
+			if nonce.Equal(failing) {
+				return nil, rpc.ErrValidationFailure
+			}
+			return []rpc.SimulatedTransaction{{FeeEstimate: rpc.FeeEstimate{OverallFee: nonce}}}, nil
+		}).Times(9)
+
+	txns := make([]rpc.BroadcastTxn, 10)
+	for i := range txns {
+		txns[i] = rpc.BroadcastInvokev1Txn{InvokeTxnV1: rpc.InvokeTxnV1{Nonce: new(felt.Felt).SetUint64(uint64(i))}}
+	}
+	txns[7] = "not a transaction"
+
+	outputs, err := acnt.SimulateConcurrent(context.Background(), txns, nil, concurrency)
+	require.Len(t, outputs, len(txns))
+	require.LessOrEqual(t, maxRunning.Load(), int32(concurrency))
+	for i, output := range outputs {
+		if i == 4 || i == 7 {
+			require.Empty(t, output.Txns)
+			continue
+		}
+		require.Equal(t, new(felt.Felt).SetUint64(uint64(i)), output.Txns[0].OverallFee)
+	}
+
+	var simErr *account.ConcurrentSimulationError
+	require.ErrorAs(t, err, &simErr)
+	require.Len(t, simErr.Errs, len(txns))
+	require.Equal(t, rpc.ErrValidationFailure, simErr.Errs[4])
+	require.ErrorIs(t, simErr.Errs[7], account.ErrNotATransaction)
+	require.ErrorIs(t, err, rpc.ErrValidationFailure)
+
+	// a cancelled context fails the simulations not started yet
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = acnt.SimulateConcurrent(ctx, txns, nil, concurrency)
+	require.ErrorAs(t, err, &simErr)
+	for _, err := range simErr.Errs {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+// TestBuildDeclareTxnV3MOCK tests that BuildDeclareTxnV3 declares the Sierra class with its class hash and
+// compiled class hash, and signs the transaction hash with the account key.
+//
+// Parameters:
+//   - t: The testing.T instance for running the test
+//
+// Returns:
+//
+//	none
+func TestBuildDeclareTxnV3MOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	privKey := utils.TestHexToFelt(t, "0x04818374f8071c3b4c3070ff7ce766e7b9352628df7b815ea4de26e0fadb5cc9")
+	pubX, pubY, err := curve.Curve.PrivateToPoint(utils.FeltToBigInt(privKey))
+	require.NoError(t, err)
+	pubKey := utils.BigIntToFelt(pubX)
+	ks := account.SetNewMemKeystore(pubKey.String(), utils.FeltToBigInt(privKey))
+
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pubKey.String()), 2)
+	require.NoError(t, err)
+
+	sierraClass, err := contracts.UnmarshalSierraClass("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	casmClass, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	resourceBounds := rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x186a0", MaxPricePerUnit: "0x5af3107a4000"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+
+	tx, err := acnt.BuildDeclareTxnV3(context.Background(), *sierraClass, *casmClass, new(felt.Felt).SetUint64(3), resourceBounds)
+	require.NoError(t, err)
+	require.Equal(t, rpc.TransactionV3, tx.Version)
+	require.Equal(t, address, tx.SenderAddress)
+	require.Equal(t, "0x785fa5f2bacf0bfe3bc413be5820a61e1ea63f2ec27ef00331ee9f46ad07603", tx.CompiledClassHash.String())
+	require.Equal(t, sierraClass.ContractClass(), *tx.ContractClass)
+
+	txHash, err := acnt.TransactionHashDeclare(rpc.DeclareTxnV3{
+		Type:                  tx.Type,
+		SenderAddress:         tx.SenderAddress,
+		CompiledClassHash:     tx.CompiledClassHash,
+		Version:               tx.Version,
+		Nonce:                 tx.Nonce,
+		ClassHash:             utils.TestHexToFelt(t, "0x4ec2ecf58014bc2ffd7c84843c3525e5ecb0a2cac33c47e9c347f39fc0c0944"),
+		ResourceBounds:        tx.ResourceBounds,
+		Tip:                   tx.Tip,
+		PayMasterData:         tx.PayMasterData,
+		AccountDeploymentData: tx.AccountDeploymentData,
+		NonceDataMode:         tx.NonceDataMode,
+		FeeMode:               tx.FeeMode,
+	})
+	require.NoError(t, err)
+	require.Len(t, tx.Signature, 2)
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(txHash), utils.FeltToBigInt(tx.Signature[0]), utils.FeltToBigInt(tx.Signature[1]), pubX, pubY))
+}
+
+// TestEstimateFeeInMOCK tests that EstimateFeeIn estimates the invoke transaction of the spec version of the
+// account, and converts the estimated fee with the price of the fee token of its unit.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestEstimateFeeInMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil).Times(2)
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    account.ETHTokenAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{address, new(felt.Felt).SetUint64(1), new(felt.Felt)},
+	}}
+	mockRpcProvider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag(rpc.BlockTagPending), address).Return(new(felt.Felt).SetUint64(4), nil).Times(2)
+
+	// an invoke v1 transaction paid in ETH before 0.6.0
+	v1Account, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2, account.WithSpecVersion("0.5.1"))
+	require.NoError(t, err)
+	mockRpcProvider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Nil(), rpc.WithBlockTag(rpc.BlockTagPending)).DoAndReturn(
+		func(_ context.Context, requests []rpc.BroadcastTxn, _ []rpc.SimulationFlag, _ rpc.BlockID) ([]rpc.FeeEstimate, error) {
+			require.Len(t, requests, 1)
+			txn := requests[0].(rpc.BroadcastInvokev1Txn)
+			require.Equal(t, new(felt.Felt).SetUint64(4), txn.Nonce)
+			require.Equal(t, account.FmtCallDataCairo2(calls), txn.Calldata)
+			require.Len(t, txn.Signature, 2)
+			// 0.002 ETH
+			return []rpc.FeeEstimate{{OverallFee: new(felt.Felt).SetUint64(2_000_000_000_000_000), FeeUnit: rpc.UnitWei}}, nil
+		})
+
+	amount, value, err := v1Account.EstimateFeeIn(context.Background(), calls, func(token *felt.Felt) (float64, error) {
+		require.Equal(t, account.ETHTokenAddress, token)
+		return 3000, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(2_000_000_000_000_000), amount)
+	require.InDelta(t, 6.0, value, 1e-9)
+
+	// an invoke v3 transaction paid in STRK from 0.6.0 on, the unit defaulting to the one of the transaction
+	v3Account, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2)
+	require.NoError(t, err)
+	mockRpcProvider.EXPECT().SpecVersion(gomock.Any()).Return("0.7.1", nil)
+	mockRpcProvider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Nil(), rpc.WithBlockTag(rpc.BlockTagPending)).DoAndReturn(
+		func(_ context.Context, requests []rpc.BroadcastTxn, _ []rpc.SimulationFlag, _ rpc.BlockID) ([]rpc.FeeEstimate, error) {
+			require.Len(t, requests, 1)
+			txn := requests[0].(rpc.BroadcastInvokev3Txn)
+			require.Equal(t, rpc.TransactionV3, txn.Version)
+			require.Equal(t, new(felt.Felt).SetUint64(4), txn.Nonce)
+			require.Equal(t, account.FmtCallDataCairo2(calls), txn.Calldata)
+			require.Len(t, txn.Signature, 2)
+			// 5 STRK
+			return []rpc.FeeEstimate{{OverallFee: new(felt.Felt).SetUint64(5_000_000_000_000_000_000)}}, nil
+		})
+
+	amount, value, err = v3Account.EstimateFeeIn(context.Background(), calls, func(token *felt.Felt) (float64, error) {
+		require.Equal(t, account.STRKTokenAddress, token)
+		return 0.5, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(5_000_000_000_000_000_000), amount)
+	require.InDelta(t, 2.5, value, 1e-9)
+}
+
+// TestBuildInvokeTxnMOCK tests that the invoke transaction built by an account has the shape of its spec version,
+// pinned with WithSpecVersion or else negotiated with the provider.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestBuildInvokeTxnMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    account.ETHTokenAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{address, new(felt.Felt).SetUint64(1), new(felt.Felt)},
+	}}
+	nonce := new(felt.Felt).SetUint64(4)
+	maxFee := new(felt.Felt).SetUint64(1_000_000)
+	resourceBounds := rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x200"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+
+	// the pinned version overrides the one of the provider, which is not queried
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	pinned, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2, account.WithSpecVersion("0.5.1"))
+	require.NoError(t, err)
+	txn, err := pinned.BuildInvokeTxn(context.Background(), calls, nonce, maxFee, resourceBounds)
+	require.NoError(t, err)
+	v1, ok := txn.(rpc.BroadcastInvokev1Txn)
+	require.True(t, ok, "expected an invoke v1 transaction, got %T", txn)
+	require.Equal(t, rpc.TransactionV1, v1.Version)
+	require.Equal(t, maxFee, v1.MaxFee)
+	require.Equal(t, account.FmtCallDataCairo2(calls), v1.Calldata)
+	require.Len(t, v1.Signature, 2)
+
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	negotiated, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2)
+	require.NoError(t, err)
+	mockRpcProvider.EXPECT().SpecVersion(gomock.Any()).Return("0.7.1", nil)
+	txn, err = negotiated.BuildInvokeTxn(context.Background(), calls, nonce, maxFee, resourceBounds)
+	require.NoError(t, err)
+	v3, ok := txn.(rpc.BroadcastInvokev3Txn)
+	require.True(t, ok, "expected an invoke v3 transaction, got %T", txn)
+	require.Equal(t, rpc.TransactionV3, v3.Version)
+	require.Equal(t, resourceBounds, v3.ResourceBounds)
+	require.Len(t, v3.Signature, 2)
+
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	invalid, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2, account.WithSpecVersion("latest"))
+	require.NoError(t, err)
+	_, err = invalid.BuildInvokeTxn(context.Background(), calls, nonce, maxFee, resourceBounds)
+	require.ErrorIs(t, err, account.ErrInvalidSpecVersion)
+}
+
+// TestBuildInvokeTxnNonceMOCK tests the nonce reservation of a single invoke transaction: BuildInvokeTxn
+// given a nil nonce reserves it from the nonce cache, a transaction failing to be signed gives it back and so
+// does a failed broadcast with AddInvokeTransaction.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestBuildInvokeTxnNonceMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x1234")
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    account.ETHTokenAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{address, new(felt.Felt).SetUint64(1), new(felt.Felt)},
+	}}
+	resourceBounds := rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x200"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+	ctx := context.Background()
+
+	mockRpcProvider.EXPECT().ChainID(ctx).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2, account.WithSpecVersion("0.7.1"))
+	require.NoError(t, err)
+	acnt.EnableNonceCache()
+	mockRpcProvider.EXPECT().Nonce(ctx, rpc.WithBlockTag("pending"), address).Return(new(felt.Felt).SetUint64(5), nil).Times(1)
+
+	// consecutive transactions get consecutive nonces from a single fetch
+	for _, expected := range []uint64{5, 6} {
+		txn, err := acnt.BuildInvokeTxn(ctx, calls, nil, nil, resourceBounds)
+		require.NoError(t, err)
+		v3, ok := txn.(rpc.BroadcastInvokev3Txn)
+		require.True(t, ok, "expected an invoke v3 transaction, got %T", txn)
+		require.Equal(t, new(felt.Felt).SetUint64(expected), v3.Nonce)
+	}
+
+	// an explicit nonce is not reserved
+	txn, err := acnt.BuildInvokeTxn(ctx, calls, new(felt.Felt).SetUint64(42), nil, resourceBounds)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(42), txn.(rpc.BroadcastInvokev3Txn).Nonce)
+
+	// a failed broadcast of the last reserved nonce gives it back
+	txn, err = acnt.BuildInvokeTxn(ctx, calls, nil, nil, resourceBounds)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(7), txn.(rpc.BroadcastInvokev3Txn).Nonce)
+	mockRpcProvider.EXPECT().AddInvokeTransaction(ctx, txn).Return(nil, rpc.ErrDuplicateTx)
+	_, err = acnt.AddInvokeTransaction(ctx, txn)
+	require.Error(t, err)
+
+	// a transaction failing to be signed gives its nonce back
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = acnt.BuildInvokeTxn(canceled, calls, nil, nil, resourceBounds)
+	require.ErrorIs(t, err, context.Canceled)
+	txn, err = acnt.BuildInvokeTxn(ctx, calls, nil, nil, resourceBounds)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(7), txn.(rpc.BroadcastInvokev3Txn).Nonce)
+}
+
+// TestMulticallMOCK tests the __execute__ calldata of a multicall, for calls with an empty calldata, for more
+// than 10 calls and for a Cairo 0 account, and that Invoke sends the signed invoke v3 transaction with the
+// pending nonce.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestMulticallMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2)
+	require.NoError(t, err)
+	resourceBounds := rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x200"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+
+	_, err = acnt.NewMulticall().Invoke(context.Background(), resourceBounds)
+	require.ErrorIs(t, err, account.ErrEmptyMulticall)
+
+	// [number of calls, (address, selector, calldata length, calldata...) for each call]
+	token := utils.TestHexToFelt(t, "0x1")
+	approve := utils.GetSelectorFromNameFelt("approve")
+	skim := utils.GetSelectorFromNameFelt("skim")
+	two := acnt.NewMulticall().
+		Add(rpc.FunctionCall{ContractAddress: token, EntryPointSelector: approve, Calldata: []*felt.Felt{address, new(felt.Felt).SetUint64(5), new(felt.Felt)}}).
+		Add(rpc.FunctionCall{ContractAddress: token, EntryPointSelector: skim})
+	twoCalldata, err := two.Calldata()
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{
+		new(felt.Felt).SetUint64(2),
+		token, approve, new(felt.Felt).SetUint64(3), address, new(felt.Felt).SetUint64(5), new(felt.Felt),
+		token, skim, new(felt.Felt),
+	}, twoCalldata)
+
+	// a Cairo 0 account gets the call array, then the calldata of all the calls
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	cairo0, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 0)
+	require.NoError(t, err)
+	cairo0Calldata, err := cairo0.NewMulticall().Add(two.Calls()[0]).Add(two.Calls()[1]).Calldata()
+	require.NoError(t, err)
+	require.Equal(t, account.FmtCallDataCairo0(two.Calls()), cairo0Calldata)
+	require.NotEqual(t, twoCalldata, cairo0Calldata)
+
+	multicall := acnt.NewMulticall()
+	for i := uint64(0); i < 12; i++ {
+		multicall.Add(rpc.FunctionCall{ContractAddress: token, EntryPointSelector: approve, Calldata: []*felt.Felt{address, new(felt.Felt).SetUint64(i)}})
+	}
+	require.Len(t, multicall.Calls(), 12)
+	calldata, err := multicall.Calldata()
+	require.NoError(t, err)
+	require.Len(t, calldata, 1+12*5)
+	require.Equal(t, new(felt.Felt).SetUint64(12), calldata[0])
+	require.Equal(t, new(felt.Felt).SetUint64(11), calldata[1+11*5+4])
+
+	nonce := new(felt.Felt).SetUint64(7)
+	txHash := utils.TestHexToFelt(t, "0x2")
+	mockRpcProvider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag(rpc.BlockTagPending), address).Return(nonce, nil)
+	mockRpcProvider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, txn rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			v3, ok := txn.(rpc.BroadcastInvokev3Txn)
+			require.True(t, ok, "expected an invoke v3 transaction, got %T", txn)
+			require.Equal(t, rpc.TransactionV3, v3.Version)
+			require.Equal(t, nonce, v3.Nonce)
+			require.Equal(t, address, v3.SenderAddress)
+			require.Equal(t, calldata, v3.Calldata)
+			require.Equal(t, resourceBounds, v3.ResourceBounds)
+			require.Len(t, v3.Signature, 2)
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil
+		})
+	resp, err := multicall.Invoke(context.Background(), resourceBounds)
+	require.NoError(t, err)
+	require.Equal(t, txHash, resp.TransactionHash)
+}
+
+// TestBuildAndSendDeployAccountMOCK tests that BuildAndSendDeployAccount checks the precomputed address and the
+// balance of the account before sending the signed deploy account transaction.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestBuildAndSendDeployAccountMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, _ := account.GetRandomKeys()
+	salt := utils.TestHexToFelt(t, "0x5")
+	calldata := []*felt.Felt{pub}
+	address := account.PrecomputeOZAddress(pub, salt)
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, account.NewKeystoreSigner(ks, pub.String()), 2)
+	require.NoError(t, err)
+	resourceBounds := rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x200"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+
+	_, err = acnt.BuildAndSendDeployAccount(context.Background(), utils.TestHexToFelt(t, "0x6"), account.OZClassHash, calldata, resourceBounds)
+	require.ErrorIs(t, err, account.ErrDeployAddressMismatch)
+
+	balanceCall := rpc.FunctionCall{
+		ContractAddress:    account.STRKTokenAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("balance_of"),
+		Calldata:           []*felt.Felt{address},
+	}
+	mockRpcProvider.EXPECT().Call(gomock.Any(), balanceCall, rpc.WithBlockTag(rpc.BlockTagPending)).Return([]*felt.Felt{new(felt.Felt), new(felt.Felt)}, nil)
+	_, err = acnt.BuildAndSendDeployAccount(context.Background(), salt, account.OZClassHash, calldata, resourceBounds)
+	require.ErrorIs(t, err, account.ErrAccountNotFunded)
+	require.Contains(t, err.Error(), address.String())
+
+	txHash := utils.TestHexToFelt(t, "0x2")
+	mockRpcProvider.EXPECT().Call(gomock.Any(), balanceCall, rpc.WithBlockTag(rpc.BlockTagPending)).Return([]*felt.Felt{new(felt.Felt).SetUint64(1), new(felt.Felt)}, nil)
+	mockRpcProvider.EXPECT().AddDeployAccountTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, txn rpc.BroadcastAddDeployTxnType) (*rpc.AddDeployAccountTransactionResponse, error) {
+			v3, ok := txn.(rpc.BroadcastDeployAccountTxnV3)
+			require.True(t, ok, "expected a deploy account v3 transaction, got %T", txn)
+			require.Equal(t, &felt.Zero, v3.Nonce)
+			require.Equal(t, account.OZClassHash, v3.ClassHash)
+			require.Equal(t, salt, v3.ContractAddressSalt)
+			require.Equal(t, calldata, v3.ConstructorCalldata)
+			require.Equal(t, resourceBounds, v3.ResourceBounds)
+			require.Len(t, v3.Signature, 2)
+			return &rpc.AddDeployAccountTransactionResponse{TransactionHash: txHash, ContractAddress: address}, nil
+		})
+	resp, err := acnt.BuildAndSendDeployAccount(context.Background(), salt, account.OZClassHash, calldata, resourceBounds)
+	require.NoError(t, err)
+	require.Equal(t, txHash, resp.TransactionHash)
+	require.Equal(t, address, resp.ContractAddress)
+}
+
+// TestFromKeystoreMOCK tests that an account created from a keystore file has the address of the keystore and
+// signs with its encrypted key.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestFromKeystoreMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	_, pub, priv := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	data, err := keystore.EncryptWithScrypt(priv, address, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	path := t.TempDir() + "/keystore.json"
+	require.NoError(t, os.WriteFile(path, data, 0600))
+
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.FromKeystore(mockRpcProvider, path, "passphrase", 2)
+	require.NoError(t, err)
+	require.Equal(t, address, acnt.AccountAddress)
+
+	msg := utils.TestHexToFelt(t, "0x2a")
+	signature, err := acnt.Sign(context.Background(), msg)
+	require.NoError(t, err)
+	pubX, pubY, err := curve.Curve.PrivateToPoint(utils.FeltToBigInt(priv))
+	require.NoError(t, err)
+	require.Equal(t, pub, utils.BigIntToFelt(pubX))
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msg), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, pubY))
+
+	_, err = account.FromKeystore(mockRpcProvider, path, "wrong", 2)
+	require.ErrorIs(t, err, keystore.ErrDecryptFailed)
+}
+
+// TestWithSignerMOCK tests that an account created with WithSigner signs with its signer instead of a keystore.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestWithSignerMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ks, pub, priv := account.GetRandomKeys()
+	signer, err := account.NewMemorySigner(priv)
+	require.NoError(t, err)
+	require.Equal(t, pub, signer.PublicKey())
+	require.Equal(t, pub, account.NewKeystoreSigner(ks, pub.String()).PublicKey())
+
+	address := utils.TestHexToFelt(t, "0x01AE6Fe02FcD9f61A3A8c30D68a8a7c470B0d7dD6F0ee685d5BBFa0d79406ff9")
+	mockRpcProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, address, signer, 2)
+	require.NoError(t, err)
+
+	msg := utils.TestHexToFelt(t, "0x2a")
+	signature, err := acnt.Sign(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, signature, 2)
+	pubX, pubY, err := curve.Curve.PrivateToPoint(utils.FeltToBigInt(priv))
+	require.NoError(t, err)
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msg), utils.FeltToBigInt(signature[0]), utils.FeltToBigInt(signature[1]), pubX, pubY))
+
+	// the keystore signer signs with the same key
+	r, s, err := account.NewKeystoreSigner(ks, pub.String()).Sign(context.Background(), msg)
+	require.NoError(t, err)
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msg), utils.FeltToBigInt(r), utils.FeltToBigInt(s), pubX, pubY))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = acnt.Sign(ctx, msg)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestVerify tests that Verify accepts the signatures of the memory signer, rejects a signature with a flipped
+// bit and returns an error for the malformed inputs.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestVerify(t *testing.T) {
+	_, pub, priv := account.GetRandomKeys()
+	signer, err := account.NewMemorySigner(priv)
+	require.NoError(t, err)
+	msg := utils.TestHexToFelt(t, "0x2a")
+	r, s, err := signer.Sign(context.Background(), msg)
+	require.NoError(t, err)
+
+	valid, err := account.Verify(msg, r, s, pub)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	flipped := utils.BigIntToFelt(new(big.Int).Xor(utils.FeltToBigInt(s), big.NewInt(1)))
+	valid, err = account.Verify(msg, r, flipped, pub)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	otherMsg := utils.TestHexToFelt(t, "0x2b")
+	valid, err = account.Verify(otherMsg, r, s, pub)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// a zero message hash is in the range of the message hashes, as for cairo-lang
+	valid, err = account.Verify(new(felt.Felt), r, s, pub)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	outOfRange := new(felt.Felt).Sub(new(felt.Felt).SetUint64(0), new(felt.Felt).SetUint64(1))
+	_, err = account.Verify(msg, outOfRange, s, pub)
+	require.Error(t, err)
+	_, err = account.Verify(msg, r, outOfRange, pub)
+	require.Error(t, err)
+	_, err = account.Verify(msg, new(felt.Felt), s, pub)
+	require.Error(t, err)
+	_, err = account.Verify(msg, r, s, nil)
+	require.Error(t, err)
+}
+
+// TestCallAndDecodeMOCK tests that CallAndDecode finds the called function in the ABI of the class at the block
+// by its selector and decodes the output of the call on the block into a struct.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestCallAndDecodeMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, utils.TestHexToFelt(t, "0x1234"), account.NewKeystoreSigner(account.NewMemKeystore(), ""), 2)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	contract := utils.TestHexToFelt(t, "0x5678")
+	abi := `[
+		{"type": "struct", "name": "core::integer::u256", "members": [
+			{"name": "low", "type": "core::integer::u128"},
+			{"name": "high", "type": "core::integer::u128"}
+		]},
+		{"type": "struct", "name": "example::Reserves", "members": [
+			{"name": "reserve0", "type": "core::integer::u256"},
+			{"name": "reserve1", "type": "core::integer::u256"},
+			{"name": "timestamp", "type": "core::integer::u64"}
+		]},
+		{"type": "interface", "name": "example::IPair", "items": [
+			{"type": "function", "name": "get_reserves", "inputs": [], "outputs": [{"type": "example::Reserves"}], "state_mutability": "view"}
+		]}
+	]`
+	call := rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("get_reserves"),
+	}
+	block := rpc.WithBlockNumber(42)
+	mockRpcProvider.EXPECT().ClassAt(ctx, block, contract).Return(&rpc.ContractClass{ABI: abi}, nil).Times(2)
+	mockRpcProvider.EXPECT().Call(ctx, call, block).Return([]*felt.Felt{
+		new(felt.Felt).SetUint64(100), new(felt.Felt).SetUint64(0),
+		new(felt.Felt).SetUint64(200), new(felt.Felt).SetUint64(0),
+		new(felt.Felt).SetUint64(1700000000),
+	}, nil)
+
+	var reserves struct {
+		Reserve0  big.Int
+		Reserve1  big.Int
+		Timestamp uint64
+	}
+	require.NoError(t, acnt.CallAndDecode(ctx, call, block, &reserves))
+	require.Equal(t, big.NewInt(100), &reserves.Reserve0)
+	require.Equal(t, big.NewInt(200), &reserves.Reserve1)
+	require.Equal(t, uint64(1700000000), reserves.Timestamp)
+
+	unknown := rpc.FunctionCall{ContractAddress: contract, EntryPointSelector: utils.GetSelectorFromNameFelt("skim")}
+	err = acnt.CallAndDecode(ctx, unknown, block, &reserves)
+	require.ErrorIs(t, err, contracts.ErrFunctionNotInABI)
+}
+
+// TestBalanceMOCK tests that Balance calls balanceOf on the fee token of the network of the account, decoding
+// the Uint256 balance and rejecting out of range limbs, and that the token of an unknown network is not resolved.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestBalanceMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ctx := context.Background()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	mockRpcProvider.EXPECT().ChainID(ctx).Return("SN_SEPOLIA", nil)
+	acnt, err := account.NewAccount(mockRpcProvider, accountAddress, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 2)
+	require.NoError(t, err)
+
+	pending := rpc.WithBlockTag(rpc.BlockTagPending)
+	balanceOf := utils.GetSelectorFromNameFelt("balanceOf")
+	mockRpcProvider.EXPECT().Call(ctx, rpc.FunctionCall{
+		ContractAddress:    account.STRKTokenAddress,
+		EntryPointSelector: balanceOf,
+		Calldata:           []*felt.Felt{accountAddress},
+	}, pending).Return([]*felt.Felt{new(felt.Felt).SetUint64(5), new(felt.Felt).SetUint64(1)}, nil)
+	balance, err := acnt.Balance(ctx, account.FeeTokenSTRK)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5)), balance)
+
+	mockRpcProvider.EXPECT().Call(ctx, rpc.FunctionCall{
+		ContractAddress:    account.ETHTokenAddress,
+		EntryPointSelector: balanceOf,
+		Calldata:           []*felt.Felt{accountAddress},
+	}, pending).Return([]*felt.Felt{new(felt.Felt).SetUint64(7), new(felt.Felt)}, nil)
+	balance, err = acnt.Balance(ctx, account.FeeTokenETH)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7), balance)
+
+	// a low limb of 2^128 is not a Uint256
+	mockRpcProvider.EXPECT().Call(ctx, rpc.FunctionCall{
+		ContractAddress:    account.ETHTokenAddress,
+		EntryPointSelector: balanceOf,
+		Calldata:           []*felt.Felt{accountAddress},
+	}, pending).Return([]*felt.Felt{utils.TestHexToFelt(t, "0x100000000000000000000000000000000"), new(felt.Felt)}, nil)
+	_, err = acnt.Balance(ctx, account.FeeTokenETH)
+	require.ErrorIs(t, err, utils.ErrInvalidUint256)
+
+	mockRpcProvider.EXPECT().ChainID(ctx).Return("SN_DEVNET", nil)
+	devnetAccount, err := account.NewAccount(mockRpcProvider, accountAddress, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 2)
+	require.NoError(t, err)
+	_, err = devnetAccount.Balance(ctx, account.FeeTokenSTRK)
+	require.ErrorIs(t, err, account.ErrUnknownNetwork)
+	require.ErrorContains(t, err, `"SN_DEVNET"`)
+}
+
+// TestValidateInvokeMOCK tests the local checks of ValidateInvoke, the revert reason of its simulation, and that
+// WithPreflight does not broadcast an invoke transaction whose simulation reverts, given as a value or a pointer.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestValidateInvokeMOCK(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ctx := context.Background()
+	accountAddress := utils.TestHexToFelt(t, "0x1234")
+	mockRpcProvider.EXPECT().ChainID(ctx).Return("SN_SEPOLIA", nil).Times(3)
+	acnt, err := account.NewAccount(mockRpcProvider, accountAddress, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 2)
+	require.NoError(t, err)
+
+	call := rpc.FunctionCall{
+		ContractAddress:    utils.TestHexToFelt(t, "0x5678"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(2)},
+	}
+	txn := rpc.InvokeTxnV3{
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: accountAddress,
+		Calldata:      account.FmtCallDataCairo2([]rpc.FunctionCall{call}),
+		Version:       rpc.TransactionV3,
+		Signature:     []*felt.Felt{new(felt.Felt).SetUint64(7), new(felt.Felt).SetUint64(8)},
+		Nonce:         new(felt.Felt).SetUint64(3),
+		ResourceBounds: rpc.ResourceBoundsMapping{
+			L1Gas: rpc.ResourceBounds{MaxAmount: "0x10", MaxPricePerUnit: "0x20"},
+			L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		},
+		Tip:           "0x0",
+		NonceDataMode: rpc.DAModeL1,
+		FeeMode:       rpc.DAModeL1,
+	}
+
+	unsigned := txn
+	unsigned.Signature = nil
+	require.ErrorIs(t, acnt.ValidateInvoke(ctx, unsigned), account.ErrMissingSignature)
+	noNonce := txn
+	noNonce.Nonce = nil
+	require.ErrorIs(t, acnt.ValidateInvoke(ctx, noNonce), account.ErrMissingNonce)
+	noBounds := txn
+	noBounds.ResourceBounds.L1Gas.MaxPricePerUnit = "0x0"
+	require.ErrorIs(t, acnt.ValidateInvoke(ctx, noBounds), account.ErrZeroResourceBounds)
+	noBounds.ResourceBounds.L2Gas = rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x20"}
+	require.ErrorIs(t, acnt.ValidateInvoke(ctx, noBounds), account.ErrZeroResourceBounds)
+	truncated := txn
+	truncated.Calldata = txn.Calldata[:len(txn.Calldata)-1]
+	require.ErrorIs(t, acnt.ValidateInvoke(ctx, truncated), account.ErrInconsistentCalldata)
+	extra := txn
+	extra.Calldata = append(append([]*felt.Felt{}, txn.Calldata...), new(felt.Felt))
+	require.ErrorIs(t, acnt.ValidateInvoke(ctx, extra), account.ErrInconsistentCalldata)
+
+	pending := rpc.WithBlockTag(rpc.BlockTagPending)
+	skipFeeCharge := []rpc.SimulationFlag{rpc.SKIP_FEE_CHARGE}
+	reverted := []rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{
+		Type:              rpc.TransactionType_Invoke,
+		ExecuteInvocation: rpc.ExecInvocation{RevertReason: "Error message: ERC20: insufficient balance"},
+	}}}
+	// from RPC 0.8 on, the L2 gas bound alone is enough
+	l2Bounded := txn
+	l2Bounded.ResourceBounds = rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x100000", MaxPricePerUnit: "0x20"},
+	}
+	mockRpcProvider.EXPECT().SimulateTransactions(ctx, pending, []rpc.Transaction{l2Bounded}, skipFeeCharge).
+		Return([]rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{Type: rpc.TransactionType_Invoke}}}, nil)
+	require.NoError(t, acnt.ValidateInvoke(ctx, l2Bounded))
+
+	mockRpcProvider.EXPECT().SimulateTransactions(ctx, pending, []rpc.Transaction{txn}, skipFeeCharge).Return(reverted, nil)
+	err = acnt.ValidateInvoke(ctx, txn)
+	var revertErr *account.PreflightRevertError
+	require.ErrorAs(t, err, &revertErr)
+	require.Equal(t, "Error message: ERC20: insufficient balance", revertErr.RevertReason)
+	require.ErrorContains(t, err, "insufficient balance")
+
+	cairo0Account, err := account.NewAccount(mockRpcProvider, accountAddress, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 0)
+	require.NoError(t, err)
+	cairo0Txn := txn
+	cairo0Txn.Calldata = account.FmtCallDataCairo0([]rpc.FunctionCall{call, call})
+	mockRpcProvider.EXPECT().SimulateTransactions(ctx, pending, []rpc.Transaction{cairo0Txn}, skipFeeCharge).
+		Return([]rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{Type: rpc.TransactionType_Invoke}}}, nil)
+	require.NoError(t, cairo0Account.ValidateInvoke(ctx, cairo0Txn))
+	require.ErrorIs(t, cairo0Account.ValidateInvoke(ctx, txn), account.ErrInconsistentCalldata)
+
+	// the provider is not expected to broadcast the reverting transaction
+	preflightAccount, err := account.NewAccount(mockRpcProvider, accountAddress, account.NewKeystoreSigner(account.NewMemKeystore(), ""), 2, account.WithPreflight())
+	require.NoError(t, err)
+	mockRpcProvider.EXPECT().SimulateTransactions(ctx, pending, []rpc.Transaction{txn}, skipFeeCharge).Return(reverted, nil)
+	_, err = preflightAccount.AddInvokeTransaction(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: txn})
+	require.ErrorAs(t, err, &revertErr)
+	// a pointer to the transaction is validated as well
+	mockRpcProvider.EXPECT().SimulateTransactions(ctx, pending, []rpc.Transaction{txn}, skipFeeCharge).Return(reverted, nil)
+	_, err = preflightAccount.AddInvokeTransaction(ctx, &rpc.BroadcastInvokev3Txn{InvokeTxnV3: txn})
+	require.ErrorAs(t, err, &revertErr)
+
+	mockRpcProvider.EXPECT().SimulateTransactions(ctx, pending, []rpc.Transaction{txn}, skipFeeCharge).
+		Return([]rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{Type: rpc.TransactionType_Invoke}}}, nil)
+	mockRpcProvider.EXPECT().AddInvokeTransaction(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: txn}).
+		Return(&rpc.AddInvokeTransactionResponse{TransactionHash: utils.TestHexToFelt(t, "0x99")}, nil)
+	resp, err := preflightAccount.AddInvokeTransaction(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: txn})
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x99"), resp.TransactionHash)
+}
@@ -0,0 +1,53 @@
+package account
+
+import (
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+)
+
+// Class hashes of the account implementations deployed by the Precompute*Address helpers.
+var (
+	// Argent X account v0.3.1
+	ArgentClassHash, _ = new(felt.Felt).SetString("0x29927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b")
+	// Braavos base account, upgraded to the Braavos account implementation on deployment
+	BraavosBaseClassHash, _ = new(felt.Felt).SetString("0x13bfe114fb1cf405bfc3a7f8dbe2d91db146c17521d40dcf57e16d6b59fa8e6")
+	// OpenZeppelin account v0.8.1
+	OZClassHash, _ = new(felt.Felt).SetString("0x61dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f")
+)
+
+// PrecomputeArgentAddress calculates the address of an Argent X account deployed without deployer,
+// whose constructor takes the owner (signer) and guardian public keys.
+//
+// Parameters:
+// - signer: the public key of the owner of the account
+// - guardian: the public key of the guardian, zero for an account without guardian
+// - salt: the salt of the deployment, Argent X uses the signer public key
+// Returns:
+// - *felt.Felt: the precomputed address as a *felt.Felt
+func PrecomputeArgentAddress(signer, guardian *felt.Felt, salt *felt.Felt) *felt.Felt {
+	return contracts.PrecomputeAccountAddress(salt, ArgentClassHash, []*felt.Felt{signer, guardian})
+}
+
+// PrecomputeBraavosAddress calculates the address of a Braavos account deployed without deployer.
+// Braavos accounts are deployed with the Braavos base account class, whose constructor takes the Stark public key.
+//
+// Parameters:
+// - publicKey: the Stark public key of the account
+// - salt: the salt of the deployment, Braavos uses the public key
+// Returns:
+// - *felt.Felt: the precomputed address as a *felt.Felt
+func PrecomputeBraavosAddress(publicKey, salt *felt.Felt) *felt.Felt {
+	return contracts.PrecomputeAccountAddress(salt, BraavosBaseClassHash, []*felt.Felt{publicKey})
+}
+
+// PrecomputeOZAddress calculates the address of an OpenZeppelin account deployed without deployer,
+// whose constructor takes the public key.
+//
+// Parameters:
+// - publicKey: the public key of the account
+// - salt: the salt of the deployment
+// Returns:
+// - *felt.Felt: the precomputed address as a *felt.Felt
+func PrecomputeOZAddress(publicKey, salt *felt.Felt) *felt.Felt {
+	return contracts.PrecomputeAccountAddress(salt, OZClassHash, []*felt.Felt{publicKey})
+}
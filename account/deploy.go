@@ -0,0 +1,78 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrDeployAddressMismatch = errors.New("precomputed address does not match the account address")
+	ErrAccountNotFunded      = errors.New("account not funded")
+)
+
+// BuildAndSendDeployAccount builds, signs and sends the v3 deploy account transaction deploying the account
+// at its own address. The address precomputed from the salt, class hash and constructor calldata must be
+// the address of the account, and the account must hold STRK at that address to pay for its deployment:
+// the fee of a deploy account transaction is charged to the deployed account itself.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - salt: The salt of the deployment
+// - classHash: The class hash of the account contract
+// - constructorCalldata: The calldata of the constructor of the account contract, e.g. its public key
+// - resourceBounds: The resource bounds of the transaction
+// Returns:
+// - *rpc.AddDeployAccountTransactionResponse: the hash of the transaction and the address of the account
+// - error: ErrDeployAddressMismatch if the precomputed address is not the address of the account,
+// ErrAccountNotFunded if the account has no STRK to pay for its deployment, or any other error
+func (account *Account) BuildAndSendDeployAccount(ctx context.Context, salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt, resourceBounds rpc.ResourceBoundsMapping) (*rpc.AddDeployAccountTransactionResponse, error) {
+	address := contracts.PrecomputeAccountAddress(salt, classHash, constructorCalldata)
+	if !address.Equal(account.AccountAddress) {
+		return nil, fmt.Errorf("%w: precomputed %s, account %s", ErrDeployAddressMismatch, address, account.AccountAddress)
+	}
+
+	balance, err := account.provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    STRKTokenAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("balance_of"),
+		Calldata:           []*felt.Felt{address},
+	}, rpc.WithBlockTag(rpc.BlockTagPending))
+	if err != nil {
+		return nil, err
+	}
+	funded := false
+	for _, limb := range balance {
+		funded = funded || !limb.IsZero()
+	}
+	if !funded {
+		return nil, fmt.Errorf("%w: transfer STRK to %s before deploying it, the account pays the fee of its own deployment", ErrAccountNotFunded, address)
+	}
+
+	tx := rpc.DeployAccountTxnV3{
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV3,
+		Nonce:               new(felt.Felt),
+		ContractAddressSalt: salt,
+		ConstructorCalldata: constructorCalldata,
+		ClassHash:           classHash,
+		ResourceBounds:      resourceBounds,
+		Tip:                 "0x0",
+		PayMasterData:       []*felt.Felt{},
+		NonceDataMode:       rpc.DAModeL1,
+		FeeMode:             rpc.DAModeL1,
+	}
+	txHash, err := account.TransactionHashDeployAccount(tx, address)
+	if err != nil {
+		return nil, err
+	}
+	tx.Signature, err = account.Sign(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return account.AddDeployAccountTransaction(ctx, rpc.BroadcastDeployAccountTxnV3{DeployAccountTxnV3: tx})
+}
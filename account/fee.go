@@ -0,0 +1,171 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Addresses of the fee tokens on the Starknet mainnet and Sepolia.
+var (
+	// ETH, paying the fees of the v0-2 transactions, in wei
+	ETHTokenAddress, _ = new(felt.Felt).SetString("0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	// STRK, paying the fees of the v3 transactions, in fri
+	STRKTokenAddress, _ = new(felt.Felt).SetString("0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d")
+)
+
+// feeTokenDecimals is the number of decimals of both fee tokens (1 ETH is 10^18 wei, 1 STRK is 10^18 fri)
+const feeTokenDecimals = 18
+
+// FeeTokenAddress returns the address of the token paying a fee in the given unit.
+//
+// Parameters:
+// - unit: the unit of the fee, rpc.UnitWei or rpc.UnitStrk
+// Returns:
+// - *felt.Felt: the address of the fee token
+// - error: an error if the unit is unknown
+func FeeTokenAddress(unit rpc.FeePaymentUnit) (*felt.Felt, error) {
+	switch unit {
+	case rpc.UnitWei:
+		return ETHTokenAddress, nil
+	case rpc.UnitStrk:
+		return STRKTokenAddress, nil
+	}
+	return nil, fmt.Errorf("unknown fee unit %q", unit)
+}
+
+// FeeToken is a token paying the fees of the transactions, whose address is resolved from the chain ID of the
+// account by Balance.
+type FeeToken int
+
+const (
+	FeeTokenETH FeeToken = iota
+	FeeTokenSTRK
+)
+
+// String returns the symbol of the fee token.
+func (token FeeToken) String() string {
+	switch token {
+	case FeeTokenETH:
+		return "ETH"
+	case FeeTokenSTRK:
+		return "STRK"
+	}
+	return fmt.Sprintf("FeeToken(%d)", int(token))
+}
+
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// Balance returns the balance of the account in a fee token, on the pending block: the token address is resolved
+// from the chain ID of the account, cached when the account was created, and the Uint256 returned by its
+// balanceOf entry point is decoded.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - token: The fee token, FeeTokenETH or FeeTokenSTRK
+// Returns:
+// - *big.Int: the balance in the smallest unit of the token, wei or fri
+// - error: ErrUnknownNetwork if the chain ID is not the one of a public Starknet network, or an error if the
+// token is unknown, the call fails or its output is not a Uint256
+func (account *Account) Balance(ctx context.Context, token FeeToken) (*big.Int, error) {
+	address, err := account.feeTokenAddress(token)
+	if err != nil {
+		return nil, err
+	}
+	output, err := account.provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("balanceOf"),
+		Calldata:           []*felt.Felt{account.AccountAddress},
+	}, rpc.WithBlockTag(rpc.BlockTagPending))
+	if err != nil {
+		return nil, err
+	}
+	if len(output) != 2 {
+		return nil, fmt.Errorf("balanceOf of %s returned %d felts, expected a Uint256", token, len(output))
+	}
+	balance, err := utils.FeltsToUint256(output[0], output[1])
+	if err != nil {
+		return nil, fmt.Errorf("balanceOf of %s: %w", token, err)
+	}
+	return balance, nil
+}
+
+// feeTokenAddress returns the address of the fee token on the network of the account.
+func (account *Account) feeTokenAddress(token FeeToken) (*felt.Felt, error) {
+	known := false
+	for _, id := range []rpc.ChainID{rpc.ChainIDMainnet, rpc.ChainIDSepolia} {
+		known = known || (account.ChainId != nil && account.ChainId.Equal(id.Felt()))
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: chain ID %q, the address of %s cannot be resolved", ErrUnknownNetwork, utils.FeltToStr(account.ChainId), token)
+	}
+	switch token {
+	case FeeTokenETH:
+		return ETHTokenAddress, nil
+	case FeeTokenSTRK:
+		return STRKTokenAddress, nil
+	}
+	return nil, fmt.Errorf("unknown fee token %s", token)
+}
+
+// EstimateFeeIn estimates the fee of an invoke transaction executing the calls, and converts it into a display
+// currency (e.g. USD) with the price given by priceFn for the fee token. The price oracle is left to the caller:
+// priceFn receives the address of the fee token and returns the price of one token, i.e. of 10^18 wei or fri.
+// The fee is estimated on the pending block for the invoke transaction the account would send (see
+// BuildInvokeTxn): an invoke v1 transaction paid in ETH before the spec version 0.6.0, and an invoke v3
+// transaction paid in STRK from 0.6.0 on, the fee token being the one of the unit of the estimate.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - calls: The calls executed by the transaction
+// - priceFn: The price of one fee token in the display currency
+// Returns:
+// - *felt.Felt: the estimated fee in the smallest unit of the fee token
+// - float64: the estimated fee in the display currency
+// - error: an error if the estimation or the price lookup fails
+func (account *Account) EstimateFeeIn(ctx context.Context, calls []rpc.FunctionCall, priceFn func(token *felt.Felt) (float64, error)) (*felt.Felt, float64, error) {
+	nonce, err := account.provider.Nonce(ctx, rpc.WithBlockTag(rpc.BlockTagPending), account.AccountAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+	// the estimate does not depend on the fee the transaction may pay
+	zeroBound := rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}
+	txn, err := account.BuildInvokeTxn(ctx, calls, nonce, new(felt.Felt), rpc.ResourceBoundsMapping{L1Gas: zeroBound, L2Gas: zeroBound})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	estimates, err := account.provider.EstimateFee(ctx, []rpc.BroadcastTxn{txn}, nil, rpc.WithBlockTag(rpc.BlockTagPending))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(estimates) != 1 || estimates[0].OverallFee == nil {
+		return nil, 0, fmt.Errorf("expected one fee estimate, got %d", len(estimates))
+	}
+	estimate := estimates[0]
+	unit := estimate.FeeUnit
+	if unit == "" {
+		unit = rpc.UnitWei
+		if _, v3 := txn.(rpc.BroadcastInvokev3Txn); v3 {
+			unit = rpc.UnitStrk
+		}
+	}
+	token, err := FeeTokenAddress(unit)
+	if err != nil {
+		return nil, 0, err
+	}
+	price, err := priceFn(token)
+	if err != nil {
+		return nil, 0, fmt.Errorf("price of the fee token %s: %w", token, err)
+	}
+
+	amount := new(big.Float).SetInt(estimate.OverallFee.BigInt(new(big.Int)))
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(feeTokenDecimals), nil))
+	value, _ := amount.Quo(amount, scale).Mul(amount, big.NewFloat(price)).Float64()
+	return estimate.OverallFee, value, nil
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/keystore"
+	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
@@ -156,3 +158,36 @@ func GetRandomKeys() (*MemKeystore, *felt.Felt, *felt.Felt) {
 
 	return ks, pubFelt, privFelt
 }
+
+// FromKeystore creates an account from a JSON keystore file created with keystore.Encrypt, holding the private
+// key of the account encrypted with a passphrase and the address of the account. The account signs with a
+// MemorySigner of the decrypted key.
+//
+// Parameters:
+// - provider: the provider of the account
+// - path: the path of the keystore file
+// - passphrase: the passphrase the key was encrypted with
+// - cairoVersion: the Cairo version of the account contract
+// - options: the AccountOption applied to the account, as for NewAccount
+// Returns:
+// - *Account: the account
+// - error: keystore.ErrDecryptFailed if the passphrase is wrong, or an error if the file cannot be read
+func FromKeystore(provider rpc.RpcProvider, path string, passphrase string, cairoVersion int, options ...AccountOption) (*Account, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	address, err := keystore.Address(data)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := keystore.Decrypt(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := NewMemorySigner(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewAccount(provider, address, signer, cairoVersion, options...)
+}
@@ -0,0 +1,114 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrEmptyMulticall = errors.New("multicall has no calls")
+
+// Multicall builds an invoke v3 transaction executing several calls at once from the account, in the order
+// they are added. The calls are encoded in the __execute__ calldata of the Cairo version of the account (see
+// FmtCalldata), e.g. for the Cairo 1 accounts the number of calls, then for each call its contract address,
+// entry point selector, calldata length and calldata.
+type Multicall struct {
+	account *Account
+	calls   []rpc.FunctionCall
+}
+
+// NewMulticall returns an empty Multicall sent from the account.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Multicall: the multicall builder
+func (account *Account) NewMulticall() *Multicall {
+	return &Multicall{account: account}
+}
+
+// Add appends a call to the multicall.
+//
+// Parameters:
+// - call: the call to execute, its calldata may be empty
+// Returns:
+// - *Multicall: the multicall, to chain the calls to Add
+func (m *Multicall) Add(call rpc.FunctionCall) *Multicall {
+	m.calls = append(m.calls, call)
+	return m
+}
+
+// Calls returns the calls of the multicall.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []rpc.FunctionCall: the calls, in the order they were added
+func (m *Multicall) Calls() []rpc.FunctionCall {
+	return m.calls
+}
+
+// Calldata returns the __execute__ calldata of the calls, encoded for the Cairo version of the account.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the calldata of the invoke transaction
+// - error: an error if the Cairo version of the account is not supported
+func (m *Multicall) Calldata() ([]*felt.Felt, error) {
+	return m.account.FmtCalldata(m.calls)
+}
+
+// Build builds and signs the invoke v3 transaction executing the calls.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - nonce: The nonce of the account
+// - resourceBounds: The resource bounds of the transaction
+// Returns:
+// - rpc.BroadcastInvokev3Txn: the signed transaction
+// - error: ErrEmptyMulticall if no call was added, or an error if the calls cannot be encoded or the signing
+// fails
+func (m *Multicall) Build(ctx context.Context, nonce *felt.Felt, resourceBounds rpc.ResourceBoundsMapping) (rpc.BroadcastInvokev3Txn, error) {
+	if len(m.calls) == 0 {
+		return rpc.BroadcastInvokev3Txn{}, ErrEmptyMulticall
+	}
+	calldata, err := m.Calldata()
+	if err != nil {
+		return rpc.BroadcastInvokev3Txn{}, err
+	}
+	return m.account.buildInvokeTxnV3(ctx, calldata, nonce, resourceBounds)
+}
+
+// Invoke builds, signs and sends the invoke v3 transaction executing the calls, with the nonce given
+// by NextNonce.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - resourceBounds: The resource bounds of the transaction
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the hash of the sent transaction
+// - error: ErrEmptyMulticall if no call was added, or an error if the transaction cannot be built or sent
+func (m *Multicall) Invoke(ctx context.Context, resourceBounds rpc.ResourceBoundsMapping) (*rpc.AddInvokeTransactionResponse, error) {
+	if len(m.calls) == 0 {
+		return nil, ErrEmptyMulticall
+	}
+	nonce, err := m.account.NextNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := m.Build(ctx, nonce, resourceBounds)
+	if err != nil {
+		m.account.releaseNonce(nonce, err)
+		return nil, err
+	}
+	return m.account.AddInvokeTransaction(ctx, tx)
+}
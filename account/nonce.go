@@ -0,0 +1,199 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// nonceCache keeps track of the next nonce of an account so that transactions
+// can be built concurrently without querying the node for every one of them.
+type nonceCache struct {
+	mu      sync.Mutex
+	enabled bool
+	// next is the next nonce to hand out, nil when it has to be fetched from the node
+	next *felt.Felt
+	// base is the nonce fetched from the node that next counts from
+	base *felt.Felt
+	// fetching is closed once the nonce being fetched from the node is cached, nil when no fetch is in flight
+	fetching chan struct{}
+	// generation is incremented when the cache is dropped, so that a nonce fetched meanwhile is not cached
+	generation uint64
+}
+
+// drop clears the cached nonce, account.nonces.mu must be held.
+func (cache *nonceCache) drop() {
+	cache.next = nil
+	cache.base = nil
+	cache.generation++
+}
+
+// EnableNonceCache turns on the in-memory nonce cache of the account.
+// Once enabled, NextNonce reserves nonces locally, for BuildInvokeTxn given a nil nonce and for
+// Multicall.Invoke, and AddInvokeTransaction and AddDeclareTransaction release the nonce of a
+// transaction that failed to be broadcast. A nonce passed explicitly is not reserved.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (account *Account) EnableNonceCache() {
+	account.nonces.mu.Lock()
+	defer account.nonces.mu.Unlock()
+	account.nonces.enabled = true
+	account.nonces.drop()
+}
+
+// InvalidateNonceCache drops the cached nonce so that the next call to NextNonce
+// fetches it again from the pending block, e.g. after a transaction got rejected.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (account *Account) InvalidateNonceCache() {
+	account.nonces.mu.Lock()
+	defer account.nonces.mu.Unlock()
+	account.nonces.drop()
+}
+
+// NextNonce returns the nonce to use for the next transaction of the account.
+// When the nonce cache is enabled the nonce is reserved and the cache is incremented,
+// so concurrent callers always get distinct nonces. Otherwise it is the nonce at the pending block.
+// The nonce is fetched without holding the cache, and the concurrent callers wait for the same fetch.
+//
+// Parameters:
+// - ctx: the context.Context for the function call
+// Returns:
+// - *felt.Felt: the nonce to use
+// - error: an error if the nonce could not be fetched from the node
+func (account *Account) NextNonce(ctx context.Context) (*felt.Felt, error) {
+	cache := &account.nonces
+	for {
+		cache.mu.Lock()
+		if !cache.enabled {
+			cache.mu.Unlock()
+			return account.provider.Nonce(ctx, rpc.WithBlockTag("pending"), account.AccountAddress)
+		}
+		if cache.next != nil {
+			nonce := new(felt.Felt).Set(cache.next)
+			cache.next = new(felt.Felt).Add(nonce, new(felt.Felt).SetUint64(1))
+			cache.mu.Unlock()
+			return nonce, nil
+		}
+		if fetching := cache.fetching; fetching != nil {
+			cache.mu.Unlock()
+			select {
+			case <-fetching:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		fetching := make(chan struct{})
+		cache.fetching = fetching
+		generation := cache.generation
+		cache.mu.Unlock()
+
+		nonce, err := account.provider.Nonce(ctx, rpc.WithBlockTag("pending"), account.AccountAddress)
+
+		cache.mu.Lock()
+		cache.fetching = nil
+		close(fetching)
+		// a nonce fetched before the cache was dropped may be stale, it is fetched again
+		if err == nil && cache.generation == generation && cache.next == nil {
+			cache.next = nonce
+			cache.base = nonce
+		}
+		cache.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// releaseNonce gives back the nonce of a transaction that failed to be broadcast.
+// If it is the latest nonce handed out since the nonce was fetched the cache is rolled back. A nonce
+// handed out before the nonce was fetched is ignored, the fetched nonce already accounts for it. Otherwise
+// later nonces are already in use and the cache is invalidated to be refetched from the pending block.
+// A nonce error from the node always invalidates the cache.
+//
+// Parameters:
+// - nonce: the nonce of the failed transaction
+// - err: the error returned by the node
+// Returns:
+//
+//	none
+func (account *Account) releaseNonce(nonce *felt.Felt, err error) {
+	account.nonces.mu.Lock()
+	defer account.nonces.mu.Unlock()
+
+	cache := &account.nonces
+	if !cache.enabled || cache.next == nil || nonce == nil {
+		return
+	}
+	if errors.Is(err, rpc.ErrInvalidTransactionNonce) {
+		cache.drop()
+		return
+	}
+	if nonce.Cmp(cache.base) < 0 {
+		return
+	}
+	if new(felt.Felt).Add(nonce, new(felt.Felt).SetUint64(1)).Equal(cache.next) {
+		cache.next = nonce
+		return
+	}
+	cache.drop()
+}
+
+// txnNonce returns the nonce of a broadcast transaction, given as a value or a pointer, nil for transaction
+// types without a nonce.
+//
+// Parameters:
+// - txn: the broadcast transaction
+// Returns:
+// - *felt.Felt: the nonce of the transaction
+func txnNonce(txn interface{}) *felt.Felt {
+	switch tx := txn.(type) {
+	case rpc.BroadcastInvokev1Txn:
+		return tx.Nonce
+	case *rpc.BroadcastInvokev1Txn:
+		if tx != nil {
+			return tx.Nonce
+		}
+	case rpc.BroadcastInvokev3Txn:
+		return tx.Nonce
+	case *rpc.BroadcastInvokev3Txn:
+		if tx != nil {
+			return tx.Nonce
+		}
+	case rpc.BroadcastDeclareTxnV1:
+		return tx.Nonce
+	case *rpc.BroadcastDeclareTxnV1:
+		if tx != nil {
+			return tx.Nonce
+		}
+	case rpc.BroadcastDeclareTxnV2:
+		return tx.Nonce
+	case *rpc.BroadcastDeclareTxnV2:
+		if tx != nil {
+			return tx.Nonce
+		}
+	case rpc.BroadcastDeclareTxnV3:
+		return tx.Nonce
+	case *rpc.BroadcastDeclareTxnV3:
+		if tx != nil {
+			return tx.Nonce
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrMissingSignature     = errors.New("missing signature")
+	ErrMissingNonce         = errors.New("missing nonce")
+	ErrZeroResourceBounds   = errors.New("zero resource bounds")
+	ErrInconsistentCalldata = errors.New("inconsistent calldata")
+)
+
+// PreflightRevertError reports an invoke transaction whose simulation by ValidateInvoke reverted.
+type PreflightRevertError struct {
+	// The revert reason of the simulated execution
+	RevertReason string
+}
+
+// Error returns the revert reason of the simulation.
+func (e *PreflightRevertError) Error() string {
+	return "simulated transaction reverted: " + e.RevertReason
+}
+
+// WithPreflight makes AddInvokeTransaction validate the invoke v3 transactions, as values or pointers, with
+// ValidateInvoke before broadcasting them, so that a transaction the sequencer would reject or revert is not sent. The nonce of a
+// transaction failing the validation is released as for a failed broadcast.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - AccountOption: the option to pass to NewAccount
+func WithPreflight() AccountOption {
+	return func(account *Account) {
+		account.preflight = true
+	}
+}
+
+// ValidateInvoke checks an invoke v3 transaction before it is broadcast. The transaction must be signed, have
+// a nonce and at least one non-zero resource bound (L1 gas, or from RPC 0.8 on L2 gas), and its
+// calldata must be the calls encoded for the Cairo version of the account (see FmtCalldata). The transaction is then simulated on the pending block with SKIP_FEE_CHARGE, so
+// that a transaction reverting in its execution is caught without paying a fee.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - txn: The signed invoke v3 transaction
+// Returns:
+// - error: ErrMissingSignature, ErrMissingNonce, ErrZeroResourceBounds or ErrInconsistentCalldata for the
+// malformed transactions, a *PreflightRevertError holding the revert reason of the simulation, or the error of
+// the simulation
+func (account *Account) ValidateInvoke(ctx context.Context, txn rpc.InvokeTxnV3) error {
+	switch {
+	case len(txn.Signature) == 0:
+		return ErrMissingSignature
+	case txn.Nonce == nil:
+		return ErrMissingNonce
+	case !hasResourceBound(txn.ResourceBounds):
+		return fmt.Errorf("%w: l1_gas max_amount %q, max_price_per_unit %q, l2_gas max_amount %q, max_price_per_unit %q",
+			ErrZeroResourceBounds, txn.ResourceBounds.L1Gas.MaxAmount, txn.ResourceBounds.L1Gas.MaxPricePerUnit,
+			txn.ResourceBounds.L2Gas.MaxAmount, txn.ResourceBounds.L2Gas.MaxPricePerUnit)
+	}
+	if err := checkCalldata(txn.Calldata, account.CairoVersion); err != nil {
+		return err
+	}
+
+	simulated, err := account.provider.SimulateTransactions(ctx, rpc.WithBlockTag(rpc.BlockTagPending), []rpc.Transaction{txn}, []rpc.SimulationFlag{rpc.SKIP_FEE_CHARGE})
+	if err != nil {
+		return err
+	}
+	for _, result := range simulated {
+		if reason, reverted := simulatedRevert(result.TxnTrace); reverted {
+			return &PreflightRevertError{RevertReason: reason}
+		}
+	}
+	return nil
+}
+
+// simulatedRevert returns the revert reason of a simulated invoke transaction.
+//
+// Parameters:
+// - trace: the trace of the simulated transaction
+// Returns:
+// - string: the revert reason
+// - bool: true if the execution reverted
+func simulatedRevert(trace rpc.TxnTrace) (string, bool) {
+	switch trace := trace.(type) {
+	case rpc.InvokeTxnTrace:
+		return trace.RevertReason()
+	case *rpc.InvokeTxnTrace:
+		return trace.RevertReason()
+	}
+	return "", false
+}
+
+// broadcastInvokeV3 returns the invoke v3 transaction of a broadcast invoke transaction, given as a value or
+// a pointer.
+//
+// Parameters:
+// - txn: the broadcast invoke transaction
+// Returns:
+// - rpc.InvokeTxnV3: the invoke v3 transaction
+// - bool: true if txn is an invoke v3 transaction
+func broadcastInvokeV3(txn rpc.BroadcastInvokeTxnType) (rpc.InvokeTxnV3, bool) {
+	switch tx := txn.(type) {
+	case rpc.BroadcastInvokev3Txn:
+		return tx.InvokeTxnV3, true
+	case *rpc.BroadcastInvokev3Txn:
+		if tx != nil {
+			return tx.InvokeTxnV3, true
+		}
+	}
+	return rpc.InvokeTxnV3{}, false
+}
+
+// hasResourceBound reports whether one of the resource bounds has a non-zero amount and price.
+func hasResourceBound(bounds rpc.ResourceBoundsMapping) bool {
+	nonZero := func(bound rpc.ResourceBounds) bool {
+		return !isZeroAmount(string(bound.MaxAmount)) && !isZeroAmount(string(bound.MaxPricePerUnit))
+	}
+	return nonZero(bounds.L1Gas) || nonZero(bounds.L2Gas)
+}
+
+// isZeroAmount reports whether a hex amount of the resource bounds is zero or missing.
+func isZeroAmount(amount string) bool {
+	value, ok := new(big.Int).SetString(amount, 0)
+	return !ok || value.Sign() == 0
+}
+
+// checkCalldata checks that the calldata of an invoke transaction is a list of calls encoded as by
+// FmtCallDataCairo0 or FmtCallDataCairo2, its length matching the number of calls and their calldata lengths.
+//
+// Parameters:
+// - calldata: the calldata of the transaction
+// - cairoVersion: the Cairo version of the account, 0 for the Cairo 0 encoding
+// Returns:
+// - error: ErrInconsistentCalldata describing the first inconsistency, nil for a consistent calldata
+func checkCalldata(calldata []*felt.Felt, cairoVersion int) error {
+	if len(calldata) == 0 {
+		return fmt.Errorf("%w: missing the number of calls", ErrInconsistentCalldata)
+	}
+	calls, ok := calldataLength(calldata[0])
+	if !ok {
+		return fmt.Errorf("%w: invalid number of calls %s", ErrInconsistentCalldata, calldata[0])
+	}
+
+	if cairoVersion == 0 {
+		// the call array of (to, selector, data_offset, data_len), then the length and felts of the calldata
+		end := 1 + 4*calls
+		if end >= uint64(len(calldata)) {
+			return fmt.Errorf("%w: %d calls in %d felts", ErrInconsistentCalldata, calls, len(calldata))
+		}
+		offset := uint64(0)
+		for i := uint64(0); i < calls; i++ {
+			dataOffset, okOffset := calldataLength(calldata[1+4*i+2])
+			dataLen, okLen := calldataLength(calldata[1+4*i+3])
+			if !okOffset || !okLen || dataOffset != offset {
+				return fmt.Errorf("%w: invalid data offset or length of call %d", ErrInconsistentCalldata, i)
+			}
+			offset += dataLen
+		}
+		total, ok := calldataLength(calldata[end])
+		if !ok || total != offset || end+1+total != uint64(len(calldata)) {
+			return fmt.Errorf("%w: %d felts of call data for calls of %d felts", ErrInconsistentCalldata, uint64(len(calldata))-end-1, offset)
+		}
+		return nil
+	}
+
+	// each call as to, selector, data_len and its data
+	next := uint64(1)
+	for i := uint64(0); i < calls; i++ {
+		if next+3 > uint64(len(calldata)) {
+			return fmt.Errorf("%w: call %d truncated", ErrInconsistentCalldata, i)
+		}
+		dataLen, ok := calldataLength(calldata[next+2])
+		if !ok || next+3+dataLen > uint64(len(calldata)) {
+			return fmt.Errorf("%w: calldata of call %d truncated", ErrInconsistentCalldata, i)
+		}
+		next += 3 + dataLen
+	}
+	if next != uint64(len(calldata)) {
+		return fmt.Errorf("%w: %d unexpected felts after %d calls", ErrInconsistentCalldata, uint64(len(calldata))-next, calls)
+	}
+	return nil
+}
+
+// calldataLength returns a length or count of the calldata, false if the felt is nil or too large to be one.
+func calldataLength(f *felt.Felt) (uint64, bool) {
+	if f == nil {
+		return 0, false
+	}
+	value := utils.FeltToBigInt(f)
+	if !value.IsUint64() || value.Uint64() > 1<<32 {
+		return 0, false
+	}
+	return value.Uint64(), true
+}
@@ -0,0 +1,265 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var ErrInvalidSignatureLayout = errors.New("signature does not match the account signature layout")
+
+// SignerType is the kind of key that produced a signature.
+type SignerType int
+
+const (
+	// SignerTypeStark is a key on the Stark curve, as held by the account's Signer
+	SignerTypeStark SignerType = iota
+	// SignerTypeSecp256r1 is a hardware key on the secp256r1 curve (e.g. a Braavos hardware signer)
+	SignerTypeSecp256r1
+)
+
+// SignerSignature is the signature produced by a single signer of an account.
+type SignerSignature struct {
+	Type SignerType
+	// SignerID identifies a hardware signer in the account, unused for Stark signers
+	SignerID *felt.Felt
+	R        *big.Int
+	S        *big.Int
+}
+
+// SignatureLayout packs the signatures of the account's signers into the signature
+// array expected by the account contract, and decodes such an array back.
+type SignatureLayout interface {
+	Pack(signatures ...SignerSignature) ([]*felt.Felt, error)
+	Unpack(signature []*felt.Felt) ([]SignerSignature, error)
+}
+
+var (
+	_ SignatureLayout = StandardSignatureLayout{}
+	_ SignatureLayout = BraavosSignatureLayout{}
+)
+
+// StandardSignatureLayout is the [r, s] layout of accounts with a single Stark signer (OpenZeppelin, Argent without guardian...).
+type StandardSignatureLayout struct{}
+
+// Pack returns the [r, s] signature array.
+//
+// Parameters:
+// - signatures: exactly one Stark signature
+// Returns:
+// - []*felt.Felt: the signature array
+// - error: ErrInvalidSignatureLayout if the signatures are not a single Stark signature
+func (StandardSignatureLayout) Pack(signatures ...SignerSignature) ([]*felt.Felt, error) {
+	if len(signatures) != 1 || signatures[0].Type != SignerTypeStark {
+		return nil, fmt.Errorf("%w: expected a single stark signature", ErrInvalidSignatureLayout)
+	}
+	return []*felt.Felt{utils.BigIntToFelt(signatures[0].R), utils.BigIntToFelt(signatures[0].S)}, nil
+}
+
+// Unpack decodes a [r, s] signature array.
+//
+// Parameters:
+// - signature: the signature array
+// Returns:
+// - []SignerSignature: the Stark signature
+// - error: ErrInvalidSignatureLayout if the array does not hold exactly two elements
+func (StandardSignatureLayout) Unpack(signature []*felt.Felt) ([]SignerSignature, error) {
+	if len(signature) != 2 {
+		return nil, fmt.Errorf("%w: expected 2 elements, got %d", ErrInvalidSignatureLayout, len(signature))
+	}
+	return []SignerSignature{{
+		Type: SignerTypeStark,
+		R:    utils.FeltToBigInt(signature[0]),
+		S:    utils.FeltToBigInt(signature[1]),
+	}}, nil
+}
+
+// BraavosSignatureLayout is the layout of Braavos accounts. The Stark signature, when present,
+// comes first as [r, s], followed by each hardware signature as
+// [signer_id, r.low, r.high, s.low, s.high] since secp256r1 scalars are Uint256 values.
+type BraavosSignatureLayout struct{}
+
+const braavosHardwareSignatureLen = 5
+
+// Pack returns the Braavos signature array.
+//
+// Parameters:
+// - signatures: at most one Stark signature and any number of secp256r1 signatures
+// Returns:
+// - []*felt.Felt: the signature array, Stark signature first
+// - error: ErrInvalidSignatureLayout if no signature, several Stark signatures, or a hardware signature without signer id or a component exceeding 256 bits are given
+func (BraavosSignatureLayout) Pack(signatures ...SignerSignature) ([]*felt.Felt, error) {
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("%w: no signature", ErrInvalidSignatureLayout)
+	}
+	var stark []*felt.Felt
+	var hardware []*felt.Felt
+	for _, sig := range signatures {
+		switch sig.Type {
+		case SignerTypeStark:
+			if stark != nil {
+				return nil, fmt.Errorf("%w: more than one stark signature", ErrInvalidSignatureLayout)
+			}
+			stark = []*felt.Felt{utils.BigIntToFelt(sig.R), utils.BigIntToFelt(sig.S)}
+		case SignerTypeSecp256r1:
+			if sig.SignerID == nil {
+				return nil, fmt.Errorf("%w: hardware signature without signer id", ErrInvalidSignatureLayout)
+			}
+			rLow, rHigh, err := utils.Uint256ToFelts(sig.R)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidSignatureLayout, err)
+			}
+			sLow, sHigh, err := utils.Uint256ToFelts(sig.S)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidSignatureLayout, err)
+			}
+			hardware = append(hardware, sig.SignerID, rLow, rHigh, sLow, sHigh)
+		default:
+			return nil, fmt.Errorf("%w: unknown signer type %d", ErrInvalidSignatureLayout, sig.Type)
+		}
+	}
+	return append(stark, hardware...), nil
+}
+
+// Unpack decodes a Braavos signature array.
+//
+// Parameters:
+// - signature: the signature array
+// Returns:
+// - []SignerSignature: the Stark signature, if any, followed by the hardware signatures
+// - error: ErrInvalidSignatureLayout if the length of the array does not match the layout
+func (BraavosSignatureLayout) Unpack(signature []*felt.Felt) ([]SignerSignature, error) {
+	var result []SignerSignature
+	rest := signature
+	switch len(signature) % braavosHardwareSignatureLen {
+	case 2:
+		result = append(result, SignerSignature{
+			Type: SignerTypeStark,
+			R:    utils.FeltToBigInt(signature[0]),
+			S:    utils.FeltToBigInt(signature[1]),
+		})
+		rest = signature[2:]
+	case 0:
+		if len(signature) == 0 {
+			return nil, fmt.Errorf("%w: empty signature", ErrInvalidSignatureLayout)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unexpected length %d", ErrInvalidSignatureLayout, len(signature))
+	}
+	for i := 0; i < len(rest); i += braavosHardwareSignatureLen {
+		r, err := utils.FeltsToUint256(rest[i+1], rest[i+2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: r of signer %d: %w", ErrInvalidSignatureLayout, len(result), err)
+		}
+		s, err := utils.FeltsToUint256(rest[i+3], rest[i+4])
+		if err != nil {
+			return nil, fmt.Errorf("%w: s of signer %d: %w", ErrInvalidSignatureLayout, len(result), err)
+		}
+		result = append(result, SignerSignature{
+			Type:     SignerTypeSecp256r1,
+			SignerID: rest[i],
+			R:        r,
+			S:        s,
+		})
+	}
+	return result, nil
+}
+
+// Known Braavos account class hashes, used to select the BraavosSignatureLayout.
+var braavosClassHashes = []string{
+	// Braavos proxy (Cairo 0)
+	"0x3131fa018d520a037686ce3efddeab8f28895662f019ca3ca18a626650f7d1e",
+	// Braavos account v1.0.0 (Cairo 1)
+	"0x816dd0297efc55dc1e7559020a3a825e81ef734b558f03c83325d4da7e6253",
+	// Braavos base account (Cairo 1)
+	"0x13bfe114fb1cf405bfc3a7f8dbe2d91db146c17521d40dcf57e16d6b59fa8e6",
+}
+
+// SignatureLayoutForClassHash returns the signature layout of the account implementation with the given class hash.
+// Unknown class hashes use the StandardSignatureLayout.
+//
+// Parameters:
+// - classHash: the class hash of the account contract
+// Returns:
+// - SignatureLayout: the signature layout of the account
+func SignatureLayoutForClassHash(classHash *felt.Felt) SignatureLayout {
+	for _, braavos := range braavosClassHashes {
+		if classHash.String() == braavos {
+			return BraavosSignatureLayout{}
+		}
+	}
+	return StandardSignatureLayout{}
+}
+
+// DetectSignatureLayout fetches the class hash of the account contract and sets the
+// signature layout of the account accordingly.
+//
+// Parameters:
+// - ctx: the context.Context for the function call
+// Returns:
+// - SignatureLayout: the detected signature layout
+// - error: an error if the class hash cannot be retrieved
+func (account *Account) DetectSignatureLayout(ctx context.Context) (SignatureLayout, error) {
+	classHash, err := account.provider.ClassHashAt(ctx, rpc.WithBlockTag("latest"), account.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	account.SetSignatureLayout(SignatureLayoutForClassHash(classHash))
+	return account.signatureLayout, nil
+}
+
+// SetSignatureLayout sets the layout used to pack the signatures of the account.
+//
+// Parameters:
+// - layout: the signature layout, nil resets it to the StandardSignatureLayout
+// Returns:
+//
+//	none
+func (account *Account) SetSignatureLayout(layout SignatureLayout) {
+	account.signatureLayout = layout
+}
+
+// SignWithSigners signs the message with the account's Signer and packs the resulting signature,
+// together with the signatures of the account's other signers (e.g. hardware signers), using the
+// signature layout of the account.
+//
+// Parameters:
+// - ctx: the context used for the signing operation
+// - msg: the felt message to be signed
+// - additional: the signatures of the other signers of the account
+// Returns:
+// - []*felt.Felt: the packed signature
+// - error: an error, if any
+func (account *Account) SignWithSigners(ctx context.Context, msg *felt.Felt, additional ...SignerSignature) ([]*felt.Felt, error) {
+	r, s, err := account.signer.Sign(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	layout := account.signatureLayout
+	if layout == nil {
+		layout = StandardSignatureLayout{}
+	}
+	signatures := append([]SignerSignature{{Type: SignerTypeStark, R: utils.FeltToBigInt(r), S: utils.FeltToBigInt(s)}}, additional...)
+	return layout.Pack(signatures...)
+}
+
+// Verify verifies a Stark signature of a message hash against a public key, e.g. to authenticate a message
+// signed off-chain by the Signer of an account. See curve.Verify.
+//
+// Parameters:
+// - msgHash: the message hash that was signed
+// - r: the r of the signature
+// - s: the s of the signature
+// - pubKey: the public key of the signer
+// Returns:
+// - bool: true if the signature is valid, false otherwise
+// - error: an error if an input is malformed, e.g. r or s out of range
+func Verify(msgHash, r, s, pubKey *felt.Felt) (bool, error) {
+	return curve.Verify(msgHash, r, s, pubKey)
+}
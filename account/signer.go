@@ -0,0 +1,124 @@
+package account
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Signer signs the transactions and messages of an account with its Stark key, e.g. a key held in memory,
+// by a hardware wallet or by a remote signing service.
+type Signer interface {
+	// Sign signs the message hash with the Stark key, returning the r and s of the signature
+	Sign(ctx context.Context, msgHash *felt.Felt) (r, s *felt.Felt, err error)
+	// PublicKey returns the public key of the Stark key
+	PublicKey() *felt.Felt
+}
+
+var _ Signer = &MemorySigner{}
+var _ Signer = &KeystoreSigner{}
+
+// MemorySigner is a Signer holding the private key in memory.
+type MemorySigner struct {
+	privateKey *big.Int
+	publicKey  *felt.Felt
+}
+
+// NewMemorySigner creates a MemorySigner for the private key.
+//
+// Parameters:
+// - privateKey: the Stark private key
+// Returns:
+// - *MemorySigner: the signer
+// - error: an error if the public key cannot be derived from the private key
+func NewMemorySigner(privateKey *felt.Felt) (*MemorySigner, error) {
+	key := utils.FeltToBigInt(privateKey)
+	publicKey, _, err := curve.Curve.PrivateToPoint(key)
+	if err != nil {
+		return nil, err
+	}
+	return &MemorySigner{privateKey: key, publicKey: utils.BigIntToFelt(publicKey)}, nil
+}
+
+// Sign signs the message hash with the private key.
+//
+// Parameters:
+// - ctx: the context.Context of the signing, the message is not signed if it is done
+// - msgHash: the message hash to be signed
+// Returns:
+// - r: the r of the signature
+// - s: the s of the signature
+// - err: an error if any
+func (signer *MemorySigner) Sign(ctx context.Context, msgHash *felt.Felt) (r, s *felt.Felt, err error) {
+	x, y, err := sign(ctx, utils.FeltToBigInt(msgHash), signer.privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return utils.BigIntToFelt(x), utils.BigIntToFelt(y), nil
+}
+
+// PublicKey returns the public key of the private key.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the public key
+func (signer *MemorySigner) PublicKey() *felt.Felt {
+	return signer.publicKey
+}
+
+// KeystoreSigner is a Signer signing with the key of a public key in a Keystore, adapting a Keystore to
+// NewAccount.
+type KeystoreSigner struct {
+	ks        Keystore
+	publicKey string
+}
+
+// NewKeystoreSigner creates a KeystoreSigner signing with the key of the public key in the keystore.
+//
+// Parameters:
+// - ks: the keystore holding the key
+// - publicKey: the public key, identifying the key in the keystore
+// Returns:
+// - *KeystoreSigner: the signer
+func NewKeystoreSigner(ks Keystore, publicKey string) *KeystoreSigner {
+	return &KeystoreSigner{ks: ks, publicKey: publicKey}
+}
+
+// Sign signs the message hash with the key of the public key in the keystore.
+//
+// Parameters:
+// - ctx: the context.Context of the signing
+// - msgHash: the message hash to be signed
+// Returns:
+// - r: the r of the signature
+// - s: the s of the signature
+// - err: an error if any
+func (signer *KeystoreSigner) Sign(ctx context.Context, msgHash *felt.Felt) (r, s *felt.Felt, err error) {
+	x, y, err := signer.ks.Sign(ctx, signer.publicKey, utils.FeltToBigInt(msgHash))
+	if err != nil {
+		return nil, nil, err
+	}
+	return utils.BigIntToFelt(x), utils.BigIntToFelt(y), nil
+}
+
+// PublicKey returns the public key identifying the key in the keystore.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the public key, nil if it is not a felt
+func (signer *KeystoreSigner) PublicKey() *felt.Felt {
+	publicKey, err := new(felt.Felt).SetString(signer.publicKey)
+	if err != nil {
+		return nil
+	}
+	return publicKey
+}
@@ -0,0 +1,110 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrNotATransaction = errors.New("not a transaction")
+
+// ConcurrentSimulationError holds the errors of the failed simulations of SimulateConcurrent.
+type ConcurrentSimulationError struct {
+	// The error of each transaction, by index in the simulated transactions, nil for the successful simulations
+	Errs []error
+}
+
+// Error returns the errors of the failed simulations with their index.
+func (e *ConcurrentSimulationError) Error() string {
+	var msgs []string
+	for i, err := range e.Errs {
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("transaction %d: %v", i, err))
+		}
+	}
+	return "simulation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the failed simulations, so that they can be matched with errors.Is and errors.As.
+func (e *ConcurrentSimulationError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// SimulateConcurrent simulates each transaction on its own with SimulateTransactions on the pending block,
+// running at most concurrency simulations at a time. The results are in the order of the transactions. The simulations
+// not started when the context is done fail with the context error.
+// The simulations go through the provider, so the retries of rate-limited requests configured on it
+// (see rpc.WithRetry) apply to each of them.
+//
+// Parameters:
+// - ctx: The context.Context object
+// - txns: The transactions to simulate, each implementing rpc.Transaction
+// - simulationFlags: The slice of rpc.SimulationFlag applied to every simulation
+// - concurrency: The maximum number of simulations running at once, at least 1
+// Returns:
+// - []rpc.SimulateTransactionOutput: the simulation of each transaction, empty for the failed ones
+// - error: a *ConcurrentSimulationError if any simulation failed
+func (account *Account) SimulateConcurrent(ctx context.Context, txns []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, concurrency int) ([]rpc.SimulateTransactionOutput, error) {
+	outputs := make([]rpc.SimulateTransactionOutput, len(txns))
+	errs := make([]error, len(txns))
+	concurrency = max(1, min(concurrency, len(txns)))
+	blockID := rpc.WithBlockTag(rpc.BlockTagPending)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				txn, ok := txns[i].(rpc.Transaction)
+				if !ok {
+					errs[i] = fmt.Errorf("%w: %T", ErrNotATransaction, txns[i])
+					continue
+				}
+				simulated, err := account.provider.SimulateTransactions(ctx, blockID, []rpc.Transaction{txn}, simulationFlags)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				outputs[i] = rpc.SimulateTransactionOutput{Txns: simulated}
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(txns); next++ {
+		select {
+		case indexes <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	for i := next; i < len(txns); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return outputs, &ConcurrentSimulationError{Errs: errs}
+		}
+	}
+	return outputs, nil
+}
@@ -0,0 +1,183 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrInvalidSpecVersion = errors.New("invalid spec version")
+
+// AccountOption configures an Account created with NewAccount.
+type AccountOption func(*Account)
+
+// WithSpecVersion pins the RPC spec version the account builds its transactions against, instead of the
+// version negotiated with the provider (see rpc.Provider.SpecVersion). The version selects the shape of the
+// transactions and their hash algorithm, e.g. invoke v1 hashed with Pedersen before 0.6.0 and invoke v3
+// hashed with Poseidon from 0.6.0 on.
+//
+// Parameters:
+// - version: the spec version, e.g. "0.5.1" or "0.7.1"
+// Returns:
+// - AccountOption: the option to pass to NewAccount
+func WithSpecVersion(version string) AccountOption {
+	return func(account *Account) {
+		account.specVersion = version
+	}
+}
+
+// transactionSpecVersion returns the RPC spec version the account builds its transactions against: the version
+// pinned with WithSpecVersion, or else the version of the provider.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// Returns:
+// - string: the spec version
+// - error: an error if the version of the provider cannot be fetched
+func (account *Account) transactionSpecVersion(ctx context.Context) (string, error) {
+	if account.specVersion != "" {
+		return account.specVersion, nil
+	}
+	return account.provider.SpecVersion(ctx)
+}
+
+// supportsV3 reports whether the spec version has the v3 transactions, introduced in 0.6.0.
+//
+// Parameters:
+// - version: the spec version
+// Returns:
+// - bool: true for 0.6.0 and later
+// - error: an error if the version is not a major.minor[.patch] version
+func supportsV3(version string) (bool, error) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return false, fmt.Errorf("%w: %q", ErrInvalidSpecVersion, version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false, fmt.Errorf("%w: %q", ErrInvalidSpecVersion, version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false, fmt.Errorf("%w: %q", ErrInvalidSpecVersion, version)
+	}
+	return major > 0 || minor >= 6, nil
+}
+
+// BuildInvokeTxn builds and signs an invoke transaction executing the calls, in the shape of the spec version
+// of the account (see WithSpecVersion): an invoke v1 transaction paying at most maxFee before 0.6.0, and an invoke
+// v3 transaction bounded by resourceBounds from 0.6.0 on. The parameter of the other shape is ignored.
+// A nil nonce is reserved with NextNonce, and released if the transaction cannot be built.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - calls: The calls executed by the transaction
+// - nonce: The nonce of the account, nil to reserve the next one
+// - maxFee: The maximum fee of an invoke v1 transaction
+// - resourceBounds: The resource bounds of an invoke v3 transaction
+// Returns:
+// - rpc.BroadcastInvokeTxnType: the signed rpc.BroadcastInvokev1Txn or rpc.BroadcastInvokev3Txn
+// - error: an error if any
+func (account *Account) BuildInvokeTxn(ctx context.Context, calls []rpc.FunctionCall, nonce *felt.Felt, maxFee *felt.Felt, resourceBounds rpc.ResourceBoundsMapping) (rpc.BroadcastInvokeTxnType, error) {
+	if nonce != nil {
+		return account.buildInvokeTxn(ctx, calls, nonce, maxFee, resourceBounds)
+	}
+	nonce, err := account.NextNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := account.buildInvokeTxn(ctx, calls, nonce, maxFee, resourceBounds)
+	if err != nil {
+		account.releaseNonce(nonce, err)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// buildInvokeTxn builds and signs the invoke transaction of BuildInvokeTxn with the given nonce.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - calls: The calls executed by the transaction
+// - nonce: The nonce of the account
+// - maxFee: The maximum fee of an invoke v1 transaction
+// - resourceBounds: The resource bounds of an invoke v3 transaction
+// Returns:
+// - rpc.BroadcastInvokeTxnType: the signed rpc.BroadcastInvokev1Txn or rpc.BroadcastInvokev3Txn
+// - error: an error if any
+func (account *Account) buildInvokeTxn(ctx context.Context, calls []rpc.FunctionCall, nonce *felt.Felt, maxFee *felt.Felt, resourceBounds rpc.ResourceBoundsMapping) (rpc.BroadcastInvokeTxnType, error) {
+	version, err := account.transactionSpecVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v3, err := supportsV3(version)
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := account.FmtCalldata(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	if !v3 {
+		tx := rpc.InvokeTxnV1{
+			MaxFee:        maxFee,
+			Version:       rpc.TransactionV1,
+			Nonce:         nonce,
+			Type:          rpc.TransactionType_Invoke,
+			SenderAddress: account.AccountAddress,
+			Calldata:      calldata,
+		}
+		if err := account.SignInvokeTransaction(ctx, &tx); err != nil {
+			return nil, err
+		}
+		return rpc.BroadcastInvokev1Txn{InvokeTxnV1: tx}, nil
+	}
+
+	tx, err := account.buildInvokeTxnV3(ctx, calldata, nonce, resourceBounds)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// buildInvokeTxnV3 builds and signs an invoke v3 transaction with the calldata of the account's __execute__.
+//
+// Parameters:
+// - ctx: The context.Context for the request
+// - calldata: The calldata passed to __execute__
+// - nonce: The nonce of the account
+// - resourceBounds: The resource bounds of the transaction
+// Returns:
+// - rpc.BroadcastInvokev3Txn: the signed transaction
+// - error: an error if any
+func (account *Account) buildInvokeTxnV3(ctx context.Context, calldata []*felt.Felt, nonce *felt.Felt, resourceBounds rpc.ResourceBoundsMapping) (rpc.BroadcastInvokev3Txn, error) {
+	tx := rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         account.AccountAddress,
+		Calldata:              calldata,
+		Version:               rpc.TransactionV3,
+		Nonce:                 nonce,
+		ResourceBounds:        resourceBounds,
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+	txHash, err := account.TransactionHashInvoke(tx)
+	if err != nil {
+		return rpc.BroadcastInvokev3Txn{}, err
+	}
+	signature, err := account.Sign(ctx, txHash)
+	if err != nil {
+		return rpc.BroadcastInvokev3Txn{}, err
+	}
+	tx.Signature = signature
+	return rpc.BroadcastInvokev3Txn{InvokeTxnV3: tx}, nil
+}
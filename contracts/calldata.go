@@ -0,0 +1,345 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrSelectorNotInABI = errors.New("selector not found in the ABI")
+	ErrCalldataTooShort = errors.New("calldata too short")
+	ErrZeroNonZero      = errors.New("zero value of a NonZero type")
+	ErrOutOfBounds      = errors.New("value out of the bounds of a BoundedInt type")
+)
+
+// DecodedCall is a call of a multicall decoded with the ABI of the called contract.
+type DecodedCall struct {
+	ContractAddress    *felt.Felt
+	EntryPointSelector *felt.Felt
+	FunctionName       string
+	// The arguments of the call by name: felts, integers, addresses and class hashes are *felt.Felt,
+	// u256 and Uint256 are *big.Int, bool is bool, arrays, spans and tuples are []any, structs are
+	// map[string]any of their members and enums are a map[string]any holding the decoded variant.
+	// The NonZero and BoundedInt wrappers are decoded as their underlying type.
+	Args map[string]any
+}
+
+// DecodeCalldata decodes the calldata of the __execute__ entrypoint of a Cairo 1 account, i.e. a number
+// of calls followed by each call as [to, selector, calldata_len, calldata...], into the called functions
+// and their named arguments. The functions are looked up by selector in the ABI, including the functions
+// declared inside its interfaces.
+//
+// Parameters:
+// - abi: the JSON ABI of the called contracts (e.g. ContractClass.ABI)
+// - calldata: the calldata of the account transaction
+// Returns:
+// - []DecodedCall: the decoded calls, in order
+// - error: an error if the calldata does not match the layout or the ABI, ErrSelectorNotInABI if a function is missing
+func DecodeCalldata(abi []byte, calldata []*felt.Felt) ([]DecodedCall, error) {
+	var entries rpc.ABI
+	if err := json.Unmarshal(abi, &entries); err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	decoder := newCalldataDecoder(entries)
+
+	if len(calldata) == 0 {
+		return nil, ErrCalldataTooShort
+	}
+	numCalls := utils.FeltToBigInt(calldata[0])
+	if !numCalls.IsUint64() || numCalls.Uint64() > uint64(len(calldata)) {
+		return nil, fmt.Errorf("invalid number of calls %s", numCalls)
+	}
+	rest := calldata[1:]
+
+	calls := make([]DecodedCall, 0, numCalls.Uint64())
+	for i := uint64(0); i < numCalls.Uint64(); i++ {
+		if len(rest) < 3 {
+			return nil, fmt.Errorf("call %d: %w", i, ErrCalldataTooShort)
+		}
+		call := DecodedCall{ContractAddress: rest[0], EntryPointSelector: rest[1]}
+		length := utils.FeltToBigInt(rest[2])
+		rest = rest[3:]
+		if !length.IsUint64() || length.Uint64() > uint64(len(rest)) {
+			return nil, fmt.Errorf("call %d: %w", i, ErrCalldataTooShort)
+		}
+		callData := rest[:length.Uint64()]
+		rest = rest[length.Uint64():]
+
+		function, ok := decoder.functions[call.EntryPointSelector.String()]
+		if !ok {
+			return nil, fmt.Errorf("call %d: %w: %s", i, ErrSelectorNotInABI, call.EntryPointSelector)
+		}
+		call.FunctionName = function.Name
+
+		args, err := decoder.decodeInputs(function.Inputs, callData)
+		if err != nil {
+			return nil, fmt.Errorf("call %d (%s): %w", i, function.Name, err)
+		}
+		call.Args = args
+		calls = append(calls, call)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%d unexpected felts after the last call", len(rest))
+	}
+	return calls, nil
+}
+
+// calldataDecoder decodes calldata according to the types of an ABI.
+type calldataDecoder struct {
+	functions map[string]*rpc.FunctionABIEntry
+	structs   map[string]*rpc.StructABIEntry
+	enums     map[string]*rpc.EnumABIEntry
+}
+
+func newCalldataDecoder(abi rpc.ABI) *calldataDecoder {
+	decoder := &calldataDecoder{
+		functions: map[string]*rpc.FunctionABIEntry{},
+		structs:   map[string]*rpc.StructABIEntry{},
+		enums:     map[string]*rpc.EnumABIEntry{},
+	}
+	addFunction := func(function *rpc.FunctionABIEntry) {
+		decoder.functions[utils.GetSelectorFromNameFelt(function.Name).String()] = function
+	}
+	for _, entry := range abi {
+		switch entry := entry.(type) {
+		case *rpc.FunctionABIEntry:
+			addFunction(entry)
+		case *rpc.InterfaceABIEntry:
+			for _, function := range entry.Items {
+				addFunction(function)
+			}
+		case *rpc.StructABIEntry:
+			decoder.structs[entry.Name] = entry
+		case *rpc.EnumABIEntry:
+			decoder.enums[entry.Name] = entry
+		}
+	}
+	return decoder
+}
+
+// decodeInputs decodes the whole calldata of a function into its named inputs.
+func (d *calldataDecoder) decodeInputs(inputs []rpc.TypedParameter, data []*felt.Felt) (map[string]any, error) {
+	args := make(map[string]any, len(inputs))
+	for _, input := range inputs {
+		var value any
+		var err error
+		if elemType, ok := strings.CutSuffix(input.Type, "*"); ok {
+			// Cairo 0 arrays are preceded by their length in a <name>_len input
+			length, ok := args[input.Name+"_len"].(*felt.Felt)
+			if !ok {
+				return nil, fmt.Errorf("missing length of the array %s", input.Name)
+			}
+			value, data, err = d.decodeArray(elemType, utils.FeltToBigInt(length), data)
+		} else {
+			value, data, err = d.decode(input.Type, data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", input.Name, err)
+		}
+		args[input.Name] = value
+	}
+	if len(data) != 0 {
+		return nil, fmt.Errorf("%d unexpected felts after the last argument", len(data))
+	}
+	return args, nil
+}
+
+// decode decodes a value of the given type from the head of the data, and returns the remaining data.
+func (d *calldataDecoder) decode(typ string, data []*felt.Felt) (any, []*felt.Felt, error) {
+	typ = strings.TrimSpace(typ)
+	switch {
+	case typ == "()":
+		return nil, data, nil
+	case typ == "core::integer::u256" || typ == "Uint256":
+		if len(data) < 2 {
+			return nil, nil, ErrCalldataTooShort
+		}
+		value, err := utils.FeltsToUint256(data[0], data[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		return value, data[2:], nil
+	case typ == "core::bool":
+		if len(data) < 1 {
+			return nil, nil, ErrCalldataTooShort
+		}
+		return !data[0].IsZero(), data[1:], nil
+	case strings.HasPrefix(typ, "("):
+		return d.decodeTuple(typ, data)
+	}
+	if inner, ok := genericArgument(typ, "core::zeroable::NonZero::<"); ok {
+		return d.decodeNonZero(inner, data)
+	}
+	if bounds, ok := genericArgument(typ, "core::internal::bounded_int::BoundedInt::<"); ok {
+		return decodeBoundedInt(bounds, data)
+	}
+	if elemType, ok := arrayElementType(typ); ok {
+		if len(data) < 1 {
+			return nil, nil, ErrCalldataTooShort
+		}
+		return d.decodeArray(elemType, utils.FeltToBigInt(data[0]), data[1:])
+	}
+	if entry, ok := d.structs[typ]; ok {
+		members := make(map[string]any, len(entry.Members))
+		for _, member := range entry.Members {
+			value, rest, err := d.decode(member.Type, data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s.%s: %w", typ, member.Name, err)
+			}
+			members[member.Name] = value
+			data = rest
+		}
+		return members, data, nil
+	}
+	if entry, ok := d.enums[typ]; ok {
+		if len(data) < 1 {
+			return nil, nil, ErrCalldataTooShort
+		}
+		index := utils.FeltToBigInt(data[0])
+		if !index.IsInt64() || index.Int64() >= int64(len(entry.Variants)) {
+			return nil, nil, fmt.Errorf("invalid variant %s of the enum %s", index, typ)
+		}
+		variant := entry.Variants[index.Int64()]
+		value, rest, err := d.decode(variant.Type, data[1:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s::%s: %w", typ, variant.Name, err)
+		}
+		return map[string]any{variant.Name: value}, rest, nil
+	}
+	// felts, integers, addresses, class hashes and the other single felt types
+	if len(data) < 1 {
+		return nil, nil, ErrCalldataTooShort
+	}
+	return data[0], data[1:], nil
+}
+
+// decodeArray decodes length values of the element type from the head of the data.
+func (d *calldataDecoder) decodeArray(elemType string, length *big.Int, data []*felt.Felt) ([]any, []*felt.Felt, error) {
+	if !length.IsInt64() || length.Int64() > int64(len(data)) {
+		return nil, nil, ErrCalldataTooShort
+	}
+	values := make([]any, 0, length.Int64())
+	for i := int64(0); i < length.Int64(); i++ {
+		value, rest, err := d.decode(elemType, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		values = append(values, value)
+		data = rest
+	}
+	return values, data, nil
+}
+
+// decodeTuple decodes a tuple type such as (core::felt252, core::bool) from the head of the data.
+func (d *calldataDecoder) decodeTuple(typ string, data []*felt.Felt) ([]any, []*felt.Felt, error) {
+	inner, ok := strings.CutSuffix(strings.TrimPrefix(typ, "("), ")")
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid tuple type %s", typ)
+	}
+	var values []any
+	for _, elemType := range splitTopLevel(inner) {
+		value, rest, err := d.decode(elemType, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values, value)
+		data = rest
+	}
+	return values, data, nil
+}
+
+// decodeNonZero decodes a NonZero<T> value as its underlying value, which must not be zero.
+func (d *calldataDecoder) decodeNonZero(typ string, data []*felt.Felt) (any, []*felt.Felt, error) {
+	value, rest, err := d.decode(typ, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch v := value.(type) {
+	case *felt.Felt:
+		if v.IsZero() {
+			return nil, nil, fmt.Errorf("%w: NonZero::<%s>", ErrZeroNonZero, typ)
+		}
+	case *big.Int:
+		if v.Sign() == 0 {
+			return nil, nil, fmt.Errorf("%w: NonZero::<%s>", ErrZeroNonZero, typ)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported type NonZero::<%s>", typ)
+	}
+	return value, rest, nil
+}
+
+// decodeBoundedInt decodes a BoundedInt<MIN, MAX> value as a felt, whose signed value (the felts above
+// half the field prime being negative) must be within the bounds.
+func decodeBoundedInt(bounds string, data []*felt.Felt) (any, []*felt.Felt, error) {
+	limits := splitTopLevel(bounds)
+	if len(limits) != 2 {
+		return nil, nil, fmt.Errorf("invalid BoundedInt bounds %s", bounds)
+	}
+	minValue, okMin := new(big.Int).SetString(strings.TrimSpace(limits[0]), 0)
+	maxValue, okMax := new(big.Int).SetString(strings.TrimSpace(limits[1]), 0)
+	if !okMin || !okMax {
+		return nil, nil, fmt.Errorf("invalid BoundedInt bounds %s", bounds)
+	}
+	if len(data) < 1 {
+		return nil, nil, ErrCalldataTooShort
+	}
+	value := utils.FeltToBigInt(data[0])
+	prime := utils.FeltToBigInt(new(felt.Felt).Sub(&felt.Zero, new(felt.Felt).SetUint64(1)))
+	prime.Add(prime, big.NewInt(1))
+	if value.Cmp(new(big.Int).Rsh(prime, 1)) > 0 {
+		value.Sub(value, prime)
+	}
+	if value.Cmp(minValue) < 0 || value.Cmp(maxValue) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s not in [%s, %s]", ErrOutOfBounds, value, minValue, maxValue)
+	}
+	return data[0], data[1:], nil
+}
+
+// arrayElementType returns the element type of a Cairo 1 array or span type.
+func arrayElementType(typ string) (string, bool) {
+	for _, prefix := range []string{"core::array::Array::<", "core::array::Span::<"} {
+		if elemType, ok := genericArgument(typ, prefix); ok {
+			return elemType, true
+		}
+	}
+	return "", false
+}
+
+// genericArgument returns the generic arguments of a type of the form <prefix>...>.
+func genericArgument(typ, prefix string) (string, bool) {
+	if arg, ok := strings.CutPrefix(typ, prefix); ok {
+		return strings.CutSuffix(arg, ">")
+	}
+	return "", false
+}
+
+// splitTopLevel splits a comma separated list of types, ignoring the commas of nested types.
+func splitTopLevel(list string) []string {
+	var types []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(', '<':
+			depth++
+		case ')', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				types = append(types, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(list[start:]); last != "" {
+		types = append(types, last)
+	}
+	return types
+}
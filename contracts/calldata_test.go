@@ -0,0 +1,150 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const testCalldataABI = `[
+	{"type": "struct", "name": "core::integer::u256", "members": [
+		{"name": "low", "type": "core::integer::u128"},
+		{"name": "high", "type": "core::integer::u128"}
+	]},
+	{"type": "enum", "name": "core::bool", "variants": [{"name": "False", "type": "()"}, {"name": "True", "type": "()"}]},
+	{"type": "struct", "name": "example::Order", "members": [
+		{"name": "id", "type": "core::felt252"},
+		{"name": "amounts", "type": "core::array::Span::<core::integer::u256>"}
+	]},
+	{"type": "enum", "name": "example::Side", "variants": [
+		{"name": "Buy", "type": "()"},
+		{"name": "Sell", "type": "(core::felt252, core::bool)"}
+	]},
+	{"type": "interface", "name": "example::IExchange", "items": [
+		{"type": "function", "name": "transfer", "inputs": [
+			{"name": "recipient", "type": "core::starknet::contract_address::ContractAddress"},
+			{"name": "amount", "type": "core::integer::u256"}
+		], "outputs": [{"type": "core::bool"}], "state_mutability": "external"},
+		{"type": "function", "name": "place_order", "inputs": [
+			{"name": "order", "type": "example::Order"},
+			{"name": "side", "type": "example::Side"},
+			{"name": "post_only", "type": "core::bool"}
+		], "outputs": [], "state_mutability": "external"}
+	]},
+	{"type": "function", "name": "set_values", "inputs": [
+		{"name": "values_len", "type": "felt"},
+		{"name": "values", "type": "felt*"}
+	], "outputs": []}
+]`
+
+// TestDecodeCalldata tests the decoding of the __execute__ calldata of a Cairo 1 account holding several calls.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestDecodeCalldata(t *testing.T) {
+	token := utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	exchange := utils.TestHexToFelt(t, "0x1234")
+	recipient := utils.TestHexToFelt(t, "0x5678")
+	felts := func(values ...uint64) []*felt.Felt {
+		result := make([]*felt.Felt, len(values))
+		for i, value := range values {
+			result[i] = new(felt.Felt).SetUint64(value)
+		}
+		return result
+	}
+
+	calldata := []*felt.Felt{new(felt.Felt).SetUint64(3)}
+	// transfer(recipient, 2^128 + 5)
+	calldata = append(calldata, token, utils.GetSelectorFromNameFelt("transfer"), new(felt.Felt).SetUint64(3), recipient)
+	calldata = append(calldata, felts(5, 1)...)
+	// place_order(Order{id: 7, amounts: [1, 2]}, Side::Sell((9, true)), false)
+	calldata = append(calldata, exchange, utils.GetSelectorFromNameFelt("place_order"))
+	calldata = append(calldata, felts(10, 7, 2, 1, 0, 2, 0, 1, 9, 1, 0)...)
+	// set_values(2, [3, 4])
+	calldata = append(calldata, exchange, utils.GetSelectorFromNameFelt("set_values"))
+	calldata = append(calldata, felts(3, 2, 3, 4)...)
+
+	calls, err := DecodeCalldata([]byte(testCalldataABI), calldata)
+	require.NoError(t, err)
+	require.Len(t, calls, 3)
+
+	require.Equal(t, token, calls[0].ContractAddress)
+	require.Equal(t, "transfer", calls[0].FunctionName)
+	require.Equal(t, recipient, calls[0].Args["recipient"])
+	expectedAmount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5))
+	require.Equal(t, expectedAmount, calls[0].Args["amount"])
+
+	require.Equal(t, "place_order", calls[1].FunctionName)
+	order := calls[1].Args["order"].(map[string]any)
+	require.Equal(t, new(felt.Felt).SetUint64(7), order["id"])
+	require.Equal(t, []any{big.NewInt(1), big.NewInt(2)}, order["amounts"])
+	require.Equal(t, map[string]any{"Sell": []any{new(felt.Felt).SetUint64(9), true}}, calls[1].Args["side"])
+	require.Equal(t, false, calls[1].Args["post_only"])
+
+	require.Equal(t, "set_values", calls[2].FunctionName)
+	require.Equal(t, []any{new(felt.Felt).SetUint64(3), new(felt.Felt).SetUint64(4)}, calls[2].Args["values"])
+
+	// a selector missing from the ABI
+	unknown := []*felt.Felt{new(felt.Felt).SetUint64(1), token, utils.GetSelectorFromNameFelt("approve"), new(felt.Felt).SetUint64(0)}
+	_, err = DecodeCalldata([]byte(testCalldataABI), unknown)
+	require.ErrorIs(t, err, ErrSelectorNotInABI)
+
+	// truncated calldata
+	_, err = DecodeCalldata([]byte(testCalldataABI), calldata[:len(calldata)-1])
+	require.ErrorIs(t, err, ErrCalldataTooShort)
+
+	// a u256 limb exceeding 128 bits
+	overflow := []*felt.Felt{new(felt.Felt).SetUint64(1), token, utils.GetSelectorFromNameFelt("transfer"), new(felt.Felt).SetUint64(3), recipient}
+	overflow = append(overflow, utils.BigIntToFelt(new(big.Int).Lsh(big.NewInt(1), 128)), new(felt.Felt))
+	_, err = DecodeCalldata([]byte(testCalldataABI), overflow)
+	require.ErrorIs(t, err, utils.ErrInvalidUint256)
+}
+
+// TestDecodeCalldataWrappers tests that the NonZero and BoundedInt wrappers are decoded as their underlying
+// type and that their constraints are checked.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestDecodeCalldataWrappers(t *testing.T) {
+	abi := []byte(`[
+		{"type": "function", "name": "split", "inputs": [
+			{"name": "amount", "type": "core::zeroable::NonZero::<core::integer::u256>"},
+			{"name": "divisor", "type": "core::zeroable::NonZero::<core::felt252>"},
+			{"name": "offset", "type": "core::internal::bounded_int::BoundedInt::<-128, 127>"}
+		], "outputs": [], "state_mutability": "external"}
+	]`)
+	contract := utils.TestHexToFelt(t, "0x1234")
+	minusOne := new(felt.Felt).Sub(&felt.Zero, new(felt.Felt).SetUint64(1))
+	call := func(args ...*felt.Felt) []*felt.Felt {
+		calldata := []*felt.Felt{new(felt.Felt).SetUint64(1), contract, utils.GetSelectorFromNameFelt("split"), new(felt.Felt).SetUint64(uint64(len(args)))}
+		return append(calldata, args...)
+	}
+
+	calls, err := DecodeCalldata(abi, call(new(felt.Felt).SetUint64(0), new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(3), minusOne))
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Lsh(big.NewInt(1), 128), calls[0].Args["amount"])
+	require.Equal(t, new(felt.Felt).SetUint64(3), calls[0].Args["divisor"])
+	require.Equal(t, minusOne, calls[0].Args["offset"])
+
+	// a zero NonZero<u256> and a zero NonZero<felt252>
+	_, err = DecodeCalldata(abi, call(new(felt.Felt).SetUint64(0), new(felt.Felt).SetUint64(0), new(felt.Felt).SetUint64(3), minusOne))
+	require.ErrorIs(t, err, ErrZeroNonZero)
+	_, err = DecodeCalldata(abi, call(new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(0), new(felt.Felt).SetUint64(0), minusOne))
+	require.ErrorIs(t, err, ErrZeroNonZero)
+
+	// out of the bounds on both sides
+	_, err = DecodeCalldata(abi, call(new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(0), new(felt.Felt).SetUint64(3), new(felt.Felt).SetUint64(128)))
+	require.ErrorIs(t, err, ErrOutOfBounds)
+	_, err = DecodeCalldata(abi, call(new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(0), new(felt.Felt).SetUint64(3),
+		new(felt.Felt).Sub(&felt.Zero, new(felt.Felt).SetUint64(129))))
+	require.ErrorIs(t, err, ErrOutOfBounds)
+}
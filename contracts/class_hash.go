@@ -0,0 +1,350 @@
+package contracts
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrUnsupportedClass = errors.New("unsupported class type")
+
+// ClassHash calculates the class hash of a Cairo 0 (deprecated) or Cairo 1 (Sierra) contract class, i.e. the
+// hash the class is declared with.
+// ref: https://docs.starknet.io/architecture-and-concepts/smart-contracts/class-hash/
+//
+// Parameters:
+//   - class: the contract class, a rpc.ContractClass or SierraClass for Cairo 1, a rpc.DeprecatedContractClass
+//     for Cairo 0 (or a pointer to one of them, as returned by rpc.Provider.Class)
+//
+// Returns:
+// - *felt.Felt: the class hash
+// - error: an error if the class cannot be hashed, ErrUnsupportedClass if it is not a contract class
+func ClassHash(class rpc.ClassOutput) (*felt.Felt, error) {
+	switch class := class.(type) {
+	case rpc.ContractClass:
+		return sierraClassHash(class)
+	case *rpc.ContractClass:
+		return sierraClassHash(*class)
+	case SierraClass:
+		return sierraClassHash(class.ContractClass())
+	case *SierraClass:
+		return sierraClassHash(class.ContractClass())
+	case rpc.DeprecatedContractClass:
+		return deprecatedClassHash(class)
+	case *rpc.DeprecatedContractClass:
+		return deprecatedClassHash(*class)
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedClass, class)
+}
+
+// sierraClassHash calculates the Poseidon based class hash of a Cairo 1 contract class.
+func sierraClassHash(contract rpc.ContractClass) (*felt.Felt, error) {
+	Version := "CONTRACT_CLASS_V" + contract.ContractClassVersion
+	ContractClassVersionHash := new(felt.Felt).SetBytes([]byte(Version))
+	ConstructorHash := hashSierraEntryPointByType(contract.EntryPointsByType.Constructor)
+	ExternalHash := hashSierraEntryPointByType(contract.EntryPointsByType.External)
+	L1HandleHash := hashSierraEntryPointByType(contract.EntryPointsByType.L1Handler)
+	SierraProgamHash := curve.Curve.PoseidonArray(contract.SierraProgram...)
+	ABIHash, err := curve.Curve.StarknetKeccak([]byte(contract.ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return curve.Curve.PoseidonArray(ContractClassVersionHash, ExternalHash, L1HandleHash, ConstructorHash, ABIHash, SierraProgamHash), nil
+}
+
+// hashSierraEntryPointByType calculates the hash of the Sierra entry points of a type.
+func hashSierraEntryPointByType(entryPoint []rpc.SierraEntryPoint) *felt.Felt {
+	flattened := make([]*felt.Felt, 0, len(entryPoint))
+	for _, elt := range entryPoint {
+		flattened = append(flattened, elt.Selector, new(felt.Felt).SetUint64(uint64(elt.FunctionIdx)))
+	}
+	return curve.Curve.PoseidonArray(flattened...)
+}
+
+// deprecatedClassHash calculates the Pedersen based class hash of a Cairo 0 contract class.
+// ref: https://github.com/starkware-libs/cairo-lang/blob/master/src/starkware/starknet/core/os/contract_class/deprecated_class_hash.py
+func deprecatedClassHash(class rpc.DeprecatedContractClass) (*felt.Felt, error) {
+	program, err := decodeProgram(class.Program)
+	if err != nil {
+		return nil, err
+	}
+
+	var bytecode []string
+	if err := json.Unmarshal(program["data"], &bytecode); err != nil {
+		return nil, fmt.Errorf("invalid program data: %w", err)
+	}
+	data := make([]*felt.Felt, len(bytecode))
+	for i, value := range bytecode {
+		if data[i], err = new(felt.Felt).SetString(value); err != nil {
+			return nil, fmt.Errorf("invalid program data: %w", err)
+		}
+	}
+
+	var builtinNames []string
+	if err := json.Unmarshal(program["builtins"], &builtinNames); err != nil {
+		return nil, fmt.Errorf("invalid program builtins: %w", err)
+	}
+	builtins := make([]*felt.Felt, len(builtinNames))
+	for i, builtin := range builtinNames {
+		builtins[i] = new(felt.Felt).SetBytes([]byte(builtin))
+	}
+
+	externalHash, err := hashDeprecatedEntryPoints(class.DeprecatedEntryPointsByType.External)
+	if err != nil {
+		return nil, err
+	}
+	l1HandlerHash, err := hashDeprecatedEntryPoints(class.DeprecatedEntryPointsByType.L1Handler)
+	if err != nil {
+		return nil, err
+	}
+	constructorHash, err := hashDeprecatedEntryPoints(class.DeprecatedEntryPointsByType.Constructor)
+	if err != nil {
+		return nil, err
+	}
+	hintedClassHash, err := hintedClassHash(program, class.ABI)
+	if err != nil {
+		return nil, err
+	}
+
+	return curve.PedersenArray(
+		&felt.Zero, // API version
+		externalHash,
+		l1HandlerHash,
+		constructorHash,
+		curve.PedersenArray(builtins...),
+		hintedClassHash,
+		curve.PedersenArray(data...),
+	), nil
+}
+
+// hashDeprecatedEntryPoints calculates the hash of the Cairo 0 entry points of a type.
+func hashDeprecatedEntryPoints(entryPoints []rpc.DeprecatedCairoEntryPoint) (*felt.Felt, error) {
+	flattened := make([]*felt.Felt, 0, 2*len(entryPoints))
+	for _, entryPoint := range entryPoints {
+		offset, err := new(felt.Felt).SetString(string(entryPoint.Offset))
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry point offset %q: %w", entryPoint.Offset, err)
+		}
+		flattened = append(flattened, entryPoint.Selector, offset)
+	}
+	return curve.PedersenArray(flattened...), nil
+}
+
+// decodeProgram decodes the base64 encoded and gzip compressed program of a Cairo 0 contract class.
+func decodeProgram(encoded string) (map[string]json.RawMessage, error) {
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid program encoding: %w", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid program compression: %w", err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid program compression: %w", err)
+	}
+
+	var program map[string]json.RawMessage
+	if err := json.Unmarshal(content, &program); err != nil {
+		return nil, fmt.Errorf("invalid program: %w", err)
+	}
+	return program, nil
+}
+
+// hintedClassHash calculates the starknet keccak of the program without its debug info and of the ABI,
+// serialized as cairo-lang does (Python's json.dumps with sorted keys).
+func hintedClassHash(program map[string]json.RawMessage, abi *rpc.ABI) (*felt.Felt, error) {
+	toHash := map[string]any{}
+	for key, raw := range program {
+		value, err := decodeJSONValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid program %s: %w", key, err)
+		}
+		toHash[key] = value
+	}
+	toHash["debug_info"] = nil
+	// the hints are keyed by pc, which cairo-lang sorts as integers
+	if hints, ok := toHash["hints"].(map[string]any); ok {
+		toHash["hints"] = pcKeyedMap(hints)
+	}
+
+	// for backward compatibility with the classes deployed before the attributes were added to the program
+	if attributes, ok := toHash["attributes"].([]any); ok {
+		if len(attributes) == 0 {
+			delete(toHash, "attributes")
+		}
+		for _, attribute := range attributes {
+			attribute, ok := attribute.(map[string]any)
+			if !ok {
+				continue
+			}
+			if scopes, ok := attribute["accessible_scopes"].([]any); ok && len(scopes) == 0 {
+				delete(attribute, "accessible_scopes")
+			}
+			if value, ok := attribute["flow_tracking_data"]; ok && value == nil {
+				delete(attribute, "flow_tracking_data")
+			}
+		}
+	}
+
+	// the programs compiled before Cairo 0.10.0 format the named tuples as "(a : felt)" instead of "(a: felt)"
+	if version, ok := toHash["compiler_version"]; !ok || version == nil {
+		addNamedTupleSpaces(toHash["identifiers"])
+		addNamedTupleSpaces(toHash["reference_manager"])
+	}
+
+	var abiValue any
+	if abi != nil {
+		content, err := json.Marshal(abi)
+		if err != nil {
+			return nil, err
+		}
+		if abiValue, err = decodeJSONValue(content); err != nil {
+			return nil, err
+		}
+	}
+
+	var dumped strings.Builder
+	dumpPythonJSON(&dumped, map[string]any{"abi": abiValue, "program": toHash})
+	return curve.Curve.StarknetKeccak([]byte(dumped.String()))
+}
+
+// addNamedTupleSpaces adds the space before the colons of the Cairo types held by the program identifiers
+// and references.
+func addNamedTupleSpaces(value any) {
+	switch value := value.(type) {
+	case []any:
+		for _, item := range value {
+			addNamedTupleSpaces(item)
+		}
+	case map[string]any:
+		for key, item := range value {
+			if text, ok := item.(string); ok {
+				if key == "cairo_type" || key == "value" {
+					value[key] = strings.ReplaceAll(strings.ReplaceAll(text, ": ", " : "), "  :", " :")
+				}
+				continue
+			}
+			addNamedTupleSpaces(item)
+		}
+	}
+}
+
+// pcKeyedMap is a JSON object keyed by integers, whose keys are sorted numerically when dumped.
+type pcKeyedMap map[string]any
+
+// decodeJSONValue decodes JSON content into maps, slices and scalars, keeping the numbers as written.
+func decodeJSONValue(content []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// dumpPythonJSON writes the value as Python's json.dumps(value, sort_keys=True) does: sorted keys, ", " and ": "
+// separators, and the non ASCII characters escaped.
+func dumpPythonJSON(w *strings.Builder, value any) {
+	switch value := value.(type) {
+	case nil:
+		w.WriteString("null")
+	case bool:
+		w.WriteString(strconv.FormatBool(value))
+	case json.Number:
+		w.WriteString(value.String())
+	case string:
+		dumpPythonString(w, value)
+	case []any:
+		w.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			dumpPythonJSON(w, item)
+		}
+		w.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		dumpPythonObject(w, keys, value)
+	case pcKeyedMap:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		dumpPythonObject(w, keys, value)
+	}
+}
+
+// dumpPythonObject writes the entries of a JSON object in the order of the keys.
+func dumpPythonObject(w *strings.Builder, keys []string, value map[string]any) {
+	w.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		dumpPythonString(w, key)
+		w.WriteString(": ")
+		dumpPythonJSON(w, value[key])
+	}
+	w.WriteByte('}')
+}
+
+// dumpPythonString writes the string as a JSON string with Python's ensure_ascii escaping.
+func dumpPythonString(w *strings.Builder, s string) {
+	w.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			w.WriteString(`\"`)
+		case '\\':
+			w.WriteString(`\\`)
+		case '\n':
+			w.WriteString(`\n`)
+		case '\r':
+			w.WriteString(`\r`)
+		case '\t':
+			w.WriteString(`\t`)
+		case '\b':
+			w.WriteString(`\b`)
+		case '\f':
+			w.WriteString(`\f`)
+		default:
+			switch {
+			case r < 0x20 || (r >= 0x7f && r < 0x10000):
+				fmt.Fprintf(w, `\u%04x`, r)
+			case r >= 0x10000:
+				high, low := utf16.EncodeRune(r)
+				fmt.Fprintf(w, `\u%04x\u%04x`, high, low)
+			default:
+				w.WriteRune(r)
+			}
+		}
+	}
+	w.WriteByte('"')
+}
@@ -0,0 +1,88 @@
+package contracts
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClassHash tests the class hash of Cairo 0 classes (the StarkGate ERC20 implementation and an OpenZeppelin
+// account of the execute/get_nonce era, as returned by starknet_getClass) and of Cairo 1 classes (a class
+// declared on mainnet and the hello_starknet class of starknet.py) against the hashes they are declared with.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestClassHash(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		classHash string
+	}{
+		{name: "cairo 0 erc20", classHash: "0x01b661756bf7d16210fc611626e1af4569baa1781ffc964bd018f4585ae241c1"},
+		// the OpenZeppelin Account of cairo-contracts v0.1, declared on goerli
+		{name: "cairo 0 account", classHash: "0x056b96c1d1bbfa01af44b465763d1b71150fa00c6c9d54c3947f57e979ff68c3"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := os.ReadFile("./tests/" + test.classHash + ".json")
+			require.NoError(t, err)
+			var class rpc.DeprecatedContractClass
+			require.NoError(t, json.Unmarshal(content, &class))
+
+			hash, err := ClassHash(&class)
+			require.NoError(t, err)
+			require.Equal(t, utils.TestHexToFelt(t, test.classHash), hash)
+		})
+	}
+
+	t.Run("cairo 1 mainnet", func(t *testing.T) {
+		// a Sierra class declared on mainnet, shared with the tests of starknet_getClass
+		content, err := os.ReadFile("../rpc/tests/contract/0x03e9b96873987da76121f74a3df71e38c44527d8ce2ad115bcfda3cba0548cc3.json")
+		require.NoError(t, err)
+		var class rpc.ContractClass
+		require.NoError(t, json.Unmarshal(content, &class))
+
+		hash, err := ClassHash(class)
+		require.NoError(t, err)
+		require.Equal(t, "0x3e9b96873987da76121f74a3df71e38c44527d8ce2ad115bcfda3cba0548cc3", hash.String())
+	})
+
+	t.Run("cairo 1", func(t *testing.T) {
+		//https://github.com/software-mansion/starknet.py/blob/development/starknet_py/hash/class_hash_test.py
+		class, err := UnmarshalSierraClass("./tests/hello_starknet_compiled.sierra.json")
+		require.NoError(t, err)
+
+		hash, err := ClassHash(class)
+		require.NoError(t, err)
+		require.Equal(t, "0x4ec2ecf58014bc2ffd7c84843c3525e5ecb0a2cac33c47e9c347f39fc0c0944", hash.String())
+		contractClassHash, err := ClassHash(class.ContractClass())
+		require.NoError(t, err)
+		require.Equal(t, hash, contractClassHash)
+	})
+
+	_, err := ClassHash(CasmClass{})
+	require.ErrorIs(t, err, ErrUnsupportedClass)
+}
+
+// TestDumpPythonJSON tests that the hinted class hash input is serialized as Python's json.dumps(value, sort_keys=True).
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestDumpPythonJSON(t *testing.T) {
+	value, err := decodeJSONValue([]byte(`{"b": [1, -2, {"d": null, "c": true}], "a": "é \"x\"\n€😀", "hints": {}}`))
+	require.NoError(t, err)
+	value.(map[string]any)["hints"] = pcKeyedMap{"10": json.Number("1"), "9": json.Number("2"), "100": json.Number("3")}
+
+	var dumped strings.Builder
+	dumpPythonJSON(&dumped, value)
+	require.Equal(t, `{"a": "\u00e9 \"x\"\n\u20ac\ud83d\ude00", "b": [1, -2, {"c": true, "d": null}], "hints": {"9": 2, "10": 1, "100": 3}}`, dumped.String())
+}
@@ -1,20 +1,32 @@
 package contracts
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
 	"os"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
 )
 
 var PREFIX_CONTRACT_ADDRESS = new(felt.Felt).SetBytes([]byte("STARKNET_CONTRACT_ADDRESS"))
 
+// addressUpperBound is the exclusive upper bound of the L2 addresses, 2^251 - 256 (L2_ADDRESS_UPPER_BOUND in cairo-lang)
+var addressUpperBound = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 251), big.NewInt(256))
+
+var ErrBytecodeSegmentLengths = errors.New("bytecode segment lengths do not match the bytecode")
+
 type CasmClass struct {
 	Prime            string                     `json:"prime"`
 	Version          string                     `json:"compiler_version"`
 	ByteCode         []*felt.Felt               `json:"bytecode"`
 	EntryPointByType CasmClassEntryPointsByType `json:"entry_points_by_type"`
+	// The lengths of the bytecode segments, missing from the classes compiled before Cairo 2.6.0
+	BytecodeSegmentLengths *NestedUints `json:"bytecode_segment_lengths,omitempty"`
 	// Hints            any                        `json:"hints"`
 }
 
@@ -50,7 +62,121 @@ func UnmarshalCasmClass(filePath string) (*CasmClass, error) {
 	return &casmClass, nil
 }
 
-// PrecomputeAddress calculates the precomputed address for a contract instance.
+// NestedUints is an entry of the bytecode segment lengths of a CASM class: either the length of a segment,
+// or the lengths of the nested segments of a segment.
+type NestedUints struct {
+	IsArray bool
+	Value   uint64
+	Values  []NestedUints
+}
+
+// UnmarshalJSON unmarshals a segment length or a list of nested segment lengths.
+func (n *NestedUints) UnmarshalJSON(content []byte) error {
+	content = bytes.TrimSpace(content)
+	if len(content) > 0 && content[0] == '[' {
+		*n = NestedUints{IsArray: true}
+		return json.Unmarshal(content, &n.Values)
+	}
+	*n = NestedUints{}
+	return json.Unmarshal(content, &n.Value)
+}
+
+// MarshalJSON marshals the segment length or the list of nested segment lengths.
+func (n NestedUints) MarshalJSON() ([]byte, error) {
+	if n.IsArray {
+		if n.Values == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(n.Values)
+	}
+	return json.Marshal(n.Value)
+}
+
+// CompiledClassHash calculates the hash of a compiled class in the Casm format, i.e. the compiled class
+// hash of a declare v2 or v3 transaction. When the class holds its bytecode segment lengths (compilers
+// 2.6.0 and later), the bytecode is hashed as the Merkle-like structure of its segments as the sequencer does.
+// ref: https://github.com/starkware-libs/cairo-lang/blob/master/src/starkware/starknet/core/os/contract_class/compiled_class_hash.py
+//
+// Parameters:
+// - casmClass: A CasmClass object
+// Returns:
+// - *felt.Felt: the compiled class hash
+// - error: ErrBytecodeSegmentLengths if the segment lengths do not add up to the bytecode length
+func CompiledClassHash(casmClass CasmClass) (*felt.Felt, error) {
+	ContractClassVersionHash := new(felt.Felt).SetBytes([]byte("COMPILED_CLASS_V1"))
+	ExternalHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.External)
+	L1HandleHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.L1Handler)
+	ConstructorHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.Constructor)
+
+	ByteCodeHash := curve.Curve.PoseidonArray(casmClass.ByteCode...)
+	if casmClass.BytecodeSegmentLengths != nil {
+		hash, length, err := hashBytecodeSegments(casmClass.ByteCode, *casmClass.BytecodeSegmentLengths)
+		if err != nil {
+			return nil, err
+		}
+		if length != len(casmClass.ByteCode) {
+			return nil, fmt.Errorf("%w: %d segment felts for %d bytecode felts", ErrBytecodeSegmentLengths, length, len(casmClass.ByteCode))
+		}
+		ByteCodeHash = hash
+	}
+
+	return curve.Curve.PoseidonArray(ContractClassVersionHash, ExternalHash, L1HandleHash, ConstructorHash, ByteCodeHash), nil
+}
+
+// hashBytecodeSegments hashes the segments of the bytecode described by the segment lengths: a segment is
+// hashed as its bytecode, a list of segments as poseidon(length_1, hash_1, length_2, hash_2, ...) + 1.
+//
+// Parameters:
+// - bytecode: the bytecode starting at the segment
+// - lengths: the lengths of the segment
+// Returns:
+// - *felt.Felt: the hash of the segment
+// - int: the length of the segment
+// - error: ErrBytecodeSegmentLengths if the segment goes past the bytecode
+func hashBytecodeSegments(bytecode []*felt.Felt, lengths NestedUints) (*felt.Felt, int, error) {
+	if !lengths.IsArray {
+		if lengths.Value > uint64(len(bytecode)) {
+			return nil, 0, fmt.Errorf("%w: segment of %d felts past the end of the bytecode", ErrBytecodeSegmentLengths, lengths.Value)
+		}
+		return curve.Curve.PoseidonArray(bytecode[:lengths.Value]...), int(lengths.Value), nil
+	}
+
+	flattened := make([]*felt.Felt, 0, 2*len(lengths.Values))
+	total := 0
+	for _, segment := range lengths.Values {
+		hash, length, err := hashBytecodeSegments(bytecode[total:], segment)
+		if err != nil {
+			return nil, 0, err
+		}
+		flattened = append(flattened, new(felt.Felt).SetUint64(uint64(length)), hash)
+		total += length
+	}
+	hash := curve.Curve.PoseidonArray(flattened...)
+	return hash.Add(hash, new(felt.Felt).SetUint64(1)), total, nil
+}
+
+// hashCasmClassEntryPointByType calculates the hash of a CasmClassEntryPoint array.
+//
+// Parameters:
+// - entryPoint: An array of CasmClassEntryPoint objects
+// Returns:
+// - *felt.Felt: a pointer to a Felt type
+func hashCasmClassEntryPointByType(entryPoint []CasmClassEntryPoint) *felt.Felt {
+	flattened := make([]*felt.Felt, 0, len(entryPoint))
+	for _, elt := range entryPoint {
+		builtInFlat := []*felt.Felt{}
+		for _, builtIn := range elt.Builtins {
+			builtInFlat = append(builtInFlat, new(felt.Felt).SetBytes([]byte(builtIn)))
+		}
+		builtInHash := curve.Curve.PoseidonArray(builtInFlat...)
+		flattened = append(flattened, elt.Selector, new(felt.Felt).SetUint64(uint64(elt.Offset)), builtInHash)
+	}
+	return curve.Curve.PoseidonArray(flattened...)
+}
+
+// PrecomputeAddress calculates the precomputed address for a contract instance, as the Pedersen hash chain of the
+// STARKNET_CONTRACT_ADDRESS prefix, the deployer address, the salt, the class hash and the hash of the constructor
+// calldata, reduced below the 2^251 - 256 bound of the L2 addresses.
 // ref: https://github.com/starkware-libs/cairo-lang/blob/master/src/starkware/starknet/core/os/contract_address/contract_address.py
 //
 // Parameters:
@@ -61,11 +187,26 @@ func UnmarshalCasmClass(filePath string) (*CasmClass, error) {
 // Returns:
 // - *felt.Felt: the precomputed address as a *felt.Felt
 func PrecomputeAddress(deployerAddress *felt.Felt, salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) *felt.Felt {
-	return curve.PedersenArray(
+	rawAddress := curve.PedersenArray(
 		PREFIX_CONTRACT_ADDRESS,
 		deployerAddress,
 		salt,
 		classHash,
 		curve.PedersenArray(constructorCalldata...),
 	)
+	return utils.BigIntToFelt(new(big.Int).Mod(utils.FeltToBigInt(rawAddress), addressUpperBound))
+}
+
+// PrecomputeAccountAddress calculates the address of an account deploying itself with a deploy account
+// transaction, i.e. with a zero deployer address. The account can be funded at this address before
+// the transaction is sent.
+//
+// Parameters:
+// - salt: the contract address salt of the deploy account transaction
+// - classHash: the class hash of the account
+// - constructorCalldata: the constructor calldata of the account
+// Returns:
+// - *felt.Felt: the precomputed address as a *felt.Felt
+func PrecomputeAccountAddress(salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) *felt.Felt {
+	return PrecomputeAddress(&felt.Zero, salt, classHash, constructorCalldata)
 }
@@ -0,0 +1,96 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompiledClassHashSegments tests the hashing of the bytecode of a CASM class along its segment lengths.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestCompiledClassHashSegments(t *testing.T) {
+	casmClass, err := UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	unsegmented, err := CompiledClassHash(*casmClass)
+	require.NoError(t, err)
+
+	// a single segment holding the whole bytecode is hashed as the bytecode
+	casmClass.BytecodeSegmentLengths = &NestedUints{Value: uint64(len(casmClass.ByteCode))}
+	hash, err := CompiledClassHash(*casmClass)
+	require.NoError(t, err)
+	require.Equal(t, unsegmented, hash)
+
+	// [1, [2]] over the bytecode [a, b, c]
+	bytecode := []*felt.Felt{new(felt.Felt).SetUint64(10), new(felt.Felt).SetUint64(11), new(felt.Felt).SetUint64(12)}
+	var lengths NestedUints
+	require.NoError(t, json.Unmarshal([]byte(`[1, [2]]`), &lengths))
+	encoded, err := json.Marshal(lengths)
+	require.NoError(t, err)
+	require.JSONEq(t, `[1, [2]]`, string(encoded))
+
+	one := new(felt.Felt).SetUint64(1)
+	two := new(felt.Felt).SetUint64(2)
+	inner := curve.Curve.PoseidonArray(two, curve.Curve.PoseidonArray(bytecode[1:]...))
+	inner.Add(inner, one)
+	expected := curve.Curve.PoseidonArray(one, curve.Curve.PoseidonArray(bytecode[0]), two, inner)
+	expected.Add(expected, one)
+
+	segmentsHash, length, err := hashBytecodeSegments(bytecode, lengths)
+	require.NoError(t, err)
+	require.Equal(t, 3, length)
+	require.Equal(t, expected, segmentsHash)
+
+	// segment lengths not covering the bytecode
+	casmClass.ByteCode = bytecode
+	casmClass.BytecodeSegmentLengths = &NestedUints{IsArray: true, Values: []NestedUints{{Value: 2}}}
+	_, err = CompiledClassHash(*casmClass)
+	require.ErrorIs(t, err, ErrBytecodeSegmentLengths)
+	casmClass.BytecodeSegmentLengths = &NestedUints{IsArray: true, Values: []NestedUints{{Value: 2}, {Value: 2}}}
+	_, err = CompiledClassHash(*casmClass)
+	require.ErrorIs(t, err, ErrBytecodeSegmentLengths)
+}
+
+// TestUnmarshalSierraClassABI tests that the ABI of a Sierra class is read either as a string or as a JSON array.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestUnmarshalSierraClassABI(t *testing.T) {
+	var fromString, fromArray SierraClass
+	require.NoError(t, json.Unmarshal([]byte(`{"sierra_program": ["0x1"], "contract_class_version": "0.1.0", "abi": "[{\"type\":\"event\"}]"}`), &fromString))
+	require.NoError(t, json.Unmarshal([]byte(`{"sierra_program": ["0x1"], "contract_class_version": "0.1.0", "abi": [ {"type": "event"} ]}`), &fromArray))
+	require.Equal(t, `[{"type":"event"}]`, fromString.ABI)
+	require.Equal(t, fromString, fromArray)
+	require.Equal(t, fromString.ABI, fromString.ContractClass().ABI)
+}
+
+// TestPrecomputeAccountAddress tests the address of an account deploying itself against a deployed account.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestPrecomputeAccountAddress(t *testing.T) {
+	salt, err := new(felt.Felt).SetString("0x0702e82f1ec15656ad4502268dad530197141f3b59f5529835af9318ef399da5")
+	require.NoError(t, err)
+	classHash, err := new(felt.Felt).SetString("0x064728e0c0713811c751930f8d3292d683c23f107c89b0a101425d9e80adb1c0")
+	require.NoError(t, err)
+	publicKey, err := new(felt.Felt).SetString("0x022f3e55b61d86c2ac5239fa3b3b8761f26b9a5c0b5f61ddbd5d756ced498b46")
+	require.NoError(t, err)
+
+	address := PrecomputeAccountAddress(salt, classHash, []*felt.Felt{publicKey})
+	require.Equal(t, "0x31463b5263a6631be4d1fe92d64d13e3a8498c440bf789e69ccb951eb8ad5da", address.String())
+	require.Equal(t, address, PrecomputeAddress(&felt.Zero, salt, classHash, []*felt.Felt{publicKey}))
+	require.NotEqual(t, address, PrecomputeAddress(new(felt.Felt).SetUint64(1), salt, classHash, []*felt.Felt{publicKey}))
+}
@@ -0,0 +1,222 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var ErrEventNotInABI = errors.New("event selector not found in the ABI")
+
+// DecodedEvent is an event of a receipt or a trace decoded with the ABI of the emitting contract.
+type DecodedEvent struct {
+	// The event as emitted
+	rpc.Event
+	// The name of the struct event in the ABI, e.g. openzeppelin::token::erc20::ERC20Component::Transfer, empty
+	// if the event could not be decoded
+	Name string
+	// The members of the event by name, keys and data alike, decoded as the Args of a DecodedCall, nil if the
+	// event could not be decoded
+	Args map[string]any
+}
+
+// DecodeEvent decodes the keys and data of an event, e.g. an event of a receipt, into the named members of its
+// event in the ABI. The events of a Cairo 1 contract are the variants of its Event enum: the first key is the
+// selector of the variant name, followed by the selectors of the nested enum variants, the "flat" variants
+// adding none, and then the members of the struct event marked as keys, while its other members are the data.
+// An event is also matched by the name of a struct event alone, e.g. for an ABI missing the Event enum, or
+// for a Cairo 0 event.
+//
+// Parameters:
+// - abi: the JSON ABI of the emitting contract (e.g. ContractClass.ABI)
+// - event: the event to decode
+// Returns:
+// - *DecodedEvent: the decoded event
+// - error: ErrEventNotInABI if no event of the ABI has the selector of the event, or an error if the keys
+// or data of the event do not match its members
+func DecodeEvent(abi []byte, event rpc.Event) (*DecodedEvent, error) {
+	var entries rpc.ABI
+	if err := json.Unmarshal(abi, &entries); err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	return newEventDecoder(entries).decodeEvent(event)
+}
+
+// DecodeEvents decodes the events emitted by every invocation of a trace, including the inner calls, with the
+// events of an ABI as DecodeEvent does, whatever the contract emitting them. The events are returned in
+// execution order (see rpc.CollectEvents), with the address of the emitting contract as FromAddress. The events
+// that are not in the ABI or do not match its members are returned undecoded rather than dropped.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// - abi: the JSON ABI the events are decoded with (e.g. ContractClass.ABI)
+// Returns:
+// - []DecodedEvent: the events of the trace, decoded where possible
+// - error: an error if the ABI is invalid, or rpc.ErrUnknownTraceType if the trace is not a transaction trace
+func DecodeEvents(trace rpc.TxnTrace, abi []byte) ([]DecodedEvent, error) {
+	var entries rpc.ABI
+	if err := json.Unmarshal(abi, &entries); err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	events, err := rpc.CollectEvents(trace)
+	if err != nil {
+		return nil, err
+	}
+	decoder := newEventDecoder(entries)
+	decoded := make([]DecodedEvent, len(events))
+	for i, event := range events {
+		decoded[i] = decoder.decodeOrKeep(event)
+	}
+	return decoded, nil
+}
+
+// eventDecoder decodes events according to the events and the types of an ABI.
+type eventDecoder struct {
+	*calldataDecoder
+	events map[string]*rpc.EventABIEntry
+	// the events in the order of the ABI
+	ordered []*rpc.EventABIEntry
+}
+
+func newEventDecoder(abi rpc.ABI) *eventDecoder {
+	decoder := &eventDecoder{calldataDecoder: newCalldataDecoder(abi), events: map[string]*rpc.EventABIEntry{}}
+	for _, entry := range abi {
+		if event, ok := entry.(*rpc.EventABIEntry); ok {
+			decoder.events[event.Name] = event
+			decoder.ordered = append(decoder.ordered, event)
+		}
+	}
+	return decoder
+}
+
+// decodeEvent decodes the event with the events of the ABI, see DecodeEvent.
+func (d *eventDecoder) decodeEvent(event rpc.Event) (*DecodedEvent, error) {
+	if len(event.Keys) == 0 {
+		return nil, fmt.Errorf("%w: the event has no keys", ErrEventNotInABI)
+	}
+	for _, root := range d.roots() {
+		decoded, matched, err := d.decodeEnum(root, event.Keys, event.Data)
+		if matched {
+			return withEvent(decoded, event), err
+		}
+	}
+	for _, entry := range d.ordered {
+		if entry.Kind == "enum" || utils.GetSelectorFromNameFelt(shortEventName(entry.Name)).String() != event.Keys[0].String() {
+			continue
+		}
+		decoded, err := d.decodeStruct(entry, event.Keys[1:], event.Data)
+		return withEvent(decoded, event), err
+	}
+	return nil, fmt.Errorf("%w: %s", ErrEventNotInABI, event.Keys[0])
+}
+
+// decodeOrKeep decodes the event, or returns it undecoded if it is not in the ABI or does not match its members.
+func (d *eventDecoder) decodeOrKeep(event rpc.Event) DecodedEvent {
+	decoded, err := d.decodeEvent(event)
+	if err != nil {
+		return DecodedEvent{Event: event}
+	}
+	return *decoded
+}
+
+// withEvent sets the event a decoded event was decoded from, nil for a failed decoding.
+func withEvent(decoded *DecodedEvent, event rpc.Event) *DecodedEvent {
+	if decoded != nil {
+		decoded.Event = event
+	}
+	return decoded
+}
+
+// roots returns the enum events that are not a variant of another enum event, i.e. the Event enum of the
+// contract, in the order of the ABI.
+func (d *eventDecoder) roots() []*rpc.EventABIEntry {
+	variants := map[string]bool{}
+	for _, entry := range d.ordered {
+		for _, variant := range entry.Variants {
+			variants[variant.Type] = true
+		}
+	}
+	var roots []*rpc.EventABIEntry
+	for _, entry := range d.ordered {
+		if entry.Kind == "enum" && !variants[entry.Name] {
+			roots = append(roots, entry)
+		}
+	}
+	return roots
+}
+
+// decodeEnum decodes the event as a variant of the enum event, and reports whether a variant matched the
+// selectors of the keys.
+func (d *eventDecoder) decodeEnum(entry *rpc.EventABIEntry, keys, data []*felt.Felt) (*DecodedEvent, bool, error) {
+	for _, variant := range entry.Variants {
+		inner, ok := d.events[variant.Type]
+		if !ok {
+			continue
+		}
+		rest := keys
+		if variant.Kind != "flat" {
+			if len(keys) == 0 || utils.GetSelectorFromNameFelt(variant.Name).String() != keys[0].String() {
+				continue
+			}
+			rest = keys[1:]
+		}
+		if inner.Kind == "enum" {
+			if decoded, matched, err := d.decodeEnum(inner, rest, data); matched {
+				return decoded, true, err
+			}
+			continue
+		}
+		decoded, err := d.decodeStruct(inner, rest, data)
+		return decoded, true, err
+	}
+	return nil, false, nil
+}
+
+// decodeStruct decodes the keys, after the selectors, and the data of the event into the members of the
+// struct event.
+func (d *eventDecoder) decodeStruct(entry *rpc.EventABIEntry, keys, data []*felt.Felt) (*DecodedEvent, error) {
+	keyMembers, dataMembers := entry.Keys, entry.Data
+	for _, member := range entry.Members {
+		switch member.Kind {
+		case "key":
+			keyMembers = append(keyMembers, member.TypedParameter)
+		case "data":
+			dataMembers = append(dataMembers, member.TypedParameter)
+		default:
+			return nil, fmt.Errorf("%s.%s: unsupported member kind %q", entry.Name, member.Name, member.Kind)
+		}
+	}
+	args := make(map[string]any, len(keyMembers)+len(dataMembers))
+	for _, members := range []struct {
+		params []rpc.TypedParameter
+		felts  []*felt.Felt
+		part   string
+	}{{keyMembers, keys, "keys"}, {dataMembers, data, "data"}} {
+		felts := members.felts
+		for _, member := range members.params {
+			value, rest, err := d.decode(member.Type, felts)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", entry.Name, member.Name, err)
+			}
+			args[member.Name] = value
+			felts = rest
+		}
+		if len(felts) != 0 {
+			return nil, fmt.Errorf("%s: %d unexpected felts after the last member of the %s", entry.Name, len(felts), members.part)
+		}
+	}
+	return &DecodedEvent{Name: entry.Name, Args: args}, nil
+}
+
+// shortEventName returns the name of an event without its module path, whose selector is the one of the event.
+func shortEventName(name string) string {
+	if i := strings.LastIndex(name, "::"); i >= 0 {
+		return name[i+2:]
+	}
+	return name
+}
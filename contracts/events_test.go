@@ -0,0 +1,225 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/mocks"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testEventsABI = `[
+	{"type": "struct", "name": "core::integer::u256", "members": [
+		{"name": "low", "type": "core::integer::u128"},
+		{"name": "high", "type": "core::integer::u128"}
+	]},
+	{"type": "struct", "name": "example::Order", "members": [
+		{"name": "id", "type": "core::felt252"},
+		{"name": "amounts", "type": "core::array::Span::<core::integer::u256>"}
+	]},
+	{"type": "event", "name": "openzeppelin::token::erc20::ERC20Component::Transfer", "kind": "struct", "members": [
+		{"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+		{"name": "to", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+		{"name": "value", "type": "core::integer::u256", "kind": "data"}
+	]},
+	{"type": "event", "name": "openzeppelin::token::erc20::ERC20Component::Approval", "kind": "struct", "members": [
+		{"name": "owner", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+		{"name": "spender", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+		{"name": "value", "type": "core::integer::u256", "kind": "data"}
+	]},
+	{"type": "event", "name": "openzeppelin::token::erc20::ERC20Component::Event", "kind": "enum", "variants": [
+		{"name": "Transfer", "type": "openzeppelin::token::erc20::ERC20Component::Transfer", "kind": "nested"},
+		{"name": "Approval", "type": "openzeppelin::token::erc20::ERC20Component::Approval", "kind": "nested"}
+	]},
+	{"type": "event", "name": "example::Exchange::OrderPlaced", "kind": "struct", "members": [
+		{"name": "trader", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+		{"name": "order", "type": "example::Order", "kind": "data"},
+		{"name": "post_only", "type": "core::bool", "kind": "data"}
+	]},
+	{"type": "event", "name": "example::Exchange::Event", "kind": "enum", "variants": [
+		{"name": "ERC20Event", "type": "openzeppelin::token::erc20::ERC20Component::Event", "kind": "flat"},
+		{"name": "OrderPlaced", "type": "example::Exchange::OrderPlaced", "kind": "nested"}
+	]}
+]`
+
+// TestDecodeEvent tests the decoding of the events of a Cairo 1 contract embedding the ERC20 component, and of
+// an event missing from the ABI.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestDecodeEvent(t *testing.T) {
+	from := utils.TestHexToFelt(t, "0x1234")
+	to := utils.TestHexToFelt(t, "0x5678")
+	felts := func(values ...uint64) []*felt.Felt {
+		result := make([]*felt.Felt, len(values))
+		for i, value := range values {
+			result[i] = new(felt.Felt).SetUint64(value)
+		}
+		return result
+	}
+
+	type testSetType struct {
+		Event        rpc.Event
+		ExpectedName string
+		ExpectedArgs map[string]any
+	}
+	testSet := []testSetType{
+		{
+			// Transfer of 2^128 + 5 through the flat ERC20Event variant
+			Event:        rpc.Event{Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer"), from, to}, Data: felts(5, 1)},
+			ExpectedName: "openzeppelin::token::erc20::ERC20Component::Transfer",
+			ExpectedArgs: map[string]any{
+				"from":  from,
+				"to":    to,
+				"value": new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5)),
+			},
+		},
+		{
+			Event:        rpc.Event{Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Approval"), from, to}, Data: felts(7, 0)},
+			ExpectedName: "openzeppelin::token::erc20::ERC20Component::Approval",
+			ExpectedArgs: map[string]any{"owner": from, "spender": to, "value": big.NewInt(7)},
+		},
+		{
+			// OrderPlaced(trader, Order{id: 9, amounts: [1, 2]}, true)
+			Event:        rpc.Event{Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("OrderPlaced"), from}, Data: felts(9, 2, 1, 0, 2, 0, 1)},
+			ExpectedName: "example::Exchange::OrderPlaced",
+			ExpectedArgs: map[string]any{
+				"trader":    from,
+				"order":     map[string]any{"id": new(felt.Felt).SetUint64(9), "amounts": []any{big.NewInt(1), big.NewInt(2)}},
+				"post_only": true,
+			},
+		},
+	}
+
+	for _, test := range testSet {
+		decoded, err := DecodeEvent([]byte(testEventsABI), test.Event)
+		require.NoError(t, err)
+		require.Equal(t, test.Event, decoded.Event)
+		require.Equal(t, test.ExpectedName, decoded.Name)
+		require.Equal(t, test.ExpectedArgs, decoded.Args)
+	}
+
+	unknown := utils.GetSelectorFromNameFelt("Burn")
+	_, err := DecodeEvent([]byte(testEventsABI), rpc.Event{Keys: []*felt.Felt{unknown}})
+	require.ErrorIs(t, err, ErrEventNotInABI)
+	require.ErrorContains(t, err, unknown.String())
+
+	// a Transfer missing the high felt of its value
+	_, err = DecodeEvent([]byte(testEventsABI), rpc.Event{Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer"), from, to}, Data: felts(5)})
+	require.ErrorIs(t, err, ErrCalldataTooShort)
+}
+
+// TestDecodeEvents tests that the events of every invocation of a trace are decoded with an ABI in execution
+// order, and that the events not in the ABI or not matching its members are kept undecoded.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestDecodeEvents(t *testing.T) {
+	transfer := utils.GetSelectorFromNameFelt("Transfer").String()
+	var trace rpc.InvokeTxnTrace
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "INVOKE",
+		"execute_invocation": {
+			"contract_address": "0xa", "calldata": [], "result": [], "messages": [], "execution_resources": {"steps": 1},
+			"events": [{"order": 1, "keys": ["`+transfer+`", "0x5", "0x9"], "data": ["0x64", "0x0"]}],
+			"calls": [{
+				"contract_address": "0xb", "calldata": [], "result": [], "messages": [], "calls": [], "execution_resources": {"steps": 1},
+				"events": [{"order": 0, "keys": ["0x1234"], "data": ["0x1"]}]
+			}]
+		},
+		"fee_transfer_invocation": {
+			"contract_address": "0xfee", "calldata": [], "result": [], "messages": [], "calls": [], "execution_resources": {"steps": 1},
+			"events": [{"order": 0, "keys": ["`+transfer+`", "0x5"], "data": ["0x10", "0x0"]}]
+		},
+		"execution_resources": {"steps": 3, "data_availability": {"l1_gas": 0, "l1_data_gas": 0}}
+	}`), &trace))
+
+	events, err := DecodeEvents(&trace, []byte(testEventsABI))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	// the event of the inner call is emitted first
+	require.Equal(t, utils.TestHexToFelt(t, "0xb"), events[0].FromAddress)
+	require.Empty(t, events[0].Name)
+	require.Nil(t, events[0].Args)
+
+	require.Equal(t, utils.TestHexToFelt(t, "0xa"), events[1].FromAddress)
+	require.Equal(t, "openzeppelin::token::erc20::ERC20Component::Transfer", events[1].Name)
+	require.Equal(t, map[string]any{
+		"from":  utils.TestHexToFelt(t, "0x5"),
+		"to":    utils.TestHexToFelt(t, "0x9"),
+		"value": big.NewInt(100),
+	}, events[1].Args)
+
+	// the fee transfer event misses a key of the ABI event
+	require.Equal(t, utils.TestHexToFelt(t, "0xfee"), events[2].FromAddress)
+	require.Empty(t, events[2].Name)
+	require.Len(t, events[2].Keys, 2)
+
+	events, err = DecodeEvents(rpc.L1HandlerTxnTrace{}, []byte(testEventsABI))
+	require.NoError(t, err)
+	require.Empty(t, events)
+	_, err = DecodeEvents("not a trace", []byte(testEventsABI))
+	require.ErrorIs(t, err, rpc.ErrUnknownTraceType)
+}
+
+// TestTransactionDetail tests that TransactionDetail decodes the events of the receipt with the ABI of the class
+// of their emitter at the block of the transaction, and leaves the events of a contract without class undecoded.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestTransactionDetail(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	provider := mocks.NewMockRpcProvider(mockCtrl)
+
+	ctx := context.Background()
+	txHash := utils.TestHexToFelt(t, "0x1234")
+	token, noClass := utils.TestHexToFelt(t, "0xe20"), utils.TestHexToFelt(t, "0xdead")
+	from, to := utils.TestHexToFelt(t, "0x5"), utils.TestHexToFelt(t, "0x9")
+	transfer := rpc.Event{
+		FromAddress: token,
+		Keys:        []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer"), from, to},
+		Data:        []*felt.Felt{new(felt.Felt).SetUint64(100), new(felt.Felt)},
+	}
+	unknown := rpc.Event{FromAddress: noClass, Keys: []*felt.Felt{new(felt.Felt).SetUint64(1)}, Data: []*felt.Felt{}}
+	blockHash := utils.TestHexToFelt(t, "0xb10c")
+	receipt := &rpc.TransactionReceiptWithBlockInfo{
+		TransactionReceipt: rpc.TransactionReceipt{TransactionHash: txHash, Events: []rpc.Event{transfer, unknown, transfer}},
+		BlockHash:          blockHash,
+	}
+
+	provider.EXPECT().TransactionByHash(gomock.Any(), txHash).Return(&rpc.BlockTransaction{}, nil)
+	provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(receipt, nil)
+	provider.EXPECT().TraceTransaction(gomock.Any(), txHash).Return(nil, rpc.ErrNoTraceAvailable)
+	// the class of each emitter is fetched once
+	provider.EXPECT().ClassAt(ctx, rpc.WithBlockHash(blockHash), token).Return(&rpc.ContractClass{ABI: testEventsABI}, nil)
+	provider.EXPECT().ClassAt(ctx, rpc.WithBlockHash(blockHash), noClass).Return(nil, rpc.ErrContractNotFound)
+
+	details, err := TransactionDetail(ctx, provider, txHash)
+	require.NoError(t, err)
+	require.Equal(t, receipt, details.Receipt)
+	require.Nil(t, details.Trace)
+	require.Len(t, details.Events, 3)
+	for _, i := range []int{0, 2} {
+		require.Equal(t, transfer, details.Events[i].Event)
+		require.Equal(t, "openzeppelin::token::erc20::ERC20Component::Transfer", details.Events[i].Name)
+		require.Equal(t, map[string]any{"from": from, "to": to, "value": big.NewInt(100)}, details.Events[i].Args)
+	}
+	require.Equal(t, DecodedEvent{Event: unknown}, details.Events[1])
+}
@@ -0,0 +1,374 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrFunctionNotInABI = errors.New("function not found in the ABI")
+	ErrOutputTooShort   = errors.New("call output too short")
+)
+
+var (
+	feltType   = reflect.TypeOf(felt.Felt{})
+	bigIntType = reflect.TypeOf(big.Int{})
+)
+
+// DecodeReturn decodes the output of a call, e.g. as returned by Provider.Call, into dest according to the
+// return types of the function in the ABI. The single return value of a Cairo 1 function is decoded into dest,
+// the several named return values of a Cairo 0 function into the fields of a dest struct.
+// The values are decoded into the Go types as follows:
+//   - felts, integers, addresses and class hashes into felt.Felt, big.Int or a Go integer type large enough
+//   - u256 and Uint256 into big.Int, felt.Felt or a Go integer type large enough
+//   - bool into bool
+//   - arrays and spans, prefixed by their length, into slices, or arrays of the same length
+//   - tuples into slices, arrays or the fields of a struct in order
+//   - structs into the fields of a struct, matched by their abi tag (e.g. `abi:"pending_word"`) or else by their
+//     name ignoring case and underscores, the members without a field being skipped
+//
+// A pointer is allocated when nil and decoded into, and an interface{} or a map[string]any is filled with the
+// value decoded as the Args of a DecodedCall, which is also the only way to decode an enum.
+//
+// Parameters:
+// - abi: the JSON ABI of the called contract (e.g. ContractClass.ABI)
+// - functionName: the name of the called function
+// - output: the output of the call
+// - dest: a pointer to the value to decode the output into
+// Returns:
+// - error: ErrFunctionNotInABI if the function is missing, ErrOutputTooShort with the offset of the felt
+// missing if the output is too short, or an error if the output or dest do not match the return types
+func DecodeReturn(abi []byte, functionName string, output []*felt.Felt, dest interface{}) error {
+	var entries rpc.ABI
+	if err := json.Unmarshal(abi, &entries); err != nil {
+		return fmt.Errorf("invalid ABI: %w", err)
+	}
+	function, ok := entries.Function(functionName)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrFunctionNotInABI, functionName)
+	}
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("dest must be a non-nil pointer, got %T", dest)
+	}
+
+	decoder := &returnDecoder{calldataDecoder: newCalldataDecoder(entries), output: output}
+	if err := decoder.decodeOutputs(function.Outputs, value.Elem()); err != nil {
+		return fmt.Errorf("%s: %w", functionName, err)
+	}
+	if decoder.offset != len(output) {
+		return fmt.Errorf("%s: %d unexpected felts after the return values", functionName, len(output)-decoder.offset)
+	}
+	return nil
+}
+
+// returnDecoder decodes the output of a call into Go values, keeping the offset of the next felt to decode.
+type returnDecoder struct {
+	*calldataDecoder
+	output []*felt.Felt
+	offset int
+}
+
+// decodeOutputs decodes the return values of a function into the value.
+func (d *returnDecoder) decodeOutputs(outputs []rpc.TypedParameter, v reflect.Value) error {
+	switch {
+	case len(outputs) == 0:
+		return nil
+	case len(outputs) == 1 && !strings.HasSuffix(outputs[0].Type, "*"):
+		return d.fill(outputs[0].Type, v)
+	case isGenericValue(v):
+		args, err := d.decodeInputs(outputs, d.output[d.offset:])
+		if err != nil {
+			return fmt.Errorf("from offset %d: %w", d.offset, err)
+		}
+		d.offset = len(d.output)
+		v.Set(reflect.ValueOf(args))
+		return nil
+	case v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decodeOutputs(outputs, v.Elem())
+	case v.Kind() != reflect.Struct:
+		return fmt.Errorf("cannot decode %d return values into %s", len(outputs), v.Type())
+	}
+
+	// Cairo 0 arrays are preceded by their length in a <name>_len output
+	lengths := map[string]*felt.Felt{}
+	for _, output := range outputs {
+		field := structField(v, output.Name)
+		if d.offset < len(d.output) {
+			lengths[output.Name] = d.output[d.offset]
+		}
+		var err error
+		if elemType, ok := strings.CutSuffix(output.Type, "*"); ok {
+			length, ok := lengths[output.Name+"_len"]
+			if !ok {
+				return fmt.Errorf("missing length of the array %s", output.Name)
+			}
+			err = d.fillArray(elemType, utils.FeltToBigInt(length), field)
+		} else {
+			err = d.fill(output.Type, field)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", output.Name, err)
+		}
+	}
+	return nil
+}
+
+// fill decodes a value of the given type from the output into v.
+func (d *returnDecoder) fill(typ string, v reflect.Value) error {
+	typ = strings.TrimSpace(typ)
+	if isGenericValue(v) {
+		value, rest, err := d.decode(typ, d.output[d.offset:])
+		if errors.Is(err, ErrCalldataTooShort) {
+			return fmt.Errorf("%w: %s from offset %d", ErrOutputTooShort, typ, d.offset)
+		}
+		if err != nil {
+			return fmt.Errorf("from offset %d: %w", d.offset, err)
+		}
+		d.offset = len(d.output) - len(rest)
+		if value != nil {
+			v.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.fill(typ, v.Elem())
+	}
+
+	switch {
+	case typ == "()":
+		return nil
+	case typ == "core::integer::u256" || typ == "Uint256":
+		felts, err := d.take(typ, 2)
+		if err != nil {
+			return err
+		}
+		low, high := utils.FeltToBigInt(felts[0]), utils.FeltToBigInt(felts[1])
+		return setBigInt(v, new(big.Int).Add(new(big.Int).Lsh(high, 128), low), typ)
+	case typ == "core::bool":
+		felts, err := d.take(typ, 1)
+		if err != nil {
+			return err
+		}
+		if v.Kind() != reflect.Bool {
+			return fmt.Errorf("cannot decode %s into %s", typ, v.Type())
+		}
+		v.SetBool(!felts[0].IsZero())
+		return nil
+	case strings.HasPrefix(typ, "("):
+		return d.fillTuple(typ, v)
+	}
+	if inner, ok := genericArgument(typ, "core::zeroable::NonZero::<"); ok {
+		start := d.offset
+		if err := d.fill(inner, v); err != nil {
+			return err
+		}
+		for _, f := range d.output[start:d.offset] {
+			if !f.IsZero() {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: NonZero::<%s> at offset %d", ErrZeroNonZero, inner, start)
+	}
+	if bounds, ok := genericArgument(typ, "core::internal::bounded_int::BoundedInt::<"); ok {
+		if _, _, err := decodeBoundedInt(bounds, d.output[d.offset:]); errors.Is(err, ErrCalldataTooShort) {
+			return fmt.Errorf("%w: %s needs 1 felt at offset %d", ErrOutputTooShort, typ, d.offset)
+		} else if err != nil {
+			return fmt.Errorf("at offset %d: %w", d.offset, err)
+		}
+		return d.fill("core::felt252", v)
+	}
+	if elemType, ok := arrayElementType(typ); ok {
+		felts, err := d.take(typ, 1)
+		if err != nil {
+			return err
+		}
+		return d.fillArray(elemType, utils.FeltToBigInt(felts[0]), v)
+	}
+	if entry, ok := d.structs[typ]; ok {
+		if v.Kind() != reflect.Struct || v.Type() == feltType || v.Type() == bigIntType {
+			return fmt.Errorf("cannot decode %s into %s", typ, v.Type())
+		}
+		for _, member := range entry.Members {
+			if err := d.fill(member.Type, structField(v, member.Name)); err != nil {
+				return fmt.Errorf("%s.%s: %w", typ, member.Name, err)
+			}
+		}
+		return nil
+	}
+	if _, ok := d.enums[typ]; ok {
+		return fmt.Errorf("cannot decode the enum %s into %s, only into an interface{} or a map[string]any", typ, v.Type())
+	}
+	// felts, integers, addresses, class hashes and the other single felt types
+	felts, err := d.take(typ, 1)
+	if err != nil {
+		return err
+	}
+	return setBigInt(v, utils.FeltToBigInt(felts[0]), typ)
+}
+
+// fillArray decodes length values of the element type from the output into the slice or array v.
+func (d *returnDecoder) fillArray(elemType string, length *big.Int, v reflect.Value) error {
+	if !length.IsInt64() || length.Int64() > int64(len(d.output)-d.offset) {
+		return fmt.Errorf("%w: %s elements of %s from offset %d", ErrOutputTooShort, length, elemType, d.offset)
+	}
+	n := int(length.Int64())
+	switch {
+	case isGenericValue(v):
+		values := make([]any, n)
+		v.Set(reflect.ValueOf(values))
+		v = reflect.ValueOf(values)
+	case v.Kind() == reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.fillArray(elemType, length, v.Elem())
+	case v.Kind() == reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	case v.Kind() == reflect.Array:
+		if v.Len() != n {
+			return fmt.Errorf("cannot decode %d elements of %s into %s", n, elemType, v.Type())
+		}
+	default:
+		return fmt.Errorf("cannot decode an array of %s into %s", elemType, v.Type())
+	}
+	for i := 0; i < n; i++ {
+		if err := d.fill(elemType, v.Index(i)); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// fillTuple decodes a tuple type such as (core::felt252, core::bool) from the output into the slice, array or
+// struct v.
+func (d *returnDecoder) fillTuple(typ string, v reflect.Value) error {
+	inner, ok := strings.CutSuffix(strings.TrimPrefix(typ, "("), ")")
+	if !ok {
+		return fmt.Errorf("invalid tuple type %s", typ)
+	}
+	elemTypes := splitTopLevel(inner)
+	var elem func(i int) reflect.Value
+	switch v.Kind() {
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), len(elemTypes), len(elemTypes)))
+		elem = v.Index
+	case reflect.Array:
+		if v.Len() != len(elemTypes) {
+			return fmt.Errorf("cannot decode %s into %s", typ, v.Type())
+		}
+		elem = v.Index
+	case reflect.Struct:
+		fields := exportedFields(v.Type())
+		if len(fields) != len(elemTypes) {
+			return fmt.Errorf("cannot decode %s into %s", typ, v.Type())
+		}
+		elem = func(i int) reflect.Value { return v.Field(fields[i]) }
+	default:
+		return fmt.Errorf("cannot decode %s into %s", typ, v.Type())
+	}
+	for i, elemType := range elemTypes {
+		if err := d.fill(elemType, elem(i)); err != nil {
+			return fmt.Errorf("%s.%d: %w", typ, i, err)
+		}
+	}
+	return nil
+}
+
+// take returns the next n felts of the output, or ErrOutputTooShort with the offset of the value of the type.
+func (d *returnDecoder) take(typ string, n int) ([]*felt.Felt, error) {
+	if len(d.output)-d.offset < n {
+		return nil, fmt.Errorf("%w: %s needs %d felts at offset %d, %d left", ErrOutputTooShort, typ, n, d.offset, len(d.output)-d.offset)
+	}
+	felts := d.output[d.offset : d.offset+n]
+	d.offset += n
+	return felts, nil
+}
+
+// setBigInt sets the felt.Felt, big.Int or Go integer v to the value of the type.
+func setBigInt(v reflect.Value, value *big.Int, typ string) error {
+	switch {
+	case v.Type() == bigIntType:
+		v.Addr().Interface().(*big.Int).Set(value)
+		return nil
+	case v.Type() == feltType:
+		f, err := new(felt.Felt).SetString("0x" + value.Text(16))
+		if err != nil {
+			return fmt.Errorf("%s %s does not fit in a felt", typ, value)
+		}
+		v.Addr().Interface().(*felt.Felt).Set(f)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !value.IsUint64() || v.OverflowUint(value.Uint64()) {
+			return fmt.Errorf("%s %s overflows %s", typ, value, v.Type())
+		}
+		v.SetUint(value.Uint64())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !value.IsInt64() || v.OverflowInt(value.Int64()) {
+			return fmt.Errorf("%s %s overflows %s", typ, value, v.Type())
+		}
+		v.SetInt(value.Int64())
+		return nil
+	}
+	return fmt.Errorf("cannot decode %s into %s", typ, v.Type())
+}
+
+// isGenericValue reports whether v is an interface{} or a map[string]any, filled with the value decoded as
+// the Args of a DecodedCall.
+func isGenericValue(v reflect.Value) bool {
+	t := v.Type()
+	return (t.Kind() == reflect.Interface && t.NumMethod() == 0) ||
+		(t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.Interface && t.Elem().NumMethod() == 0)
+}
+
+// structField returns the field of the struct v decoding the member name: the field tagged abi:"<name>", or else
+// the field of the same name ignoring case and underscores, or a new interface{} discarding the member.
+func structField(v reflect.Value, name string) reflect.Value {
+	normalized := strings.ReplaceAll(name, "_", "")
+	var byName reflect.Value
+	for _, i := range exportedFields(v.Type()) {
+		field := v.Type().Field(i)
+		if tag, ok := field.Tag.Lookup("abi"); ok {
+			if tag == name {
+				return v.Field(i)
+			}
+			continue
+		}
+		if !byName.IsValid() && strings.EqualFold(strings.ReplaceAll(field.Name, "_", ""), normalized) {
+			byName = v.Field(i)
+		}
+	}
+	if byName.IsValid() {
+		return byName
+	}
+	var discard interface{}
+	return reflect.ValueOf(&discard).Elem()
+}
+
+// exportedFields returns the indexes of the exported fields of the struct type.
+func exportedFields(t reflect.Type) []int {
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,108 @@
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+const testReturnsABI = `[
+	{"type": "struct", "name": "core::integer::u256", "members": [
+		{"name": "low", "type": "core::integer::u128"},
+		{"name": "high", "type": "core::integer::u128"}
+	]},
+	{"type": "struct", "name": "example::Position", "members": [
+		{"name": "owner", "type": "core::starknet::contract_address::ContractAddress"},
+		{"name": "size", "type": "core::integer::u256"},
+		{"name": "is_open", "type": "core::bool"},
+		{"name": "fills", "type": "core::array::Array::<core::integer::u64>"}
+	]},
+	{"type": "struct", "name": "example::Account", "members": [
+		{"name": "id", "type": "core::felt252"},
+		{"name": "position", "type": "example::Position"}
+	]},
+	{"type": "interface", "name": "example::IExchange", "items": [
+		{"type": "function", "name": "get_account", "inputs": [], "outputs": [{"type": "example::Account"}], "state_mutability": "view"},
+		{"type": "function", "name": "get_pair", "inputs": [], "outputs": [{"type": "(core::felt252, core::bool)"}], "state_mutability": "view"},
+		{"type": "function", "name": "balance_of", "inputs": [], "outputs": [{"type": "core::integer::u256"}], "state_mutability": "view"}
+	]},
+	{"type": "function", "name": "get_values", "inputs": [], "outputs": [
+		{"name": "total", "type": "felt"},
+		{"name": "values_len", "type": "felt"},
+		{"name": "values", "type": "felt*"}
+	]}
+]`
+
+// TestDecodeReturn tests the decoding of the outputs of calls into Go values, and the offset reported when the
+// output is too short.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestDecodeReturn(t *testing.T) {
+	felts := func(values ...uint64) []*felt.Felt {
+		result := make([]*felt.Felt, len(values))
+		for i, value := range values {
+			result[i] = new(felt.Felt).SetUint64(value)
+		}
+		return result
+	}
+	abi := []byte(testReturnsABI)
+
+	type position struct {
+		Owner  *felt.Felt
+		Size   big.Int
+		IsOpen bool `abi:"is_open"`
+		Fills  []uint64
+	}
+	var account struct {
+		ID       uint64
+		Position position
+	}
+	// id, owner, size (low, high), is_open, fills
+	require.NoError(t, DecodeReturn(abi, "get_account", felts(7, 0x1234, 5, 1, 1, 2, 10, 20), &account))
+	require.Equal(t, uint64(7), account.ID)
+	require.Equal(t, "0x1234", account.Position.Owner.String())
+	require.Equal(t, new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5)), &account.Position.Size)
+	require.True(t, account.Position.IsOpen)
+	require.Equal(t, []uint64{10, 20}, account.Position.Fills)
+
+	var pair struct {
+		Key felt.Felt
+		Ok  bool
+	}
+	require.NoError(t, DecodeReturn(abi, "get_pair", felts(3, 1), &pair))
+	require.Equal(t, "0x3", pair.Key.String())
+	require.True(t, pair.Ok)
+
+	var balance uint8
+	err := DecodeReturn(abi, "balance_of", felts(256, 0), &balance)
+	require.ErrorContains(t, err, "overflows uint8")
+
+	var values struct {
+		Total  uint64
+		Values []*felt.Felt
+	}
+	require.NoError(t, DecodeReturn(abi, "get_values", felts(30, 2, 10, 20), &values))
+	require.Equal(t, uint64(30), values.Total)
+	require.Equal(t, felts(10, 20), values.Values)
+
+	var generic interface{}
+	require.NoError(t, DecodeReturn(abi, "get_pair", felts(3, 0), &generic))
+	require.NotNil(t, generic)
+
+	// the fills of the position are missing their second element
+	err = DecodeReturn(abi, "get_account", felts(7, 0x1234, 5, 1, 1, 2, 10), &account)
+	require.ErrorIs(t, err, ErrOutputTooShort)
+	require.ErrorContains(t, err, "from offset 6")
+
+	err = DecodeReturn(abi, "get_pair", felts(3, 1, 4), &pair)
+	require.ErrorContains(t, err, "1 unexpected felts")
+
+	err = DecodeReturn(abi, "missing", nil, &pair)
+	require.ErrorIs(t, err, ErrFunctionNotInABI)
+}
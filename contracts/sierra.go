@@ -0,0 +1,98 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// SierraClass is a Cairo 1 contract class, as output by the compiler (e.g. <name>.contract_class.json) and
+// declared by a declare v2 or v3 transaction.
+type SierraClass struct {
+	// The list of Sierra instructions of which the program consists
+	SierraProgram []*felt.Felt `json:"sierra_program"`
+
+	// The version of the contract class object. Currently, the Starknet OS supports version 0.1.0
+	ContractClassVersion string `json:"contract_class_version"`
+
+	EntryPointsByType rpc.EntryPointsByType `json:"entry_points_by_type"`
+
+	// The ABI of the class as a JSON string, as hashed in the class hash
+	ABI string `json:"abi,omitempty"`
+}
+
+// UnmarshalJSON unmarshals a Sierra class. The abi field is either the JSON string hashed in the class hash,
+// or a JSON array (as output by Scarb), which is then compacted into the string declared with the class.
+//
+// Parameters:
+// - content: the JSON content of the Sierra class
+// Returns:
+// - error: an error if the content is not a valid Sierra class
+func (c *SierraClass) UnmarshalJSON(content []byte) error {
+	var raw struct {
+		SierraProgram        []*felt.Felt          `json:"sierra_program"`
+		ContractClassVersion string                `json:"contract_class_version"`
+		EntryPointsByType    rpc.EntryPointsByType `json:"entry_points_by_type"`
+		ABI                  json.RawMessage       `json:"abi"`
+	}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return err
+	}
+	c.SierraProgram = raw.SierraProgram
+	c.ContractClassVersion = raw.ContractClassVersion
+	c.EntryPointsByType = raw.EntryPointsByType
+	c.ABI = ""
+
+	abi := bytes.TrimSpace(raw.ABI)
+	switch {
+	case len(abi) == 0 || bytes.Equal(abi, []byte("null")):
+	case abi[0] == '"':
+		return json.Unmarshal(abi, &c.ABI)
+	default:
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, abi); err != nil {
+			return err
+		}
+		c.ABI = compacted.String()
+	}
+	return nil
+}
+
+// ContractClass returns the Sierra class as the contract class of a declare transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.ContractClass: the contract class
+func (c SierraClass) ContractClass() rpc.ContractClass {
+	return rpc.ContractClass{
+		SierraProgram:        c.SierraProgram,
+		ContractClassVersion: c.ContractClassVersion,
+		EntryPointsByType:    c.EntryPointsByType,
+		ABI:                  c.ABI,
+	}
+}
+
+// UnmarshalSierraClass is a function that unmarshals a SierraClass object from a file.
+//
+// It takes a file path as a parameter and returns a pointer to the unmarshaled SierraClass object and an error.
+func UnmarshalSierraClass(filePath string) (*SierraClass, error) {
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var sierraClass SierraClass
+	err = json.Unmarshal(content, &sierraClass)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sierraClass, nil
+}
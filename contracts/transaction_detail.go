@@ -0,0 +1,89 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// TransactionDetails holds everything known about a transaction, e.g. to display it in a block explorer.
+type TransactionDetails struct {
+	rpc.TransactionDetails
+	// The events of the receipt, decoded with the ABI of their emitter where possible
+	Events []DecodedEvent
+}
+
+// TransactionDetail fetches the transaction, its receipt and its trace as rpc.TransactionDetail does, and decodes
+// the events of the receipt with the ABI of the class of each emitting contract at the block of the
+// transaction. The events whose class cannot be fetched, or that do not match the events of its ABI, are left
+// undecoded.
+//
+// Parameters:
+// - ctx: the context.Context object for the requests
+// - provider: the provider the details are fetched from
+// - txHash: the hash of the transaction
+// Returns:
+// - *TransactionDetails: the details of the transaction
+// - error: an error if the transaction cannot be fetched
+func TransactionDetail(ctx context.Context, provider rpc.RpcProvider, txHash *felt.Felt) (*TransactionDetails, error) {
+	fetched, err := rpc.TransactionDetail(ctx, provider, txHash)
+	if err != nil {
+		return nil, err
+	}
+	details := &TransactionDetails{TransactionDetails: *fetched}
+	if details.Receipt == nil {
+		return details, nil
+	}
+
+	blockID := rpc.WithBlockTag(rpc.BlockTagPending)
+	if details.Receipt.BlockHash != nil {
+		blockID = rpc.WithBlockHash(details.Receipt.BlockHash)
+	}
+	decoders := map[string]*eventDecoder{}
+	details.Events = make([]DecodedEvent, len(details.Receipt.Events))
+	for i, event := range details.Receipt.Events {
+		details.Events[i].Event = event
+		if event.FromAddress == nil {
+			continue
+		}
+		emitter := event.FromAddress.String()
+		decoder, ok := decoders[emitter]
+		if !ok {
+			decoder = classEventDecoder(ctx, provider, blockID, event.FromAddress)
+			decoders[emitter] = decoder
+		}
+		details.Events[i] = decoder.decodeOrKeep(event)
+	}
+	return details, nil
+}
+
+// classEventDecoder returns the decoder of the events of the class of a contract.
+//
+// Parameters:
+// - ctx: the context.Context object for the request
+// - provider: the provider the class is fetched from
+// - blockID: the block of the class
+// - address: the address of the contract
+// Returns:
+// - *eventDecoder: the decoder of the events of the ABI, decoding no event if the class or its ABI cannot be
+// fetched
+func classEventDecoder(ctx context.Context, provider rpc.RpcProvider, blockID rpc.BlockID, address *felt.Felt) *eventDecoder {
+	var abi rpc.ABI
+	class, err := provider.ClassAt(ctx, blockID, address)
+	if err != nil {
+		return newEventDecoder(nil)
+	}
+	switch class := class.(type) {
+	case *rpc.ContractClass:
+		if err := json.Unmarshal([]byte(class.ABI), &abi); err != nil {
+			return newEventDecoder(nil)
+		}
+	case *rpc.DeprecatedContractClass:
+		if class.ABI != nil {
+			abi = *class.ABI
+		}
+	}
+	return newEventDecoder(abi)
+}
@@ -0,0 +1,61 @@
+package contracts
+
+import (
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// The names of the standard upgrade entrypoints
+const (
+	// upgrade(new_class_hash), used by the OpenZeppelin UpgradeableComponent and most Cairo 1 contracts,
+	// and by the Cairo 0 OpenZeppelin and Argent proxies as upgrade(new_implementation)
+	UpgradeEntrypoint = "upgrade"
+	// upgradeTo(new_implementation), the camelCase variant of some Cairo 0 proxies
+	UpgradeToEntrypoint = "upgradeTo"
+	// replace_class(class_hash), used by contracts replacing their own class with the replace_class syscall
+	ReplaceClassEntrypoint = "replace_class"
+)
+
+// UpgradeCall returns the call upgrading the proxy to the new class hash with its 'upgrade' entrypoint.
+// See UpgradeCallWithEntrypoint for the proxies using another name.
+//
+// Parameters:
+// - proxy: the address of the upgraded contract
+// - newClassHash: the class hash of the new implementation
+// Returns:
+// - rpc.FunctionCall: the upgrade call
+func UpgradeCall(proxy *felt.Felt, newClassHash *felt.Felt) rpc.FunctionCall {
+	return UpgradeCallWithEntrypoint(proxy, newClassHash, UpgradeEntrypoint)
+}
+
+// UpgradeCallWithEntrypoint returns the call upgrading the proxy to the new class hash with the given entrypoint,
+// e.g. UpgradeToEntrypoint. The entrypoint must take the new class hash as its only parameter.
+//
+// Parameters:
+// - proxy: the address of the upgraded contract
+// - newClassHash: the class hash of the new implementation
+// - entrypoint: the name of the upgrade entrypoint
+// Returns:
+// - rpc.FunctionCall: the upgrade call
+func UpgradeCallWithEntrypoint(proxy *felt.Felt, newClassHash *felt.Felt, entrypoint string) rpc.FunctionCall {
+	return rpc.FunctionCall{
+		ContractAddress:    proxy,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(entrypoint),
+		Calldata:           []*felt.Felt{newClassHash},
+	}
+}
+
+// ReplaceClassCall returns the call to the 'replace_class' entrypoint of a contract replacing its own class.
+// The contract address is left nil, to be set to the contract being upgraded, usually the account sending the call.
+//
+// Parameters:
+// - newClassHash: the class hash replacing the class of the contract
+// Returns:
+// - rpc.FunctionCall: the replace_class call, without contract address
+func ReplaceClassCall(newClassHash *felt.Felt) rpc.FunctionCall {
+	return rpc.FunctionCall{
+		EntryPointSelector: utils.GetSelectorFromNameFelt(ReplaceClassEntrypoint),
+		Calldata:           []*felt.Felt{newClassHash},
+	}
+}
@@ -0,0 +1,48 @@
+package contracts
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpgradeCall tests the selectors and calldata of the upgrade calls, for the standard entrypoint and the
+// camelCase one of some Cairo 0 proxies.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestUpgradeCall(t *testing.T) {
+	proxy := utils.TestHexToFelt(t, "0x4a3ef3b46a4e0b6cdf4e7c2c1d0f7f1e1a9b3d0c7b2a1e8f9d6c5b4a3928170")
+	newClassHash := utils.TestHexToFelt(t, "0x29927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b")
+
+	call := UpgradeCall(proxy, newClassHash)
+	require.Equal(t, proxy, call.ContractAddress)
+	require.Equal(t, utils.TestHexToFelt(t, "0xf2f7c15cbe06c8d94597cd91fd7f3369eae842359235712def5584f8d270cd"), call.EntryPointSelector)
+	require.Equal(t, []*felt.Felt{newClassHash}, call.Calldata)
+
+	call = UpgradeCallWithEntrypoint(proxy, newClassHash, UpgradeToEntrypoint)
+	require.Equal(t, proxy, call.ContractAddress)
+	require.Equal(t, utils.TestHexToFelt(t, "0x9b0b8df6a7e5c0ec9b8501a95550b2ba1795f3d71554ac4e74a70a6b4f3bfa"), call.EntryPointSelector)
+	require.Equal(t, []*felt.Felt{newClassHash}, call.Calldata)
+}
+
+// TestReplaceClassCall tests the selector and calldata of the replace_class call, which has no contract address.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestReplaceClassCall(t *testing.T) {
+	newClassHash := utils.TestHexToFelt(t, "0x29927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b")
+
+	call := ReplaceClassCall(newClassHash)
+	require.Nil(t, call.ContractAddress)
+	require.Equal(t, utils.TestHexToFelt(t, "0x217df192877eed2921e241046523f8d8da7981f0a3ddafe0e7517f6523276d2"), call.EntryPointSelector)
+	require.Equal(t, []*felt.Felt{newClassHash}, call.Calldata)
+}
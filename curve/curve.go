@@ -521,6 +521,42 @@ func (sc StarkCurve) SignFelt(msgHash, privKey *felt.Felt) (*felt.Felt, *felt.Fe
 	return xFelt, yFelt, nil
 }
 
+// Verify verifies a Stark signature of a message hash against a public key, the x-coordinate of the public
+// point as held by the accounts. A signature of the right form that was not produced by the key of the public
+// key is not an error, it is reported as invalid.
+//
+// Parameters:
+// - msgHash: the message hash that was signed, in the range [0, 2^251)
+// - r: the r component of the signature, in the range (0, 2^251)
+// - s: the s component of the signature, in the range (0, EC_ORDER)
+// - pubKey: the public key, the x-coordinate of a point of the curve
+// Returns:
+// - bool: true if the signature is valid, false otherwise
+// - error: an error if an input is nil or out of its range, or the public key is not on the curve
+func Verify(msgHash, r, s, pubKey *felt.Felt) (bool, error) {
+	if msgHash == nil || r == nil || s == nil || pubKey == nil {
+		return false, fmt.Errorf("nil message hash, signature or public key")
+	}
+	msgHashInt, rInt, sInt := utils.FeltToBigInt(msgHash), utils.FeltToBigInt(r), utils.FeltToBigInt(s)
+	zero := big.NewInt(0)
+	if msgHashInt.Cmp(Curve.Max) != -1 {
+		return false, fmt.Errorf("message hash out of range: %s", msgHash)
+	}
+	if rInt.Cmp(zero) != 1 || rInt.Cmp(Curve.Max) != -1 {
+		return false, fmt.Errorf("r out of range: %s", r)
+	}
+	if sInt.Cmp(zero) != 1 || sInt.Cmp(Curve.N) != -1 {
+		return false, fmt.Errorf("s out of range: %s", s)
+	}
+	pubX := utils.FeltToBigInt(pubKey)
+	pubY := Curve.GetYCoordinate(pubX)
+	if pubY == nil || !Curve.IsOnCurve(pubX, pubY) {
+		return false, fmt.Errorf("public key not on the curve: %s", pubKey)
+	}
+	// Verify tries both y-coordinates of the public point
+	return Curve.Verify(msgHashInt, rInt, sInt, pubX, pubY), nil
+}
+
 // HashPedersenElements calculates the hash of a list of elements using a golang Pedersen Hash.
 // Parameters:
 // - elems: slice of big.Int pointers to be hashed
@@ -543,16 +579,15 @@ func HashPedersenElements(elems []*big.Int) (hash *big.Int) {
 
 // ComputeHashOnElements computes the hash on the given elements using a golang Pedersen Hash implementation.
 //
-// The function appends the length of `elems` to the slice and then calls the `HashPedersenElements` method
-// passing in `elems` as an argument. The resulting hash is returned.
+// The elements are converted to felts and hashed with utils.ComputeHashOnElements, which folds them with
+// Pedersen and hashes the result with the number of elements.
 //
 // Parameters:
 // - elems: slice of big.Int pointers to be hashed
 // Returns:
 // - hash: The hash of the list of elements
 func ComputeHashOnElements(elems []*big.Int) (hash *big.Int) {
-	elems = append(elems, big.NewInt(int64(len(elems))))
-	return HashPedersenElements(elems)
+	return utils.FeltToBigInt(utils.ComputeHashOnElements(utils.BigIntArrToFeltArr(elems)))
 }
 
 // Pedersen is a function that implements the Pedersen hash.
@@ -569,16 +604,14 @@ func Pedersen(a, b *felt.Felt) *felt.Felt {
 }
 
 // PedersenArray is a function that takes a variadic number of felt.Felt pointers as parameters and
-// calls the PedersenArray function from the junoCrypto package with the provided parameters.
-// NOTE: This function just wraps the Juno implementation
-// (ref: https://github.com/NethermindEth/juno/blob/32fd743c774ec11a1bb2ce3dceecb57515f4873e/core/crypto/pedersen_hash.go#L12)
+// hashes them with utils.ComputeHashOnElements.
 //
 // Parameters:
 // - felts: A variadic number of pointers to felt.Felt
 // Returns:
 // - *felt.Felt: pointer to a felt.Felt
 func PedersenArray(felts ...*felt.Felt) *felt.Felt {
-	return junoCrypto.PedersenArray(felts...)
+	return utils.ComputeHashOnElements(felts)
 }
 
 // PoseidonArray is a function that takes a variadic number of felt.Felt pointers as parameters and
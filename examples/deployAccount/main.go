@@ -49,7 +49,7 @@ func main() {
 
 	// Set up the account passing random values to 'accountAddress' and 'cairoVersion' variables,
 	// as for this case we only need the 'ks' to sign the deploy transaction.
-	accnt, err := account.NewAccount(client, pub, pub.String(), ks, 2)
+	accnt, err := account.NewAccount(client, pub, account.NewKeystoreSigner(ks, pub.String()), 2)
 	if err != nil {
 		panic(err)
 	}
@@ -73,10 +73,7 @@ func main() {
 		},
 	}
 
-	precomputedAddress, err := accnt.PrecomputeAccountAddress(pub, classHash, tx.ConstructorCalldata)
-	if err != nil {
-		panic(err)
-	}
+	precomputedAddress := accnt.PrecomputeAccountAddress(pub, classHash, tx.ConstructorCalldata)
 	fmt.Println("PrecomputedAddress:", precomputedAddress)
 
 	// Sign the transaction
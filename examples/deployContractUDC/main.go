@@ -61,7 +61,7 @@ func main() {
 	fmt.Println("Established connection with the client")
 
 	// Initialize the account
-	accnt, err := account.NewAccount(client, accountAddressInFelt, publicKey, ks, accountCairoVersion)
+	accnt, err := account.NewAccount(client, accountAddressInFelt, account.NewKeystoreSigner(ks, publicKey), accountCairoVersion)
 	if err != nil {
 		panic(err)
 	}
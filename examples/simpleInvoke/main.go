@@ -50,7 +50,7 @@ func main() {
 		panic(err)
 	}
 	// Initialize the account
-	accnt, err := account.NewAccount(client, accountAddressInFelt, publicKey, ks, accountCairoVersion)
+	accnt, err := account.NewAccount(client, accountAddressInFelt, account.NewKeystoreSigner(ks, publicKey), accountCairoVersion)
 	if err != nil {
 		panic(err)
 	}
@@ -3,8 +3,10 @@ package hash
 import (
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/contracts"
-	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // CalculateTransactionHashCommon calculates the transaction hash common to be used in the StarkNet network - a unique identifier of the transaction.
@@ -41,7 +43,7 @@ func CalculateTransactionHashCommon(
 		chainId,
 	}
 	dataToHash = append(dataToHash, additionalData...)
-	return curve.PedersenArray(dataToHash...)
+	return utils.ComputeHashOnElements(dataToHash)
 }
 
 // ClassHash calculates the hash of a contract class.
@@ -51,6 +53,7 @@ func CalculateTransactionHashCommon(
 // The elements used in the hash calculation include the contract class version, constructor entry point, external entry point, L1 handler entry point, ABI, and Sierra program.
 // The ABI is converted to bytes and then hashed using the StarknetKeccak function from the Curve package.
 // Finally, the ContractClassVersionHash, ExternalHash, L1HandleHash, ConstructorHash, ABIHash, and SierraProgamHash are combined using the PoseidonArray function from the Curve package.
+// See contracts.ClassHash for the hash of the Cairo 0 classes.
 //
 // Parameters:
 // - contract: A contract class object of type rpc.ContractClass.
@@ -59,34 +62,7 @@ func CalculateTransactionHashCommon(
 // - error: an error object if there was an error during the hash calculation.
 func ClassHash(contract rpc.ContractClass) (*felt.Felt, error) {
 	// https://docs.starknet.io/documentation/architecture_and_concepts/Smart_Contracts/class-hash/
-
-	Version := "CONTRACT_CLASS_V" + contract.ContractClassVersion
-	ContractClassVersionHash := new(felt.Felt).SetBytes([]byte(Version))
-	ConstructorHash := hashEntryPointByType(contract.EntryPointsByType.Constructor)
-	ExternalHash := hashEntryPointByType(contract.EntryPointsByType.External)
-	L1HandleHash := hashEntryPointByType(contract.EntryPointsByType.L1Handler)
-	SierraProgamHash := curve.Curve.PoseidonArray(contract.SierraProgram...)
-	ABIHash, err := curve.Curve.StarknetKeccak([]byte(contract.ABI))
-	if err != nil {
-		return nil, err
-	}
-
-	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_hash_calculation
-	return curve.Curve.PoseidonArray(ContractClassVersionHash, ExternalHash, L1HandleHash, ConstructorHash, ABIHash, SierraProgamHash), nil
-}
-
-// hashEntryPointByType calculates the hash of an entry point by type.
-//
-// Parameters:
-// - entryPoint: A slice of rpc.SierraEntryPoint objects
-// Returns:
-// - *felt.Felt: a pointer to a felt.Felt object that represents the calculated hash.
-func hashEntryPointByType(entryPoint []rpc.SierraEntryPoint) *felt.Felt {
-	flattened := make([]*felt.Felt, 0, len(entryPoint))
-	for _, elt := range entryPoint {
-		flattened = append(flattened, elt.Selector, new(felt.Felt).SetUint64(uint64(elt.FunctionIdx)))
-	}
-	return curve.Curve.PoseidonArray(flattened...)
+	return contracts.ClassHash(contract)
 }
 
 // CompiledClassHash calculates the hash of a compiled class in the Casm format.
@@ -95,32 +71,35 @@ func hashEntryPointByType(entryPoint []rpc.SierraEntryPoint) *felt.Felt {
 // - casmClass: A `contracts.CasmClass` object
 // Returns:
 // - *felt.Felt: a pointer to a felt.Felt object that represents the calculated hash.
-func CompiledClassHash(casmClass contracts.CasmClass) *felt.Felt {
-	ContractClassVersionHash := new(felt.Felt).SetBytes([]byte("COMPILED_CLASS_V1"))
-	ExternalHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.External)
-	L1HandleHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.L1Handler)
-	ConstructorHash := hashCasmClassEntryPointByType(casmClass.EntryPointByType.Constructor)
-	ByteCodeHasH := curve.Curve.PoseidonArray(casmClass.ByteCode...)
-
-	// https://github.com/software-mansion/starknet.py/blob/development/starknet_py/hash/casm_class_hash.py#L10
-	return curve.Curve.PoseidonArray(ContractClassVersionHash, ExternalHash, L1HandleHash, ConstructorHash, ByteCodeHasH)
+// - error: an error if the bytecode segment lengths of the class do not match its bytecode
+func CompiledClassHash(casmClass contracts.CasmClass) (*felt.Felt, error) {
+	return contracts.CompiledClassHash(casmClass)
 }
 
-// hashCasmClassEntryPointByType calculates the hash of a CasmClassEntryPoint array.
+// ComputeL1ToL2MessageHash computes the hash of an L1 to L2 message as the Starknet core contract does, i.e. the
+// keccak256 of the 32 bytes words of the sender, the recipient, the nonce, the selector, the length of the payload
+// and the payload. It is the key of the message in the l1ToL2Messages of the core contract, correlating the
+// L1HandlerTxnTrace of an L1 handler transaction with the L1 transaction sending its message.
+// [specification]: https://github.com/starkware-libs/cairo-lang/blob/master/src/starkware/starknet/solidity/StarknetMessaging.sol
 //
 // Parameters:
-// - entryPoint: An array of CasmClassEntryPoint objects
+// - from: The L1 address sending the message
+// - toContract: The address of the L2 contract receiving the message
+// - selector: The selector of the L1 handler of the contract
+// - nonce: The nonce of the message in the core contract
+// - payload: The payload of the message
 // Returns:
-// - *felt.Felt: a pointer to a Felt type
-func hashCasmClassEntryPointByType(entryPoint []contracts.CasmClassEntryPoint) *felt.Felt {
-	flattened := make([]*felt.Felt, 0, len(entryPoint))
-	for _, elt := range entryPoint {
-		builtInFlat := []*felt.Felt{}
-		for _, builtIn := range elt.Builtins {
-			builtInFlat = append(builtInFlat, new(felt.Felt).SetBytes([]byte(builtIn)))
-		}
-		builtInHash := curve.Curve.PoseidonArray(builtInFlat...)
-		flattened = append(flattened, elt.Selector, new(felt.Felt).SetUint64(uint64(elt.Offset)), builtInHash)
+// - common.Hash: the hash of the message
+func ComputeL1ToL2MessageHash(from common.Address, toContract, selector, nonce *felt.Felt, payload []*felt.Felt) common.Hash {
+	words := make([][]byte, 0, 5+len(payload))
+	words = append(words, common.LeftPadBytes(from.Bytes(), 32))
+	for _, f := range []*felt.Felt{toContract, nonce, selector, new(felt.Felt).SetUint64(uint64(len(payload)))} {
+		word := f.Bytes()
+		words = append(words, word[:])
+	}
+	for _, f := range payload {
+		word := f.Bytes()
+		words = append(words, word[:])
 	}
-	return curve.Curve.PoseidonArray(flattened...)
+	return crypto.Keccak256Hash(words...)
 }
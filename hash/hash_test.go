@@ -5,9 +5,12 @@ import (
 	"os"
 	"testing"
 
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -72,6 +75,56 @@ func TestCompiledClassHash(t *testing.T) {
 	err = json.Unmarshal(content, &casmClass)
 	require.NoError(t, err)
 
-	hash := hash.CompiledClassHash(casmClass)
+	hash, err := hash.CompiledClassHash(casmClass)
+	require.NoError(t, err)
 	require.Equal(t, expectedHash, hash.String())
 }
+
+// TestSierraClassHashes tests the class hash and the compiled class hash of a Sierra class and its CASM compilation
+// loaded with the contracts package, as declared by a declare v3 transaction.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//   none
+func TestSierraClassHashes(t *testing.T) {
+	//https://github.com/software-mansion/starknet.py/blob/development/starknet_py/hash/class_hash_test.py
+	expectedClassHash := "0x4ec2ecf58014bc2ffd7c84843c3525e5ecb0a2cac33c47e9c347f39fc0c0944"
+	expectedCompiledClassHash := "0x785fa5f2bacf0bfe3bc413be5820a61e1ea63f2ec27ef00331ee9f46ad07603"
+
+	sierraClass, err := contracts.UnmarshalSierraClass("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	classHash, err := hash.ClassHash(sierraClass.ContractClass())
+	require.NoError(t, err)
+	require.Equal(t, expectedClassHash, classHash.String())
+
+	casmClass, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	compiledClassHash, err := contracts.CompiledClassHash(*casmClass)
+	require.NoError(t, err)
+	require.Equal(t, expectedCompiledClassHash, compiledClassHash.String())
+}
+
+// TestComputeL1ToL2MessageHash tests the hash of the L1 to L2 message of a mainnet deposit on the StarkGate bridge.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//
+//	none
+func TestComputeL1ToL2MessageHash(t *testing.T) {
+	from := common.HexToAddress("0x8453fc6cd1bcfe8d4dfc069c400b433054d47bdc")
+	toContract := utils.TestHexToFelt(t, "0x04c5772d1914fe6ce891b64eb35bf3522aeae1315647314aac58b01137607f3f")
+	selector := utils.TestHexToFelt(t, "0x01b64b1b3b690b43b9b514fb81377518f4039cd3e4f4914d8a6bdf01d679fb19")
+	nonce := new(felt.Felt).SetUint64(8288)
+	payload := []*felt.Felt{
+		new(felt.Felt).SetUint64(4543560),
+		utils.TestHexToFelt(t, "0x914f021563b57a5f785b63661c709da629f3508c"),
+		utils.TestHexToFelt(t, "0x7a75bbfece99f70a4862093d16124b5c179b94640e615e9d5384d7e1d463549"),
+		new(felt.Felt).SetUint64(9000000000000000),
+		new(felt.Felt),
+	}
+
+	hash := hash.ComputeL1ToL2MessageHash(from, toContract, selector, nonce, payload)
+	require.Equal(t, "0x2e350fa9d830482605cb68be4fdb9f0cb3e1f95a0c51623ac1a5d1bd997c2090", hash.Hex())
+}
@@ -0,0 +1,226 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	ErrDecryptFailed   = errors.New("could not decrypt the key with the given passphrase")
+	ErrInvalidKeystore = errors.New("invalid keystore")
+)
+
+// The scrypt parameters of the keystores: the standard ones take about a second and 256MB of memory to derive
+// the key, the light ones a few milliseconds, e.g. for tests.
+const (
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+	LightScryptN    = 1 << 12
+	LightScryptP    = 6
+
+	scryptR     = 8
+	scryptDKLen = 32
+	version     = 3
+)
+
+// keystoreJSON is the JSON keystore file, in the layout of the Ethereum V3 keystores with the Starknet address
+// of the account instead of an Ethereum address.
+type keystoreJSON struct {
+	Address string     `json:"address"`
+	Crypto  cryptoJSON `json:"crypto"`
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+type cryptoJSON struct {
+	Cipher       string           `json:"cipher"`
+	CipherText   string           `json:"ciphertext"`
+	CipherParams cipherParamsJSON `json:"cipherparams"`
+	KDF          string           `json:"kdf"`
+	KDFParams    scryptParamsJSON `json:"kdfparams"`
+	MAC          string           `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+type scryptParamsJSON struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+// Encrypt encrypts the private key of an account into a JSON keystore, with the standard scrypt parameters.
+//
+// Parameters:
+// - privKey: the private key of the account
+// - address: the address of the account, stored in clear in the keystore
+// - passphrase: the passphrase the key is encrypted with
+// Returns:
+// - []byte: the JSON keystore
+// - error: an error if the key cannot be encrypted
+func Encrypt(privKey *felt.Felt, address *felt.Felt, passphrase string) ([]byte, error) {
+	return EncryptWithScrypt(privKey, address, passphrase, StandardScryptN, StandardScryptP)
+}
+
+// EncryptWithScrypt encrypts the private key of an account into a JSON keystore: the key is encrypted with
+// AES-128-CTR by a key derived from the passphrase with scrypt, and authenticated by the Keccak-256 MAC of
+// the encrypted key, as in the Ethereum V3 keystores.
+//
+// Parameters:
+// - privKey: the private key of the account
+// - address: the address of the account, stored in clear in the keystore
+// - passphrase: the passphrase the key is encrypted with
+// - scryptN: the CPU and memory cost of scrypt, a power of 2 such as StandardScryptN or LightScryptN
+// - scryptP: the parallelization of scrypt, such as StandardScryptP or LightScryptP
+// Returns:
+// - []byte: the JSON keystore
+// - error: an error if the key cannot be encrypted, e.g. with invalid scrypt parameters
+func EncryptWithScrypt(privKey *felt.Felt, address *felt.Felt, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	if privKey == nil || address == nil {
+		return nil, errors.New("nil private key or address")
+	}
+	salt, iv, id := make([]byte, 32), make([]byte, aes.BlockSize), make([]byte, 16)
+	for _, b := range [][]byte{salt, iv, id} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	key := privKey.Bytes()
+	cipherText, err := aesCTR(derivedKey[:16], iv, key[:])
+	if err != nil {
+		return nil, err
+	}
+	// version 4 UUID
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+
+	return json.Marshal(keystoreJSON{
+		Address: address.String(),
+		Crypto: cryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams:    scryptParamsJSON{DKLen: scryptDKLen, N: scryptN, P: scryptP, R: scryptR, Salt: hex.EncodeToString(salt)},
+			MAC:          hex.EncodeToString(crypto.Keccak256(derivedKey[16:32], cipherText)),
+		},
+		ID:      fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]),
+		Version: version,
+	})
+}
+
+// Decrypt decrypts the private key of a JSON keystore created by Encrypt.
+//
+// Parameters:
+// - data: the JSON keystore
+// - passphrase: the passphrase the key was encrypted with
+// Returns:
+// - *felt.Felt: the private key
+// - error: ErrDecryptFailed if the passphrase is wrong, ErrInvalidKeystore if the keystore cannot be read
+func Decrypt(data []byte, passphrase string) (*felt.Felt, error) {
+	ks, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	params := ks.Crypto.KDFParams
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: salt: %v", ErrInvalidKeystore, err)
+	}
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("%w: iv: %v", ErrInvalidKeystore, err)
+	}
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ciphertext: %v", ErrInvalidKeystore, err)
+	}
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mac: %v", ErrInvalidKeystore, err)
+	}
+	if params.DKLen < 32 {
+		return nil, fmt.Errorf("%w: dklen %d", ErrInvalidKeystore, params.DKLen)
+	}
+
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	if subtle.ConstantTimeCompare(crypto.Keccak256(derivedKey[16:32], cipherText), mac) != 1 {
+		return nil, ErrDecryptFailed
+	}
+	key, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	return new(felt.Felt).SetBytes(key), nil
+}
+
+// Address returns the address of the account of a JSON keystore, which is not encrypted.
+//
+// Parameters:
+// - data: the JSON keystore
+// Returns:
+// - *felt.Felt: the address of the account
+// - error: ErrInvalidKeystore if the keystore cannot be read
+func Address(data []byte) (*felt.Felt, error) {
+	ks, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	address, err := new(felt.Felt).SetString(ks.Address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: address: %v", ErrInvalidKeystore, err)
+	}
+	return address, nil
+}
+
+// parse decodes a JSON keystore, checking its version, cipher and key derivation function.
+func parse(data []byte) (*keystoreJSON, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	switch {
+	case ks.Version != version:
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidKeystore, ks.Version)
+	case ks.Crypto.Cipher != "aes-128-ctr":
+		return nil, fmt.Errorf("%w: unsupported cipher %q", ErrInvalidKeystore, ks.Crypto.Cipher)
+	case ks.Crypto.KDF != "scrypt":
+		return nil, fmt.Errorf("%w: unsupported kdf %q", ErrInvalidKeystore, ks.Crypto.KDF)
+	}
+	return &ks, nil
+}
+
+// aesCTR encrypts or decrypts the data with AES in CTR mode.
+func aesCTR(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv length %d", len(iv))
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package keystore_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/keystore"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncryptDecrypt tests that a private key encrypted into a keystore is decrypted with its passphrase only,
+// and that the address of the account is kept in the keystore.
+//
+// Parameters:
+// - t: A testing.T object used for running the test and reporting any failures.
+// Returns:
+//
+//	none
+func TestEncryptDecrypt(t *testing.T) {
+	privKey := utils.TestHexToFelt(t, "0x4d6f635b8be8bd1e735cb6fe2ee7bdd1ba2c7a324b4b8ec5a8988e09dc852e5")
+	address := utils.TestHexToFelt(t, "0x1ae6fe02fcd9f61a3a8c30d68a8a7c470b0d7dd6f0ee685d5bbfa0d79406ff9")
+
+	data, err := keystore.EncryptWithScrypt(privKey, address, "correct horse", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, float64(3), fields["version"])
+	require.Equal(t, address.String(), fields["address"])
+	require.NotContains(t, string(data), privKey.String()[2:])
+
+	decrypted, err := keystore.Decrypt(data, "correct horse")
+	require.NoError(t, err)
+	require.Equal(t, privKey, decrypted)
+	decodedAddress, err := keystore.Address(data)
+	require.NoError(t, err)
+	require.Equal(t, address, decodedAddress)
+
+	_, err = keystore.Decrypt(data, "wrong horse")
+	require.ErrorIs(t, err, keystore.ErrDecryptFailed)
+
+	// every encryption has its own salt and iv
+	other, err := keystore.EncryptWithScrypt(privKey, address, "correct horse", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	require.NotEqual(t, data, other)
+
+	_, err = keystore.Decrypt([]byte(`{"version": 1}`), "correct horse")
+	require.ErrorIs(t, err, keystore.ErrInvalidKeystore)
+	_, err = keystore.EncryptWithScrypt(new(felt.Felt), address, "", 3, 1)
+	require.Error(t, err)
+}
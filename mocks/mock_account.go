@@ -43,12 +43,11 @@ func (m *MockAccountInterface) EXPECT() *MockAccountInterfaceMockRecorder {
 }
 
 // PrecomputeAccountAddress mocks base method.
-func (m *MockAccountInterface) PrecomputeAccountAddress(salt, classHash *felt.Felt, constructorCalldata []*felt.Felt) (*felt.Felt, error) {
+func (m *MockAccountInterface) PrecomputeAccountAddress(salt, classHash *felt.Felt, constructorCalldata []*felt.Felt) *felt.Felt {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PrecomputeAccountAddress", salt, classHash, constructorCalldata)
 	ret0, _ := ret[0].(*felt.Felt)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	return ret0
 }
 
 // PrecomputeAccountAddress indicates an expected call of PrecomputeAccountAddress.
@@ -417,10 +417,10 @@ func (mr *MockRpcProviderMockRecorder) TraceBlockTransactions(ctx, blockID any)
 }
 
 // TraceTransaction mocks base method.
-func (m *MockRpcProvider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (rpc.TxnTrace, error) {
+func (m *MockRpcProvider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (rpc.TransactionTrace, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "TraceTransaction", ctx, transactionHash)
-	ret0, _ := ret[0].(rpc.TxnTrace)
+	ret0, _ := ret[0].(rpc.TransactionTrace)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -475,3 +475,507 @@ func (mr *MockRpcProviderMockRecorder) TransactionReceipt(ctx, transactionHash a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionReceipt", reflect.TypeOf((*MockRpcProvider)(nil).TransactionReceipt), ctx, transactionHash)
 }
+
+// MockReadProvider is a mock of ReadProvider interface.
+type MockReadProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadProviderMockRecorder
+}
+
+// MockReadProviderMockRecorder is the mock recorder for MockReadProvider.
+type MockReadProviderMockRecorder struct {
+	mock *MockReadProvider
+}
+
+// NewMockReadProvider creates a new mock instance.
+func NewMockReadProvider(ctrl *gomock.Controller) *MockReadProvider {
+	mock := &MockReadProvider{ctrl: ctrl}
+	mock.recorder = &MockReadProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReadProvider) EXPECT() *MockReadProviderMockRecorder {
+	return m.recorder
+}
+
+// BlockHashAndNumber mocks base method.
+func (m *MockReadProvider) BlockHashAndNumber(ctx context.Context) (*rpc.BlockHashAndNumberOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockHashAndNumber", ctx)
+	ret0, _ := ret[0].(*rpc.BlockHashAndNumberOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockHashAndNumber indicates an expected call of BlockHashAndNumber.
+func (mr *MockReadProviderMockRecorder) BlockHashAndNumber(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHashAndNumber", reflect.TypeOf((*MockReadProvider)(nil).BlockHashAndNumber), ctx)
+}
+
+// BlockNumber mocks base method.
+func (m *MockReadProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockNumber", ctx)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockNumber indicates an expected call of BlockNumber.
+func (mr *MockReadProviderMockRecorder) BlockNumber(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockNumber", reflect.TypeOf((*MockReadProvider)(nil).BlockNumber), ctx)
+}
+
+// BlockTransactionCount mocks base method.
+func (m *MockReadProvider) BlockTransactionCount(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockTransactionCount", ctx, blockID)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockTransactionCount indicates an expected call of BlockTransactionCount.
+func (mr *MockReadProviderMockRecorder) BlockTransactionCount(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockTransactionCount", reflect.TypeOf((*MockReadProvider)(nil).BlockTransactionCount), ctx, blockID)
+}
+
+// BlockWithReceipts mocks base method.
+func (m *MockReadProvider) BlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockWithReceipts", ctx, blockID)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockWithReceipts indicates an expected call of BlockWithReceipts.
+func (mr *MockReadProviderMockRecorder) BlockWithReceipts(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockWithReceipts", reflect.TypeOf((*MockReadProvider)(nil).BlockWithReceipts), ctx, blockID)
+}
+
+// BlockWithTxHashes mocks base method.
+func (m *MockReadProvider) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockWithTxHashes", ctx, blockID)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockWithTxHashes indicates an expected call of BlockWithTxHashes.
+func (mr *MockReadProviderMockRecorder) BlockWithTxHashes(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockWithTxHashes", reflect.TypeOf((*MockReadProvider)(nil).BlockWithTxHashes), ctx, blockID)
+}
+
+// BlockWithTxs mocks base method.
+func (m *MockReadProvider) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockWithTxs", ctx, blockID)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockWithTxs indicates an expected call of BlockWithTxs.
+func (mr *MockReadProviderMockRecorder) BlockWithTxs(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockWithTxs", reflect.TypeOf((*MockReadProvider)(nil).BlockWithTxs), ctx, blockID)
+}
+
+// Call mocks base method.
+func (m *MockReadProvider) Call(ctx context.Context, call rpc.FunctionCall, block rpc.BlockID) ([]*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", ctx, call, block)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockReadProviderMockRecorder) Call(ctx, call, block any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockReadProvider)(nil).Call), ctx, call, block)
+}
+
+// ChainID mocks base method.
+func (m *MockReadProvider) ChainID(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainID", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChainID indicates an expected call of ChainID.
+func (mr *MockReadProviderMockRecorder) ChainID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainID", reflect.TypeOf((*MockReadProvider)(nil).ChainID), ctx)
+}
+
+// Class mocks base method.
+func (m *MockReadProvider) Class(ctx context.Context, blockID rpc.BlockID, classHash *felt.Felt) (rpc.ClassOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Class", ctx, blockID, classHash)
+	ret0, _ := ret[0].(rpc.ClassOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Class indicates an expected call of Class.
+func (mr *MockReadProviderMockRecorder) Class(ctx, blockID, classHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Class", reflect.TypeOf((*MockReadProvider)(nil).Class), ctx, blockID, classHash)
+}
+
+// ClassAt mocks base method.
+func (m *MockReadProvider) ClassAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (rpc.ClassOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClassAt", ctx, blockID, contractAddress)
+	ret0, _ := ret[0].(rpc.ClassOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClassAt indicates an expected call of ClassAt.
+func (mr *MockReadProviderMockRecorder) ClassAt(ctx, blockID, contractAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClassAt", reflect.TypeOf((*MockReadProvider)(nil).ClassAt), ctx, blockID, contractAddress)
+}
+
+// ClassHashAt mocks base method.
+func (m *MockReadProvider) ClassHashAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClassHashAt", ctx, blockID, contractAddress)
+	ret0, _ := ret[0].(*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClassHashAt indicates an expected call of ClassHashAt.
+func (mr *MockReadProviderMockRecorder) ClassHashAt(ctx, blockID, contractAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClassHashAt", reflect.TypeOf((*MockReadProvider)(nil).ClassHashAt), ctx, blockID, contractAddress)
+}
+
+// EstimateFee mocks base method.
+func (m *MockReadProvider) EstimateFee(ctx context.Context, requests []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, blockID rpc.BlockID) ([]rpc.FeeEstimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateFee", ctx, requests, simulationFlags, blockID)
+	ret0, _ := ret[0].([]rpc.FeeEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateFee indicates an expected call of EstimateFee.
+func (mr *MockReadProviderMockRecorder) EstimateFee(ctx, requests, simulationFlags, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateFee", reflect.TypeOf((*MockReadProvider)(nil).EstimateFee), ctx, requests, simulationFlags, blockID)
+}
+
+// EstimateMessageFee mocks base method.
+func (m *MockReadProvider) EstimateMessageFee(ctx context.Context, msg rpc.MsgFromL1, blockID rpc.BlockID) (*rpc.FeeEstimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateMessageFee", ctx, msg, blockID)
+	ret0, _ := ret[0].(*rpc.FeeEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateMessageFee indicates an expected call of EstimateMessageFee.
+func (mr *MockReadProviderMockRecorder) EstimateMessageFee(ctx, msg, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateMessageFee", reflect.TypeOf((*MockReadProvider)(nil).EstimateMessageFee), ctx, msg, blockID)
+}
+
+// Events mocks base method.
+func (m *MockReadProvider) Events(ctx context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events", ctx, input)
+	ret0, _ := ret[0].(*rpc.EventChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockReadProviderMockRecorder) Events(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockReadProvider)(nil).Events), ctx, input)
+}
+
+// GetTransactionStatus mocks base method.
+func (m *MockReadProvider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*rpc.TxnStatusResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionStatus", ctx, transactionHash)
+	ret0, _ := ret[0].(*rpc.TxnStatusResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionStatus indicates an expected call of GetTransactionStatus.
+func (mr *MockReadProviderMockRecorder) GetTransactionStatus(ctx, transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionStatus", reflect.TypeOf((*MockReadProvider)(nil).GetTransactionStatus), ctx, transactionHash)
+}
+
+// Nonce mocks base method.
+func (m *MockReadProvider) Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nonce", ctx, blockID, contractAddress)
+	ret0, _ := ret[0].(*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nonce indicates an expected call of Nonce.
+func (mr *MockReadProviderMockRecorder) Nonce(ctx, blockID, contractAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nonce", reflect.TypeOf((*MockReadProvider)(nil).Nonce), ctx, blockID, contractAddress)
+}
+
+// SpecVersion mocks base method.
+func (m *MockReadProvider) SpecVersion(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SpecVersion", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpecVersion indicates an expected call of SpecVersion.
+func (mr *MockReadProviderMockRecorder) SpecVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpecVersion", reflect.TypeOf((*MockReadProvider)(nil).SpecVersion), ctx)
+}
+
+// StateUpdate mocks base method.
+func (m *MockReadProvider) StateUpdate(ctx context.Context, blockID rpc.BlockID) (*rpc.StateUpdateOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StateUpdate", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.StateUpdateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateUpdate indicates an expected call of StateUpdate.
+func (mr *MockReadProviderMockRecorder) StateUpdate(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateUpdate", reflect.TypeOf((*MockReadProvider)(nil).StateUpdate), ctx, blockID)
+}
+
+// StorageAt mocks base method.
+func (m *MockReadProvider) StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID rpc.BlockID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageAt", ctx, contractAddress, key, blockID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageAt indicates an expected call of StorageAt.
+func (mr *MockReadProviderMockRecorder) StorageAt(ctx, contractAddress, key, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageAt", reflect.TypeOf((*MockReadProvider)(nil).StorageAt), ctx, contractAddress, key, blockID)
+}
+
+// Syncing mocks base method.
+func (m *MockReadProvider) Syncing(ctx context.Context) (*rpc.SyncStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Syncing", ctx)
+	ret0, _ := ret[0].(*rpc.SyncStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Syncing indicates an expected call of Syncing.
+func (mr *MockReadProviderMockRecorder) Syncing(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Syncing", reflect.TypeOf((*MockReadProvider)(nil).Syncing), ctx)
+}
+
+// TransactionByBlockIdAndIndex mocks base method.
+func (m *MockReadProvider) TransactionByBlockIdAndIndex(ctx context.Context, blockID rpc.BlockID, index uint64) (*rpc.BlockTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionByBlockIdAndIndex", ctx, blockID, index)
+	ret0, _ := ret[0].(*rpc.BlockTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionByBlockIdAndIndex indicates an expected call of TransactionByBlockIdAndIndex.
+func (mr *MockReadProviderMockRecorder) TransactionByBlockIdAndIndex(ctx, blockID, index any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionByBlockIdAndIndex", reflect.TypeOf((*MockReadProvider)(nil).TransactionByBlockIdAndIndex), ctx, blockID, index)
+}
+
+// TransactionByHash mocks base method.
+func (m *MockReadProvider) TransactionByHash(ctx context.Context, hash *felt.Felt) (*rpc.BlockTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionByHash", ctx, hash)
+	ret0, _ := ret[0].(*rpc.BlockTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionByHash indicates an expected call of TransactionByHash.
+func (mr *MockReadProviderMockRecorder) TransactionByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionByHash", reflect.TypeOf((*MockReadProvider)(nil).TransactionByHash), ctx, hash)
+}
+
+// TransactionReceipt mocks base method.
+func (m *MockReadProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionReceipt", ctx, transactionHash)
+	ret0, _ := ret[0].(*rpc.TransactionReceiptWithBlockInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionReceipt indicates an expected call of TransactionReceipt.
+func (mr *MockReadProviderMockRecorder) TransactionReceipt(ctx, transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionReceipt", reflect.TypeOf((*MockReadProvider)(nil).TransactionReceipt), ctx, transactionHash)
+}
+
+// MockTraceProvider is a mock of TraceProvider interface.
+type MockTraceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockTraceProviderMockRecorder
+}
+
+// MockTraceProviderMockRecorder is the mock recorder for MockTraceProvider.
+type MockTraceProviderMockRecorder struct {
+	mock *MockTraceProvider
+}
+
+// NewMockTraceProvider creates a new mock instance.
+func NewMockTraceProvider(ctrl *gomock.Controller) *MockTraceProvider {
+	mock := &MockTraceProvider{ctrl: ctrl}
+	mock.recorder = &MockTraceProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTraceProvider) EXPECT() *MockTraceProviderMockRecorder {
+	return m.recorder
+}
+
+// SimulateTransactions mocks base method.
+func (m *MockTraceProvider) SimulateTransactions(ctx context.Context, blockID rpc.BlockID, txns []rpc.Transaction, simulationFlags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateTransactions", ctx, blockID, txns, simulationFlags)
+	ret0, _ := ret[0].([]rpc.SimulatedTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateTransactions indicates an expected call of SimulateTransactions.
+func (mr *MockTraceProviderMockRecorder) SimulateTransactions(ctx, blockID, txns, simulationFlags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateTransactions", reflect.TypeOf((*MockTraceProvider)(nil).SimulateTransactions), ctx, blockID, txns, simulationFlags)
+}
+
+// TraceBlockTransactions mocks base method.
+func (m *MockTraceProvider) TraceBlockTransactions(ctx context.Context, blockID rpc.BlockID) ([]rpc.Trace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceBlockTransactions", ctx, blockID)
+	ret0, _ := ret[0].([]rpc.Trace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceBlockTransactions indicates an expected call of TraceBlockTransactions.
+func (mr *MockTraceProviderMockRecorder) TraceBlockTransactions(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceBlockTransactions", reflect.TypeOf((*MockTraceProvider)(nil).TraceBlockTransactions), ctx, blockID)
+}
+
+// TraceTransaction mocks base method.
+func (m *MockTraceProvider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (rpc.TransactionTrace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TraceTransaction", ctx, transactionHash)
+	ret0, _ := ret[0].(rpc.TransactionTrace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceTransaction indicates an expected call of TraceTransaction.
+func (mr *MockTraceProviderMockRecorder) TraceTransaction(ctx, transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceTransaction", reflect.TypeOf((*MockTraceProvider)(nil).TraceTransaction), ctx, transactionHash)
+}
+
+// MockWriteProvider is a mock of WriteProvider interface.
+type MockWriteProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriteProviderMockRecorder
+}
+
+// MockWriteProviderMockRecorder is the mock recorder for MockWriteProvider.
+type MockWriteProviderMockRecorder struct {
+	mock *MockWriteProvider
+}
+
+// NewMockWriteProvider creates a new mock instance.
+func NewMockWriteProvider(ctrl *gomock.Controller) *MockWriteProvider {
+	mock := &MockWriteProvider{ctrl: ctrl}
+	mock.recorder = &MockWriteProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriteProvider) EXPECT() *MockWriteProviderMockRecorder {
+	return m.recorder
+}
+
+// AddDeclareTransaction mocks base method.
+func (m *MockWriteProvider) AddDeclareTransaction(ctx context.Context, declareTransaction rpc.BroadcastDeclareTxnType) (*rpc.AddDeclareTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeclareTransaction", ctx, declareTransaction)
+	ret0, _ := ret[0].(*rpc.AddDeclareTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDeclareTransaction indicates an expected call of AddDeclareTransaction.
+func (mr *MockWriteProviderMockRecorder) AddDeclareTransaction(ctx, declareTransaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeclareTransaction", reflect.TypeOf((*MockWriteProvider)(nil).AddDeclareTransaction), ctx, declareTransaction)
+}
+
+// AddDeployAccountTransaction mocks base method.
+func (m *MockWriteProvider) AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction rpc.BroadcastAddDeployTxnType) (*rpc.AddDeployAccountTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeployAccountTransaction", ctx, deployAccountTransaction)
+	ret0, _ := ret[0].(*rpc.AddDeployAccountTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDeployAccountTransaction indicates an expected call of AddDeployAccountTransaction.
+func (mr *MockWriteProviderMockRecorder) AddDeployAccountTransaction(ctx, deployAccountTransaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeployAccountTransaction", reflect.TypeOf((*MockWriteProvider)(nil).AddDeployAccountTransaction), ctx, deployAccountTransaction)
+}
+
+// AddInvokeTransaction mocks base method.
+func (m *MockWriteProvider) AddInvokeTransaction(ctx context.Context, invokeTxn rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddInvokeTransaction", ctx, invokeTxn)
+	ret0, _ := ret[0].(*rpc.AddInvokeTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddInvokeTransaction indicates an expected call of AddInvokeTransaction.
+func (mr *MockWriteProviderMockRecorder) AddInvokeTransaction(ctx, invokeTxn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddInvokeTransaction", reflect.TypeOf((*MockWriteProvider)(nil).AddInvokeTransaction), ctx, invokeTxn)
+}
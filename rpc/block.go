@@ -21,7 +21,10 @@ func (provider *Provider) BlockNumber(ctx context.Context) (uint64, error) {
 		if errors.Is(err, errNotFound) {
 			return 0, ErrNoBlocks
 		}
-		return 0, Err(InternalError, err)
+		if ctxErr := contextError(err); ctxErr != nil {
+			return 0, ctxErr
+		}
+		return 0, internalCallErr(err)
 	}
 	return blockNumber, nil
 }
@@ -72,9 +75,9 @@ func WithBlockHash(h *felt.Felt) BlockID {
 // - tag: The tag for the BlockID
 // Returns:
 // - BlockID: A BlockID struct with the specified tag
-func WithBlockTag(tag string) BlockID {
+func WithBlockTag(tag BlockTag) BlockID {
 	return BlockID{
-		Tag: tag,
+		Tag: string(tag),
 	}
 }
 
@@ -106,6 +109,47 @@ func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID
 	return &result, nil
 }
 
+// GasPrices are the gas prices of a block, in wei and in fri.
+type GasPrices struct {
+	L1GasPrice     ResourcePrice
+	L1DataGasPrice ResourcePrice
+	// The price of l2 gas, nil if the node does not send it (before RPC 0.8)
+	L2GasPrice *ResourcePrice
+	// The number of the block the prices are read from, nil for the pending block
+	BlockNumber *uint64
+}
+
+// CurrentGasPrices returns the gas prices of the pending block, e.g. to set the resource bounds of a v3
+// transaction, or the gas prices of the latest block if the node does not serve the pending block: when the
+// pending block is not found, or when the node answers with the latest block instead.
+//
+// Parameters:
+// - ctx: The context.Context object for controlling the function call
+// Returns:
+// - *GasPrices: The gas prices of the pending or latest block
+// - error: An error, if any
+func (provider *Provider) CurrentGasPrices(ctx context.Context) (*GasPrices, error) {
+	var header BlockHeader
+	err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &header, WithBlockTag(BlockTagPending))
+	if err != nil {
+		err = tryUnwrapToRPCErr(err, ErrBlockNotFound)
+		if !errors.Is(err, ErrBlockNotFound) {
+			return nil, err
+		}
+		header = BlockHeader{}
+		if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &header, WithBlockTag(BlockTagLatest)); err != nil {
+			return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+		}
+	}
+
+	prices := &GasPrices{L1GasPrice: header.L1GasPrice, L1DataGasPrice: header.L1DataGasPrice, L2GasPrice: header.L2GasPrice}
+	// the pending block has no hash
+	if header.BlockHash != nil {
+		prices.BlockNumber = &header.BlockNumber
+	}
+	return prices, nil
+}
+
 // StateUpdate is a function that performs a state update operation
 // (gets the information about the result of executing the requested block).
 //
@@ -123,21 +167,22 @@ func (provider *Provider) StateUpdate(ctx context.Context, blockID BlockID) (*St
 	return &state, nil
 }
 
-// BlockTransactionCount returns the number of transactions in a specific block.
+// BlockTransactionCount returns the number of transactions in a specific block, without fetching them.
+// The block can be given by hash, number or tag, the pending and pre_confirmed tags included.
 //
 // Parameters:
 // - ctx: The context.Context object to handle cancellation signals and timeouts
 // - blockID: The ID of the block to retrieve the number of transactions from
 // Returns:
 // - uint64: The number of transactions in the block
-// - error: An error, if any
+// - error: ErrBlockNotFound for an unknown block, or another error, if any
 func (provider *Provider) BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error) {
 	var result uint64
 	if err := do(ctx, provider.c, "starknet_getBlockTransactionCount", &result, blockID); err != nil {
 		if errors.Is(err, errNotFound) {
 			return 0, ErrBlockNotFound
 		}
-		return 0, Err(InternalError, err)
+		return 0, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 	return result, nil
 }
@@ -168,11 +213,22 @@ func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (in
 	return &result, nil
 }
 
-// Get block information with full transactions and receipts given the block id
+// BlockWithReceipts returns a block with its transactions and their receipts in a single call, instead of
+// fetching the receipt of each transaction (starknet_getBlockWithReceipts, RPC 0.7).
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block
+// Returns:
+// - interface{}: a *BlockWithReceipts, or a *PendingBlockWithReceipts for the pending block
+// - error: An error if any occurred during the execution, ErrBlockNotFound if the block is unknown
 func (provider *Provider) BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error) {
+	if err := provider.checkMethod(ctx, "starknet_getBlockWithReceipts"); err != nil {
+		return nil, err
+	}
 	var result json.RawMessage
 	if err := do(ctx, provider.c, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
-		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+		return nil, provider.unsupportedMethodErr("starknet_getBlockWithReceipts", tryUnwrapToRPCErr(err, ErrBlockNotFound))
 	}
 
 	var m map[string]interface{}
@@ -2,7 +2,9 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -899,7 +901,8 @@ func TestCaptureUnsupportedBlockTxn(t *testing.T) {
 				_, okdec3 := v.(BlockDeclareTxnV3)
 				_, okdep := v.(BlockDeployTxn)
 				_, okdepac := v.(BlockDeployAccountTxn)
-				if !okv0 && !okv1 && !okv3 && !okl1 && !okdec0 && !okdec1 && !okdec2 && !okdec3 && !okdep && !okdepac {
+				_, okdepac3 := v.(BlockDeployAccountTxnV3)
+				if !okv0 && !okv1 && !okv3 && !okl1 && !okdec0 && !okdec1 && !okdec2 && !okdec3 && !okdep && !okdepac && !okdepac3 {
 					t.Fatalf("New Type Detected %T at Block(%d)/Txn(%d)", v, i, k)
 				}
 			}
@@ -1091,3 +1094,142 @@ func TestStateUpdate(t *testing.T) {
 		)
 	}
 }
+
+// TestCurrentGasPrices tests the gas prices read from the pending block, and from the latest block when the node
+// does not serve the pending block or answers with the latest block instead.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestCurrentGasPrices(t *testing.T) {
+	pendingKey := MockKey("starknet_getBlockWithTxHashes", WithBlockTag(BlockTagPending))
+	latestKey := MockKey("starknet_getBlockWithTxHashes", WithBlockTag(BlockTagLatest))
+	pendingHeader := json.RawMessage(`{
+		"parent_hash": "0x1", "timestamp": 1, "sequencer_address": "0x2", "transactions": [],
+		"l1_gas_price": {"price_in_wei": "0x10", "price_in_fri": "0x11"},
+		"l1_data_gas_price": {"price_in_wei": "0x20", "price_in_fri": "0x21"},
+		"l2_gas_price": {"price_in_wei": "0x30", "price_in_fri": "0x31"},
+		"l1_da_mode": "BLOB", "starknet_version": "0.13.4"
+	}`)
+	latestHeader := json.RawMessage(`{
+		"status": "ACCEPTED_ON_L2", "block_hash": "0x3", "block_number": 7, "new_root": "0x4",
+		"parent_hash": "0x1", "timestamp": 1, "sequencer_address": "0x2", "transactions": [],
+		"l1_gas_price": {"price_in_wei": "0x40", "price_in_fri": "0x41"},
+		"l1_data_gas_price": {"price_in_wei": "0x50", "price_in_fri": "0x51"},
+		"l1_da_mode": "CALLDATA", "starknet_version": "0.13.2"
+	}`)
+	blockNumber := uint64(7)
+
+	type testSetType struct {
+		Fixtures map[string]json.RawMessage
+		Expected GasPrices
+	}
+	testSet := []testSetType{
+		{
+			Fixtures: map[string]json.RawMessage{pendingKey: pendingHeader, latestKey: latestHeader},
+			Expected: GasPrices{
+				L1GasPrice:     ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x10"), PriceInFRI: utils.TestHexToFelt(t, "0x11")},
+				L1DataGasPrice: ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x20"), PriceInFRI: utils.TestHexToFelt(t, "0x21")},
+				L2GasPrice:     &ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x30"), PriceInFRI: utils.TestHexToFelt(t, "0x31")},
+			},
+		},
+		{
+			// pending block disabled
+			Fixtures: map[string]json.RawMessage{
+				pendingKey: json.RawMessage(`{"error": {"code": 24, "message": "Block not found"}}`),
+				latestKey:  latestHeader,
+			},
+			Expected: GasPrices{
+				L1GasPrice:     ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x40"), PriceInFRI: utils.TestHexToFelt(t, "0x41")},
+				L1DataGasPrice: ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x50"), PriceInFRI: utils.TestHexToFelt(t, "0x51")},
+				BlockNumber:    &blockNumber,
+			},
+		},
+		{
+			// latest block returned for the pending tag
+			Fixtures: map[string]json.RawMessage{pendingKey: latestHeader},
+			Expected: GasPrices{
+				L1GasPrice:     ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x40"), PriceInFRI: utils.TestHexToFelt(t, "0x41")},
+				L1DataGasPrice: ResourcePrice{PriceInWei: utils.TestHexToFelt(t, "0x50"), PriceInFRI: utils.TestHexToFelt(t, "0x51")},
+				BlockNumber:    &blockNumber,
+			},
+		},
+	}
+
+	for _, test := range testSet {
+		prices, err := NewMockProvider(test.Fixtures).CurrentGasPrices(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, test.Expected, *prices)
+	}
+
+	_, err := NewMockProvider(map[string]json.RawMessage{
+		pendingKey: json.RawMessage(`{"error": {"code": -32603, "message": "Internal error"}}`),
+	}).CurrentGasPrices(context.Background())
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrBlockNotFound)
+}
+
+// TestBlockWithReceiptsFixture tests the decoding of a block with receipts captured on Sepolia, each transaction
+// paired with its receipt, and the ErrBlockNotFound of an unknown block.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBlockWithReceiptsFixture(t *testing.T) {
+	content, err := os.ReadFile("tests/blockWithReceipts/sepoliaBlockReceipts64159.json")
+	require.NoError(t, err)
+	var fixture struct {
+		Result json.RawMessage `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(content, &fixture))
+	provider := NewMockProvider(map[string]json.RawMessage{
+		MockKey("starknet_getBlockWithReceipts", WithBlockNumber(64159)): fixture.Result,
+		MockKey("starknet_getBlockWithReceipts", WithBlockNumber(1<<40)): json.RawMessage(`{"error": {"code": 24, "message": "Block not found"}}`),
+	})
+
+	result, err := provider.BlockWithReceipts(context.Background(), WithBlockNumber(64159))
+	require.NoError(t, err)
+	block, ok := result.(*BlockWithReceipts)
+	require.True(t, ok, "should return *BlockWithReceipts, instead: %T", result)
+	require.Equal(t, uint64(64159), block.BlockNumber)
+	require.NotEmpty(t, block.Transactions)
+	for _, txn := range block.Transactions {
+		require.NotNil(t, txn.Transaction.Hash())
+		require.Equal(t, txn.Transaction.Hash(), txn.Receipt.TransactionHash)
+		require.NotEmpty(t, txn.Receipt.ExecutionStatus)
+	}
+
+	_, err = provider.BlockWithReceipts(context.Background(), WithBlockNumber(1<<40))
+	require.ErrorIs(t, err, ErrBlockNotFound)
+}
+
+// TestBlockTransactionCountFixture tests BlockTransactionCount on a mock provider: the count of a block given by
+// number or by the pending tag, and the ErrBlockNotFound of an unknown block.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBlockTransactionCountFixture(t *testing.T) {
+	provider := NewMockProvider(map[string]json.RawMessage{
+		MockKey("starknet_getBlockTransactionCount", WithBlockNumber(64159)):        json.RawMessage(`42`),
+		MockKey("starknet_getBlockTransactionCount", WithBlockTag(BlockTagPending)): json.RawMessage(`3`),
+		MockKey("starknet_getBlockTransactionCount", WithBlockNumber(1<<40)):        json.RawMessage(`{"error": {"code": 24, "message": "Block not found"}}`),
+	})
+
+	count, err := provider.BlockTransactionCount(context.Background(), WithBlockNumber(64159))
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), count)
+
+	count, err = provider.BlockTransactionCount(context.Background(), WithBlockTag(BlockTagPending))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+
+	_, err = provider.BlockTransactionCount(context.Background(), WithBlockNumber(1<<40))
+	require.ErrorIs(t, err, ErrBlockNotFound)
+}
@@ -3,10 +3,46 @@ package rpc
 import (
 	"context"
 
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
+// ChainID is the chain ID of a Starknet network, as returned by Provider.ChainID.
+type ChainID string
+
+// The chain IDs of the public Starknet networks.
+const (
+	ChainIDMainnet ChainID = "SN_MAIN"
+	ChainIDSepolia ChainID = "SN_SEPOLIA"
+)
+
+// IsKnown reports whether the chain ID is the one of a public Starknet network.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for ChainIDMainnet and ChainIDSepolia
+func (id ChainID) IsKnown() bool {
+	return id == ChainIDMainnet || id == ChainIDSepolia
+}
+
+// Felt returns the chain ID encoded as a short string, as used in the transaction hashes and the typed data domains.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the encoded chain ID
+func (id ChainID) Felt() *felt.Felt {
+	return new(felt.Felt).SetBytes([]byte(id))
+}
+
 // ChainID returns the chain ID for transaction replay protection.
+// The chain ID is cached after the first successful call for the lifetime of the provider, so that the later
+// calls do not reach the node. The concurrent first calls share a single request.
 //
 // Parameters:
 // - ctx: The context.Context object for the function
@@ -14,16 +50,71 @@ import (
 // - string: The chain ID
 // - error: An error if any occurred during the execution
 func (provider *Provider) ChainID(ctx context.Context) (string, error) {
-	if provider.chainID != "" {
-		return provider.chainID, nil
+	for {
+		provider.chainIDMu.Lock()
+		if chainID := provider.chainID; chainID != "" {
+			provider.chainIDMu.Unlock()
+			return chainID, nil
+		}
+		if fetch := provider.chainIDFetch; fetch != nil {
+			provider.chainIDMu.Unlock()
+			select {
+			case <-fetch:
+				continue
+			case <-ctx.Done():
+				return "", contextError(ctx.Err())
+			}
+		}
+		fetch := make(chan struct{})
+		provider.chainIDFetch = fetch
+		provider.chainIDMu.Unlock()
+
+		chainID, err := provider.fetchChainID(ctx)
+
+		provider.chainIDMu.Lock()
+		provider.chainIDFetch = nil
+		close(fetch)
+		if err == nil {
+			provider.chainID = chainID
+		}
+		provider.chainIDMu.Unlock()
+		return chainID, err
 	}
+}
+
+// fetchChainID fetches the chain ID from the node.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// Returns:
+// - string: The chain ID
+// - error: An error if any occurred during the execution
+func (provider *Provider) fetchChainID(ctx context.Context) (string, error) {
 	var result string
 	// Note: []interface{}{}...force an empty `params[]` in the jsonrpc request
 	if err := provider.c.CallContext(ctx, &result, "starknet_chainId", []interface{}{}...); err != nil {
-		return "", Err(InternalError, err)
+		if ctxErr := contextError(err); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", internalCallErr(err)
+	}
+	return utils.HexToShortStr(result), nil
+}
+
+// ChainIDFelt returns the chain ID encoded as a short string, as used in the transaction hashes. The chain ID
+// is resolved and cached as by ChainID.
+//
+// Parameters:
+// - ctx: The context.Context object for the function
+// Returns:
+// - *felt.Felt: the encoded chain ID
+// - error: An error if the chain ID cannot be fetched
+func (provider *Provider) ChainIDFelt(ctx context.Context) (*felt.Felt, error) {
+	chainID, err := provider.ChainID(ctx)
+	if err != nil {
+		return nil, err
 	}
-	provider.chainID = utils.HexToShortStr(result)
-	return provider.chainID, nil
+	return ChainID(chainID).Felt(), nil
 }
 
 // Syncing retrieves the synchronization status of the provider.
@@ -37,7 +128,10 @@ func (provider *Provider) Syncing(ctx context.Context) (*SyncStatus, error) {
 	var result interface{}
 	// Note: []interface{}{}...force an empty `params[]` in the jsonrpc request
 	if err := provider.c.CallContext(ctx, &result, "starknet_syncing", []interface{}{}...); err != nil {
-		return nil, Err(InternalError, err)
+		if ctxErr := contextError(err); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, internalCallErr(err)
 	}
 	switch res := result.(type) {
 	case bool:
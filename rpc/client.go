@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
 )
@@ -21,8 +22,12 @@ type callCloser interface {
 // - data: the interface{} to store the result of the RPC call
 // - args: variadic and can be used to pass additional arguments to the RPC method
 // Returns:
-// - error: an error if any occurred during the function call
+// - error: an error if any occurred during the function call, wrapping ErrInvalidBlockID if a BlockID argument is invalid
 func do(ctx context.Context, call callCloser, method string, data interface{}, args ...interface{}) error {
+	if err := validateArgs(args); err != nil {
+		return err
+	}
+
 	var raw json.RawMessage
 	err := call.CallContext(ctx, &raw, method, args...)
 	if err != nil {
@@ -37,6 +42,68 @@ func do(ctx context.Context, call callCloser, method string, data interface{}, a
 	return nil
 }
 
+// validateArgs checks the BlockID arguments of a call before it is sent.
+func validateArgs(args []interface{}) error {
+	for _, arg := range args {
+		if blockID, ok := arg.(BlockID); ok {
+			if err := blockID.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonrpcRequest is the body of a JSON-RPC request, serialized with the fields of the requests posted by ethrpc.
+type jsonrpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// BuildRequest builds the body of the JSON-RPC request posted for a call with the given method and parameters,
+// without sending it, e.g. to log, diff or replay the request, or to send it from another process.
+// The parameters are validated and serialized as by the calls of the provider, and the id of the request is
+// the next request ID of the provider, as for its calls (see RPCError.RequestID).
+//
+// Parameters:
+// - method: the string representing the RPC method, e.g. "starknet_getNonce"
+// - params: the positional parameters of the method
+// Returns:
+// - json.RawMessage: the JSON body of the request
+// - error: an error if a parameter is invalid or can not be serialized, wrapping ErrInvalidBlockID if a BlockID parameter is invalid
+func (provider *Provider) BuildRequest(method string, params ...interface{}) (json.RawMessage, error) {
+	_, body, err := provider.buildRequest(method, params)
+	return body, err
+}
+
+// buildRequest builds the body of a JSON-RPC request as BuildRequest does.
+//
+// Parameters:
+// - method: the string representing the RPC method
+// - params: the positional parameters of the method
+// Returns:
+// - uint64: the request ID of the request, its JSON-RPC id
+// - json.RawMessage: the JSON body of the request
+// - error: an error if a parameter is invalid or can not be serialized
+func (provider *Provider) buildRequest(method string, params []interface{}) (uint64, json.RawMessage, error) {
+	if err := validateArgs(params); err != nil {
+		return 0, nil, err
+	}
+	id := provider.requestIDs.Add(1)
+	req := jsonrpcRequest{JSONRPC: "2.0", ID: strconv.AppendUint(nil, id, 10), Method: method}
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return 0, nil, err
+		}
+		req.Params = encoded
+	}
+	body, err := json.Marshal(req)
+	return id, body, err
+}
+
 // NewClient creates a new ethrpc.Client instance.
 //
 // Parameters:
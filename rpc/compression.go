@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// compressionTransport is an http.RoundTripper asking the node for gzip or deflate compressed responses,
+// e.g. for the large traces of the mainnet blocks, and decompressing their bodies before they are decoded.
+// Unlike the transparent gzip of http.Transport, it also works with any transport and decodes deflate.
+type compressionTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request with an Accept-Encoding header, unless it already has one, and decompresses
+// the body of a gzip or deflate encoded response.
+func (rt *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") != "" {
+		return rt.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	resp, err := rt.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	var decode func(io.Reader) (io.ReadCloser, error)
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip":
+		decode = func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+	case "deflate":
+		// the deflate content coding of HTTP is the zlib format
+		decode = zlib.NewReader
+	default:
+		return resp, nil
+	}
+	resp.Body = &decompressedBody{body: resp.Body, decode: decode}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// decompressedBody is the body of a compressed response, decompressed as it is read. The decompressor is only
+// created by the first read, so that an empty body is not an error until it is read.
+type decompressedBody struct {
+	body   io.ReadCloser
+	decode func(io.Reader) (io.ReadCloser, error)
+	reader io.ReadCloser
+	err    error
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.reader == nil && b.err == nil {
+		b.reader, b.err = b.decode(b.body)
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.reader.Read(p)
+}
+
+func (b *decompressedBody) Close() error {
+	if b.reader != nil {
+		b.reader.Close()
+	}
+	return b.body.Close()
+}
+
+// uncompressedTransport is the pooled transport of the providers created with WithCompression(false), which
+// neither asks for nor decodes compressed responses.
+var uncompressedTransport = func() *http.Transport {
+	transport := newPooledTransport()
+	transport.DisableCompression = true
+	return transport
+}()
+
+// compressionOption is the option of WithCompression.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type compressionOption struct {
+	ethrpc.ClientOption
+	enabled bool
+}
+
+// WithCompression returns an option for NewProvider enabling or disabling the compression of the responses.
+// By default the provider asks the node for gzip or deflate compressed responses and decompresses them, which
+// can make the traces of a block several times smaller; it can be disabled for the nodes failing on the
+// Accept-Encoding header. The option has no effect along with WithHTTPClient or ethrpc.WithHTTPClient, the
+// compression then being up to the transport of the client.
+//
+// Parameters:
+// - enabled: false to neither ask for nor decode compressed responses
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithCompression(enabled bool) ethrpc.ClientOption {
+	return compressionOption{ClientOption: ethrpc.WithHeaders(nil), enabled: enabled}
+}
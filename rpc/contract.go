@@ -3,8 +3,11 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 
 	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/utils"
@@ -114,6 +117,140 @@ func (provider *Provider) StorageAt(ctx context.Context, contractAddress *felt.F
 	return value, nil
 }
 
+// GetStorageProof returns the Merkle proofs of the given classes, contracts and storage keys against the state
+// of a block, along with the roots of the state trees (starknet_getStorageProof, RPC 0.8).
+//
+// Parameters:
+// - ctx: The context.Context for the function
+// - input: The block of the proofs and the classes, contracts and storage keys to prove
+// Returns:
+// - *StorageProofResult: The proofs and the roots of the trees
+// - error: An error if any occurred during the execution, ErrStorageProofNotSupported if the block is too old
+// and ErrMethodUnsupported if the node runs an RPC older than 0.8
+func (provider *Provider) GetStorageProof(ctx context.Context, input StorageProofInput) (*StorageProofResult, error) {
+	classHashes, contractAddresses, storageKeys := input.ClassHashes, input.ContractAddresses, input.ContractsStorageKeys
+	if classHashes == nil {
+		classHashes = []*felt.Felt{}
+	}
+	if contractAddresses == nil {
+		contractAddresses = []*felt.Felt{}
+	}
+	if storageKeys == nil {
+		storageKeys = []ContractStorageKeys{}
+	}
+	if err := provider.checkMethod(ctx, "starknet_getStorageProof"); err != nil {
+		return nil, err
+	}
+	var result StorageProofResult
+	if err := do(ctx, provider.c, "starknet_getStorageProof", &result, input.BlockID, classHashes, contractAddresses, storageKeys); err != nil {
+		return nil, provider.unsupportedMethodErr("starknet_getStorageProof", tryUnwrapToRPCErr(err, ErrBlockNotFound, ErrStorageProofNotSupported))
+	}
+	return &result, nil
+}
+
+// GetCompiledCasm returns the CASM the node compiled a declared Sierra class to, e.g. to check that the class
+// matches local artifacts (starknet_getCompiledCasm, RPC 0.8).
+//
+// Parameters:
+// - ctx: The context.Context for the function
+// - classHash: The hash of the Sierra class
+// Returns:
+// - *CasmCompiledContractClass: The compiled class
+// - error: An error if any occurred during the execution, ErrClassHashNotFound if the class is not declared
+// and ErrCompilationError if the node fails to compile it, ErrMethodUnsupported if the node runs an RPC older
+// than 0.8
+func (provider *Provider) GetCompiledCasm(ctx context.Context, classHash *felt.Felt) (*CasmCompiledContractClass, error) {
+	if err := provider.checkMethod(ctx, "starknet_getCompiledCasm"); err != nil {
+		return nil, err
+	}
+	var result CasmCompiledContractClass
+	if err := do(ctx, provider.c, "starknet_getCompiledCasm", &result, classHash); err != nil {
+		return nil, provider.unsupportedMethodErr("starknet_getCompiledCasm", tryUnwrapToRPCErr(err, ErrClassHashNotFound, ErrCompilationError))
+	}
+	return &result, nil
+}
+
+var ErrInvalidStorageValue = errors.New("invalid storage value")
+
+// StorageType is the type a storage value is decoded as by StorageValue.
+type StorageType int
+
+const (
+	// StorageFelt decodes the slot as a *felt.Felt
+	StorageFelt StorageType = iota
+	// StorageUint256 decodes the slots key (low 128 bits) and key+1 (high 128 bits) as a *big.Int
+	StorageUint256
+	// StorageBool decodes the slot as a bool, the slot must hold 0 or 1
+	StorageBool
+	// StorageAddress decodes the slot as a contract address *felt.Felt, the slot must hold a value below 2^251
+	StorageAddress
+)
+
+var maxStorageAddress = new(big.Int).Lsh(big.NewInt(1), 251)
+
+// StorageValue reads the storage of a contract at the given storage address and decodes it as the given type.
+// It assumes the Cairo 1 storage layout: a value is stored at the address of its storage variable and a u256
+// spans two consecutive slots, its low 128 bits at the address and its high 128 bits at the address + 1.
+// Unlike StorageAt, the key is the storage address itself (e.g. the output of utils.GetSelectorFromNameFelt
+// for a storage variable, or the hashed address of a map entry).
+//
+// Parameters:
+// - ctx: The context.Context for the function
+// - contractAddress: The address of the contract
+// - key: The storage address of the value
+// - blockID: The ID of the block at which to retrieve the storage value
+// - as: The type to decode the value as
+// Returns:
+// - interface{}: The decoded value, a *felt.Felt for StorageFelt and StorageAddress, a *big.Int for StorageUint256, a bool for StorageBool
+// - error: An error if any occurred during the execution, ErrInvalidStorageValue if the value does not fit the type
+func (provider *Provider) StorageValue(ctx context.Context, contractAddress, key *felt.Felt, blockID BlockID, as StorageType) (interface{}, error) {
+	value, err := provider.storageSlot(ctx, contractAddress, key, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch as {
+	case StorageFelt:
+		return value, nil
+	case StorageUint256:
+		if utils.FeltToBigInt(value).BitLen() > 128 {
+			return nil, fmt.Errorf("%w: u256 low part %s exceeds 128 bits", ErrInvalidStorageValue, value)
+		}
+		highKey := new(felt.Felt).Add(key, new(felt.Felt).SetUint64(1))
+		high, err := provider.storageSlot(ctx, contractAddress, highKey, blockID)
+		if err != nil {
+			return nil, err
+		}
+		if utils.FeltToBigInt(high).BitLen() > 128 {
+			return nil, fmt.Errorf("%w: u256 high part %s exceeds 128 bits", ErrInvalidStorageValue, high)
+		}
+		return new(big.Int).Add(new(big.Int).Lsh(utils.FeltToBigInt(high), 128), utils.FeltToBigInt(value)), nil
+	case StorageBool:
+		switch {
+		case value.IsZero():
+			return false, nil
+		case value.IsOne():
+			return true, nil
+		}
+		return nil, fmt.Errorf("%w: %s is not a bool", ErrInvalidStorageValue, value)
+	case StorageAddress:
+		if utils.FeltToBigInt(value).Cmp(maxStorageAddress) >= 0 {
+			return nil, fmt.Errorf("%w: %s is not a contract address", ErrInvalidStorageValue, value)
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("unknown storage type %d", as)
+}
+
+// storageSlot reads the felt stored at a storage address of a contract.
+func (provider *Provider) storageSlot(ctx context.Context, contractAddress, key *felt.Felt, blockID BlockID) (*felt.Felt, error) {
+	var value *felt.Felt
+	if err := do(ctx, provider.c, "starknet_getStorageAt", &value, contractAddress, key, blockID); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
+	}
+	return value, nil
+}
+
 // Nonce retrieves the nonce for a given block ID and contract address.
 //
 // Parameters:
@@ -132,9 +269,106 @@ func (provider *Provider) Nonce(ctx context.Context, blockID BlockID, contractAd
 	return nonce, nil
 }
 
-// Estimates the resources required by a given sequence of transactions when applied on a given state.
-// If one of the transactions reverts or fails due to any reason (e.g. validation failure or an internal error),
-// a TRANSACTION_EXECUTION_ERROR is returned. For v0-2 transactions the estimate is given in wei, and for v3 transactions it is given in fri.
+// NonceChange describes an increase of a contract's nonce observed by WatchNonce.
+type NonceChange struct {
+	// From is the last nonce observed before the change
+	From *felt.Felt
+	// To is the new nonce
+	To *felt.Felt
+	// BlockNumber is the number of the block in which the new nonce was observed
+	BlockNumber uint64
+}
+
+// WatchNonce polls the nonce of the given contract at every interval and sends a NonceChange on ch
+// whenever it increases. A contract that is not deployed yet is reported with a nonce of zero, so the
+// deployment of a watched account is detected as well. WatchNonce blocks until the context is cancelled
+// or a call to the node fails.
+//
+// Parameters:
+// - ctx: the context.Context used to stop watching
+// - account: the address of the contract to watch
+// - interval: the interval between two polls
+// - ch: the channel on which the changes are sent
+// Returns:
+// - error: ctx.Err() once the context is cancelled, or the error of the failing call
+func (provider *Provider) WatchNonce(ctx context.Context, account *felt.Felt, interval time.Duration, ch chan<- NonceChange) error {
+	if interval <= 0 {
+		return Err(InvalidParams, "interval must be positive")
+	}
+	current, _, err := provider.latestNonce(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			nonce, blockNumber, err := provider.latestNonce(ctx, account)
+			if err != nil {
+				return err
+			}
+			if nonce.Cmp(current) <= 0 {
+				continue
+			}
+			select {
+			case ch <- NonceChange{From: current, To: nonce, BlockNumber: blockNumber}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			current = nonce
+		}
+	}
+}
+
+// latestNonce retrieves the nonce of the given contract at the latest block, together with the number of that block.
+// A contract that does not exist has a nonce of zero.
+//
+// Parameters:
+// - ctx: the context.Context for the function call
+// - contractAddress: the address of the contract
+// Returns:
+// - *felt.Felt: the contract's nonce
+// - uint64: the number of the block the nonce was read at
+// - error: an error if any
+func (provider *Provider) latestNonce(ctx context.Context, contractAddress *felt.Felt) (*felt.Felt, uint64, error) {
+	blockNumber, err := provider.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, err
+	}
+	nonce, err := provider.Nonce(ctx, WithBlockNumber(blockNumber), contractAddress)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		if errors.Is(err, ErrContractNotFound) {
+			return new(felt.Felt), blockNumber, nil
+		}
+		return nil, 0, err
+	}
+	return nonce, blockNumber, nil
+}
+
+// EstimateFee estimates the resources required by a given sequence of transactions when applied on a given state,
+// without the traces of SimulateTransactions. If one of the transactions reverts or fails due to any reason
+// (e.g. validation failure or an internal error), a TRANSACTION_EXECUTION_ERROR is returned. For v0-2 transactions
+// the estimate is given in wei, and for v3 transactions it is given in fri.
+// From RPC 0.8 on, the consumed gas and the gas prices are given by resource: L1 gas, L2 gas and L1 data gas.
+//
+// Parameters:
+// - ctx: The context of the function call
+// - requests: The transactions to estimate, applied in order
+// - simulationFlags: The flags of the estimation, e.g. SKIP_VALIDATE to estimate unsigned transactions
+// - blockID: The ID of the block whose state the transactions are applied on
+// Returns:
+// - []FeeEstimate: the fee estimates of the transactions, in the order of the requests
+// - error: ErrTxnExec or ErrBlockNotFound, or an error if any occurred during the execution
 func (provider *Provider) EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error) {
 	var raw []FeeEstimate
 	if err := do(ctx, provider.c, "starknet_estimateFee", &raw, requests, simulationFlags, blockID); err != nil {
@@ -143,15 +377,18 @@ func (provider *Provider) EstimateFee(ctx context.Context, requests []BroadcastT
 	return raw, nil
 }
 
-// EstimateMessageFee estimates the L2 fee of a message sent on L1 (Provider struct).
+// EstimateMessageFee estimates the L2 fee of a message sent on L1 (Provider struct), i.e. the fee the L1 contract
+// sending the message must pay for its l1_handler to be run on L2. From RPC 0.8 on, the estimate is given by
+// resource, as the estimates of EstimateFee.
 //
 // Parameters:
 // - ctx: The context of the function call
-// - msg: The message to estimate the fee for
+// - msg: The message to estimate the fee for, sent by an L1 contract (an Ethereum address) to an L2 contract
 // - blockID: The ID of the block to estimate the fee in
 // Returns:
 // - *FeeEstimate: the fee estimated for the message
-// - error: an error if any occurred during the execution
+// - error: ErrContractNotFound if the target contract does not exist at the block, ErrBlockNotFound, or an
+// error if any occurred during the execution
 func (provider *Provider) EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error) {
 	var raw FeeEstimate
 	if err := do(ctx, provider.c, "starknet_estimateMessageFee", &raw, msg, blockID); err != nil {
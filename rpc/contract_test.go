@@ -2,9 +2,13 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
+	"math/big"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/utils"
@@ -387,6 +391,80 @@ func TestNonce(t *testing.T) {
 	}
 }
 
+// nonceSequenceMock is a callCloser answering starknet_blockNumber and starknet_getNonce
+// with a scripted sequence of nonces. A nil entry answers with ErrContractNotFound.
+type nonceSequenceMock struct {
+	nonces []*felt.Felt
+	calls  int
+}
+
+func (m *nonceSequenceMock) Close() {}
+
+func (m *nonceSequenceMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	switch method {
+	case "starknet_blockNumber":
+		*result.(*uint64) = uint64(100 + m.calls)
+		return nil
+	case "starknet_getNonce":
+		nonce := m.nonces[min(m.calls, len(m.nonces)-1)]
+		m.calls++
+		if nonce == nil {
+			return ErrContractNotFound
+		}
+		return remarshal(nonce, result)
+	default:
+		return errNotFound
+	}
+}
+
+// TestWatchNonce tests that WatchNonce reports every increase of the nonce and stops on context cancellation.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWatchNonce(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("WatchNonce is only tested against a scripted mock")
+	}
+	provider := &Provider{c: &nonceSequenceMock{
+		nonces: []*felt.Felt{
+			nil,
+			utils.TestHexToFelt(t, "0x0"),
+			utils.TestHexToFelt(t, "0x1"),
+			utils.TestHexToFelt(t, "0x1"),
+			utils.TestHexToFelt(t, "0x3"),
+		},
+	}}
+	account := utils.TestHexToFelt(t, "0xdeadbeef")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan NonceChange)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- provider.WatchNonce(ctx, account, time.Millisecond, ch)
+	}()
+
+	expected := []NonceChange{
+		{From: new(felt.Felt), To: utils.TestHexToFelt(t, "0x1"), BlockNumber: 102},
+		{From: utils.TestHexToFelt(t, "0x1"), To: utils.TestHexToFelt(t, "0x3"), BlockNumber: 104},
+	}
+	for _, want := range expected {
+		select {
+		case got := <-ch:
+			require.Equal(t, want, got)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a nonce change")
+		}
+	}
+
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+
+	require.Error(t, provider.WatchNonce(context.Background(), account, 0, ch))
+}
+
 // TestEstimateMessageFee is a test function to test the EstimateMessageFee function.
 //
 // Parameters:
@@ -427,6 +505,51 @@ func TestEstimateMessageFee(t *testing.T) {
 	}
 }
 
+// TestEstimateMessageFeeByResource tests the estimate by resource of RPC 0.8 of a message sent from L1, and the
+// ErrContractNotFound of a message sent to a contract that does not exist.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEstimateMessageFeeByResource(t *testing.T) {
+	msg := MsgFromL1{
+		FromAddress: "0x8453fc6cd1bcfe8d4dfc069c400b433054d47bdc",
+		ToAddress:   utils.TestHexToFelt(t, "0x4c5772d1914fe6ce891b64eb35bf3522aeae1315647314aac58b01137607f3f"),
+		Selector:    utils.TestHexToFelt(t, "0x2d757788a8d8d6f21d1cd40bce38a8222d70654214e96ff95d8086e684fbee5"),
+		Payload:     utils.TestHexArrToFelt(t, []string{"0x53c91253bc9682c04929ca02ed00b3e423f6710d2ee7e0d5ebb06f3ecf368a8", "0x38d7ea4c68000", "0x0"}),
+	}
+	missing := msg
+	missing.ToAddress = utils.TestHexToFelt(t, "0xdead")
+	blockID := WithBlockTag(BlockTagLatest)
+	provider := NewMockProvider(map[string]json.RawMessage{
+		MockKey("starknet_estimateMessageFee", msg, blockID): json.RawMessage(`{
+			"l1_gas_consumed": "0x4ed1",
+			"l1_gas_price": "0x7e15fffd",
+			"l2_gas_consumed": "0x0",
+			"l2_gas_price": "0x1dcd6500",
+			"l1_data_gas_consumed": "0x80",
+			"l1_data_gas_price": "0x1",
+			"overall_fee": "0x26d1a3f5140d",
+			"unit": "WEI"
+		}`),
+		MockKey("starknet_estimateMessageFee", missing, blockID): json.RawMessage(`{"error": {"code": 20, "message": "Contract not found"}}`),
+	})
+
+	estimate, err := provider.EstimateMessageFee(context.Background(), msg, blockID)
+	require.NoError(t, err)
+	require.Equal(t, UnitWei, estimate.FeeUnit)
+	require.Equal(t, utils.TestHexToFelt(t, "0x4ed1"), estimate.L1GasConsumed)
+	require.Equal(t, utils.TestHexToFelt(t, "0x7e15fffd"), estimate.L1GasPrice)
+	require.Equal(t, &felt.Zero, estimate.L2GasConsumed)
+	require.Equal(t, utils.TestHexToFelt(t, "0x80"), estimate.L1DataGasConsumed)
+	require.Equal(t, utils.TestHexToFelt(t, "0x26d1a3f5140d"), estimate.OverallFee)
+
+	_, err = provider.EstimateMessageFee(context.Background(), missing, blockID)
+	require.ErrorIs(t, err, ErrContractNotFound)
+}
+
 func TestEstimateFee(t *testing.T) {
 	testConfig := beforeEach(t)
 
@@ -530,3 +653,273 @@ func TestEstimateFee(t *testing.T) {
 		require.Equal(t, test.expectedResp, resp)
 	}
 }
+
+// TestEstimateFeeSkipValidate tests that EstimateFee sends the SKIP_VALIDATE flag and decodes the fee estimate of
+// RPC 0.8, by resource, of an unsigned invoke transaction.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEstimateFeeSkipValidate(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the estimate is only tested against its fixture")
+	}
+	fixture, err := os.ReadFile("./tests/estimateFee/invokeV3SkipValidate.json")
+	require.NoError(t, err)
+
+	txns := []BroadcastTxn{BroadcastInvokev3Txn{InvokeTxnV3: InvokeTxnV3{
+		Type:          TransactionType_Invoke,
+		SenderAddress: utils.TestHexToFelt(t, "0x36d67ab362562a97f9fba8a1051cf8e37ff1a1449530fb9f1f0e32ac2da7d06"),
+		Calldata:      utils.TestHexArrToFelt(t, []string{"0x1", "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7", "0x83afd3f4caedc6eebf44246fe54e38c95e3179a5ec9ea81740eca5b482d12e", "0x0"}),
+		Version:       TransactionV3,
+		Signature:     []*felt.Felt{},
+		Nonce:         utils.TestHexToFelt(t, "0x3"),
+		ResourceBounds: ResourceBoundsMapping{
+			L1Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L2Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		},
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         DAModeL1,
+		FeeMode:               DAModeL1,
+	}}}
+	flags := []SimulationFlag{SKIP_VALIDATE}
+	blockID := WithBlockTag("latest")
+	provider := NewMockProvider(map[string]json.RawMessage{
+		MockKey("starknet_estimateFee", txns, flags, blockID): fixture,
+		MockKey("starknet_estimateFee", txns, []SimulationFlag(nil), blockID): json.RawMessage(`{"error": {
+			"code": 41,
+			"message": "Transaction execution error",
+			"data": {"transaction_index": 0, "execution_error": "Account validation failed: invalid signature"}
+		}}`),
+	})
+
+	estimates, err := provider.EstimateFee(context.Background(), txns, flags, blockID)
+	require.NoError(t, err)
+	require.Len(t, estimates, 1)
+	estimate := estimates[0]
+	require.Equal(t, UnitStrk, estimate.FeeUnit)
+	require.Equal(t, &felt.Zero, estimate.L1GasConsumed)
+	require.Equal(t, utils.TestHexToFelt(t, "0x3ad3d1ba5f0c"), estimate.L1GasPrice)
+	require.Equal(t, utils.TestHexToFelt(t, "0x10c8e0"), estimate.L2GasConsumed)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1dcd6500"), estimate.L2GasPrice)
+	require.Equal(t, utils.TestHexToFelt(t, "0x80"), estimate.L1DataGasConsumed)
+	require.Equal(t, utils.TestHexToFelt(t, "0x8b0"), estimate.L1DataGasPrice)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1f438daa4b800"), estimate.OverallFee)
+
+	// the overall fee is the sum of the fees of the resources
+	overallFee := new(felt.Felt).Mul(estimate.L1GasConsumed, estimate.L1GasPrice)
+	overallFee.Add(overallFee, new(felt.Felt).Mul(estimate.L2GasConsumed, estimate.L2GasPrice))
+	overallFee.Add(overallFee, new(felt.Felt).Mul(estimate.L1DataGasConsumed, estimate.L1DataGasPrice))
+	require.Equal(t, estimate.OverallFee, overallFee)
+
+	// without the flag the validation of the unsigned transaction fails
+	_, err = provider.EstimateFee(context.Background(), txns, nil, blockID)
+	require.ErrorIs(t, err, ErrTxnExec)
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	data, err := rpcErr.TypedData()
+	require.NoError(t, err)
+	txnErr, ok := data.(*TransactionExecutionErrorData)
+	require.True(t, ok)
+	require.Equal(t, 0, txnErr.TransactionIndex)
+	require.Equal(t, "Account validation failed: invalid signature", txnErr.ExecutionError.Message)
+}
+
+// storageSlotsMock is a callCloser answering starknet_getStorageAt with the values of its slots by storage address.
+type storageSlotsMock struct {
+	slots map[string]*felt.Felt
+}
+
+func (m *storageSlotsMock) Close() {}
+
+func (m *storageSlotsMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_getStorageAt" {
+		return errNotFound
+	}
+	value, ok := m.slots[args[1].(*felt.Felt).String()]
+	if !ok {
+		value = &felt.Zero
+	}
+	return remarshal(value, result)
+}
+
+// TestStorageValue tests the decoding of storage values by StorageValue, including the two slots of a u256.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestStorageValue(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	balance := utils.GetSelectorFromNameFelt("balance")
+	provider := &Provider{c: &storageSlotsMock{slots: map[string]*felt.Felt{
+		balance.String(): utils.TestHexToFelt(t, "0x5"),
+		new(felt.Felt).Add(balance, new(felt.Felt).SetUint64(1)).String(): utils.TestHexToFelt(t, "0x2"),
+		"0xa": utils.TestHexToFelt(t, "0x1"),
+		"0xb": utils.TestHexToFelt(t, "0x2"),
+		"0xc": utils.TestHexToFelt(t, "0x800000000000011000000000000000000000000000000000000000000000000"),
+	}}}
+	contract := utils.TestHexToFelt(t, "0x1234")
+	latest := WithBlockTag("latest")
+
+	value, err := provider.StorageValue(context.Background(), contract, balance, latest, StorageUint256)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Add(new(big.Int).Lsh(big.NewInt(2), 128), big.NewInt(5)), value)
+
+	value, err = provider.StorageValue(context.Background(), contract, balance, latest, StorageFelt)
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x5"), value)
+
+	value, err = provider.StorageValue(context.Background(), contract, utils.TestHexToFelt(t, "0xa"), latest, StorageBool)
+	require.NoError(t, err)
+	require.Equal(t, true, value)
+	_, err = provider.StorageValue(context.Background(), contract, utils.TestHexToFelt(t, "0xb"), latest, StorageBool)
+	require.ErrorIs(t, err, ErrInvalidStorageValue)
+
+	value, err = provider.StorageValue(context.Background(), contract, utils.TestHexToFelt(t, "0xb"), latest, StorageAddress)
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2"), value)
+	_, err = provider.StorageValue(context.Background(), contract, utils.TestHexToFelt(t, "0xc"), latest, StorageAddress)
+	require.ErrorIs(t, err, ErrInvalidStorageValue)
+}
+
+// storageProofMock is a callCloser recording the JSON encoded params of the calls, and answering them with a storage proof.
+type storageProofMock struct {
+	params string
+}
+
+func (m *storageProofMock) Close() {}
+
+func (m *storageProofMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_getStorageProof" {
+		return ErrUnexpectedError
+	}
+	params, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	m.params = string(params)
+	return json.Unmarshal([]byte(`{
+		"classes_proof": [],
+		"contracts_proof": {
+			"nodes": [
+				{"node_hash": "0x10", "node": {"left": "0x11", "right": "0x12"}},
+				{"node_hash": "0x11", "node": {"path": "0x5", "length": 3, "child": "0x13"}}
+			],
+			"contract_leaves_data": [{"nonce": "0x2", "class_hash": "0x14"}]
+		},
+		"contracts_storage_proofs": [[{"node_hash": "0x20", "node": {"path": "0x0", "length": 251, "child": "0x7"}}]],
+		"global_roots": {"contracts_tree_root": "0x10", "classes_tree_root": "0x30", "block_hash": "0x40"}
+	}`), result)
+}
+
+// TestGetStorageProof tests the params of GetStorageProof and the decoding of the binary and edge nodes of the proofs.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGetStorageProof(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mock := &storageProofMock{}
+	provider := &Provider{c: mock}
+
+	result, err := provider.GetStorageProof(context.Background(), StorageProofInput{
+		BlockID:           WithBlockNumber(100),
+		ContractAddresses: []*felt.Felt{utils.TestHexToFelt(t, "0x1")},
+		ContractsStorageKeys: []ContractStorageKeys{
+			{ContractAddress: utils.TestHexToFelt(t, "0x1"), StorageKeys: []*felt.Felt{utils.TestHexToFelt(t, "0x2")}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `[{"block_number":100},[],["0x1"],[{"contract_address":"0x1","storage_keys":["0x2"]}]]`, mock.params)
+
+	require.Empty(t, result.ClassesProof)
+	require.Len(t, result.ContractsProof.Nodes, 2)
+	require.Equal(t, &BinaryNode{Left: utils.TestHexToFelt(t, "0x11"), Right: utils.TestHexToFelt(t, "0x12")}, result.ContractsProof.Nodes[0].Node)
+	require.Equal(t, &EdgeNode{Path: utils.TestHexToFelt(t, "0x5"), Length: 3, Child: utils.TestHexToFelt(t, "0x13")}, result.ContractsProof.Nodes[1].Node)
+	require.Equal(t, utils.TestHexToFelt(t, "0x11"), result.ContractsProof.Nodes[1].NodeHash)
+	require.Nil(t, result.ContractsProof.ContractLeavesData[0].StorageRoot)
+	require.Len(t, result.ContractsStorageProofs, 1)
+	require.IsType(t, &EdgeNode{}, result.ContractsStorageProofs[0][0].Node)
+	require.Equal(t, utils.TestHexToFelt(t, "0x40"), result.GlobalRoots.BlockHash)
+
+	var node NodeHashToNode
+	require.Error(t, json.Unmarshal([]byte(`{"node_hash": "0x1", "node": {"child": "0x2"}}`), &node))
+}
+
+// compiledCasmMock is a callCloser answering starknet_getCompiledCasm with a compiled class, or with err if set.
+type compiledCasmMock struct {
+	err *RPCError
+}
+
+func (m *compiledCasmMock) Close() {}
+
+func (m *compiledCasmMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_getCompiledCasm" || len(args) != 1 {
+		return ErrUnexpectedError
+	}
+	if m.err != nil {
+		return m.err
+	}
+	return json.Unmarshal([]byte(`{
+		"entry_points_by_type": {
+			"CONSTRUCTOR": [],
+			"EXTERNAL": [{"selector": "0x362398bec32bc0ebb411203221a35a0301193a96f317ebe5e40be9f60d15320", "offset": 0, "builtins": ["range_check"]}],
+			"L1_HANDLER": []
+		},
+		"bytecode": ["0xa0680017fff8000", "0x7"],
+		"prime": "0x800000000000011000000000000000000000000000000000000000000000001",
+		"compiler_version": "2.6.0",
+		"hints": [[0, [{"TestLessThanOrEqual": {"lhs": {"Immediate": "0x0"}}}]]],
+		"bytecode_segment_lengths": [2]
+	}`), result)
+}
+
+// TestGetCompiledCasm tests the decoding of the compiled class of GetCompiledCasm and its errors.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestGetCompiledCasm(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mock := &compiledCasmMock{}
+	provider := &Provider{c: mock}
+
+	casm, err := provider.GetCompiledCasm(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.NoError(t, err)
+	require.Equal(t, "2.6.0", casm.CompilerVersion)
+	require.Equal(t, []*felt.Felt{utils.TestHexToFelt(t, "0xa0680017fff8000"), utils.TestHexToFelt(t, "0x7")}, casm.Bytecode)
+	require.Len(t, casm.EntryPointsByType.External, 1)
+	require.Equal(t, []string{"range_check"}, casm.EntryPointsByType.External[0].Builtins)
+	require.Equal(t, []int{2}, casm.BytecodeSegmentLengths)
+	require.Len(t, casm.Hints, 1)
+	require.Equal(t, 0, casm.Hints[0].PC)
+	require.Len(t, casm.Hints[0].Hints, 1)
+
+	hints, err := json.Marshal(casm.Hints)
+	require.NoError(t, err)
+	require.JSONEq(t, `[[0, [{"TestLessThanOrEqual": {"lhs": {"Immediate": "0x0"}}}]]]`, string(hints))
+
+	mock.err = ErrClassHashNotFound
+	_, err = provider.GetCompiledCasm(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.ErrorIs(t, err, ErrClassHashNotFound)
+
+	mock.err = &RPCError{Code: ErrCompilationError.Code, Message: ErrCompilationError.Message, Data: map[string]interface{}{"compilation_error": "unsupported libfunc"}}
+	_, err = provider.GetCompiledCasm(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.ErrorIs(t, err, ErrCompilationError)
+}
@@ -1,7 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 )
 
 const (
@@ -36,6 +40,8 @@ func Err(code int, data any) *RPCError {
 
 // tryUnwrapToRPCErr unwraps the error and checks if it matches any of the given RPC errors.
 // If a match is found, the corresponding RPC error is returned.
+// If the call was aborted by its context, context.Canceled or context.DeadlineExceeded is returned.
+// If a BlockID argument of the call is invalid, the ErrInvalidBlockID error is returned as is.
 // If no match is found, the function returns an InternalError with the original error.
 //
 // Parameters:
@@ -43,7 +49,15 @@ func Err(code int, data any) *RPCError {
 // - rpcErrors: variadic list of *RPCError objects to be checked
 // Returns:
 // - error: the original error
-func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) *RPCError {
+func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) error {
+	requestID, err := splitRequestID(err)
+	if ctxErr := contextError(err); ctxErr != nil {
+		return ctxErr
+	}
+	if errors.Is(err, ErrInvalidBlockID) {
+		return err
+	}
+
 	errBytes, errIn := json.Marshal(err)
 	if errIn != nil {
 		return Err(InternalError, errIn.Error())
@@ -57,26 +71,107 @@ func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) *RPCError {
 
 	for _, rpcErr := range rpcErrors {
 		if nodeErr.Code == rpcErr.Code && nodeErr.Message == rpcErr.Message {
+			nodeErr.RequestID = requestID
 			return &nodeErr
 		}
 	}
 
+	var unwrapped *RPCError
 	if nodeErr.Code == 0 {
-		return Err(InternalError, err.Error())
+		unwrapped = Err(InternalError, err.Error())
+	} else {
+		unwrapped = Err(nodeErr.Code, nodeErr.Data)
 	}
-	return Err(nodeErr.Code, nodeErr.Data)
+	unwrapped.RequestID = requestID
+	return unwrapped
+}
+
+// contextError returns context.Canceled or context.DeadlineExceeded if the error,
+// possibly wrapped by the HTTP transport, comes from the context of the call, and nil otherwise.
+func contextError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return context.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return context.Canceled
+	}
+	return nil
+}
+
+// invalidParamsErr returns the InvalidParams error of an input rejected before the call, its data being the
+// message of the validation error. The validation error is wrapped as well, so that errors.Is and errors.As
+// match its sentinel or type (e.g. ErrInvalidSimulationFlag) as well as the RPC error.
+//
+// Parameters:
+// - err: the validation error of the input
+// Returns:
+// - error: the InvalidParams error wrapping the validation error
+func invalidParamsErr(err error) error {
+	return fmt.Errorf("%w: %w", Err(InvalidParams, err.Error()), err)
 }
 
 type RPCError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+	// RequestID is the request ID of the failed call, the JSON-RPC id of its request for an HTTP provider, or
+	// 0 if the error does not come from a call.
+	RequestID uint64 `json:"-"`
 }
 
 func (e RPCError) Error() string {
 	return e.Message
 }
 
+// Is reports whether the target is an RPC error with the same code, so that errors.Is matches the errors returned
+// by the node against the sentinel errors (e.g. ErrHashNotFound) whatever their message and data.
+//
+// Parameters:
+// - target: the error to compare with
+// Returns:
+// - bool: true if the target is an *RPCError or an RPCError with the same code
+func (e *RPCError) Is(target error) bool {
+	switch t := target.(type) {
+	case *RPCError:
+		return t != nil && e.Code == t.Code
+	case RPCError:
+		return e.Code == t.Code
+	}
+	return false
+}
+
+// Equal reports whether the other RPC error has the same code, message and data.
+//
+// Parameters:
+// - other: the RPC error to compare with
+// Returns:
+// - bool: true if both errors are nil or have the same code, message and data
+func (e *RPCError) Equal(other *RPCError) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+	return e.Code == other.Code && e.Message == other.Message && reflect.DeepEqual(e.Data, other.Data)
+}
+
+// As sets the target to the RPC error when it is an *RPCError, so that errors.As extracts the RPC error of a
+// chain of errors into an RPCError value as well as into an *RPCError.
+//
+// Parameters:
+// - target: the *RPCError to set
+// Returns:
+// - bool: true if the target was set
+func (e *RPCError) As(target any) bool {
+	t, ok := target.(*RPCError)
+	if !ok || t == nil {
+		return false
+	}
+	*t = *e
+	return true
+}
+
+// The errors of the Starknet JSON-RPC specification. The errors returned by the node carry their own message
+// and data, so they should be matched with errors.Is, which compares their codes (see RPCError.Is), rather than
+// with == or by their message; errors.As then gives access to their code and data.
 var (
 	ErrFailedToReceiveTxn = &RPCError{
 		Code:    1,
@@ -138,6 +233,10 @@ var (
 		Code:    41,
 		Message: "Transaction execution error",
 	}
+	ErrStorageProofNotSupported = &RPCError{
+		Code:    42,
+		Message: "the node doesn't support storage proofs for blocks that are too far in the past",
+	}
 	ErrInvalidContractClass = &RPCError{
 		Code:    50,
 		Message: "Invalid contract class",
@@ -194,4 +293,8 @@ var (
 		Code:    63,
 		Message: "An unexpected error occurred",
 	}
+	ErrCompilationError = &RPCError{
+		Code:    100,
+		Message: "Failed to compile the contract",
+	}
 )
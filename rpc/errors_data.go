@@ -0,0 +1,206 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// ContractErrorData is the data of ErrContractError.
+type ContractErrorData struct {
+	// The error raised by the contract, along the calls it was raised through
+	RevertError ContractExecutionError `json:"revert_error"`
+}
+
+// TransactionExecutionErrorData is the data of ErrTxnExec.
+type TransactionExecutionErrorData struct {
+	// The index of the failed transaction in the executed transactions
+	TransactionIndex int `json:"transaction_index"`
+	// The error raised by the transaction, along the calls it was raised through
+	ExecutionError ContractExecutionError `json:"execution_error"`
+}
+
+// NoTraceAvailableErrorData is the data of ErrNoTraceAvailable.
+type NoTraceAvailableErrorData struct {
+	// The status of the transaction, RECEIVED or REJECTED
+	Status string `json:"status"`
+}
+
+// TraceUnavailableError is the error of TraceTransaction when the node has no trace of the transaction yet
+// (ErrNoTraceAvailable), with the status of the transaction given in the data of the error: the trace of a
+// RECEIVED transaction may be available later, a REJECTED transaction will never have one.
+type TraceUnavailableError struct {
+	// The status of the transaction, RECEIVED or REJECTED, empty if the node does not give it
+	Status string
+	// The error returned by the node
+	Err *RPCError
+}
+
+func (e *TraceUnavailableError) Error() string {
+	if e.Status == "" {
+		return e.Err.Error()
+	}
+	return e.Err.Error() + ": " + e.Status
+}
+
+// Unwrap returns the error of the node, so that the error matches ErrNoTraceAvailable with errors.Is.
+func (e *TraceUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the trace may become available, i.e. the transaction is not rejected.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: false if the transaction is REJECTED, true otherwise
+func (e *TraceUnavailableError) Retryable() bool {
+	return e.Status != string(TxnStatus_Rejected)
+}
+
+// traceUnavailableErr returns the ErrNoTraceAvailable error of the node, possibly wrapped, as a
+// *TraceUnavailableError, and the other errors unchanged.
+func traceUnavailableErr(err error) error {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != ErrNoTraceAvailable.Code {
+		return err
+	}
+	return &TraceUnavailableError{Status: traceUnavailableStatus(rpcErr.Data), Err: rpcErr}
+}
+
+// traceUnavailableStatus returns the transaction status of the data of ErrNoTraceAvailable, which the nodes
+// send either as a NoTraceAvailableErrorData object or as the status string alone.
+//
+// Parameters:
+// - data: the data of the error
+// Returns:
+// - string: the status of the transaction, empty if the data does not hold one
+func traceUnavailableStatus(data any) string {
+	raw, ok := data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(data); err != nil {
+			return ""
+		}
+	}
+	var status string
+	if err := json.Unmarshal(raw, &status); err == nil {
+		return status
+	}
+	var object NoTraceAvailableErrorData
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return object.Status
+	}
+	return ""
+}
+
+// CompilationErrorData is the data of ErrCompilationError.
+type CompilationErrorData struct {
+	// The error of the compiler
+	CompilationError string `json:"compilation_error"`
+}
+
+// ContractExecutionError is a node of the tree of an execution error: either the error message raised by the
+// innermost call, or a call along the inner error it raised.
+type ContractExecutionError struct {
+	// The error message, empty if the error is raised by an inner call
+	Message string
+	// The call raising the inner error, nil if the error is a message
+	*ContractExecutionErrorInner
+}
+
+// ContractExecutionErrorInner is a call raising an inner execution error.
+type ContractExecutionErrorInner struct {
+	ContractAddress *felt.Felt              `json:"contract_address"`
+	ClassHash       *felt.Felt              `json:"class_hash"`
+	Selector        *felt.Felt              `json:"selector"`
+	Error           *ContractExecutionError `json:"error"`
+}
+
+// UnmarshalJSON unmarshals the message or the call of the execution error.
+//
+// Parameters:
+// - data: The JSON string or object
+// Returns:
+// - error: an error if the data is neither a string nor a call
+func (e *ContractExecutionError) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = ContractExecutionError{Message: message}
+		return nil
+	}
+	var inner ContractExecutionErrorInner
+	if err := json.Unmarshal(data, &inner); err != nil {
+		return err
+	}
+	*e = ContractExecutionError{ContractExecutionErrorInner: &inner}
+	return nil
+}
+
+// MarshalJSON marshals the execution error into a string for a message, and an object for a call.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: The JSON string or object
+// - error: an error if any
+func (e ContractExecutionError) MarshalJSON() ([]byte, error) {
+	if e.ContractExecutionErrorInner != nil {
+		return json.Marshal(e.ContractExecutionErrorInner)
+	}
+	return json.Marshal(e.Message)
+}
+
+// TypedData decodes the data of the error into the type of the data of its code: a *ContractErrorData,
+// *TransactionExecutionErrorData, *NoTraceAvailableErrorData or *CompilationErrorData, or a string for the
+// errors whose data is a message (e.g. ErrValidationFailure). The data of the other codes is returned
+// as a json.RawMessage.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - any: the decoded data, nil if the error has no data
+// - error: an error if the data does not match the type of its code
+func (e *RPCError) TypedData() (any, error) {
+	if e.Data == nil {
+		return nil, nil
+	}
+	raw, ok := e.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(e.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	var data any
+	switch e.Code {
+	case ErrContractError.Code:
+		data = &ContractErrorData{}
+	case ErrTxnExec.Code:
+		data = &TransactionExecutionErrorData{}
+	case ErrNoTraceAvailable.Code:
+		data = &NoTraceAvailableErrorData{}
+	case ErrCompilationError.Code:
+		data = &CompilationErrorData{}
+	case ErrValidationFailure.Code, ErrCompilationFailed.Code, ErrUnexpectedError.Code:
+		var message string
+		if err := json.Unmarshal(raw, &message); err != nil {
+			return nil, err
+		}
+		return message, nil
+	default:
+		return raw, nil
+	}
+	if err := json.Unmarshal(raw, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -2,8 +2,12 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,3 +25,134 @@ func TestRPCError(t *testing.T) {
 		require.NotNil(t, rpcErr.Data, "-ChuckSize error message-")
 	}
 }
+
+// TestRPCErrorIs tests that errors.Is matches the RPC errors on their code only, while Equal compares them entirely.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRPCErrorIs(t *testing.T) {
+	nodeErr := &RPCError{Code: ErrHashNotFound.Code, Message: "Transaction hash not found", Data: "0x1234"}
+	wrapped := fmt.Errorf("fetching receipt: %w", nodeErr)
+
+	require.ErrorIs(t, nodeErr, ErrHashNotFound)
+	require.ErrorIs(t, wrapped, ErrHashNotFound)
+	require.NotErrorIs(t, wrapped, ErrBlockNotFound)
+	require.ErrorIs(t, wrapped, *ErrHashNotFound)
+	require.False(t, errors.Is(wrapped, (*RPCError)(nil)))
+
+	require.False(t, nodeErr.Equal(ErrHashNotFound))
+	require.True(t, nodeErr.Equal(&RPCError{Code: nodeErr.Code, Message: nodeErr.Message, Data: "0x1234"}))
+	require.True(t, (*RPCError)(nil).Equal(nil))
+}
+
+// TestRPCErrorAs tests that two RPC errors sharing a code but differing in data both match the sentinel error
+// of their code, and that errors.As extracts them from a chain of errors with their code and data.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRPCErrorAs(t *testing.T) {
+	first := &RPCError{Code: ErrContractError.Code, Message: "Contract error", Data: map[string]any{"revert_error": "first"}}
+	second := &RPCError{Code: ErrContractError.Code, Message: "Contract error", Data: map[string]any{"revert_error": "second"}}
+
+	for _, nodeErr := range []*RPCError{first, second} {
+		wrapped := fmt.Errorf("call: %w", fmt.Errorf("estimate: %w", nodeErr))
+		require.ErrorIs(t, wrapped, ErrContractError)
+		require.NotErrorIs(t, wrapped, ErrTxnExec)
+
+		var pointer *RPCError
+		require.ErrorAs(t, wrapped, &pointer)
+		require.Same(t, nodeErr, pointer)
+
+		var value RPCError
+		require.ErrorAs(t, wrapped, &value)
+		require.Equal(t, ErrContractError.Code, value.Code)
+		require.Equal(t, nodeErr.Data, value.Data)
+	}
+	require.False(t, first.Equal(second))
+	require.True(t, errors.Is(first, second))
+
+	var value RPCError
+	require.False(t, errors.As(fmt.Errorf("not an RPC error"), &value))
+}
+
+// TestRPCErrorTypedData tests the decoding of the data of the RPC errors into the type of their code.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRPCErrorTypedData(t *testing.T) {
+	var nodeErr RPCError
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"code": 41,
+		"message": "Transaction execution error",
+		"data": {
+			"transaction_index": 1,
+			"execution_error": {
+				"contract_address": "0x1",
+				"class_hash": "0x2",
+				"selector": "0x3",
+				"error": {"contract_address": "0x4", "class_hash": "0x5", "selector": "0x6", "error": "Input too long for arguments"}
+			}
+		}
+	}`), &nodeErr))
+	data, err := nodeErr.TypedData()
+	require.NoError(t, err)
+	txnErr, ok := data.(*TransactionExecutionErrorData)
+	require.True(t, ok)
+	require.Equal(t, 1, txnErr.TransactionIndex)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), txnErr.ExecutionError.ContractAddress)
+	inner := txnErr.ExecutionError.Error
+	require.Equal(t, utils.TestHexToFelt(t, "0x6"), inner.Selector)
+	require.Nil(t, inner.Error.ContractExecutionErrorInner)
+	require.Equal(t, "Input too long for arguments", inner.Error.Message)
+
+	data, err = (&RPCError{Code: ErrContractError.Code, Data: map[string]any{"revert_error": "Out of gas"}}).TypedData()
+	require.NoError(t, err)
+	require.Equal(t, &ContractErrorData{RevertError: ContractExecutionError{Message: "Out of gas"}}, data)
+
+	data, err = (&RPCError{Code: ErrValidationFailure.Code, Data: "invalid signature"}).TypedData()
+	require.NoError(t, err)
+	require.Equal(t, "invalid signature", data)
+
+	data, err = (&RPCError{Code: ErrHashNotFound.Code, Data: map[string]any{"hash": "0x1"}}).TypedData()
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage(`{"hash":"0x1"}`), data)
+
+	data, err = ErrBlockNotFound.TypedData()
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	_, err = (&RPCError{Code: ErrTxnExec.Code, Data: "not an object"}).TypedData()
+	require.Error(t, err)
+}
+
+// TestTraceUnavailableErr tests that the ErrNoTraceAvailable error of the node is returned as a
+// *TraceUnavailableError, also when it is wrapped, and that the other errors are returned unchanged.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceUnavailableErr(t *testing.T) {
+	nodeErr := &RPCError{Code: ErrNoTraceAvailable.Code, Message: "No trace available for transaction", Data: "RECEIVED"}
+	for _, err := range []error{nodeErr, fmt.Errorf("trace: %w", nodeErr)} {
+		var unavailable *TraceUnavailableError
+		require.ErrorAs(t, traceUnavailableErr(err), &unavailable)
+		require.Equal(t, "RECEIVED", unavailable.Status)
+		require.Same(t, nodeErr, unavailable.Err)
+		require.True(t, unavailable.Retryable())
+	}
+
+	wrapped := fmt.Errorf("trace: %w", ErrHashNotFound)
+	require.Same(t, ErrHashNotFound, traceUnavailableErr(ErrHashNotFound))
+	require.Equal(t, wrapped, traceUnavailableErr(wrapped))
+}
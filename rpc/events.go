@@ -5,17 +5,124 @@ import (
 )
 
 // Events retrieves events from the provider matching the given filter.
+// The input is checked with EventsInput.Validate before the request is sent. The events of several contracts
+// (EventFilter.Addresses) are requested without address and filtered by their emitter, the continuation token
+// of the page still being the one of the node.
 //
 // Parameters:
 // - ctx: The context to use for the request
 // - input: The input parameters for retrieving events
 // Returns
 // - eventChunk: The retrieved events
-// - error: An error if any
+// - error: An InvalidParams error if the input is invalid, or an error if any
 func (provider *Provider) Events(ctx context.Context, input EventsInput) (*EventChunk, error) {
+	if err := input.Validate(); err != nil {
+		return nil, invalidParamsErr(err)
+	}
+	addresses := input.Addresses
+	if len(addresses) == 1 {
+		input.Address, addresses = addresses[0], nil
+	}
+	input.Addresses = nil
+
 	var result EventChunk
 	if err := do(ctx, provider.c, "starknet_getEvents", &result, input); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrPageSizeTooBig, ErrInvalidContinuationToken, ErrBlockNotFound, ErrTooManyKeysInFilter)
 	}
+	if len(addresses) > 0 {
+		events := make([]EmittedEvent, 0, len(result.Events))
+		for _, event := range result.Events {
+			if matchesAddresses(event, addresses) {
+				events = append(events, event)
+			}
+		}
+		result.Events = events
+	}
 	return &result, nil
 }
+
+// EventIterator iterates over the events matching a filter, fetching the pages with starknet_getEvents
+// as they are consumed.
+type EventIterator struct {
+	ctx      context.Context
+	provider *Provider
+	input    EventsInput
+
+	page  []EmittedEvent
+	index int
+	event EmittedEvent
+	last  bool
+	err   error
+}
+
+// EventIterator returns an iterator over the events matching the filter of the input. The pages are
+// requested with the chunk size of the input, starting from its continuation token if any, until the
+// node returns an empty continuation token.
+//
+// Parameters:
+// - ctx: The context to use for the requests, cancelling it stops the iteration
+// - input: The filter and page size of the events
+// Returns
+// - *EventIterator: The iterator, positioned before the first event
+func (provider *Provider) EventIterator(ctx context.Context, input EventsInput) *EventIterator {
+	return &EventIterator{ctx: ctx, provider: provider, input: input}
+}
+
+// Next advances the iterator to the next event, requesting the next page when the current one is consumed.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: false when there are no more events or the iteration failed, see Err
+func (it *EventIterator) Next() bool {
+	for it.err == nil {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		if it.index < len(it.page) {
+			it.event = it.page[it.index]
+			it.index++
+			return true
+		}
+		if it.last {
+			return false
+		}
+
+		chunk, err := it.provider.Events(it.ctx, it.input)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.index = chunk.Events, 0
+		it.input.ContinuationToken = chunk.ContinuationToken
+		it.last = chunk.ContinuationToken == ""
+	}
+	return false
+}
+
+// Event returns the current event of the iterator, valid after Next returned true.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - EmittedEvent: the current event
+func (it *EventIterator) Event() EmittedEvent {
+	return it.event
+}
+
+// Err returns the error that stopped the iteration, such as a failed request or the context error.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the error, or nil if the iteration stopped after the last event
+func (it *EventIterator) Err() error {
+	return it.err
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -99,3 +100,172 @@ func TestEvents(t *testing.T) {
 		require.Equal(t, events.Events[0].TransactionHash, test.expectedResp.Events[0].TransactionHash, "TransactionHash mismatch")
 	}
 }
+
+// eventPagesMock is a callCloser answering starknet_getEvents with scripted pages, chained by their continuation tokens.
+type eventPagesMock struct {
+	pages      map[string]EventChunk
+	chunkSizes []int
+}
+
+func (m *eventPagesMock) Close() {}
+
+func (m *eventPagesMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_getEvents" {
+		return errNotFound
+	}
+	input := args[0].(EventsInput)
+	m.chunkSizes = append(m.chunkSizes, input.ChunkSize)
+	page, ok := m.pages[input.ContinuationToken]
+	if !ok {
+		return ErrInvalidContinuationToken
+	}
+	return remarshal(page, result)
+}
+
+// TestEventIterator tests that the EventIterator follows the continuation tokens until the last page,
+// and that a cancelled context stops the iteration.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEventIterator(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("EventIterator is only tested against scripted pages")
+	}
+	event := func(blockNumber uint64) EmittedEvent {
+		return EmittedEvent{BlockNumber: blockNumber, TransactionHash: new(felt.Felt).SetUint64(blockNumber)}
+	}
+	mock := &eventPagesMock{pages: map[string]EventChunk{
+		"":   {Events: []EmittedEvent{event(1), event(2)}, ContinuationToken: "p2"},
+		"p2": {Events: []EmittedEvent{}, ContinuationToken: "p3"},
+		"p3": {Events: []EmittedEvent{event(3)}},
+	}}
+	provider := &Provider{c: mock}
+	input := EventsInput{ResultPageRequest: ResultPageRequest{ChunkSize: 2}}
+
+	it := provider.EventIterator(context.Background(), input)
+	var blockNumbers []uint64
+	for it.Next() {
+		blockNumbers = append(blockNumbers, it.Event().BlockNumber)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []uint64{1, 2, 3}, blockNumbers)
+	require.Equal(t, []int{2, 2, 2}, mock.chunkSizes)
+	require.False(t, it.Next(), "the iterator stays exhausted")
+
+	// cancelling the context mid-iteration
+	ctx, cancel := context.WithCancel(context.Background())
+	it = provider.EventIterator(ctx, input)
+	require.True(t, it.Next())
+	cancel()
+	require.False(t, it.Next())
+	require.ErrorIs(t, it.Err(), context.Canceled)
+
+	// failed requests stop the iteration
+	it = provider.EventIterator(context.Background(), EventsInput{ResultPageRequest: ResultPageRequest{ContinuationToken: "unknown", ChunkSize: 2}})
+	require.False(t, it.Next())
+	require.Equal(t, ErrInvalidContinuationToken, it.Err())
+}
+
+// eventsInputMock is a callCloser answering starknet_getEvents with its events, recording the inputs of the calls.
+type eventsInputMock struct {
+	events []EmittedEvent
+	inputs []EventsInput
+}
+
+func (m *eventsInputMock) Close() {}
+
+func (m *eventsInputMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_getEvents" {
+		return errNotFound
+	}
+	m.inputs = append(m.inputs, args[0].(EventsInput))
+	return remarshal(EventChunk{Events: m.events, ContinuationToken: "next"}, result)
+}
+
+// TestEventsAddresses tests the filtering of the events of several contracts and the check of the input of
+// Events before the request is sent.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEventsAddresses(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	transfer := utils.GetSelectorFromNameFelt("Transfer")
+	eth, strk, other := utils.TestHexToFelt(t, "0xe7"), utils.TestHexToFelt(t, "0x57"), utils.TestHexToFelt(t, "0x1")
+	event := func(from *felt.Felt) EmittedEvent {
+		return EmittedEvent{Event: Event{FromAddress: from, Keys: []*felt.Felt{transfer}, Data: []*felt.Felt{}}, TransactionHash: from}
+	}
+	mock := &eventsInputMock{events: []EmittedEvent{event(eth), event(other), event(strk), event(eth)}}
+	provider := &Provider{c: mock}
+	input := EventsInput{
+		EventFilter: EventFilter{
+			FromBlock: WithBlockNumber(100),
+			ToBlock:   WithBlockNumber(200),
+			Addresses: []*felt.Felt{eth, strk},
+			Keys:      [][]*felt.Felt{{transfer}, {}, {other, eth}},
+		},
+		ResultPageRequest: ResultPageRequest{ChunkSize: 10},
+	}
+
+	chunk, err := provider.Events(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, []EmittedEvent{event(eth), event(strk), event(eth)}, chunk.Events)
+	require.Equal(t, "next", chunk.ContinuationToken)
+	require.Nil(t, mock.inputs[0].Address)
+	require.Nil(t, mock.inputs[0].Addresses)
+	require.Equal(t, input.Keys, mock.inputs[0].Keys)
+
+	// a single address is filtered by the node
+	input.Addresses = []*felt.Felt{strk}
+	_, err = provider.Events(context.Background(), input)
+	require.NoError(t, err)
+	require.Equal(t, strk, mock.inputs[1].Address)
+
+	encoded, err := json.Marshal(input)
+	require.NoError(t, err)
+	require.NotContains(t, string(encoded), "addresses")
+
+	type testSetType struct {
+		Input         EventsInput
+		ExpectedError string
+	}
+	testSet := []testSetType{
+		{
+			Input:         EventsInput{EventFilter: EventFilter{FromBlock: WithBlockNumber(200), ToBlock: WithBlockNumber(100)}, ResultPageRequest: ResultPageRequest{ChunkSize: 10}},
+			ExpectedError: "invalid events input: to_block 100 before from_block 200",
+		},
+		{
+			Input:         EventsInput{EventFilter: EventFilter{FromBlock: WithBlockTag(BlockTagPending), ToBlock: WithBlockTag(BlockTagLatest)}, ResultPageRequest: ResultPageRequest{ChunkSize: 10}},
+			ExpectedError: "invalid events input: to_block before the pending from_block",
+		},
+		{
+			Input:         EventsInput{EventFilter: EventFilter{FromBlock: WithBlockTag(BlockTagPreConfirmed), ToBlock: WithBlockNumber(100)}, ResultPageRequest: ResultPageRequest{ChunkSize: 10}},
+			ExpectedError: "invalid events input: to_block before the pre_confirmed from_block",
+		},
+		{
+			Input:         EventsInput{EventFilter: EventFilter{Address: eth, Addresses: []*felt.Felt{strk}}, ResultPageRequest: ResultPageRequest{ChunkSize: 10}},
+			ExpectedError: "invalid events input: both address and addresses are set",
+		},
+		{
+			Input:         EventsInput{ResultPageRequest: ResultPageRequest{ChunkSize: MaxEventsChunkSize + 1}},
+			ExpectedError: "invalid events input: chunk_size 1025 above 1024",
+		},
+	}
+	for _, test := range testSet {
+		require.ErrorIs(t, test.Input.Validate(), ErrInvalidEventsInput)
+		_, err := provider.Events(context.Background(), test.Input)
+		require.ErrorIs(t, err, ErrInvalidEventsInput)
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		require.Equal(t, InvalidParams, rpcErr.Code)
+		require.Equal(t, test.ExpectedError, rpcErr.Data)
+	}
+	require.Len(t, mock.inputs, 2)
+}
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// feltModulus is the modulus of the field of the felts, 2^251 + 17 * 2^192 + 1.
+var feltModulus, _ = new(big.Int).SetString("0x800000000000011000000000000000000000000000000000000000000000001", 0)
+
+// decodeFeltField decodes a felt field sent either as a hex string, as the spec requires, or as a JSON integer,
+// as some nodes send the numeric fields.
+//
+// Parameters:
+// - name: the name of the field, for the errors
+// - raw: the JSON value of the field
+// Returns:
+// - *felt.Felt: the felt, nil if the field is missing or null
+// - error: an error naming the field if the value is not a felt, e.g. exceeds the felt modulus
+func decodeFeltField(name string, raw json.RawMessage) (*felt.Felt, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	value, err := decodeJSONInteger(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if value.Sign() < 0 || value.Cmp(feltModulus) >= 0 {
+		return nil, fmt.Errorf("%s: %s exceeds the felt modulus", name, raw)
+	}
+	return new(felt.Felt).SetBytes(value.Bytes()), nil
+}
+
+// decodeUint64Field decodes a uint64 field, such as a block number, sent either as a JSON integer, as the spec
+// requires, or as a hex string, as some nodes send it.
+//
+// Parameters:
+// - name: the name of the field, for the errors
+// - raw: the JSON value of the field
+// Returns:
+// - uint64: the value, 0 if the field is missing or null
+// - error: an error naming the field if the value is not a uint64
+func decodeUint64Field(name string, raw json.RawMessage) (uint64, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return 0, nil
+	}
+	value, err := decodeJSONInteger(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	if !value.IsUint64() {
+		return 0, fmt.Errorf("%s: %s is not a uint64", name, raw)
+	}
+	return value.Uint64(), nil
+}
+
+// decodeJSONInteger decodes an integer sent either as a string, hex with a 0x prefix or decimal, or as a JSON
+// integer.
+//
+// Parameters:
+// - raw: the JSON value
+// Returns:
+// - *big.Int: the integer
+// - error: an error if the value is not an integer
+func decodeJSONInteger(raw json.RawMessage) (*big.Int, error) {
+	text := string(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+	}
+	base := 10
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		text, base = text[2:], 16
+	}
+	value, ok := new(big.Int).SetString(text, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s, expected a hex string or a JSON integer", raw)
+	}
+	return value, nil
+}
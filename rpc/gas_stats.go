@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// GasDistribution is the distribution of an amount across the transactions of a block.
+type GasDistribution struct {
+	Min    *big.Int
+	Median *big.Int
+	P95    *big.Int
+	Max    *big.Int
+}
+
+// GasStats are the gas statistics of the transactions of a block, e.g. for fee analytics.
+type GasStats struct {
+	// The number of transactions, the reverted ones included
+	Transactions int
+	// The number of reverted transactions, counted in the statistics with the gas they consumed
+	Reverted int
+	// The distribution of the overall fees, zero for the traces, which carry no fee
+	OverallFee GasDistribution
+	// The distribution of the L1 gas consumed
+	L1GasConsumed GasDistribution
+}
+
+// BlockGasStats returns the gas statistics of the transaction traces of a block, e.g. as returned by
+// TraceBlockTransactions: the distribution of the L1 gas they consumed, the l1_gas total of their execution
+// resources from RPC 0.8 on, or their computation gas plus the L1 gas of their data availability before. The
+// traces without execution resources count as zero. The traces carry no fee, so the distribution of the overall fees is
+// zero, SimulatedGasStats returning it for simulated transactions. An empty block returns zero statistics.
+//
+// Parameters:
+// - traces: the transaction traces of the block
+// Returns:
+// - GasStats: the gas statistics of the traces
+func BlockGasStats(traces []Trace) GasStats {
+	l1Gas := make([]*big.Int, len(traces))
+	reverted := 0
+	for i, trace := range traces {
+		resources, _ := blockTraceResources(trace.TraceRoot)
+		l1Gas[i] = new(big.Int).SetUint64(traceL1Gas(resources))
+		if traceReverted(trace.TraceRoot) {
+			reverted++
+		}
+	}
+	return GasStats{
+		Transactions:  len(traces),
+		Reverted:      reverted,
+		OverallFee:    gasDistribution(nil),
+		L1GasConsumed: gasDistribution(l1Gas),
+	}
+}
+
+// SimulatedGasStats returns the gas statistics of simulated transactions, e.g. the transactions of a block
+// simulated with SimulateTransactions: the distributions of their overall fees and of the L1 gas they consume,
+// the gas_consumed of the nodes before RPC 0.8 being taken when l1_gas_consumed is missing. An empty list returns
+// zero statistics.
+//
+// Parameters:
+// - simulated: the simulated transactions
+// Returns:
+// - GasStats: the gas statistics of the simulated transactions
+func SimulatedGasStats(simulated []SimulatedTransaction) GasStats {
+	fees := make([]*big.Int, len(simulated))
+	l1Gas := make([]*big.Int, len(simulated))
+	reverted := 0
+	for i, txn := range simulated {
+		fees[i] = feeAmount(txn.OverallFee)
+		l1Gas[i] = feeAmount(firstFelt(txn.L1GasConsumed, txn.GasConsumed))
+		if traceReverted(txn.TxnTrace) {
+			reverted++
+		}
+	}
+	return GasStats{
+		Transactions:  len(simulated),
+		Reverted:      reverted,
+		OverallFee:    gasDistribution(fees),
+		L1GasConsumed: gasDistribution(l1Gas),
+	}
+}
+
+// gasDistribution returns the distribution of the amounts, zero for no amounts. The median of an even number of
+// amounts is the mean of the two middle ones, rounded down, and the 95th percentile is taken by nearest rank.
+func gasDistribution(amounts []*big.Int) GasDistribution {
+	if len(amounts) == 0 {
+		return GasDistribution{Min: new(big.Int), Median: new(big.Int), P95: new(big.Int), Max: new(big.Int)}
+	}
+	sorted := append([]*big.Int(nil), amounts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	n := len(sorted)
+	median := new(big.Int).Set(sorted[n/2])
+	if n%2 == 0 {
+		median.Add(median, sorted[n/2-1]).Rsh(median, 1)
+	}
+	// nearest rank: the smallest amount with at least 95% of the amounts less than or equal to it
+	p95 := (95*n + 99) / 100
+	return GasDistribution{
+		Min:    new(big.Int).Set(sorted[0]),
+		Median: median,
+		P95:    new(big.Int).Set(sorted[p95-1]),
+		Max:    new(big.Int).Set(sorted[n-1]),
+	}
+}
+
+// traceReverted reports whether the execution of the transaction of the trace reverted, either typed or decoded
+// as a JSON object.
+func traceReverted(trace TxnTrace) bool {
+	switch trace := trace.(type) {
+	case InvokeTxnTrace:
+		_, reverted := trace.RevertReason()
+		return reverted
+	case *InvokeTxnTrace:
+		_, reverted := trace.RevertReason()
+		return reverted
+	case map[string]any:
+		execute, _ := trace["execute_invocation"].(map[string]any)
+		reason, _ := execute["revert_reason"].(string)
+		return reason != ""
+	}
+	return false
+}
+
+// firstFelt returns the first non-nil felt, nil if all are nil.
+func firstFelt(felts ...*felt.Felt) *felt.Felt {
+	for _, f := range felts {
+		if f != nil {
+			return f
+		}
+	}
+	return nil
+}
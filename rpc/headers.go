@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// headersOption is the option of WithHeaders.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type headersOption struct {
+	ethrpc.ClientOption
+	headers map[string]string
+}
+
+// WithHeaders returns an option for NewProvider sending the given HTTP headers with every request of the
+// provider, e.g. the Authorization or API key headers of an authenticated node. Unlike ethrpc.WithHeaders,
+// the headers are also sent with the streamed calls (see TraceBlockTransactionsStream). For a websocket
+// provider, the headers are sent with the handshake.
+// The values of the Authorization header and of the headers ending in -key are redacted from the logs of
+// WithDebugLogger.
+//
+// Parameters:
+// - headers: the headers of the requests, by name
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithHeaders(headers map[string]string) ethrpc.ClientOption {
+	return headersOption{ClientOption: ethrpc.WithHeaders(nil), headers: headers}
+}
+
+// redactedValue replaces the values of the secret headers in the logs.
+const redactedValue = "REDACTED"
+
+// isSecretHeader reports whether the value of the header must not be logged: the Authorization header and the
+// API key headers, whose name ends in -key (e.g. X-Api-Key).
+func isSecretHeader(name string) bool {
+	name = strings.ToLower(name)
+	return name == "authorization" || strings.HasSuffix(name, "-key")
+}
+
+// loggedHeaders are the headers of the requests as logged by WithDebugLogger, with the values of the secret
+// headers redacted.
+type loggedHeaders http.Header
+
+// LogValue returns the headers as a group of attributes, redacting the values of the secret headers.
+func (h loggedHeaders) LogValue() slog.Value {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	attrs := make([]slog.Attr, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if isSecretHeader(name) {
+			value = redactedValue
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import "time"
+
+// MetricsObserver receives the metrics of a WsProvider, e.g. to record them as OpenTelemetry instruments
+// (a gauge for the subscription lag and a counter for the reconnections).
+// Its methods are called from the goroutines of the provider and must not block.
+type MetricsObserver interface {
+	// SubscriptionLag records the delay between the timestamp of the latest block received by a subscription
+	// and the wall-clock time, a growing lag meaning that the node or the consumer falls behind.
+	SubscriptionLag(method string, lag time.Duration)
+	// Reconnected records that the connection was re-established after it dropped.
+	Reconnected()
+}
+
+// SetMetricsObserver sets the observer receiving the metrics of the provider, nil to stop observing.
+//
+// Parameters:
+// - observer: the MetricsObserver
+// Returns:
+//
+//	none
+func (ws *WsProvider) SetMetricsObserver(observer MetricsObserver) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.observer = observer
+}
+
+// metricsObserver returns the observer of the provider, nil if not set.
+func (ws *WsProvider) metricsObserver() MetricsObserver {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.observer
+}
+
+// observeBlockLag records the lag of a subscription from the Unix timestamp of the block it received.
+func (ws *WsProvider) observeBlockLag(method string, timestamp uint64) {
+	if observer := ws.metricsObserver(); observer != nil {
+		observer.SubscriptionLag(method, time.Since(time.Unix(int64(timestamp), 0)))
+	}
+}
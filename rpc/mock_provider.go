@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// hashMethods are the methods whose only param is a transaction hash, answered with ErrHashNotFound when the
+// hash is not mapped.
+var hashMethods = map[string]bool{
+	"starknet_getTransactionByHash":  true,
+	"starknet_getTransactionReceipt": true,
+	"starknet_getTransactionStatus":  true,
+	"starknet_traceTransaction":      true,
+}
+
+// MockKey returns the key of the fixture of NewMockProvider answering the call of the method with the params,
+// e.g. MockKey("starknet_traceTransaction", txHash) for the TraceTransaction call of txHash.
+//
+// Parameters:
+// - method: the JSON-RPC method, e.g. "starknet_traceTransaction"
+// - params: the params of the call, as passed by the Provider
+// Returns:
+// - string: the method followed by the JSON encoding of the params, e.g. `starknet_traceTransaction["0xc0ffee"]`
+func MockKey(method string, params ...interface{}) string {
+	if params == nil {
+		params = []interface{}{}
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return method
+	}
+	return method + string(encoded)
+}
+
+// NewMockProvider creates a Provider answering the calls with canned responses instead of a node, e.g. to unit
+// test the code using TraceTransaction or SimulateTransactions. A call is answered with the fixture of its method
+// and params (see MockKey), or else with the fixture of its method alone, whatever its params.
+// A fixture is the JSON result of the call, or a JSON-RPC error such as
+// {"error": {"code": 29, "message": "Transaction hash not found"}}, returned as an *RPCError.
+// As a node does, the unmapped calls of a transaction hash return ErrHashNotFound; the other unmapped
+// calls return a MethodNotFound error.
+//
+// Parameters:
+// - fixtures: the responses by key, either MockKey(method, params...) or the method
+// Returns:
+// - *Provider: the provider answering with the fixtures
+func NewMockProvider(fixtures map[string]json.RawMessage) *Provider {
+	provider := &Provider{}
+	provider.c = &requestIDClient{callCloser: &fixtureClient{fixtures: fixtures}, ids: &provider.requestIDs}
+	return provider
+}
+
+// fixtureClient is the callCloser of NewMockProvider.
+type fixtureClient struct {
+	fixtures map[string]json.RawMessage
+}
+
+// Close does nothing, there is no connection to close.
+func (c *fixtureClient) Close() {}
+
+// CallContext answers the call with its fixture.
+//
+// Parameters:
+// - ctx: the context.Context of the call
+// - result: the result the fixture is decoded into
+// - method: the JSON-RPC method
+// - args: the params of the call
+// Returns:
+// - error: the *RPCError of the fixture or of an unmapped call, or an error if the fixture cannot be decoded
+func (c *fixtureClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fixture, ok := c.fixtures[MockKey(method, args...)]
+	if !ok {
+		fixture, ok = c.fixtures[method]
+	}
+	if !ok {
+		if hashMethods[method] {
+			return ErrHashNotFound
+		}
+		return &RPCError{Code: MethodNotFound, Message: "Method Not Found", Data: MockKey(method, args...)}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(fixture, &fields); err == nil && len(fields) == 1 && fields["error"] != nil {
+		var rpcErr RPCError
+		if err := json.Unmarshal(fields["error"], &rpcErr); err != nil {
+			return err
+		}
+		return &rpcErr
+	}
+	return json.Unmarshal(fixture, result)
+}
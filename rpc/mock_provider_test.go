@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// ExampleNewMockProvider shows a provider answering TraceTransaction with a fixture for one hash, and with
+// a not-found error for the unmapped 0xc0ffee hash.
+func ExampleNewMockProvider() {
+	known, _ := new(felt.Felt).SetString("0x1234")
+	unknown, _ := new(felt.Felt).SetString("0xc0ffee")
+	provider := NewMockProvider(map[string]json.RawMessage{
+		MockKey("starknet_traceTransaction", known): json.RawMessage(`{"type": "L1_HANDLER", "function_invocation": {"contract_address": "0x1"}}`),
+	})
+
+	trace, err := provider.TraceTransaction(context.Background(), known)
+	fmt.Println(trace.(L1HandlerTxnTrace).FunctionInvocation.ContractAddress, err)
+
+	_, err = provider.TraceTransaction(context.Background(), unknown)
+	fmt.Println(errors.Is(err, ErrHashNotFound))
+	// Output:
+	// 0x1 <nil>
+	// true
+}
+
+// TestNewMockProvider tests the lookup of the fixtures of a mock provider by method and params, by method
+// alone, and the errors of the fixtures and of the unmapped calls.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestNewMockProvider(t *testing.T) {
+	provider := NewMockProvider(map[string]json.RawMessage{
+		"starknet_blockNumber":                         json.RawMessage(`42`),
+		MockKey("starknet_getStorageAt", "0x1"):        json.RawMessage(`"0x0"`),
+		MockKey("starknet_getClassHashAt", "x"):        json.RawMessage(`"0x0"`),
+		MockKey("starknet_traceTransaction", "0xdead"): json.RawMessage(`{"error": {"code": 10, "message": "No trace available for transaction", "data": {"status": "RECEIVED"}}}`),
+		"starknet_simulateTransactions":                json.RawMessage(`[{"transaction_trace": {"type": "L1_HANDLER", "function_invocation": {}}, "overall_fee": "0x10", "unit": "WEI"}]`),
+	})
+	require.Equal(t, `starknet_traceTransaction["0xdead"]`, MockKey("starknet_traceTransaction", utils.TestHexToFelt(t, "0xdead")))
+	require.Equal(t, `starknet_blockNumber[]`, MockKey("starknet_blockNumber"))
+
+	number, err := provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), number)
+
+	simulated, err := provider.SimulateTransactions(context.Background(), WithBlockTag(BlockTagLatest), []Transaction{L1HandlerTxn{Type: TransactionType_L1Handler}}, nil)
+	require.NoError(t, err)
+	require.Len(t, simulated, 1)
+	require.IsType(t, L1HandlerTxnTrace{}, simulated[0].TxnTrace)
+	require.Equal(t, utils.TestHexToFelt(t, "0x10"), simulated[0].OverallFee)
+
+	_, err = provider.TraceTransaction(context.Background(), utils.TestHexToFelt(t, "0xdead"))
+	require.ErrorIs(t, err, ErrNoTraceAvailable)
+	var unavailable *TraceUnavailableError
+	require.ErrorAs(t, err, &unavailable)
+	require.Equal(t, "RECEIVED", unavailable.Status)
+	require.True(t, unavailable.Retryable())
+	data, err := unavailable.Err.TypedData()
+	require.NoError(t, err)
+	require.Equal(t, &NoTraceAvailableErrorData{Status: "RECEIVED"}, data)
+
+	_, err = provider.TransactionReceipt(context.Background(), utils.TestHexToFelt(t, "0xc0ffee"))
+	require.ErrorIs(t, err, ErrHashNotFound)
+
+	_, err = provider.Nonce(context.Background(), WithBlockTag(BlockTagLatest), utils.TestHexToFelt(t, "0x1"))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrHashNotFound)
+}
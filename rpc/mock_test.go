@@ -1187,6 +1187,19 @@ func mock_starknet_traceBlockTransactions(result interface{}, method string, arg
 		}
 		return json.Unmarshal(BlockTrace, &r)
 	}
+	if blockID.Hash.String() == "0xbad" {
+		var rawError struct {
+			Error *RPCError `json:"error"`
+		}
+		read, err := os.ReadFile("tests/trace/traceBlockTxnExecError.json")
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(read, &rawError); err != nil {
+			return err
+		}
+		return rawError.Error
+	}
 
 	return ErrBlockNotFound
 }
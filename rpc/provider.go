@@ -3,12 +3,18 @@ package rpc
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/cookiejar"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/net/publicsuffix"
+	"golang.org/x/time/rate"
 )
 
 // ErrNotFound is returned by API methods if the requested item does not exist.
@@ -18,38 +24,239 @@ var (
 
 // Provider provides the provider for starknet.go/rpc implementation.
 type Provider struct {
-	c       callCloser
-	chainID string
+	c callCloser
+	// chainID caches the chain ID of the node after the first successful call to ChainID
+	chainIDMu sync.Mutex
+	chainID   string
+	// chainIDFetch is closed once the chain ID being fetched from the node is cached, nil when no fetch is in
+	// flight
+	chainIDFetch chan struct{}
+	// url and httpClient send the streamed calls of an HTTP provider, nil httpClient for the other transports
+	url        string
+	httpClient *http.Client
+	// headers are the headers of WithHeaders, also sent with the streamed calls
+	headers http.Header
+	// limiter is the limiter of WithRateLimit, also applied to the streamed calls, nil without limit
+	limiter *rate.Limiter
+	// timeout is the default timeout of WithTimeout, also applied to the streamed calls, zero without timeout
+	timeout time.Duration
+	// logger is the logger of WithDebugLogger, also logging the failed streamed calls, nil without logger
+	logger *slog.Logger
+	// specVersion is the spec version declared with WithSpecVersion, empty to detect the layout of the results
+	specVersion string
+	// nodeSpecVersion caches the spec version of the node after the first successful call to SpecVersion, for
+	// the lifetime of the provider
+	specVersionMu   sync.RWMutex
+	nodeSpecVersion string
+	// requestIDs is the counter of the request IDs of the calls, see requestIDClient
+	requestIDs atomic.Uint64
 }
 
 // NewProvider creates a new rpc Provider instance.
+// The provider asks the node for compressed responses, unless disabled with WithCompression(false).
+// By default the provider does not set any request timeout: the duration of a call is only bounded by its context,
+// and a call aborted by its context returns context.Canceled or context.DeadlineExceeded.
+// A custom client given with WithHTTPClient or ethrpc.WithHTTPClient should leave http.Client.Timeout unset for the same reason,
+// a default timeout of the calls without deadline being set with WithTimeout instead.
 func NewProvider(url string, options ...ethrpc.ClientOption) (*Provider, error) {
-	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
-	if err != nil {
-		return nil, err
+	var httpClient *http.Client
+	compression := true
+	var specVersion string
+	var logger *slog.Logger
+	headers := http.Header{}
+	var limiter *rate.Limiter
+	var retry *RetryConfig
+	var timeout time.Duration
+	var clientOptions []ethrpc.ClientOption
+	for _, option := range options {
+		switch option := option.(type) {
+		case timeoutOption:
+			timeout = option.timeout
+		case httpClientOption:
+			httpClient = option.client
+		case specVersionOption:
+			specVersion = option.version
+		case compressionOption:
+			compression = option.enabled
+		case loggerOption:
+			logger = option.logger
+		case rateLimitOption:
+			limiter = option.limiter
+		case retryOption:
+			retry = &option.config
+		case headersOption:
+			for name, value := range option.headers {
+				headers.Set(name, value)
+			}
+		default:
+			clientOptions = append(clientOptions, option)
+		}
+	}
+	if httpClient == nil {
+		if compression {
+			httpClient = newHTTPClient(nil)
+		} else {
+			httpClient = newHTTPClient(uncompressedTransport)
+		}
 	}
-	client := &http.Client{Jar: jar}
-	// prepend the custom client to allow users to override
-	options = append([]ethrpc.ClientOption{ethrpc.WithHTTPClient(client)}, options...)
-	c, err := ethrpc.DialOptions(context.Background(), url, options...)
+	if retry != nil {
+		httpClient = wrapTransport(httpClient, func(base http.RoundTripper) http.RoundTripper {
+			return newRetryTransport(*retry, base, limiter)
+		})
+	}
+	httpClient = wrapTransport(httpClient, func(base http.RoundTripper) http.RoundTripper {
+		return &requestIDTransport{base: base}
+	})
+	// prepend the client of the provider to allow users to override it with ethrpc.WithHTTPClient
+	clientOptions = append([]ethrpc.ClientOption{ethrpc.WithHTTPClient(httpClient)}, clientOptions...)
+	if len(headers) > 0 {
+		clientOptions = append(clientOptions, ethrpc.WithHeaders(headers))
+	}
+	client, err := ethrpc.DialOptions(context.Background(), url, clientOptions...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &Provider{c: c}, nil
+	var c callCloser = client
+	if limiter != nil {
+		// innermost, so that the wait for a token counts towards the timeout of WithTimeout
+		c = &rateLimitClient{callCloser: c, limiter: limiter}
+	}
+	if timeout > 0 {
+		c = &timeoutClient{callCloser: c, timeout: timeout}
+	}
+	provider := &Provider{
+		headers:     headers,
+		limiter:     limiter,
+		timeout:     timeout,
+		logger:      logger,
+		specVersion: specVersion,
+	}
+	provider.c = &requestIDClient{callCloser: c, ids: &provider.requestIDs, logger: logger, headers: headers}
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		provider.url = url
+		provider.httpClient = httpClient
+	}
+	return provider, nil
+}
+
+// The default limits of the connection pool of the providers: http.DefaultTransport keeps only 2 idle
+// connections per host, so that the calls of hundreds of goroutines sharing a provider keep opening and
+// closing connections to the node.
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 100
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// defaultTransport is the transport of the providers created without an HTTP client, shared so that the
+// providers of the same node reuse the same connections.
+var defaultTransport = newPooledTransport()
+
+// newPooledTransport creates a transport with the settings of http.DefaultTransport, keep-alives included,
+// and the default limits of the connection pool of the providers.
+func newPooledTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = defaultMaxIdleConns
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = defaultIdleConnTimeout
+	transport.DisableKeepAlives = false
+	return transport
+}
+
+// newHTTPClient creates the HTTP client of a Provider, keeping the cookies of the node (e.g. for sticky sessions).
+// A nil transport uses the pooled transport shared by the providers, decompressing the responses.
+func newHTTPClient(transport http.RoundTripper) *http.Client {
+	if transport == nil {
+		transport = &compressionTransport{base: defaultTransport}
+	}
+	// cookiejar.New only fails on invalid options
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	return &http.Client{Jar: jar, Transport: transport}
+}
+
+// wrapTransport returns a copy of the HTTP client whose transport is wrapped, the transport of a client without
+// one being http.DefaultTransport.
+//
+// Parameters:
+// - client: the HTTP client
+// - wrap: the function wrapping the transport of the client
+// Returns:
+// - *http.Client: the copy of the client with the wrapped transport
+func wrapTransport(client *http.Client, wrap func(http.RoundTripper) http.RoundTripper) *http.Client {
+	wrapped := *client
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped.Transport = wrap(base)
+	return &wrapped
+}
+
+// httpClientOption is the option of WithHTTPClient.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type httpClientOption struct {
+	ethrpc.ClientOption
+	client *http.Client
+}
+
+// WithHTTPClient returns an option for NewProvider sending the calls with the given HTTP client, e.g. to tune
+// the connection pool of its transport for a provider shared by many goroutines. Unlike ethrpc.WithHTTPClient,
+// the client also sends the streamed calls (see TraceBlockTransactionsStream), and the calls are posted with
+// their request ID as JSON-RPC id (see RPCError.RequestID). The client is copied, its transport being wrapped
+// by the transports of the provider. Without this option, the
+// provider uses a transport keeping up to 100 idle connections per host alive.
+// As for NewProvider, the client should leave http.Client.Timeout unset.
+//
+// Parameters:
+// - client: the HTTP client of the provider
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithHTTPClient(client *http.Client) ethrpc.ClientOption {
+	return httpClientOption{ClientOption: ethrpc.WithHeaders(nil), client: client}
+}
+
+// specVersionOption is the option of WithSpecVersion.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type specVersionOption struct {
+	ethrpc.ClientOption
+	version string
+}
+
+// WithSpecVersion returns an option for NewProvider declaring the version of the JSON-RPC specification of
+// the node, e.g. "0.7.1" or "0.8.0", for the results whose layout depends on it: the execution resources of the
+// traces are sent as steps, builtins and data availability gas up to RPC 0.7, and as l1_gas, l1_data_gas and
+// l2_gas from RPC 0.8 on. Without this option, the layout of each result is detected from its fields.
+//
+// Parameters:
+// - version: the spec version of the node, as returned by SpecVersion
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithSpecVersion(version string) ethrpc.ClientOption {
+	return specVersionOption{ClientOption: ethrpc.WithHeaders(nil), version: version}
 }
 
 //go:generate mockgen -destination=../mocks/mock_rpc_provider.go -package=mocks -source=provider.go api
+
+// RpcProvider is the interface of the methods of Provider, e.g. for the code using a provider to be tested with
+// the mocks of the mocks package. It is composed of ReadProvider, TraceProvider and WriteProvider, so that the
+// code only using some of the methods can depend on the smaller interfaces.
 type RpcProvider interface {
-	AddInvokeTransaction(ctx context.Context, invokeTxn BroadcastInvokeTxnType) (*AddInvokeTransactionResponse, error)
-	AddDeclareTransaction(ctx context.Context, declareTransaction BroadcastDeclareTxnType) (*AddDeclareTransactionResponse, error)
-	AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction BroadcastAddDeployTxnType) (*AddDeployAccountTransactionResponse, error)
+	ReadProvider
+	TraceProvider
+	WriteProvider
+}
+
+// ReadProvider is the interface of the methods of Provider reading the chain: its blocks, state, classes,
+// transactions and events, and calling or estimating the fee of transactions without sending them.
+type ReadProvider interface {
 	BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error)
 	BlockNumber(ctx context.Context) (uint64, error)
 	BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error)
 	BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error)
 	BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error)
+	BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error)
 	Call(ctx context.Context, call FunctionCall, block BlockID) ([]*felt.Felt, error)
 	ChainID(ctx context.Context) (string, error)
 	Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error)
@@ -58,19 +265,30 @@ type RpcProvider interface {
 	EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error)
 	EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error)
 	Events(ctx context.Context, input EventsInput) (*EventChunk, error)
-	BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error)
 	GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error)
 	Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
-	SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error)
 	StateUpdate(ctx context.Context, blockID BlockID) (*StateUpdateOutput, error)
 	StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID BlockID) (string, error)
 	SpecVersion(ctx context.Context) (string, error)
 	Syncing(ctx context.Context) (*SyncStatus, error)
-	TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error)
 	TransactionByBlockIdAndIndex(ctx context.Context, blockID BlockID, index uint64) (*BlockTransaction, error)
 	TransactionByHash(ctx context.Context, hash *felt.Felt) (*BlockTransaction, error)
 	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*TransactionReceiptWithBlockInfo, error)
-	TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error)
+}
+
+// TraceProvider is the interface of the methods of Provider tracing the executed transactions and simulating
+// new ones.
+type TraceProvider interface {
+	SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error)
+	TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error)
+	TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TransactionTrace, error)
+}
+
+// WriteProvider is the interface of the methods of Provider sending transactions to the node.
+type WriteProvider interface {
+	AddInvokeTransaction(ctx context.Context, invokeTxn BroadcastInvokeTxnType) (*AddInvokeTransactionResponse, error)
+	AddDeclareTransaction(ctx context.Context, declareTransaction BroadcastDeclareTxnType) (*AddDeclareTransactionResponse, error)
+	AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction BroadcastAddDeployTxnType) (*AddDeployAccountTransactionResponse, error)
 }
 
 var _ RpcProvider = &Provider{}
@@ -1,19 +1,32 @@
 package rpc
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/require"
 )
@@ -158,6 +171,76 @@ func TestChainID(t *testing.T) {
 	}
 }
 
+// chainIDCounterMock is a callCloser answering starknet_chainId with SN_SEPOLIA and counting the calls.
+type chainIDCounterMock struct {
+	calls atomic.Int32
+}
+
+func (m *chainIDCounterMock) Close() {}
+
+func (m *chainIDCounterMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	m.calls.Add(1)
+	return json.Unmarshal([]byte(`"0x534e5f5345504f4c4941"`), result)
+}
+
+// TestChainIDCache tests that the chain ID is fetched once by the concurrent first calls and then served from
+// the cache, and its short string encoding.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestChainIDCache(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mock := &chainIDCounterMock{}
+	provider := &Provider{c: mock}
+
+	// the chain ID is resolved by the first call, ChainIDFelt included
+	type result struct {
+		chainID string
+		felt    *felt.Felt
+		err     error
+	}
+	results := make(chan result, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				chainID, err := provider.ChainID(context.Background())
+				results <- result{chainID: chainID, err: err}
+				return
+			}
+			chainIDFelt, err := provider.ChainIDFelt(context.Background())
+			results <- result{felt: chainIDFelt, err: err}
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+	for result := range results {
+		require.NoError(t, result.err)
+		if result.felt != nil {
+			require.Equal(t, ChainIDSepolia.Felt(), result.felt)
+		} else {
+			require.Equal(t, string(ChainIDSepolia), result.chainID)
+		}
+	}
+	require.Equal(t, int32(1), mock.calls.Load())
+
+	chainID, err := provider.ChainID(context.Background())
+	require.NoError(t, err)
+	require.True(t, ChainID(chainID).IsKnown())
+	require.Equal(t, int32(1), mock.calls.Load())
+
+	require.Equal(t, "0x534e5f5345504f4c4941", ChainIDSepolia.Felt().String())
+	require.Equal(t, "0x534e5f4d41494e", ChainIDMainnet.Felt().String())
+	require.False(t, ChainID("SN_GOERLI").IsKnown())
+}
+
 // TestSyncing tests the syncing functionality.
 //
 // It initializes a test configuration and sets up a test set. Then it loops
@@ -310,3 +393,589 @@ func TestCookieManagement(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, resp, "SN_SEPOLIA")
 }
+
+// TestContextTimeout tests that the duration of a call is bounded by its context only,
+// and that a call aborted by its deadline returns context.DeadlineExceeded.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestContextTimeout(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the timeouts are only tested against a local slow server")
+	}
+	const delay = 200 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		data := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  []interface{}{},
+		}
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			log.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(server.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*delay)
+	defer cancel()
+	_, err = provider.TraceBlockTransactions(ctx, WithBlockNumber(1))
+	require.NoError(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), delay/10)
+	defer cancel()
+	_, err = provider.TraceBlockTransactions(ctx, WithBlockNumber(1))
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	_, err = provider.BlockNumber(ctx)
+	require.Equal(t, context.DeadlineExceeded, err)
+}
+
+// TestWithTimeout tests that the default timeout bounds the calls without deadline only.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWithTimeout(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the timeouts are only tested against a local slow server")
+	}
+	const delay = 200 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		data := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  []interface{}{},
+		}
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			log.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(server.URL, WithTimeout(delay/10))
+	require.NoError(t, err)
+
+	_, err = provider.TraceBlockTransactions(context.Background(), WithBlockNumber(1))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// an explicit deadline overrides the default timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*delay)
+	defer cancel()
+	_, err = provider.TraceBlockTransactions(ctx, WithBlockNumber(1))
+	require.NoError(t, err)
+}
+
+// TestBuildRequest tests that BuildRequest builds the body posted by the provider for the same call, with the
+// next request ID of the provider as id.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBuildRequest(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the posted requests are only captured by a local server")
+	}
+	posted := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		posted <- body
+		data := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  "0x1",
+		}
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			log.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(server.URL)
+	require.NoError(t, err)
+
+	contractAddress := utils.TestHexToFelt(t, DevNetAccount032Address)
+	_, err = provider.Nonce(context.Background(), WithBlockTag(BlockTagLatest), contractAddress)
+	require.NoError(t, err)
+
+	// the request takes the next request ID of the provider, the call having taken the first one
+	req, err := provider.BuildRequest("starknet_getNonce", WithBlockTag(BlockTagLatest), contractAddress)
+	require.NoError(t, err)
+	var built jsonrpcRequest
+	require.NoError(t, json.Unmarshal(req, &built))
+	require.Equal(t, json.RawMessage("2"), built.ID)
+	built.ID = json.RawMessage("1")
+	expected, err := json.Marshal(built)
+	require.NoError(t, err)
+	require.Equal(t, string(<-posted), string(expected))
+
+	_, err = provider.BuildRequest("starknet_getNonce", BlockID{}, contractAddress)
+	require.ErrorIs(t, err, ErrInvalidBlockID)
+}
+
+// TestProviderCompression tests that the provider asks for compressed responses and decodes a trace served
+// gzip or deflate encoded, and that WithCompression(false) asks for uncompressed responses, with or without
+// WithRetry.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestProviderCompression(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the compression is only tested against a local server")
+	}
+	fixture, err := os.ReadFile("./tests/trace/specV08InvokeTrace.json")
+	require.NoError(t, err)
+
+	type testSetType struct {
+		Compression            bool
+		Retry                  bool
+		Encoding               string
+		ExpectedAcceptEncoding string
+	}
+	testSet := []testSetType{
+		{Compression: true, Encoding: "gzip", ExpectedAcceptEncoding: "gzip, deflate"},
+		{Compression: true, Encoding: "deflate", ExpectedAcceptEncoding: "gzip, deflate"},
+		{Compression: false, Encoding: "", ExpectedAcceptEncoding: ""},
+		// the retries are sent with the transport of the provider
+		{Compression: true, Retry: true, Encoding: "gzip", ExpectedAcceptEncoding: "gzip, deflate"},
+		{Compression: false, Retry: true, Encoding: "", ExpectedAcceptEncoding: ""},
+	}
+
+	for _, test := range testSet {
+		acceptEncoding := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			acceptEncoding <- r.Header.Get("Accept-Encoding")
+			var req struct {
+				ID json.RawMessage `json:"id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			body := []byte(`{"jsonrpc": "2.0", "id": ` + string(req.ID) + `, "result": ` + string(fixture) + `}`)
+			var compressed bytes.Buffer
+			switch test.Encoding {
+			case "gzip":
+				writer := gzip.NewWriter(&compressed)
+				writer.Write(body)
+				writer.Close()
+				body = compressed.Bytes()
+			case "deflate":
+				writer := zlib.NewWriter(&compressed)
+				writer.Write(body)
+				writer.Close()
+				body = compressed.Bytes()
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if test.Encoding != "" {
+				w.Header().Set("Content-Encoding", test.Encoding)
+			}
+			w.Write(body)
+		}))
+
+		options := []ethrpc.ClientOption{WithCompression(test.Compression)}
+		if test.Retry {
+			options = append(options, WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+		}
+		provider, err := NewProvider(server.URL, options...)
+		require.NoError(t, err)
+		trace, err := provider.TraceTransaction(context.Background(), utils.TestHexToFelt(t, "0x1"))
+		require.NoError(t, err)
+		require.IsType(t, InvokeTxnTrace{}, trace)
+		require.Equal(t, test.ExpectedAcceptEncoding, <-acceptEncoding)
+		server.Close()
+	}
+}
+
+// newBlockNumberServer starts a server answering starknet_chainId and starknet_blockNumber, echoing the id of
+// the requests so that concurrent calls get their own answer.
+//
+// Parameters:
+// - t: the testing object the server is closed with
+// Returns:
+// - *httptest.Server: the server
+func newBlockNumberServer(t testing.TB) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var result interface{} = 1234
+		if req.Method == "starknet_chainId" {
+			result = "0x534e5f5345504f4c4941"
+		}
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result}); err != nil {
+			log.Fatal(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// countingTransport is an http.RoundTripper counting the requests it sends.
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (rt *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests.Add(1)
+	return defaultTransport.RoundTrip(req)
+}
+
+// connTrace counts the connections opened and reused by the calls sent with its context, with httptrace.
+type connTrace struct {
+	opened atomic.Int32
+	reused atomic.Int32
+}
+
+// context returns a context tracing the connections of the HTTP requests sent with it.
+func (c *connTrace) context(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				c.reused.Add(1)
+			} else {
+				c.opened.Add(1)
+			}
+		},
+	})
+}
+
+// TestProviderConcurrentUse tests that a provider shared by many goroutines answers each of their calls,
+// reusing the connections of its pool, and that WithHTTPClient sends the calls with the given client.
+// It is meant to be run with -race.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestProviderConcurrentUse(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the concurrent calls are only tested against a local server")
+	}
+	server := newBlockNumberServer(t)
+	provider, err := NewProvider(server.URL)
+	require.NoError(t, err)
+
+	const goroutines, calls = 50, 20
+	var trace connTrace
+	ctx := trace.context(context.Background())
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				chainID, err := provider.ChainID(ctx)
+				if err == nil && chainID != "SN_SEPOLIA" {
+					err = fmt.Errorf("unexpected chain ID %q", chainID)
+				}
+				if err == nil {
+					var number uint64
+					number, err = provider.BlockNumber(ctx)
+					if err == nil && number != 1234 {
+						err = fmt.Errorf("unexpected block number %d", number)
+					}
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	// the block numbers and the single fetch of the chain ID
+	require.EqualValues(t, goroutines*calls+1, trace.opened.Load()+trace.reused.Load())
+	// the calls of the goroutines reuse the connections of the pool instead of opening one per call; a goroutine
+	// may open a second connection when its next call starts before the transport gets its connection back
+	require.LessOrEqual(t, trace.opened.Load(), int32(2*goroutines))
+	require.GreaterOrEqual(t, trace.reused.Load(), int32(goroutines*calls+1-2*goroutines))
+
+	transport := &countingTransport{}
+	provider, err = NewProvider(server.URL, WithHTTPClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+	_, err = provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), transport.requests.Load())
+	require.Equal(t, transport, provider.httpClient.Transport.(*requestIDTransport).base)
+}
+
+// TestRequestID tests that each call of a provider shared by many goroutines gets a distinct request ID,
+// returned in the RequestID of its RPCError, and that the request ID is the JSON-RPC id of the request and is
+// logged with WithDebugLogger.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRequestID(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the request IDs are only tested against the mock provider and a local server")
+	}
+	provider := NewMockProvider(map[string]json.RawMessage{
+		"starknet_getNonce": json.RawMessage(`{"error": {"code": 20, "message": "Contract not found"}}`),
+	})
+
+	const goroutines, calls = 50, 20
+	var wg sync.WaitGroup
+	requestIDs := make(chan uint64, goroutines*calls)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				_, err := provider.Nonce(context.Background(), WithBlockTag("latest"), new(felt.Felt).SetUint64(1))
+				var rpcErr *RPCError
+				if errors.As(err, &rpcErr) {
+					requestIDs <- rpcErr.RequestID
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(requestIDs)
+	seen := make(map[uint64]bool)
+	for id := range requestIDs {
+		require.NotZero(t, id)
+		require.False(t, seen[id], "duplicate request ID %d", id)
+		seen[id] = true
+	}
+	require.Len(t, seen, goroutines*calls)
+
+	ids := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ids <- string(req.ID)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": ` + string(req.ID) + `, "error": {"code": 24, "message": "Block not found"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	provider, err := NewProvider(server.URL, WithDebugLogger(logger))
+	require.NoError(t, err)
+	// the requests built by BuildRequest take their ids from the same counter
+	_, err = provider.BuildRequest("starknet_blockNumber")
+	require.NoError(t, err)
+	for _, expectedID := range []uint64{2, 3} {
+		_, err = provider.BlockWithTxHashes(context.Background(), WithBlockNumber(1))
+		require.ErrorIs(t, err, ErrBlockNotFound)
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		require.Equal(t, expectedID, rpcErr.RequestID)
+		require.Equal(t, fmt.Sprint(expectedID), <-ids)
+		require.Contains(t, logs.String(), fmt.Sprintf("request_id=%d method=starknet_getBlockWithTxHashes", expectedID))
+	}
+}
+
+// headerTransport is a mock http.RoundTripper recording the headers of the requests and answering them with the
+// result, or the error, of their method.
+type headerTransport struct {
+	mu      sync.Mutex
+	headers []http.Header
+	results map[string]string
+}
+
+func (rt *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	rt.mu.Lock()
+	rt.headers = append(rt.headers, req.Header.Clone())
+	rt.mu.Unlock()
+	result, ok := rt.results[body.Method]
+	if !ok {
+		result = `"error": {"code": 20, "message": "Contract not found"}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"jsonrpc": "2.0", "id": ` + string(body.ID) + `, ` + result + `}`)),
+		Request:    req,
+	}, nil
+}
+
+// TestWithHeaders tests that the headers of WithHeaders are sent with the calls, the streamed calls included,
+// and that the secret headers are redacted from the logs of WithDebugLogger.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWithHeaders(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the headers are only tested against a mock transport")
+	}
+	transport := &headerTransport{results: map[string]string{
+		"starknet_blockNumber":            `"result": 42`,
+		"starknet_traceBlockTransactions": `"result": []`,
+	}}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	provider, err := NewProvider("http://localhost:5050",
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithDebugLogger(logger),
+		WithHeaders(map[string]string{
+			"Authorization": "Bearer secret-token",
+			"X-Api-Key":     "secret-key",
+			"X-Client":      "starknet.go",
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	blockNumber, err := provider.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), blockNumber)
+	require.NoError(t, provider.TraceBlockTransactionsStream(ctx, WithBlockNumber(1), func(Trace) error { return nil }))
+	_, err = provider.Nonce(ctx, WithBlockTag("latest"), new(felt.Felt).SetUint64(1))
+	require.ErrorIs(t, err, ErrContractNotFound)
+
+	require.Len(t, transport.headers, 3)
+	for _, header := range transport.headers {
+		require.Equal(t, "Bearer secret-token", header.Get("Authorization"))
+		require.Equal(t, "secret-key", header.Get("X-Api-Key"))
+		require.Equal(t, "starknet.go", header.Get("X-Client"))
+	}
+
+	require.Contains(t, logs.String(), "method=starknet_getNonce")
+	require.Contains(t, logs.String(), "headers.Authorization=REDACTED headers.X-Api-Key=REDACTED headers.X-Client=starknet.go")
+	require.NotContains(t, logs.String(), "secret")
+}
+
+// TestWithRateLimit tests that the calls of a provider with WithRateLimit wait for the tokens of the limit, the
+// retries of WithRetry included, and that a call whose context is cancelled while waiting is not sent.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWithRateLimit(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the rate limit is only tested against a local server")
+	}
+	server := newBlockNumberServer(t)
+	transport := &countingTransport{}
+	const rps, calls = 5, 20
+	provider, err := NewProvider(server.URL, WithHTTPClient(&http.Client{Transport: transport}), WithRateLimit(rps, 1))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < calls; i++ {
+		_, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+	}
+	// the first call takes the token of the burst, each of the others waits for a new one
+	require.GreaterOrEqual(t, time.Since(start), (calls-1)*time.Second/rps-50*time.Millisecond)
+	require.EqualValues(t, calls, transport.requests.Load())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = provider.BlockNumber(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualValues(t, calls, transport.requests.Load())
+
+	// each retry of WithRetry takes a token too
+	const retryRPS = 20
+	node := newFlakyNode(t, 1234, flakyResponse{Status: http.StatusServiceUnavailable}, flakyResponse{Status: http.StatusServiceUnavailable})
+	provider, err = NewProvider(node.server.URL, WithRateLimit(retryRPS, 1), WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	require.NoError(t, err)
+	start = time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 6, node.calls())
+	require.GreaterOrEqual(t, time.Since(start), 5*time.Second/retryRPS-10*time.Millisecond)
+}
+
+// BenchmarkProviderConcurrentCalls benchmarks the calls of goroutines sharing a provider with the pooled
+// transport of the providers and with http.DefaultTransport, reporting the connections opened and reused by
+// the calls. With the pooled transport, the goroutines must not open more than two connections each.
+// The calls are starknet_blockNumber calls, as the chain ID is cached after the first call.
+func BenchmarkProviderConcurrentCalls(b *testing.B) {
+	const parallelism = 32
+	for _, bench := range []struct {
+		name    string
+		options []ethrpc.ClientOption
+		pooled  bool
+	}{
+		{name: "pooled", pooled: true},
+		{name: "default-transport", options: []ethrpc.ClientOption{WithHTTPClient(&http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()})}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			server := newBlockNumberServer(b)
+			provider, err := NewProvider(server.URL, bench.options...)
+			require.NoError(b, err)
+			var trace connTrace
+			ctx := trace.context(context.Background())
+			b.SetParallelism(parallelism)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := provider.BlockNumber(ctx); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.StopTimer()
+			b.ReportMetric(float64(trace.opened.Load()), "conns")
+			b.ReportMetric(float64(trace.reused.Load()), "reused-conns")
+			if goroutines := parallelism * runtime.GOMAXPROCS(0); bench.pooled && trace.opened.Load() > int32(2*goroutines) {
+				b.Fatalf("%d connections opened by %d goroutines", trace.opened.Load(), goroutines)
+			}
+		})
+	}
+}
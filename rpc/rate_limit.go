@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitOption is the option of WithRateLimit.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type rateLimitOption struct {
+	ethrpc.ClientOption
+	limiter *rate.Limiter
+}
+
+// WithRateLimit returns an option for NewProvider limiting the rate of the requests sent to the node, e.g. to
+// stay under the quota of a node provider. Each request, the streamed calls (see TraceBlockTransactionsStream)
+// and each retry of WithRetry included, takes a token from a bucket of burst tokens refilled at rps tokens per
+// second.
+// A call waits for a token when the bucket is empty rather than failing: a call whose context is done while
+// waiting returns context.Canceled or context.DeadlineExceeded without being sent. The wait counts towards the
+// default timeout of WithTimeout. A zero or negative rps disables the limit.
+//
+// Parameters:
+// - rps: the number of requests per second
+// - burst: the number of requests that can be sent at once, at least 1
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithRateLimit(rps float64, burst int) ethrpc.ClientOption {
+	var limiter *rate.Limiter
+	if rps > 0 {
+		limiter = rate.NewLimiter(rate.Limit(rps), max(burst, 1))
+	}
+	return rateLimitOption{ClientOption: ethrpc.WithHeaders(nil), limiter: limiter}
+}
+
+// rateLimitClient is a callCloser waiting for a token of its limiter before each call.
+type rateLimitClient struct {
+	callCloser
+	limiter *rate.Limiter
+}
+
+// CallContext performs the call once a token is available, or returns the error of the context.
+func (c *rateLimitClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := waitToken(ctx, c.limiter); err != nil {
+		return err
+	}
+	return c.callCloser.CallContext(ctx, result, method, args...)
+}
+
+// waitToken waits for a token of the limiter, if any.
+//
+// Parameters:
+// - ctx: the context of the call
+// - limiter: the limiter of the provider, nil without limit
+// Returns:
+// - error: the error of the context if it is done before a token is available
+func waitToken(ctx context.Context, limiter *rate.Limiter) error {
+	if limiter == nil {
+		return nil
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		// Wait also fails at once when the deadline of the context is before the next token
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return context.DeadlineExceeded
+	}
+	return nil
+}
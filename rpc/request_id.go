@@ -0,0 +1,152 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// requestIDClient is the callCloser of the providers giving each call a request ID, unique for the provider
+// even across goroutines, and attaching it to the error of a failed call. The request ID is the JSON-RPC id of
+// the request posted by an HTTP provider: the id generated by the ethrpc client cannot be read, so the
+// requestIDTransport of the provider replaces it with the request ID of the call.
+type requestIDClient struct {
+	callCloser
+	// ids is the request-ID counter of the provider, shared with BuildRequest
+	ids    *atomic.Uint64
+	logger *slog.Logger
+	// headers are the headers of WithHeaders, logged with their secret values redacted
+	headers http.Header
+}
+
+// requestIDKey is the context key of the request ID of a call, read by requestIDTransport.
+type requestIDKey struct{}
+
+// CallContext performs the call with the next request ID, logging its failure to the debug logger if any.
+func (c *requestIDClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	id := c.ids.Add(1)
+	err := c.callCloser.CallContext(context.WithValue(ctx, requestIDKey{}, id), result, method, args...)
+	if err == nil {
+		return nil
+	}
+	return failedRequest(c.logger, c.headers, id, method, err)
+}
+
+// failedRequest logs a failed request to the debug logger, if any, and attaches its request ID to its error.
+//
+// Parameters:
+// - logger: the debug logger of the provider, nil if the failures are not logged
+// - headers: the headers of WithHeaders, logged with their secret values redacted
+// - id: the request ID of the request
+// - method: the method of the request
+// - err: the error of the request
+// Returns:
+// - error: the error with the request ID of the request
+func failedRequest(logger *slog.Logger, headers http.Header, id uint64, method string, err error) error {
+	if logger != nil {
+		logger.Debug("starknet rpc call failed", "request_id", id, "method", method, "error", err,
+			"headers", loggedHeaders(headers))
+	}
+	return &requestError{id: id, err: err}
+}
+
+// requestIDTransport is the http.RoundTripper of the HTTP providers posting each call with its request ID as
+// JSON-RPC id. The requests without a request ID in their context, e.g. those of BuildRequest, which already
+// carry theirs, and the batches are sent as they are.
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request with the request ID of its context as JSON-RPC id.
+func (rt *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id, ok := req.Context().Value(requestIDKey{}).(uint64)
+	if !ok || req.Body == nil {
+		return rt.base.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var msg jsonrpcRequest
+	if err := json.Unmarshal(body, &msg); err == nil && msg.Method != "" {
+		msg.ID = strconv.AppendUint(nil, id, 10)
+		if encoded, err := json.Marshal(msg); err == nil {
+			body = encoded
+		}
+	}
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	req.ContentLength = int64(len(body))
+	return rt.base.RoundTrip(req)
+}
+
+// requestError is the error of a failed call along with its request ID, which tryUnwrapToRPCErr moves to the
+// RequestID of the returned RPCError.
+type requestError struct {
+	id  uint64
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// splitRequestID returns the request ID of the error of a call and the error without it.
+//
+// Parameters:
+// - err: the error of a call
+// Returns:
+// - uint64: the request ID of the call, 0 if the error does not carry one
+// - error: the error of the call without its request ID
+func splitRequestID(err error) (uint64, error) {
+	var reqErr *requestError
+	if !errors.As(err, &reqErr) {
+		return 0, err
+	}
+	return reqErr.id, reqErr.err
+}
+
+// internalCallErr returns the InternalError of a failed call whose error is not a JSON-RPC error of the node.
+//
+// Parameters:
+// - err: the error of the call
+// Returns:
+// - *RPCError: an InternalError with the error as data and the request ID of the call
+func internalCallErr(err error) *RPCError {
+	requestID, err := splitRequestID(err)
+	rpcErr := Err(InternalError, err)
+	rpcErr.RequestID = requestID
+	return rpcErr
+}
+
+// loggerOption is the option of WithDebugLogger.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type loggerOption struct {
+	ethrpc.ClientOption
+	logger *slog.Logger
+}
+
+// WithDebugLogger returns an option for NewProvider logging the failed calls at the debug level, with their
+// method, error and request ID, the RequestID of the RPCError they return and the JSON-RPC id of their request.
+//
+// Parameters:
+// - logger: the logger of the failed calls
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithDebugLogger(logger *slog.Logger) ethrpc.ClientOption {
+	return loggerOption{ClientOption: ethrpc.WithHeaders(nil), logger: logger}
+}
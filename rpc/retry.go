@@ -0,0 +1,267 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+// The "limit exceeded" JSON-RPC error code returned by rate-limited public endpoints
+const limitExceededCode = -32005
+
+// RetryConfig configures the retries of the transport installed by WithRetry.
+type RetryConfig struct {
+	// The maximum number of attempts of a request, including the first one
+	MaxAttempts int
+	// The delay before the first retry, doubled after every attempt
+	BaseDelay time.Duration
+	// The maximum delay between two attempts, no limit if zero
+	MaxDelay time.Duration
+	// Randomises each delay between half and all of its value, so that clients do not retry in lockstep
+	Jitter bool
+	// The transient JSON-RPC error codes to retry on, ErrNoTraceAvailable and "limit exceeded" (-32005) if empty.
+	// ErrHashNotFound, ErrBlockNotFound and the ErrNoTraceAvailable of a REJECTED transaction are terminal and
+	// never retried.
+	Codes []int
+}
+
+// retryOption is the option of WithRetry.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type retryOption struct {
+	ethrpc.ClientOption
+	config RetryConfig
+}
+
+// WithRetry returns a client option for NewProvider installing an HTTP transport that retries the
+// idempotent requests (reads, traces and simulations) failing with a transport-level error, an HTTP 429
+// or 5xx status, or one of the transient JSON-RPC error codes of the config. The Retry-After header of
+// the response is used as delay when present. Transactions sent with starknet_add* are never retried.
+// A cancelled context aborts the retries immediately and the request fails with ctx.Err().
+// The requests are retried with the transport of the provider: the transport of the client of WithHTTPClient,
+// or the pooled transport of the providers, decompressing the responses unless disabled with
+// WithCompression(false). The streamed calls (see TraceBlockTransactionsStream) are retried as well, the result
+// of a response being streamed rather than read by the retries. The option has no effect along with
+// ethrpc.WithHTTPClient, which replaces the client of the provider.
+//
+// Parameters:
+// - config: the retry configuration
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithRetry(config RetryConfig) ethrpc.ClientOption {
+	return retryOption{ClientOption: ethrpc.WithHeaders(nil), config: config}
+}
+
+// retryTransport is an http.RoundTripper retrying the requests according to a RetryConfig.
+type retryTransport struct {
+	config RetryConfig
+	codes  map[int]bool
+	base   http.RoundTripper
+	// limiter is the limiter of WithRateLimit, from which each retry takes a token, nil without limit
+	limiter *rate.Limiter
+}
+
+// newRetryTransport creates a retryTransport, applying the defaults of the config.
+//
+// Parameters:
+// - config: the retry configuration
+// - base: the transport of the provider, performing the requests
+// - limiter: the limiter of the provider, nil without limit
+// Returns:
+// - *retryTransport: the retry transport
+func newRetryTransport(config RetryConfig, base http.RoundTripper, limiter *rate.Limiter) *retryTransport {
+	if len(config.Codes) == 0 {
+		config.Codes = []int{ErrNoTraceAvailable.Code, limitExceededCode}
+	}
+	transport := &retryTransport{config: config, codes: make(map[int]bool, len(config.Codes)), base: base, limiter: limiter}
+	for _, code := range config.Codes {
+		transport.codes[code] = true
+	}
+	return transport
+}
+
+// RoundTrip performs the request until it succeeds, fails with a non retryable error, the attempts
+// are exhausted or the context of the request is done. Each retry waits for a token of the limiter.
+func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	idempotent := isIdempotentRequest(body)
+	ctx := req.Context()
+
+	delay := rt.config.BaseDelay
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.Clone(ctx)
+		attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+		resp, err := rt.base.RoundTrip(attemptReq)
+		if ctx.Err() != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, ctx.Err()
+		}
+		if !idempotent || attempt >= rt.config.MaxAttempts {
+			return resp, err
+		}
+
+		wait := rt.backoff(delay)
+		if err == nil {
+			var retry bool
+			resp, retry = rt.retryableResponse(resp)
+			if !retry {
+				return resp, nil
+			}
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		// the first attempt took its token before being sent to the transport
+		if err := waitToken(ctx, rt.limiter); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// backoff applies the maximum delay and the jitter of the config to the delay.
+func (rt *retryTransport) backoff(delay time.Duration) time.Duration {
+	if rt.config.MaxDelay > 0 && delay > rt.config.MaxDelay {
+		delay = rt.config.MaxDelay
+	}
+	if rt.config.Jitter && delay > 1 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	return delay
+}
+
+// retryableResponse reports whether the response has a retryable HTTP status or JSON-RPC error.
+// Only the members of the response preceding its result are read, so that a large result, e.g. a streamed
+// trace, is not read into memory. The body of the returned response can still be read from its start.
+func (rt *retryTransport) retryableResponse(resp *http.Response) (*http.Response, bool) {
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return resp, true
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, false
+	}
+
+	var read bytes.Buffer
+	rpcErr := responseError(json.NewDecoder(io.TeeReader(resp.Body, &read)))
+	resp.Body = &replayedBody{Reader: io.MultiReader(&read, resp.Body), body: resp.Body}
+	if rpcErr == nil {
+		return resp, false
+	}
+	code := rpcErr.Code
+	if code == ErrHashNotFound.Code || code == ErrBlockNotFound.Code {
+		return resp, false
+	}
+	// a rejected transaction will never have a trace
+	if code == ErrNoTraceAvailable.Code && traceUnavailableStatus(rpcErr.Data) == string(TxnStatus_Rejected) {
+		return resp, false
+	}
+	return resp, rt.codes[code]
+}
+
+// responseError reads the members of a JSON-RPC response until its result or its error.
+//
+// Parameters:
+// - dec: the decoder of the response
+// Returns:
+// - *RPCError: the error of the response, nil for a result, a batch or a response that cannot be decoded
+func responseError(dec *json.Decoder) *RPCError {
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		switch key {
+		case "result":
+			return nil
+		case "error":
+			var rpcErr *RPCError
+			if err := dec.Decode(&rpcErr); err != nil {
+				return nil
+			}
+			return rpcErr
+		}
+		var skipped json.RawMessage
+		if err := dec.Decode(&skipped); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// replayedBody is the body of a response whose start was read by retryableResponse, reading the start again
+// before the rest of the body.
+type replayedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *replayedBody) Close() error {
+	return b.body.Close()
+}
+
+// isIdempotentRequest reports whether a JSON-RPC request (or batch) only holds calls that can be safely
+// repeated, i.e. no starknet_add* call sending a transaction.
+func isIdempotentRequest(body []byte) bool {
+	type call struct {
+		Method string `json:"method"`
+	}
+	var calls []call
+	if err := json.Unmarshal(body, &calls); err != nil {
+		var single call
+		if err := json.Unmarshal(body, &single); err != nil {
+			return false
+		}
+		calls = []call{single}
+	}
+	for _, c := range calls {
+		if strings.HasPrefix(c.Method, "starknet_add") {
+			return false
+		}
+	}
+	return true
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+var _ http.RoundTripper = &retryTransport{}
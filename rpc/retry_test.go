@@ -0,0 +1,216 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyResponse is a response scripted on a flakyNode, a JSON-RPC response if Status is zero.
+type flakyResponse struct {
+	Status     int
+	RetryAfter string
+	Error      *RPCError
+}
+
+// flakyNode is an HTTP JSON-RPC server answering with the scripted responses, then with a successful result.
+type flakyNode struct {
+	server    *httptest.Server
+	mu        sync.Mutex
+	responses []flakyResponse
+	hits      int
+}
+
+func newFlakyNode(t *testing.T, result interface{}, responses ...flakyResponse) *flakyNode {
+	node := &flakyNode{responses: responses}
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+
+		node.mu.Lock()
+		node.hits++
+		var resp flakyResponse
+		if node.hits <= len(node.responses) {
+			resp = node.responses[node.hits-1]
+		}
+		node.mu.Unlock()
+
+		if resp.RetryAfter != "" {
+			w.Header().Set("Retry-After", resp.RetryAfter)
+		}
+		if resp.Status != 0 && resp.Status != http.StatusOK {
+			w.WriteHeader(resp.Status)
+			return
+		}
+		msg := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if resp.Error != nil {
+			msg["error"] = resp.Error
+		} else {
+			msg["result"] = result
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(msg)
+	}))
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+func (node *flakyNode) calls() int {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	return node.hits
+}
+
+// TestWithRetry tests that WithRetry retries the transient failures of read calls and stops on terminal errors,
+// with the client of WithHTTPClient and for the streamed calls too.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWithRetry(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("WithRetry is only tested against a local scripted server")
+	}
+	config := RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, Jitter: true}
+
+	type testSetType struct {
+		Responses     []flakyResponse
+		ExpectedCalls int
+		ExpectedError bool
+	}
+	testSet := []testSetType{
+		{
+			// rate limited and unavailable, then succeeds
+			Responses:     []flakyResponse{{Status: http.StatusTooManyRequests}, {Status: http.StatusServiceUnavailable}},
+			ExpectedCalls: 3,
+		},
+		{
+			// transient JSON-RPC errors
+			Responses:     []flakyResponse{{Error: ErrNoTraceAvailable}, {Error: &RPCError{Code: limitExceededCode, Message: "limit exceeded"}}},
+			ExpectedCalls: 3,
+		},
+		{
+			// attempts exhausted
+			Responses:     []flakyResponse{{Status: http.StatusBadGateway}, {Status: http.StatusBadGateway}, {Status: http.StatusBadGateway}},
+			ExpectedCalls: 3,
+			ExpectedError: true,
+		},
+		{
+			// terminal errors are not retried
+			Responses:     []flakyResponse{{Error: ErrHashNotFound}},
+			ExpectedCalls: 1,
+			ExpectedError: true,
+		},
+		{
+			// a rejected transaction will never have a trace
+			Responses:     []flakyResponse{{Error: &RPCError{Code: ErrNoTraceAvailable.Code, Message: ErrNoTraceAvailable.Message, Data: "REJECTED"}}},
+			ExpectedCalls: 1,
+			ExpectedError: true,
+		},
+		{
+			// client errors are not retried
+			Responses:     []flakyResponse{{Status: http.StatusBadRequest}},
+			ExpectedCalls: 1,
+			ExpectedError: true,
+		},
+	}
+
+	for _, test := range testSet {
+		node := newFlakyNode(t, 1234, test.Responses...)
+		provider, err := NewProvider(node.server.URL, WithRetry(config))
+		require.NoError(t, err)
+
+		blockNumber, err := provider.BlockNumber(context.Background())
+		require.Equal(t, test.ExpectedCalls, node.calls())
+		if test.ExpectedError {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, uint64(1234), blockNumber)
+	}
+
+	// the Retry-After header overrides the backoff
+	node := newFlakyNode(t, 1234, flakyResponse{Status: http.StatusTooManyRequests, RetryAfter: "1"})
+	provider, err := NewProvider(node.server.URL, WithRetry(config))
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	// the retries are sent with the client of WithHTTPClient
+	node = newFlakyNode(t, 1234, flakyResponse{Status: http.StatusServiceUnavailable}, flakyResponse{Error: ErrNoTraceAvailable})
+	transport := &countingTransport{}
+	provider, err = NewProvider(node.server.URL, WithHTTPClient(&http.Client{Transport: transport}), WithRetry(config))
+	require.NoError(t, err)
+	_, err = provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 3, transport.requests.Load())
+
+	// the streamed calls are retried, the traces of the result being streamed
+	node = newFlakyNode(t, []Trace{{TxnHash: utils.TestHexToFelt(t, "0x1")}, {TxnHash: utils.TestHexToFelt(t, "0x2")}},
+		flakyResponse{Status: http.StatusTooManyRequests}, flakyResponse{Error: ErrNoTraceAvailable})
+	provider, err = NewProvider(node.server.URL, WithRetry(config))
+	require.NoError(t, err)
+	var streamed []*felt.Felt
+	require.NoError(t, provider.TraceBlockTransactionsStream(context.Background(), WithBlockNumber(1), func(trace Trace) error {
+		streamed = append(streamed, trace.TxnHash)
+		return nil
+	}))
+	require.Equal(t, 3, node.calls())
+	require.Equal(t, []*felt.Felt{utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x2")}, streamed)
+
+	// transactions are never retried
+	node = newFlakyNode(t, map[string]string{"transaction_hash": "0x1"}, flakyResponse{Status: http.StatusServiceUnavailable})
+	provider, err = NewProvider(node.server.URL, WithRetry(config))
+	require.NoError(t, err)
+	_, err = provider.AddInvokeTransaction(context.Background(), BroadcastInvokev1Txn{InvokeTxnV1: InvokeTxnV1{
+		MaxFee:        utils.TestHexToFelt(t, "0x1"),
+		SenderAddress: utils.TestHexToFelt(t, "0x1"),
+		Nonce:         utils.TestHexToFelt(t, "0x1"),
+	}})
+	require.Error(t, err)
+	require.Equal(t, 1, node.calls())
+}
+
+// TestRetryTransportContext tests that a done context aborts the retries immediately with ctx.Err().
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRetryTransportContext(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("WithRetry is only tested against a local scripted server")
+	}
+	node := newFlakyNode(t, 1234, flakyResponse{Status: http.StatusServiceUnavailable, RetryAfter: "3600"})
+	client := &http.Client{Transport: newRetryTransport(RetryConfig{MaxAttempts: 5, BaseDelay: time.Hour}, http.DefaultTransport, nil)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, node.server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"starknet_blockNumber"}`))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, 1, node.calls())
+}
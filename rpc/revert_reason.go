@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var ErrEmptyRevertReason = errors.New("empty revert reason")
+
+// The first felt of the panic data of a Cairo 1 contract panicking with a ByteArray message
+var byteArrayPanicMagic, _ = new(felt.Felt).SetString("0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3")
+
+var (
+	// "contract address: 0x..." of the revert reasons of the nodes since Starknet 0.13,
+	// "Error in the called contract (0x...)" of the older ones
+	revertAddressRegexp  = regexp.MustCompile(`(?:contract address: |Error in the called contract \()(0x[0-9a-fA-F]+)`)
+	revertClassRegexp    = regexp.MustCompile(`class hash: (0x[0-9a-fA-F]+)`)
+	revertSelectorRegexp = regexp.MustCompile(`(?:selector: |EntryPointSelector\((?:StarkFelt\()?"?)(0x[0-9a-fA-F]+)`)
+	revertFailureRegexp  = regexp.MustCompile(`Failure reason: ?(.*)`)
+	revertMessageRegexp  = regexp.MustCompile(`Error message: ?(.*)`)
+	// the ('...') annotation of the felts of a failure reason, which may contain hexadecimal strings
+	revertAnnotationRegexp = regexp.MustCompile(`\('[^']*'\)`)
+	revertFeltRegexp       = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+)
+
+// RevertInfo is the revert reason of a reverted transaction, parsed by ParseRevertReason.
+type RevertInfo struct {
+	// The address of the innermost contract the error was raised in, nil if not found
+	ContractAddress *felt.Felt
+	// The class hash of the innermost contract the error was raised in, nil if not found
+	ClassHash *felt.Felt
+	// The selector of the innermost entry point the error was raised in, nil if not found
+	Selector *felt.Felt
+	// The felts of the failure reason, e.g. the panic data of a Cairo 1 contract
+	Data []*felt.Felt
+	// The readable message of the failure: the ByteArray of the panic data, or its felts decoded as short
+	// strings where possible (e.g. 'ENTRYPOINT_NOT_FOUND') joined by ", ", or the error message of the reason
+	Message string
+	// The revert reason as returned by the node
+	Raw string
+	// False if nothing could be parsed from the revert reason, which is then only available in Raw
+	Parsed bool
+}
+
+// ParseRevertReason parses the revert reason of a failed receipt (TransactionReceipt.RevertReason) into the
+// contract, class and entry point the error was raised in and the data of the failure. The reasons of the
+// different nodes are supported: the call stack described by "contract address", "class hash" and "selector"
+// or by "Error in the called contract (0x...)", and the failure data given as a single felt, a tuple or an
+// array of felts, with or without their short string annotations. The innermost call of the stack is kept.
+//
+// Parameters:
+// - reason: the revert reason
+// Returns:
+// - *RevertInfo: the parsed revert reason, with Parsed false if the reason has none of the known parts
+// - error: ErrEmptyRevertReason if the reason is empty
+func ParseRevertReason(reason string) (*RevertInfo, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrEmptyRevertReason
+	}
+	info := &RevertInfo{Raw: reason}
+	info.ContractAddress = lastRevertFelt(revertAddressRegexp, reason)
+	info.ClassHash = lastRevertFelt(revertClassRegexp, reason)
+	info.Selector = lastRevertFelt(revertSelectorRegexp, reason)
+
+	if failure := lastRevertMatch(revertFailureRegexp, reason); failure != "" {
+		for _, hex := range revertFeltRegexp.FindAllString(revertAnnotationRegexp.ReplaceAllString(failure, ""), -1) {
+			if f, err := new(felt.Felt).SetString(hex); err == nil {
+				info.Data = append(info.Data, f)
+			}
+		}
+		if len(info.Data) == 0 {
+			info.Message = strings.TrimSuffix(strings.TrimSpace(failure), ".")
+		}
+	}
+	if len(info.Data) > 0 {
+		info.Message = revertDataMessage(info.Data)
+	} else if info.Message == "" {
+		info.Message = strings.TrimSpace(lastRevertMatch(revertMessageRegexp, reason))
+	}
+
+	info.Parsed = info.ContractAddress != nil || info.Selector != nil || info.Message != ""
+	return info, nil
+}
+
+// lastRevertMatch returns the first group of the last match of the regexp in the revert reason.
+//
+// Parameters:
+// - re: the regexp, with one group
+// - reason: the revert reason
+// Returns:
+// - string: the group of the last match, empty if there is none
+func lastRevertMatch(re *regexp.Regexp, reason string) string {
+	matches := re.FindAllStringSubmatch(reason, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1][1]
+}
+
+// lastRevertFelt returns the felt of the last match of the regexp in the revert reason.
+//
+// Parameters:
+// - re: the regexp, with one group matching a hexadecimal felt
+// - reason: the revert reason
+// Returns:
+// - *felt.Felt: the felt of the last match, nil if there is none
+func lastRevertFelt(re *regexp.Regexp, reason string) *felt.Felt {
+	f, err := new(felt.Felt).SetString(lastRevertMatch(re, reason))
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// revertDataMessage returns the readable message of the felts of a failure.
+//
+// Parameters:
+// - data: the felts of the failure
+// Returns:
+// - string: the ByteArray message of a panic with a ByteArray, or else the felts decoded as short strings
+// where they are printable and as hexadecimal otherwise, joined by ", "
+func revertDataMessage(data []*felt.Felt) string {
+	// a ByteArray panic is [magic, words count, words..., pending word, pending word length]
+	if len(data) >= 4 && data[0].Equal(byteArrayPanicMagic) && data[1].Equal(new(felt.Felt).SetUint64(uint64(len(data)-4))) {
+		if message, err := utils.ByteArrFeltToString(data[1:]); err == nil {
+			return message
+		}
+	}
+	parts := make([]string, len(data))
+	for i, f := range data {
+		parts[i] = f.String()
+		if s := utils.FeltToStr(f); s != "" && isPrintableASCII(s) {
+			parts[i] = s
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isPrintableASCII reports whether the string is made of printable ASCII characters only.
+//
+// Parameters:
+// - s: the string
+// Returns:
+// - bool: true if every byte of s is between ' ' and '~'
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
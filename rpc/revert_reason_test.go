@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRevertReason tests the parsing of the revert reasons of the different nodes.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestParseRevertReason(t *testing.T) {
+	type testSetType struct {
+		Reason          string
+		ContractAddress *felt.Felt
+		ClassHash       *felt.Felt
+		Selector        *felt.Felt
+		Data            []*felt.Felt
+		Message         string
+		Parsed          bool
+	}
+	testSet := []testSetType{
+		{
+			// call stack since Starknet 0.13, with a tuple of annotated felts
+			Reason: "Transaction execution has failed:\n" +
+				"0: Error in the called contract (contract address: 0x0123, class hash: 0x0456, selector: 0x015d40a3d6ca2ac30f4031e42be28da9b056fef9bb7357ac5e85627ee876e5ad):\n" +
+				"Error at pc=0:4835:\nCairo traceback (most recent call last):\nUnknown location (pc=0:67)\n" +
+				"1: Error in the called contract (contract address: 0x0789, class hash: 0x0abc, selector: 0x0def):\n" +
+				"Execution failed. Failure reason: (0x4f7574206f6620676173 ('Out of gas'), 0x454e545259504f494e545f4641494c4544 ('ENTRYPOINT_FAILED')).\n",
+			ContractAddress: utils.TestHexToFelt(t, "0x789"),
+			ClassHash:       utils.TestHexToFelt(t, "0xabc"),
+			Selector:        utils.TestHexToFelt(t, "0xdef"),
+			Data:            []*felt.Felt{utils.TestHexToFelt(t, "0x4f7574206f6620676173"), utils.TestHexToFelt(t, "0x454e545259504f494e545f4641494c4544")},
+			Message:         "Out of gas, ENTRYPOINT_FAILED",
+			Parsed:          true,
+		},
+		{
+			// older call stack, with a single felt
+			Reason: "Error in the called contract (0x0123):\nError at pc=0:104:\n" +
+				"Execution failed. Failure reason: 0x617267656e742f696e76616c69642d7369676e6174757265 ('argent/invalid-signature').",
+			ContractAddress: utils.TestHexToFelt(t, "0x123"),
+			Data:            []*felt.Felt{utils.TestHexToFelt(t, "0x617267656e742f696e76616c69642d7369676e6174757265")},
+			Message:         "argent/invalid-signature",
+			Parsed:          true,
+		},
+		{
+			// array of felts without annotations, with a felt that is not a short string
+			Reason:   `Entry point EntryPointSelector(StarkFelt("0x0000000000000000000000000000000000000000000000000000000000000abc")) not found in contract. Failure reason: ["0x454e545259504f494e545f4e4f545f464f554e44", "0x1"]`,
+			Selector: utils.TestHexToFelt(t, "0xabc"),
+			Data:     []*felt.Felt{utils.TestHexToFelt(t, "0x454e545259504f494e545f4e4f545f464f554e44"), utils.TestHexToFelt(t, "0x1")},
+			Message:  "ENTRYPOINT_NOT_FOUND, 0x1",
+			Parsed:   true,
+		},
+		{
+			// ByteArray panic
+			Reason: "Error in the called contract (contract address: 0x0123, class hash: 0x0456, selector: 0x0789):\n" +
+				"Execution failed. Failure reason: (0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, 0x0, 0x68656c6c6f, 0x5).",
+			ContractAddress: utils.TestHexToFelt(t, "0x123"),
+			ClassHash:       utils.TestHexToFelt(t, "0x456"),
+			Selector:        utils.TestHexToFelt(t, "0x789"),
+			Data: []*felt.Felt{
+				utils.TestHexToFelt(t, "0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3"),
+				utils.TestHexToFelt(t, "0x0"), utils.TestHexToFelt(t, "0x68656c6c6f"), utils.TestHexToFelt(t, "0x5"),
+			},
+			Message: "hello",
+			Parsed:  true,
+		},
+		{
+			// Cairo 0 error message
+			Reason:          "Error in the called contract (0x0123):\nError message: Ownable: caller is not the owner\nError at pc=0:12:\nAn ASSERT_EQ instruction failed: 1 != 0.",
+			ContractAddress: utils.TestHexToFelt(t, "0x123"),
+			Message:         "Ownable: caller is not the owner",
+			Parsed:          true,
+		},
+		{
+			Reason: "Insufficient max L1 gas",
+		},
+	}
+
+	for _, test := range testSet {
+		info, err := ParseRevertReason(test.Reason)
+		require.NoError(t, err)
+		require.Equal(t, test.Reason, info.Raw)
+		require.Equal(t, test.ContractAddress, info.ContractAddress)
+		require.Equal(t, test.ClassHash, info.ClassHash)
+		require.Equal(t, test.Selector, info.Selector)
+		require.Equal(t, test.Data, info.Data)
+		require.Equal(t, test.Message, info.Message)
+		require.Equal(t, test.Parsed, info.Parsed)
+	}
+
+	_, err := ParseRevertReason(" ")
+	require.ErrorIs(t, err, ErrEmptyRevertReason)
+}
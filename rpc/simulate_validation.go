@@ -0,0 +1,150 @@
+package rpc
+
+import "fmt"
+
+// MissingTxnFieldError reports a transaction of a simulation missing a field required for its type.
+type MissingTxnFieldError struct {
+	// The index of the transaction in the simulated transactions
+	Index int
+	// The type and version of the transaction, e.g. "invoke v3"
+	Txn string
+	// The JSON name of the missing field, e.g. "resource_bounds"
+	Field string
+}
+
+// Error returns the index, type and missing field of the transaction.
+func (e *MissingTxnFieldError) Error() string {
+	return fmt.Sprintf("txn[%d]: %s missing %s", e.Index, e.Txn, e.Field)
+}
+
+// txnField is a field required by a transaction type, along whether it is missing.
+type txnField struct {
+	name    string
+	missing bool
+}
+
+// Validate checks that the simulation flags are defined by the spec and that each transaction has the
+// fields required for its type, e.g. the sender address, nonce and calldata of an invoke v1 transaction
+// and also the resource bounds of an invoke v3 transaction. The transactions of unknown types are not checked.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: a *MissingTxnFieldError for the first transaction missing a field, nil if the input is valid
+func (input SimulateTransactionInput) Validate() error {
+	if err := validateSimulationFlags(input.SimulationFlags); err != nil {
+		return err
+	}
+	return validateTxns(input.Txns)
+}
+
+// validateTxns checks that each transaction has the fields required for its type.
+//
+// Parameters:
+// - txns: the transactions to validate
+// Returns:
+// - error: a *MissingTxnFieldError for the first transaction missing a field, nil otherwise
+func validateTxns(txns []Transaction) error {
+	for i, txn := range txns {
+		name, fields := requiredTxnFields(txn)
+		for _, field := range fields {
+			if field.missing {
+				return &MissingTxnFieldError{Index: i, Txn: name, Field: field.name}
+			}
+		}
+	}
+	return nil
+}
+
+// requiredTxnFields returns the name of the transaction type and its required fields.
+//
+// Parameters:
+// - txn: the transaction
+// Returns:
+// - string: the type and version of the transaction
+// - []txnField: the required fields of the transaction, nil for the unknown types
+func requiredTxnFields(txn Transaction) (string, []txnField) {
+	switch tx := txn.(type) {
+	case BroadcastInvokev0Txn:
+		return requiredTxnFields(tx.InvokeTxnV0)
+	case BroadcastInvokev1Txn:
+		return requiredTxnFields(tx.InvokeTxnV1)
+	case BroadcastInvokev3Txn:
+		return requiredTxnFields(tx.InvokeTxnV3)
+	case BroadcastDeployAccountTxn:
+		return requiredTxnFields(tx.DeployAccountTxn)
+	case BroadcastDeployAccountTxnV3:
+		return requiredTxnFields(tx.DeployAccountTxnV3)
+	case InvokeTxnV0:
+		return "invoke v0", []txnField{
+			{"contract_address", tx.ContractAddress == nil},
+			{"entry_point_selector", tx.EntryPointSelector == nil},
+			{"max_fee", tx.MaxFee == nil},
+		}
+	case InvokeTxnV1:
+		return "invoke v1", []txnField{
+			{"sender_address", tx.SenderAddress == nil},
+			{"nonce", tx.Nonce == nil},
+			{"calldata", len(tx.Calldata) == 0},
+			{"max_fee", tx.MaxFee == nil},
+		}
+	case InvokeTxnV3:
+		return "invoke v3", []txnField{
+			{"sender_address", tx.SenderAddress == nil},
+			{"nonce", tx.Nonce == nil},
+			{"calldata", len(tx.Calldata) == 0},
+			{"resource_bounds", resourceBoundsMissing(tx.ResourceBounds)},
+		}
+	case DeclareTxnV1:
+		return "declare v1", []txnField{
+			{"sender_address", tx.SenderAddress == nil},
+			{"nonce", tx.Nonce == nil},
+			{"class_hash", tx.ClassHash == nil},
+			{"max_fee", tx.MaxFee == nil},
+		}
+	case DeclareTxnV2:
+		return "declare v2", []txnField{
+			{"sender_address", tx.SenderAddress == nil},
+			{"nonce", tx.Nonce == nil},
+			{"class_hash", tx.ClassHash == nil},
+			{"compiled_class_hash", tx.CompiledClassHash == nil},
+			{"max_fee", tx.MaxFee == nil},
+		}
+	case DeclareTxnV3:
+		return "declare v3", []txnField{
+			{"sender_address", tx.SenderAddress == nil},
+			{"nonce", tx.Nonce == nil},
+			{"class_hash", tx.ClassHash == nil},
+			{"compiled_class_hash", tx.CompiledClassHash == nil},
+			{"resource_bounds", resourceBoundsMissing(tx.ResourceBounds)},
+		}
+	case DeployAccountTxn:
+		return "deploy account v1", []txnField{
+			{"nonce", tx.Nonce == nil},
+			{"class_hash", tx.ClassHash == nil},
+			{"contract_address_salt", tx.ContractAddressSalt == nil},
+			{"max_fee", tx.MaxFee == nil},
+		}
+	case DeployAccountTxnV3:
+		return "deploy account v3", []txnField{
+			{"nonce", tx.Nonce == nil},
+			{"class_hash", tx.ClassHash == nil},
+			{"contract_address_salt", tx.ContractAddressSalt == nil},
+			{"resource_bounds", resourceBoundsMissing(tx.ResourceBounds)},
+		}
+	}
+	return "", nil
+}
+
+// resourceBoundsMissing reports whether the resource bounds of a v3 transaction are unset.
+//
+// Parameters:
+// - bounds: the resource bounds of the transaction
+// Returns:
+// - bool: true if a bound of the L1 or L2 gas is empty
+func resourceBoundsMissing(bounds ResourceBoundsMapping) bool {
+	return bounds.L1Gas.MaxAmount == "" || bounds.L1Gas.MaxPricePerUnit == "" ||
+		bounds.L2Gas.MaxAmount == "" || bounds.L2Gas.MaxPricePerUnit == ""
+}
@@ -0,0 +1,213 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+type StateDiffMismatchKind string
+
+const (
+	StateDiffMismatchStorage                 StateDiffMismatchKind = "storage"
+	StateDiffMismatchNonce                   StateDiffMismatchKind = "nonce"
+	StateDiffMismatchDeclaredClass           StateDiffMismatchKind = "declared_class"
+	StateDiffMismatchDeprecatedDeclaredClass StateDiffMismatchKind = "deprecated_declared_class"
+	StateDiffMismatchDeployedContract        StateDiffMismatchKind = "deployed_contract"
+	StateDiffMismatchReplacedClass           StateDiffMismatchKind = "replaced_class"
+)
+
+// StateDiffMismatch is an entry of a state diff that is missing from, or has another value in, the other state diff.
+type StateDiffMismatch struct {
+	Kind StateDiffMismatchKind
+	// The contract address of the entry, nil for the declared classes
+	Address *felt.Felt
+	// The storage key of a storage entry, or the class hash of a declared class
+	Key *felt.Felt
+	// The value of the entry in each state diff (storage value, nonce, compiled class hash or class hash), nil if missing
+	A, B *felt.Felt
+}
+
+// String returns a readable description of the mismatch.
+func (m StateDiffMismatch) String() string {
+	target := string(m.Kind)
+	if m.Address != nil {
+		target += " " + m.Address.String()
+	}
+	if m.Key != nil {
+		target += " [" + m.Key.String() + "]"
+	}
+	return fmt.Sprintf("%s: %s != %s", target, feltOrMissing(m.A), feltOrMissing(m.B))
+}
+
+// StateDiffEqual compares two state diffs, e.g. the state diff of a simulation and the one of the executed
+// transaction trace. The entries are matched by their felt values, so that differences in the hex formatting
+// or the order of the entries returned by the node are ignored.
+//
+// Parameters:
+// - a: the first state diff
+// - b: the second state diff
+// Returns:
+// - bool: true if the state diffs hold the same entries
+// - []StateDiffMismatch: the entries that differ, the entries of a first in their order, then the entries only in b
+func StateDiffEqual(a, b StateDiff) (bool, []StateDiffMismatch) {
+	var mismatches []StateDiffMismatch
+	mismatches = append(mismatches, compareDiffEntries(StateDiffMismatchStorage, storageDiffEntries(a), storageDiffEntries(b))...)
+	mismatches = append(mismatches, compareDiffEntries(StateDiffMismatchNonce, nonceDiffEntries(a), nonceDiffEntries(b))...)
+	mismatches = append(mismatches, compareDiffEntries(StateDiffMismatchDeclaredClass, declaredClassDiffEntries(a), declaredClassDiffEntries(b))...)
+	mismatches = append(mismatches, compareDiffEntries(StateDiffMismatchDeprecatedDeclaredClass, deprecatedDeclaredClassDiffEntries(a), deprecatedDeclaredClassDiffEntries(b))...)
+	mismatches = append(mismatches, compareDiffEntries(StateDiffMismatchDeployedContract, deployedContractDiffEntries(a), deployedContractDiffEntries(b))...)
+	mismatches = append(mismatches, compareDiffEntries(StateDiffMismatchReplacedClass, replacedClassDiffEntries(a), replacedClassDiffEntries(b))...)
+	return len(mismatches) == 0, mismatches
+}
+
+var (
+	ErrDuplicateDeclaredClass = errors.New("class declared twice in the state diff")
+	ErrMissingClassHash       = errors.New("missing class hash")
+	ErrZeroCompiledClassHash  = errors.New("zero compiled class hash")
+)
+
+// ValidateDeclaredClasses checks the declared classes of a state diff, e.g. the state diff of a simulated declare
+// transaction before broadcasting it: each class must have a class hash, declared only once in the state diff
+// and not already declared on chain, and a non-zero compiled class hash. All the problems are returned rather
+// than only the first one.
+//
+// Parameters:
+// - diff: the state diff
+// - alreadyDeclared: reports whether a class is already declared on chain (e.g. with a call to Class), nil to skip the check
+// Returns:
+// - []error: the problems in the order of the declared classes, wrapping ErrMissingClassHash, ErrDuplicateDeclaredClass,
+// ErrClassAlreadyDeclared (the error returned by the node for such a declare) or ErrZeroCompiledClassHash, nil
+// if the declared classes are consistent
+func ValidateDeclaredClasses(diff StateDiff, alreadyDeclared func(classHash *felt.Felt) bool) []error {
+	var errs []error
+	seen := make(map[string]bool, len(diff.DeclaredClasses))
+	for i, class := range diff.DeclaredClasses {
+		if class.ClassHash == nil {
+			errs = append(errs, fmt.Errorf("declared_classes[%d]: %w", i, ErrMissingClassHash))
+		} else {
+			key := feltKey(class.ClassHash)
+			if seen[key] {
+				errs = append(errs, fmt.Errorf("declared_classes[%d] %s: %w", i, key, ErrDuplicateDeclaredClass))
+			} else if alreadyDeclared != nil && alreadyDeclared(class.ClassHash) {
+				errs = append(errs, fmt.Errorf("declared_classes[%d] %s: %w", i, key, ErrClassAlreadyDeclared))
+			}
+			seen[key] = true
+		}
+		if class.CompiledClassHash == nil || class.CompiledClassHash.IsZero() {
+			errs = append(errs, fmt.Errorf("declared_classes[%d] %s: %w", i, feltOrMissing(class.ClassHash), ErrZeroCompiledClassHash))
+		}
+	}
+	return errs
+}
+
+// diffEntry is an entry of a state diff, identified by its address and key.
+type diffEntry struct {
+	address, key, value *felt.Felt
+}
+
+func (e diffEntry) id() string {
+	return feltKey(e.address) + "/" + feltKey(e.key)
+}
+
+// compareDiffEntries returns the mismatches between the entries of a kind in two state diffs.
+func compareDiffEntries(kind StateDiffMismatchKind, a, b []diffEntry) []StateDiffMismatch {
+	bValues := make(map[string]*felt.Felt, len(b))
+	for _, entry := range b {
+		bValues[entry.id()] = entry.value
+	}
+
+	var mismatches []StateDiffMismatch
+	seen := make(map[string]bool, len(a))
+	for _, entry := range a {
+		id := entry.id()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		bValue, ok := bValues[id]
+		if ok && feltKey(bValue) == feltKey(entry.value) {
+			continue
+		}
+		mismatch := StateDiffMismatch{Kind: kind, Address: entry.address, Key: entry.key, A: entry.value}
+		if ok {
+			mismatch.B = bValue
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	for _, entry := range b {
+		id := entry.id()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		mismatches = append(mismatches, StateDiffMismatch{Kind: kind, Address: entry.address, Key: entry.key, B: bValues[id]})
+	}
+	return mismatches
+}
+
+func storageDiffEntries(diff StateDiff) []diffEntry {
+	var entries []diffEntry
+	for _, item := range diff.StorageDiffs {
+		for _, storage := range item.StorageEntries {
+			entries = append(entries, diffEntry{address: item.Address, key: storage.Key, value: storage.Value})
+		}
+	}
+	return entries
+}
+
+func nonceDiffEntries(diff StateDiff) []diffEntry {
+	entries := make([]diffEntry, len(diff.Nonces))
+	for i, nonce := range diff.Nonces {
+		entries[i] = diffEntry{address: nonce.ContractAddress, value: nonce.Nonce}
+	}
+	return entries
+}
+
+func declaredClassDiffEntries(diff StateDiff) []diffEntry {
+	entries := make([]diffEntry, len(diff.DeclaredClasses))
+	for i, class := range diff.DeclaredClasses {
+		entries[i] = diffEntry{key: class.ClassHash, value: class.CompiledClassHash}
+	}
+	return entries
+}
+
+func deprecatedDeclaredClassDiffEntries(diff StateDiff) []diffEntry {
+	entries := make([]diffEntry, len(diff.DeprecatedDeclaredClasses))
+	for i, classHash := range diff.DeprecatedDeclaredClasses {
+		entries[i] = diffEntry{key: classHash, value: classHash}
+	}
+	return entries
+}
+
+func deployedContractDiffEntries(diff StateDiff) []diffEntry {
+	entries := make([]diffEntry, len(diff.DeployedContracts))
+	for i, contract := range diff.DeployedContracts {
+		entries[i] = diffEntry{address: contract.Address, value: contract.ClassHash}
+	}
+	return entries
+}
+
+func replacedClassDiffEntries(diff StateDiff) []diffEntry {
+	entries := make([]diffEntry, len(diff.ReplacedClasses))
+	for i, class := range diff.ReplacedClasses {
+		entries[i] = diffEntry{address: class.ContractClass, value: class.ClassHash}
+	}
+	return entries
+}
+
+// feltKey returns the normalized hex representation of a felt, or an empty string if it is nil.
+func feltKey(f *felt.Felt) string {
+	if f == nil {
+		return ""
+	}
+	return f.String()
+}
+
+func feltOrMissing(f *felt.Felt) string {
+	if f == nil {
+		return "missing"
+	}
+	return f.String()
+}
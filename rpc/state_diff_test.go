@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStateDiffEqual tests that StateDiffEqual ignores the felt formatting and the order of the entries,
+// and reports each differing entry.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestStateDiffEqual(t *testing.T) {
+	var simulated, executed StateDiff
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"storage_diffs": [
+			{"address": "0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7", "storage_entries": [
+				{"key": "0x01", "value": "0x0a"},
+				{"key": "0x2", "value": "0xb"}
+			]}
+		],
+		"deprecated_declared_classes": [],
+		"declared_classes": [{"class_hash": "0x0abc", "compiled_class_hash": "0x0def"}],
+		"deployed_contracts": [{"address": "0x5", "class_hash": "0x6"}],
+		"replaced_classes": [],
+		"nonces": [{"contract_address": "0x07", "nonce": "0x1"}, {"contract_address": "0x8", "nonce": "0x2"}]
+	}`), &simulated))
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"storage_diffs": [
+			{"address": "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7", "storage_entries": [
+				{"key": "0x2", "value": "0x0b"},
+				{"key": "0x1", "value": "0xa"}
+			]}
+		],
+		"deprecated_declared_classes": [],
+		"declared_classes": [{"class_hash": "0xabc", "compiled_class_hash": "0xdef"}],
+		"deployed_contracts": [{"address": "0x05", "class_hash": "0x06"}],
+		"replaced_classes": [],
+		"nonces": [{"contract_address": "0x8", "nonce": "0x02"}, {"contract_address": "0x7", "nonce": "0x01"}]
+	}`), &executed))
+
+	equal, mismatches := StateDiffEqual(simulated, executed)
+	require.True(t, equal)
+	require.Empty(t, mismatches)
+
+	// a storage value differs, a nonce is missing and an extra contract is deployed
+	executed.StorageDiffs[0].StorageEntries[0].Value = utils.TestHexToFelt(t, "0xc")
+	executed.Nonces = executed.Nonces[:1]
+	executed.DeployedContracts = append(executed.DeployedContracts, DeployedContractItem{
+		Address:   utils.TestHexToFelt(t, "0x9"),
+		ClassHash: utils.TestHexToFelt(t, "0x6"),
+	})
+
+	equal, mismatches = StateDiffEqual(simulated, executed)
+	require.False(t, equal)
+	require.Equal(t, []StateDiffMismatch{
+		{
+			Kind:    StateDiffMismatchStorage,
+			Address: simulated.StorageDiffs[0].Address,
+			Key:     utils.TestHexToFelt(t, "0x2"),
+			A:       utils.TestHexToFelt(t, "0xb"),
+			B:       utils.TestHexToFelt(t, "0xc"),
+		},
+		{
+			Kind:    StateDiffMismatchNonce,
+			Address: utils.TestHexToFelt(t, "0x7"),
+			A:       utils.TestHexToFelt(t, "0x1"),
+		},
+		{
+			Kind:    StateDiffMismatchDeployedContract,
+			Address: utils.TestHexToFelt(t, "0x9"),
+			B:       utils.TestHexToFelt(t, "0x6"),
+		},
+	}, mismatches)
+	require.Equal(t, "nonce 0x7: 0x1 != missing", mismatches[1].String())
+}
+
+// TestValidateDeclaredClasses tests that ValidateDeclaredClasses reports every inconsistent declared class of a
+// state diff, in order.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestValidateDeclaredClasses(t *testing.T) {
+	var diff StateDiff
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"declared_classes": [
+			{"class_hash": "0x1", "compiled_class_hash": "0xa"},
+			{"class_hash": "0x2", "compiled_class_hash": "0x0"},
+			{"class_hash": "0x3", "compiled_class_hash": "0xc"},
+			{"class_hash": "0x01", "compiled_class_hash": "0xa"},
+			{"compiled_class_hash": "0xd"}
+		]
+	}`), &diff))
+	onChain := utils.TestHexToFelt(t, "0x3")
+	alreadyDeclared := func(classHash *felt.Felt) bool {
+		return classHash.Equal(onChain)
+	}
+
+	errs := ValidateDeclaredClasses(diff, alreadyDeclared)
+	require.Len(t, errs, 4)
+	require.ErrorIs(t, errs[0], ErrZeroCompiledClassHash)
+	require.EqualError(t, errs[0], "declared_classes[1] 0x2: zero compiled class hash")
+	require.ErrorIs(t, errs[1], ErrClassAlreadyDeclared)
+	require.EqualError(t, errs[1], "declared_classes[2] 0x3: Class already declared")
+	require.ErrorIs(t, errs[2], ErrDuplicateDeclaredClass)
+	require.ErrorIs(t, errs[3], ErrMissingClassHash)
+
+	// without the lookup, only the state diff itself is checked
+	require.Len(t, ValidateDeclaredClasses(diff, nil), 3)
+	require.Empty(t, ValidateDeclaredClasses(StateDiff{DeclaredClasses: diff.DeclaredClasses[:1]}, alreadyDeclared))
+}
@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// timeoutOption is the option of WithTimeout.
+// It embeds a no-op ethrpc.ClientOption so that it is accepted along with the client options.
+type timeoutOption struct {
+	ethrpc.ClientOption
+	timeout time.Duration
+}
+
+// WithTimeout returns an option for NewProvider bounding the duration of the calls whose context has
+// no deadline: their context is given the default timeout, while the calls whose context carries its
+// own deadline are left untouched. A call exceeding the timeout returns context.DeadlineExceeded.
+// The streamed calls (see TraceBlockTransactionsStream) are bounded by the timeout as well.
+//
+// Parameters:
+// - timeout: the default timeout of a call
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithTimeout(timeout time.Duration) ethrpc.ClientOption {
+	return timeoutOption{ClientOption: ethrpc.WithHeaders(nil), timeout: timeout}
+}
+
+// timeoutClient is a callCloser applying a default timeout to the calls without deadline.
+type timeoutClient struct {
+	callCloser
+	timeout time.Duration
+}
+
+// CallContext performs the call, with the default timeout if the context has no deadline.
+func (c *timeoutClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.callCloser.CallContext(ctx, result, method, args...)
+}
+
+// withDefaultTimeout gives the default timeout to a context without deadline.
+//
+// Parameters:
+// - ctx: the context of the call
+// - timeout: the default timeout, none if zero or negative
+// Returns:
+// - context.Context: the context with the default timeout, or ctx if it has a deadline or there is no timeout
+// - context.CancelFunc: the function releasing the resources of the timeout
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -1,8 +1,16 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/NethermindEth/juno/core/felt"
 )
@@ -14,51 +22,164 @@ import (
 //   - transactionHash: the transaction hash to trace
 //
 // Returns:
-//   - TxnTrace: the transaction trace
-//   - error: an error if the transaction trace cannot be retrieved
-func (provider *Provider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error) {
-	var rawTxnTrace map[string]any
-	if err := do(ctx, provider.c, "starknet_traceTransaction", &rawTxnTrace, transactionHash); err != nil {
-		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound, ErrNoTraceAvailable)
+//   - TransactionTrace: the transaction trace, of the concrete type of the transaction type (see its As methods)
+//   - error: a *TraceUnavailableError if the node has no trace of the transaction yet, or an error if the
+//     transaction trace cannot be retrieved
+func (provider *Provider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TransactionTrace, error) {
+	trace, _, err := provider.TraceTransactionWithRaw(ctx, transactionHash)
+	return trace, err
+}
+
+// TraceTransactionWithRaw returns the transaction trace for the given transaction hash, along with the
+// result of the call as received from the node, e.g. to log the fields not modelled by the trace types.
+//
+// Parameters:
+//   - ctx: the context.Context object for the request
+//   - transactionHash: the transaction hash to trace
+//
+// Returns:
+//   - TransactionTrace: the transaction trace, of the concrete type of the transaction type
+//   - json.RawMessage: the JSON trace sent by the node, the result object of the response only
+//   - error: a *TraceUnavailableError if the node has no trace of the transaction yet, or an error if the
+//     transaction trace cannot be retrieved
+func (provider *Provider) TraceTransactionWithRaw(ctx context.Context, transactionHash *felt.Felt) (TransactionTrace, json.RawMessage, error) {
+	var rawTrace json.RawMessage
+	if err := do(ctx, provider.c, "starknet_traceTransaction", &rawTrace, transactionHash); err != nil {
+		return nil, nil, traceUnavailableErr(tryUnwrapToRPCErr(err, ErrHashNotFound, ErrNoTraceAvailable))
+	}
+	trace, err := decodeTxnTrace(rawTrace, provider.knownSpecVersion())
+	if err != nil {
+		return nil, rawTrace, err
+	}
+	return trace, rawTrace, nil
+}
+
+// decodeTxnTrace decodes a transaction trace into the trace type of its transaction type, with the execution
+// resources of the layout of the spec version (see WithSpecVersion).
+//
+// Parameters:
+//   - rawTrace: the JSON trace
+//   - specVersion: the spec version of the node, empty to detect the layout of the execution resources
+//
+// Returns:
+//   - TransactionTrace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace
+//   - error: an InternalError if the trace cannot be decoded or its type is unknown
+func decodeTxnTrace(rawTrace json.RawMessage, specVersion string) (TransactionTrace, error) {
+	var header struct {
+		Type TransactionType `json:"type"`
+	}
+	if err := json.Unmarshal(rawTrace, &header); err != nil {
+		return nil, Err(InternalError, err)
 	}
 
-	rawTraceByte, err := json.Marshal(rawTxnTrace)
+	var trace TransactionTrace
+	var err error
+	switch header.Type {
+	case TransactionType_Invoke:
+		var invokeTrace InvokeTxnTrace
+		err = json.Unmarshal(rawTrace, &invokeTrace)
+		trace = invokeTrace
+	case TransactionType_Declare:
+		var declareTrace DeclareTxnTrace
+		err = json.Unmarshal(rawTrace, &declareTrace)
+		trace = declareTrace
+	case TransactionType_DeployAccount:
+		var deployAccountTrace DeployAccountTxnTrace
+		err = json.Unmarshal(rawTrace, &deployAccountTrace)
+		trace = deployAccountTrace
+	case TransactionType_L1Handler:
+		var l1HandlerTrace L1HandlerTxnTrace
+		err = json.Unmarshal(rawTrace, &l1HandlerTrace)
+		trace = l1HandlerTrace
+	default:
+		return nil, Err(InternalError, "Unknown transaction type")
+	}
 	if err != nil {
 		return nil, Err(InternalError, err)
 	}
+	return withResourcesLayout(trace, specVersion).(TransactionTrace), nil
+}
 
-	switch rawTxnTrace["type"] {
-	case string(TransactionType_Invoke):
-		var trace InvokeTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
-		if err != nil {
-			return nil, Err(InternalError, err)
-		}
-		return trace, nil
-	case string(TransactionType_Declare):
-		var trace DeclareTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
-		if err != nil {
-			return nil, Err(InternalError, err)
-		}
-		return trace, nil
-	case string(TransactionType_DeployAccount):
-		var trace DeployAccountTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
-		if err != nil {
-			return nil, Err(InternalError, err)
-		}
-		return trace, nil
-	case string(TransactionType_L1Handler):
-		var trace L1HandlerTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
-		if err != nil {
-			return nil, Err(InternalError, err)
-		}
-		return trace, nil
+// withResourcesLayout keeps the execution resources of a decoded trace in the layout of the spec version: the
+// gas totals of RPC 0.8 on (l1_gas, l1_data_gas and l2_gas), or the steps, builtins and data availability of the
+// former versions. The resources hold the fields sent by the node, whatever their layout, when the spec version
+// is unknown.
+//
+// Parameters:
+//   - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, as decoded
+//   - specVersion: the spec version of the node, empty if it is unknown
+//
+// Returns:
+//   - TxnTrace: the trace with its execution resources in the layout of the spec version
+func withResourcesLayout(trace TxnTrace, specVersion string) TxnTrace {
+	if specVersion == "" {
+		return trace
+	}
+	switch t := trace.(type) {
+	case InvokeTxnTrace:
+		t.ExecutionResources = resourcesLayout(t.ExecutionResources, specVersion)
+		return t
+	case DeclareTxnTrace:
+		t.ExecutionResources = resourcesLayout(t.ExecutionResources, specVersion)
+		return t
+	case DeployAccountTxnTrace:
+		t.ExecutionResources = resourcesLayout(t.ExecutionResources, specVersion)
+		return t
+	}
+	return trace
+}
+
+// resourcesLayout returns the fields of the execution resources that belong to the layout of the spec version.
+//
+// Parameters:
+//   - resources: the execution resources as decoded
+//   - specVersion: the spec version of the node
+//
+// Returns:
+//   - ExecutionResources: the gas totals from RPC 0.8 on, the computation resources and data availability before
+func resourcesLayout(resources ExecutionResources, specVersion string) ExecutionResources {
+	version := strings.TrimPrefix(specVersion, "v")
+	if strings.HasPrefix(version, "0.6") || strings.HasPrefix(version, "0.7") {
+		return ExecutionResources{ComputationResources: resources.ComputationResources, DataAvailability: resources.DataAvailability}
 	}
-	return nil, Err(InternalError, "Unknown transaction type")
+	return ExecutionResources{TotalL1Gas: resources.TotalL1Gas, TotalL1DataGas: resources.TotalL1DataGas, L2Gas: resources.L2Gas}
+}
+
+// BlockTraceError is returned by TraceBlockTransactions when a transaction of the block cannot be traced,
+// e.g. to skip it or to trace it alone.
+type BlockTraceError struct {
+	// The index of the failed transaction in the block
+	Index int
+	// The ErrTxnExec error of the node, with its TransactionExecutionErrorData
+	Inner *RPCError
+}
+
+// Error returns the index of the failed transaction and the error of the node.
+func (e *BlockTraceError) Error() string {
+	return fmt.Sprintf("transaction %d of the block: %s", e.Index, e.Inner.Error())
+}
+
+// Unwrap returns the error of the node, so that errors.Is(err, ErrTxnExec) holds.
+func (e *BlockTraceError) Unwrap() error {
+	return e.Inner
+}
 
+// blockTraceErr returns the error of a starknet_traceBlockTransactions call as ErrBlockNotFound, or as a
+// *BlockTraceError holding the index of the failed transaction for ErrTxnExec.
+//
+// Parameters:
+// - err: the error of the call
+// Returns:
+// - error: the error of the call, unwrapped as by tryUnwrapToRPCErr
+func blockTraceErr(err error) error {
+	err = tryUnwrapToRPCErr(err, ErrBlockNotFound, ErrTxnExec)
+	var rpcErr *RPCError
+	if errors.Is(err, ErrTxnExec) && errors.As(err, &rpcErr) {
+		if data, dataErr := rpcErr.TypedData(); dataErr == nil && data != nil {
+			return &BlockTraceError{Index: data.(*TransactionExecutionErrorData).TransactionIndex, Inner: rpcErr}
+		}
+	}
+	return err
 }
 
 // TraceBlockTransactions retrieves the traces of transactions in a given block.
@@ -68,27 +189,682 @@ func (provider *Provider) TraceTransaction(ctx context.Context, transactionHash
 // - blockHash: the hash of the block to retrieve the traces from
 // Returns:
 // - []Trace: a slice of Trace objects representing the traces of transactions in the block
-// - error: an error if there was a problem retrieving the traces.
+// - error: a *BlockTraceError if a transaction of the block cannot be traced, ErrBlockNotFound, or another error
+// if there was a problem retrieving the traces.
 func (provider *Provider) TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error) {
 	var output []Trace
 	if err := do(ctx, provider.c, "starknet_traceBlockTransactions", &output, blockID); err != nil {
-		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+		return nil, blockTraceErr(err)
+	}
+	specVersion := provider.knownSpecVersion()
+	for i := range output {
+		output[i].TraceRoot = withResourcesLayout(output[i].TraceRoot, specVersion)
 	}
 	return output, nil
 
 }
 
+// TraceBlockRange retrieves the traces of the transactions of the blocks from and to, both included, with
+// TraceBlockTransactions calls fanned out over at most concurrency workers, so that no more than concurrency
+// requests are ever in flight. The first error stops the launch of new requests and cancels the ones in
+// flight; the errors are not retried here, the transient ones are meant to be retried by the transport
+// (see WithRetry).
+//
+// Parameters:
+// - ctx: the context.Context object for controlling the requests
+// - from: the number of the first block
+// - to: the number of the last block
+// - concurrency: the maximum number of requests in flight, must be positive
+// Returns:
+// - map[uint64][]Trace: the traces by block number, with the blocks traced before the first error if any
+// - error: the first error, wrapped with the number of its block, or ctx.Err() if the context is done
+func (provider *Provider) TraceBlockRange(ctx context.Context, from, to uint64, concurrency int) (map[uint64][]Trace, error) {
+	if concurrency <= 0 {
+		return nil, Err(InvalidParams, "concurrency must be positive")
+	}
+	if from > to {
+		return nil, Err(InvalidParams, fmt.Sprintf("from block %d after to block %d", from, to))
+	}
+	workers := uint64(concurrency)
+	if to-from < workers {
+		workers = to - from + 1
+	}
+
+	rangeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		results  = map[uint64][]Trace{}
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	blocks := make(chan uint64)
+	for i := uint64(0); i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range blocks {
+				traces, err := provider.TraceBlockTransactions(rangeCtx, WithBlockNumber(number))
+				mu.Lock()
+				if err == nil {
+					results[number] = traces
+				} else if firstErr == nil {
+					firstErr = fmt.Errorf("block %d: %w", number, err)
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// the context is checked before each send, as select picks randomly between a ready worker and a done context
+feed:
+	for number := from; rangeCtx.Err() == nil; number++ {
+		select {
+		case blocks <- number:
+		case <-rangeCtx.Done():
+			break feed
+		}
+		if number == to {
+			break
+		}
+	}
+	close(blocks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, ctx.Err()
+}
+
+// TraceBlockTransactionsStream retrieves the traces of the transactions of a block like TraceBlockTransactions,
+// but decodes the response of the node one trace at a time and hands each of them to fn, so that the traces
+// of the whole block are never held in memory. If fn returns an error, the decoding stops, the read of the
+// response is cancelled and the error is returned.
+// The response is only streamed for a provider created by NewProvider with an HTTP URL: the request is then
+// sent with the HTTP client of the provider, its retries (see WithRetry), headers, rate limit and request ID
+// included, and bounded by the default timeout of WithTimeout, which also bounds the calls to fn. The client
+// options of ethrpc (e.g. ethrpc.WithHeaders) are not applied to it. The traces of the other providers are
+// retrieved with TraceBlockTransactions before being handed to fn.
+//
+// Parameters:
+// - ctx: the context.Context object for controlling the request
+// - blockID: the ID of the block to retrieve the traces from
+// - fn: the function called with each trace, in the order of the transactions of the block
+// Returns:
+// - error: a *BlockTraceError if a transaction of the block cannot be traced, as for TraceBlockTransactions, an
+// error if the traces cannot be retrieved, with the request ID of the call, or the error returned by fn
+func (provider *Provider) TraceBlockTransactionsStream(ctx context.Context, blockID BlockID, fn func(Trace) error) error {
+	if provider.httpClient == nil {
+		traces, err := provider.TraceBlockTransactions(ctx, blockID)
+		if err != nil {
+			return err
+		}
+		for _, trace := range traces {
+			if err := fn(trace); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	const method = "starknet_traceBlockTransactions"
+	id, body, err := provider.buildRequest(method, []interface{}{blockID})
+	if err != nil {
+		return err
+	}
+	ctx, cancelTimeout := withDefaultTimeout(ctx, provider.timeout)
+	defer cancelTimeout()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fnFailed := false
+	err = provider.streamTraces(ctx, body, func(trace Trace) error {
+		err := fn(trace)
+		fnFailed = err != nil
+		return err
+	})
+	if err == nil || fnFailed {
+		return err
+	}
+	err = failedRequest(provider.logger, provider.headers, id, method, err)
+	if ctxErr := contextError(err); ctxErr != nil {
+		return ctxErr
+	}
+	var blockErr *BlockTraceError
+	if errors.As(err, &blockErr) {
+		blockErr.Inner.RequestID = id
+		return blockErr
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		rpcErr.RequestID = id
+		return rpcErr
+	}
+	return err
+}
+
+// streamTraces posts the request of a streamed call of starknet_traceBlockTransactions and decodes its response
+// with decodeTraceStream.
+//
+// Parameters:
+// - ctx: the context of the call
+// - body: the JSON-RPC request
+// - fn: the function called with each trace
+// Returns:
+// - error: the error of the request or of the response, or the error returned by fn
+func (provider *Provider) streamTraces(ctx context.Context, body []byte, fn func(Trace) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.url, bytes.NewReader(body))
+	if err != nil {
+		return Err(InternalError, err.Error())
+	}
+	for name, values := range provider.headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := waitToken(ctx, provider.limiter); err != nil {
+		return err
+	}
+	resp, err := provider.httpClient.Do(req)
+	if err != nil {
+		return streamError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Err(InternalError, resp.Status)
+	}
+	return decodeTraceStream(json.NewDecoder(resp.Body), provider.knownSpecVersion(), fn)
+}
+
+// decodeTraceStream decodes a JSON-RPC response of starknet_traceBlockTransactions, handing each trace of its
+// result to fn as soon as it is decoded.
+//
+// Parameters:
+// - dec: the decoder of the response
+// - specVersion: the spec version of the node, as for withResourcesLayout
+// - fn: the function called with each trace
+// Returns:
+// - error: the error of the response, a *BlockTraceError if a transaction of the block cannot be traced (see
+// TraceBlockTransactions), an error if the response is not valid, or the error returned by fn
+func decodeTraceStream(dec *json.Decoder, specVersion string, fn func(Trace) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return streamError(err)
+		}
+		switch key {
+		case "result":
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				var trace Trace
+				if err := dec.Decode(&trace); err != nil {
+					return streamError(err)
+				}
+				trace.TraceRoot = withResourcesLayout(trace.TraceRoot, specVersion)
+				if err := fn(trace); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		case "error":
+			var nodeErr RPCError
+			if err := dec.Decode(&nodeErr); err != nil {
+				return streamError(err)
+			}
+			return blockTraceErr(&nodeErr)
+		default:
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return streamError(err)
+			}
+		}
+	}
+	return nil
+}
+
+// expectDelim reads the next token of the decoder, which must be the delimiter.
+//
+// Parameters:
+// - dec: the decoder
+// - delim: the expected delimiter
+// Returns:
+// - error: an InternalError if the next token is not the delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return streamError(err)
+	}
+	if token != delim {
+		return Err(InternalError, fmt.Sprintf("expected %v in the response, got %v", delim, token))
+	}
+	return nil
+}
+
+// streamError returns the context error of a streamed call aborted by its context, or else an InternalError.
+//
+// Parameters:
+// - err: the error of the request or of the read of the response
+// Returns:
+// - error: context.Canceled, context.DeadlineExceeded or an InternalError
+func streamError(err error) error {
+	if ctxErr := contextError(err); ctxErr != nil {
+		return ctxErr
+	}
+	return Err(InternalError, err.Error())
+}
+
+// SimulateOption configures a call to SimulateTransactionsWithOptions.
+type SimulateOption func(*simulateOptions)
+
+type simulateOptions struct {
+	skipValidation bool
+}
+
+// SkipValidation sends the simulated transactions to the node without checking their required fields
+// first, e.g. to get the error of the node for an incomplete transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - SimulateOption: the option to pass to SimulateTransactionsWithOptions
+func SkipValidation() SimulateOption {
+	return func(options *simulateOptions) {
+		options.skipValidation = true
+	}
+}
+
 // SimulateTransactions simulates transactions on the blockchain.
 // Simulate a given sequence of transactions on the requested state, and generate the execution traces.
 // Note that some of the transactions may revert, in which case no error is thrown, but revert details can be seen on the returned trace object.
 // Note that some of the transactions may revert, this will be reflected by the revert_error property in the trace. Other types of failures (e.g. unexpected error or failure in the validation phase) will result in TRANSACTION_EXECUTION_ERROR.
+// Unknown simulation flags, and transactions missing a field required for their type (see
+// SimulateTransactionInput.Validate), are rejected with an InvalidParams error before the request is sent.
+// The simulated transactions are in the order of the transactions: the i-th one is the simulation of txns[i].
+// ErrSimulationMismatch is returned if the node answers with a different number of simulated transactions, or
+// with the trace of a transaction type at the index of a transaction of another type.
 func (provider *Provider) SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error) {
+	return provider.SimulateTransactionsWithOptions(ctx, blockID, txns, simulationFlags)
+}
+
+// SimulateTransactionsWithOptions simulates transactions like SimulateTransactions, configured by the options,
+// e.g. SkipValidation to skip the check of the transactions.
+//
+// Parameters:
+// - ctx: the context.Context object for the request
+// - blockID: the block to simulate the transactions on
+// - txns: the transactions to simulate
+// - simulationFlags: the simulation flags
+// - opts: the options of the simulation
+// Returns:
+// - []SimulatedTransaction: the simulated transactions, in the order of the transactions
+// - error: an InvalidParams error wrapping ErrInvalidSimulationFlag or a *MissingTxnFieldError for a rejected
+// input, or an error if the simulation fails
+func (provider *Provider) SimulateTransactionsWithOptions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag, opts ...SimulateOption) ([]SimulatedTransaction, error) {
+	var options simulateOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if err := validateSimulationFlags(simulationFlags); err != nil {
+		return nil, invalidParamsErr(err)
+	}
+	if !options.skipValidation {
+		if err := validateTxns(txns); err != nil {
+			return nil, invalidParamsErr(err)
+		}
+	}
 
 	var output []SimulatedTransaction
 	if err := do(ctx, provider.c, "starknet_simulateTransactions", &output, blockID, txns, simulationFlags); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrTxnExec, ErrBlockNotFound)
 	}
+	if err := checkSimulatedOrder(txns, output); err != nil {
+		return nil, err
+	}
+	specVersion := provider.knownSpecVersion()
+	for i := range output {
+		output[i].TxnTrace = withResourcesLayout(output[i].TxnTrace, specVersion)
+	}
 
 	return output, nil
 
 }
+
+// PinOption configures a call to SimulateTransactionsPinned.
+type PinOption func(*pinOptions)
+
+type pinOptions struct {
+	liveLatest bool
+	simulate   []SimulateOption
+}
+
+// LiveLatest makes SimulateTransactionsPinned simulate the transactions on the latest block as the node sees it
+// when the simulation runs, without resolving its number first.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - PinOption: the option to pass to SimulateTransactionsPinned
+func LiveLatest() PinOption {
+	return func(options *pinOptions) {
+		options.liveLatest = true
+	}
+}
+
+// WithSimulateOptions passes the options of the simulation, e.g. SkipValidation, to the
+// SimulateTransactionsWithOptions call of SimulateTransactionsPinned.
+//
+// Parameters:
+// - opts: the options of the simulation
+// Returns:
+// - PinOption: the option to pass to SimulateTransactionsPinned
+func WithSimulateOptions(opts ...SimulateOption) PinOption {
+	return func(options *pinOptions) {
+		options.simulate = append(options.simulate, opts...)
+	}
+}
+
+// SimulateTransactionsPinned simulates transactions like SimulateTransactions, on a block resolved first so that
+// the simulation is reproducible, e.g. when it is retried after a crash: the latest tag is resolved to the
+// number of the latest block with BlockNumber, and the transactions are simulated on that block number rather
+// than on a latest block that may have moved on in the meantime. The resolution is skipped with the LiveLatest
+// option. The other block IDs are used as they are, the pending block having no stable state to pin.
+//
+// Parameters:
+// - ctx: the context.Context object for the requests
+// - blockID: the block to simulate the transactions on
+// - txns: the transactions to simulate
+// - simulationFlags: the simulation flags
+// - opts: the options of the call, e.g. LiveLatest or WithSimulateOptions
+// Returns:
+// - []SimulatedTransaction: the simulated transactions, in the order of the transactions
+// - BlockID: the block the transactions were simulated on, the block number of the latest block if it was resolved
+// - error: an error if the latest block cannot be resolved or if the simulation fails
+func (provider *Provider) SimulateTransactionsPinned(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag, opts ...PinOption) ([]SimulatedTransaction, BlockID, error) {
+	var options pinOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if BlockTag(blockID.Tag) == BlockTagLatest && !options.liveLatest {
+		number, err := provider.BlockNumber(ctx)
+		if err != nil {
+			return nil, blockID, err
+		}
+		blockID = WithBlockNumber(number)
+	}
+	simulated, err := provider.SimulateTransactionsWithOptions(ctx, blockID, txns, simulationFlags, options.simulate...)
+	if err != nil {
+		return nil, blockID, err
+	}
+	return simulated, blockID, nil
+}
+
+var ErrSimulationMismatch = errors.New("simulated transactions do not match the transactions")
+
+// checkSimulatedOrder checks that the simulated transactions returned by the node match the simulated
+// transactions one to one, in order. A simulated transaction does not identify its transaction, so only the
+// count and the transaction types are compared.
+//
+// Parameters:
+// - txns: the simulated transactions
+// - simulated: the simulated transactions returned by the node
+// Returns:
+// - error: ErrSimulationMismatch if the counts differ or if the type of a trace is not the type of its
+// transaction
+func checkSimulatedOrder(txns []Transaction, simulated []SimulatedTransaction) error {
+	if len(simulated) != len(txns) {
+		return fmt.Errorf("%w: %d transactions, %d simulated", ErrSimulationMismatch, len(txns), len(simulated))
+	}
+	for i, txn := range txns {
+		txnType, traceType := txn.GetType(), traceTransactionType(simulated[i].TxnTrace)
+		if txnType != "" && traceType != "" && txnType != traceType {
+			return fmt.Errorf("%w: %s trace for the %s transaction %d", ErrSimulationMismatch, traceType, txnType, i)
+		}
+	}
+	return nil
+}
+
+// traceTransactionType returns the type of the transaction of a trace.
+//
+// Parameters:
+// - trace: the transaction trace
+// Returns:
+// - TransactionType: the type of the transaction, empty for a nil or unknown trace
+func traceTransactionType(trace TxnTrace) TransactionType {
+	switch trace.(type) {
+	case InvokeTxnTrace:
+		return TransactionType_Invoke
+	case DeclareTxnTrace:
+		return TransactionType_Declare
+	case DeployAccountTxnTrace:
+		return TransactionType_DeployAccount
+	case L1HandlerTxnTrace:
+		return TransactionType_L1Handler
+	default:
+		return ""
+	}
+}
+
+var ErrReplayNotSupported = errors.New("transaction type cannot be replayed")
+
+// ReplayTransaction simulates again a transaction of the chain, to get a fresh trace and fee estimate of its
+// execution, e.g. for debugging. The transaction is simulated with SKIP_VALIDATE on the state of the parent
+// block of its block, i.e. the block block_number - 1, as the state of its own block already includes its
+// effects; a pending transaction is simulated on the latest block. The effects of the transactions preceding
+// it in its block are not applied, so the execution only matches the one of the chain when the transaction
+// does not depend on them (e.g. the first transaction of the block).
+// Only the invoke and deploy account transactions can be replayed, the declare transactions lacking their
+// contract class and the L1 handler transactions not being simulated by the nodes.
+//
+// Parameters:
+// - ctx: the context.Context object for the requests
+// - txHash: the hash of the transaction
+// Returns:
+// - SimulatedTransaction: the trace and fee estimate of the simulation
+// - error: ErrReplayNotSupported for the other transaction types, or an error of the requests
+func (provider *Provider) ReplayTransaction(ctx context.Context, txHash *felt.Felt) (SimulatedTransaction, error) {
+	receipt, err := provider.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return SimulatedTransaction{}, err
+	}
+	blockID := WithBlockTag(BlockTagLatest)
+	if receipt.BlockHash != nil {
+		if receipt.BlockNumber == 0 {
+			return SimulatedTransaction{}, fmt.Errorf("%w: transaction %s of the genesis block", ErrReplayNotSupported, txHash)
+		}
+		blockID = WithBlockNumber(uint64(receipt.BlockNumber) - 1)
+	}
+
+	blockTxn, err := provider.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return SimulatedTransaction{}, err
+	}
+	var txn Transaction
+	switch tx := blockTxn.IBlockTransaction.(type) {
+	case BlockInvokeTxnV0:
+		txn = tx.InvokeTxnV0
+	case BlockInvokeTxnV1:
+		txn = tx.InvokeTxnV1
+	case BlockInvokeTxnV3:
+		txn = tx.InvokeTxnV3
+	case BlockDeployAccountTxn:
+		txn = tx.DeployAccountTxn
+	case BlockDeployAccountTxnV3:
+		txn = tx.DeployAccountTxnV3
+	default:
+		return SimulatedTransaction{}, fmt.Errorf("%w: %T", ErrReplayNotSupported, blockTxn.IBlockTransaction)
+	}
+
+	simulated, err := provider.SimulateTransactions(ctx, blockID, []Transaction{txn}, []SimulationFlag{SimulationFlagSkipValidate})
+	if err != nil {
+		return SimulatedTransaction{}, err
+	}
+	if len(simulated) != 1 {
+		return SimulatedTransaction{}, Err(InternalError, fmt.Sprintf("expected one simulated transaction, got %d", len(simulated)))
+	}
+	return simulated[0], nil
+}
+
+// Gas cost of a single builtin application (or Cairo step), scaled by gasWeightScale.
+// ref: https://docs.starknet.io/architecture-and-concepts/network-architecture/fee-mechanism/
+const (
+	gasWeightScale      = 10000
+	gasWeightStep       = 25    // 0.0025 gas per Cairo step
+	gasWeightPedersen   = 800   // 0.08 gas per Pedersen application
+	gasWeightRangeCheck = 400   // 0.04 gas per range check application
+	gasWeightECDSA      = 51200 // 5.12 gas per ECDSA application
+	gasWeightBitwise    = 1600  // 0.16 gas per bitwise application
+	gasWeightECOP       = 25600 // 2.56 gas per EC_OP application
+	gasWeightPoseidon   = 800   // 0.08 gas per Poseidon application
+	gasWeightKeccak     = 51200 // 5.12 gas per Keccak application
+)
+
+var ErrNoExecutionResources = errors.New("trace has no execution resources")
+
+// traceExecutionResources returns the execution resources of the given trace.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace or DeployAccountTxnTrace (or a pointer to one)
+// Returns:
+// - ExecutionResources: the resources consumed by the transaction
+// - error: ErrNoExecutionResources if the trace type carries no (or empty) execution resources
+func traceExecutionResources(trace TxnTrace) (ExecutionResources, error) {
+	var resources ExecutionResources
+	switch t := trace.(type) {
+	case InvokeTxnTrace:
+		resources = t.ExecutionResources
+	case *InvokeTxnTrace:
+		resources = t.ExecutionResources
+	case DeclareTxnTrace:
+		resources = t.ExecutionResources
+	case *DeclareTxnTrace:
+		resources = t.ExecutionResources
+	case DeployAccountTxnTrace:
+		resources = t.ExecutionResources
+	case *DeployAccountTxnTrace:
+		resources = t.ExecutionResources
+	default:
+		return ExecutionResources{}, ErrNoExecutionResources
+	}
+	if reflect.ValueOf(resources).IsZero() {
+		return ExecutionResources{}, ErrNoExecutionResources
+	}
+	return resources, nil
+}
+
+// computationGas returns the L1 gas charged for the computation part of the given resources,
+// which is the cost of the most expensive resource (Cairo steps or one of the builtins).
+//
+// Parameters:
+// - resources: the computation resources consumed by the transaction
+// Returns:
+// - uint64: the computation gas, rounded up and saturating instead of wrapping
+func computationGas(resources ComputationResources) uint64 {
+	weighted := []uint64{
+		saturatingMul(resources.Steps, gasWeightStep),
+		saturatingMul(resources.PedersenApps, gasWeightPedersen),
+		saturatingMul(resources.RangeCheckApps, gasWeightRangeCheck),
+		saturatingMul(resources.ECDSAApps, gasWeightECDSA),
+		saturatingMul(resources.BitwiseApps, gasWeightBitwise),
+		saturatingMul(resources.ECOPApps, gasWeightECOP),
+		saturatingMul(resources.PoseidonApps, gasWeightPoseidon),
+		saturatingMul(resources.KeccakApps, gasWeightKeccak),
+	}
+	var maxWeighted uint64
+	for _, w := range weighted {
+		if w > maxWeighted {
+			maxWeighted = w
+		}
+	}
+	gas := maxWeighted / gasWeightScale
+	if maxWeighted%gasWeightScale != 0 {
+		gas++
+	}
+	return gas
+}
+
+// traceL1Gas returns the L1 gas consumed by a transaction, the l1_gas total of its resources from RPC 0.8 on,
+// or its computation gas plus the L1 gas of its data availability before.
+//
+// Parameters:
+// - resources: the execution resources of the transaction
+// Returns:
+// - uint64: the L1 gas consumed, saturating instead of wrapping
+func traceL1Gas(resources ExecutionResources) uint64 {
+	if resources.HasGasTotals() {
+		return uint64(resources.TotalL1Gas)
+	}
+	return saturatingAdd(computationGas(resources.ComputationResources), uint64(resources.L1Gas))
+}
+
+// EstimateBoundsFromTrace derives resource bounds for resubmitting a transaction from the execution
+// resources of its trace. Each amount is the gas consumed for that resource, scaled by the multiplier and
+// rounded up to the nearest integer.
+//
+// The resource bounds carry l1_gas and l2_gas, so the L1 data gas is bounded with the L1 gas. From RPC 0.8 on,
+// the L1 bound is the l1_gas plus the l1_data_gas totals of the trace, and the L2 bound its l2_gas total.
+// Before, the L1 bound is the computation gas plus the data availability gas, l1_data_gas included, and the
+// L2 one zero.
+// The max price per unit is left at zero and should be set by the caller, e.g. from the block's gas prices.
+//
+// Parameters:
+// - trace: the transaction trace, as returned by TraceTransaction or SimulateTransactions
+// - multiplier: the factor applied to the consumed gas, must be positive
+// Returns:
+// - ResourceBoundsMapping: the derived resource bounds
+// - error: an error if the multiplier is not positive, the trace has no execution resources or a bound overflows
+func EstimateBoundsFromTrace(trace TxnTrace, multiplier float64) (ResourceBoundsMapping, error) {
+	if multiplier <= 0 {
+		return ResourceBoundsMapping{}, fmt.Errorf("invalid multiplier %v, must be positive", multiplier)
+	}
+	resources, err := traceExecutionResources(trace)
+	if err != nil {
+		return ResourceBoundsMapping{}, err
+	}
+
+	if !resources.HasGasTotals() {
+		l1Gas, err := scaledBound("l1 gas", saturatingAdd(traceL1Gas(resources), uint64(resources.L1DataGas)), multiplier)
+		if err != nil {
+			return ResourceBoundsMapping{}, err
+		}
+		return ResourceBoundsMapping{L1Gas: l1Gas, L2Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}}, nil
+	}
+
+	l1Gas, err := scaledBound("l1 gas", saturatingAdd(uint64(resources.TotalL1Gas), uint64(resources.TotalL1DataGas)), multiplier)
+	if err != nil {
+		return ResourceBoundsMapping{}, err
+	}
+	l2Gas, err := scaledBound("l2 gas", uint64(resources.L2Gas), multiplier)
+	if err != nil {
+		return ResourceBoundsMapping{}, err
+	}
+	return ResourceBoundsMapping{L1Gas: l1Gas, L2Gas: l2Gas}, nil
+}
+
+// scaledBound returns the resource bound of the gas consumed scaled by the multiplier, with a zero price.
+//
+// Parameters:
+// - resource: the name of the resource, for the error
+// - gas: the gas consumed
+// - multiplier: the factor applied to the consumed gas
+// Returns:
+// - ResourceBounds: the bound of the scaled amount, rounded up
+// - error: an error if the scaled amount overflows uint64
+func scaledBound(resource string, gas uint64, multiplier float64) (ResourceBounds, error) {
+	amount := math.Ceil(float64(gas) * multiplier)
+	if amount >= math.MaxUint64 {
+		return ResourceBounds{}, fmt.Errorf("%s bound overflows uint64: %v", resource, amount)
+	}
+	return ResourceBounds{MaxAmount: U64(fmt.Sprintf("0x%x", uint64(amount))), MaxPricePerUnit: "0x0"}, nil
+}
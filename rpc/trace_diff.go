@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// TracesDiffer compares two traces of the same block, e.g. the traces of a block traced again after a suspected
+// reorg. The traces are compared by transaction index after a JSON round trip into the typed traces, so that
+// differences in the hex formatting of the felts or in the order of the JSON fields are ignored, the traces
+// decoded as JSON objects included. A transaction present in only one of the blocks has changed.
+//
+// Parameters:
+// - a: the traces of the block, e.g. as returned by TraceBlockTransactions
+// - b: the traces of the block traced again
+// Returns:
+// - bool: true if the traces differ
+// - []int: the indices of the transactions whose traces changed, in increasing order
+func TracesDiffer(a, b []Trace) (bool, []int) {
+	var changed []int
+	for i := 0; i < max(len(a), len(b)); i++ {
+		if i >= len(a) || i >= len(b) || !sameTrace(a[i], b[i]) {
+			changed = append(changed, i)
+		}
+	}
+	return len(changed) > 0, changed
+}
+
+// sameTrace reports whether two traces have the same transaction hash and normalized trace root. Traces that
+// cannot be normalized are considered different.
+func sameTrace(a, b Trace) bool {
+	if (a.TxnHash == nil) != (b.TxnHash == nil) || (a.TxnHash != nil && !a.TxnHash.Equal(b.TxnHash)) {
+		return false
+	}
+	rootA, err := normalizedTraceRoot(a.TraceRoot)
+	if err != nil {
+		return false
+	}
+	rootB, err := normalizedTraceRoot(b.TraceRoot)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(rootA, rootB)
+}
+
+// normalizedTraceRoot returns the JSON encoding of a trace root decoded into its typed trace, whose felts and
+// fields are encoded in a canonical form. The trace root of an unknown transaction type is encoded with its
+// keys sorted.
+//
+// Parameters:
+// - root: the trace root, typed or decoded as a JSON object
+// Returns:
+// - []byte: the normalized JSON trace, null for a nil trace root
+// - error: an error if the trace root cannot be encoded or decoded
+func normalizedTraceRoot(root TxnTrace) ([]byte, error) {
+	raw, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	typed, err := decodeTraceRoot(raw)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(typed)
+}
@@ -0,0 +1,190 @@
+package rpc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// maxFormatIndent is the deepest level of the call tree indented by FormatTrace, the deeper calls are printed
+// at this level with their depth so that a pathological trace does not produce quadratic output.
+const maxFormatIndent = 32
+
+// knownSelectors are the names of the well-known entry points, by selector, as printed by FormatTrace.
+var knownSelectors = func() map[felt.Felt]string {
+	names := []string{
+		"__execute__", "__validate__", "__validate_declare__", "__validate_deploy__", "constructor",
+		"transfer", "transferFrom", "transfer_from", "approve", "increaseAllowance", "increase_allowance",
+		"decreaseAllowance", "decrease_allowance", "balanceOf", "balance_of", "allowance", "totalSupply",
+		"total_supply", "mint", "burn", "deploy_contract", "upgrade", "is_valid_signature", "handle_deposit",
+	}
+	selectors := make(map[felt.Felt]string, len(names))
+	for _, name := range names {
+		selectors[*utils.GetSelectorFromNameFelt(name)] = name
+	}
+	return selectors
+}()
+
+// formatRoot is a root invocation of a trace, labelled with its role in the transaction.
+type formatRoot struct {
+	label string
+	// The revert reason of the invocation, empty if it did not revert
+	revertReason string
+	invocation   FnInvocation
+}
+
+// formatCall is an invocation of the call tree waiting to be printed by FormatTrace, with its depth.
+type formatCall struct {
+	invocation *FnInvocation
+	depth      int
+}
+
+// FormatTrace renders a transaction trace as readable text, e.g. for debugging: the call tree of each root
+// invocation (validation, execution, constructor and fee transfer), indented by depth, with the shortened
+// address of the called contract and the name of the entry point when it is a well-known one (e.g. __execute__
+// or transfer), followed by a summary of the emitted events and the messages sent to L1. A reverted execution
+// is printed with its revert reason, and a failed inner call, whose revert may have been caught by its caller,
+// is marked as FAILED with its failure reason.
+// The call tree is walked without recursion, so that arbitrarily deep traces can be printed.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - string: the rendered trace, or a one-line description of the value for unknown trace types
+func FormatTrace(trace TxnTrace) string {
+	txnType, roots, ok := formatRoots(trace)
+	if !ok {
+		return fmt.Sprintf("unknown trace %T", trace)
+	}
+
+	var out strings.Builder
+	var events []OrderedEvent
+	var messages []OrderedMsg
+	fmt.Fprintf(&out, "%s transaction trace\n", txnType)
+	for _, root := range roots {
+		if root.revertReason != "" {
+			fmt.Fprintf(&out, "%s: REVERTED: %s\n", root.label, formatRevertReason(root.revertReason))
+			continue
+		}
+		if optionalInvocation(root.invocation) == nil {
+			continue
+		}
+		fmt.Fprintf(&out, "%s: ", root.label)
+		stack := []formatCall{{invocation: &root.invocation}}
+		for len(stack) > 0 {
+			call := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			invocation := call.invocation
+			if call.depth > 0 {
+				indent := min(call.depth, maxFormatIndent)
+				out.WriteString(strings.Repeat("  ", indent))
+				if call.depth > maxFormatIndent {
+					fmt.Fprintf(&out, "[depth %d] ", call.depth)
+				}
+			}
+			fmt.Fprintf(&out, "%s %s", shortFelt(invocation.ContractAddress), selectorName(invocation.EntryPointSelector))
+			if invocation.IsReverted {
+				out.WriteString(" FAILED")
+				if invocation.FailureReason != "" {
+					fmt.Fprintf(&out, ": %s", formatRevertReason(invocation.FailureReason))
+				}
+			}
+			out.WriteString("\n")
+
+			events = append(events, invocation.InvocationEvents...)
+			for _, msg := range invocation.L1Messages {
+				if msg.FromAddress == nil {
+					msg.FromAddress = invocation.ContractAddress
+				}
+				messages = append(messages, msg)
+			}
+			// pushed in reverse so that the calls are printed in order
+			for i := len(invocation.NestedCalls) - 1; i >= 0; i-- {
+				stack = append(stack, formatCall{invocation: &invocation.NestedCalls[i], depth: call.depth + 1})
+			}
+		}
+	}
+
+	fmt.Fprintf(&out, "events: %d\n", len(events))
+	for _, event := range events {
+		keys := make([]string, len(event.Event.Keys))
+		for i, key := range event.Event.Keys {
+			keys[i] = shortFelt(key)
+		}
+		fmt.Fprintf(&out, "  #%d %s keys [%s] data %d felts\n", event.Order, shortFelt(event.Event.FromAddress),
+			strings.Join(keys, " "), len(event.Event.Data))
+	}
+	fmt.Fprintf(&out, "l1 messages: %d\n", len(messages))
+	for _, msg := range messages {
+		fmt.Fprintf(&out, "  #%d %s -> %s payload %d felts\n", msg.Order, shortFelt(msg.FromAddress), shortFelt(msg.ToAddress),
+			len(msg.Payload))
+	}
+	return out.String()
+}
+
+// formatRootLabels are the labels of the root invocations returned by traceRootInvocations, by transaction type.
+var formatRootLabels = map[TransactionType][]string{
+	TransactionType_Invoke:        {"validate", "execute", "fee transfer"},
+	TransactionType_Declare:       {"validate", "fee transfer"},
+	TransactionType_DeployAccount: {"constructor", "validate", "fee transfer"},
+	TransactionType_L1Handler:     {"l1 handler"},
+}
+
+// formatRoots returns the type and the labelled root invocations of a trace, in the order they are run.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - TransactionType: the type of the transaction
+// - []formatRoot: the root invocations of the trace
+// - bool: false for unknown trace types
+func formatRoots(trace TxnTrace) (TransactionType, []formatRoot, bool) {
+	invocations, ok := traceRootInvocations(trace)
+	typed, isTyped := trace.(TransactionTrace)
+	if !ok || !isTyped {
+		return "", nil, false
+	}
+	txnType := TransactionType(typed.TraceType())
+	labels := formatRootLabels[txnType]
+	roots := make([]formatRoot, len(invocations))
+	for i, invocation := range invocations {
+		roots[i] = formatRoot{label: labels[i], invocation: invocation}
+	}
+	if invoke, ok := typed.AsInvoke(); ok {
+		roots[1].revertReason = invoke.ExecuteInvocation.RevertReason
+	}
+	return txnType, roots, true
+}
+
+// formatRevertReason returns the readable message of a revert reason, or else its first line.
+func formatRevertReason(reason string) string {
+	if info, err := ParseRevertReason(reason); err == nil && info.Parsed && info.Message != "" {
+		return info.Message
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(reason), "\n")
+	return line
+}
+
+// selectorName returns the name of a well-known entry point, or else the shortened selector.
+func selectorName(selector *felt.Felt) string {
+	if selector != nil {
+		if name, ok := knownSelectors[*selector]; ok {
+			return name
+		}
+	}
+	return shortFelt(selector)
+}
+
+// shortFelt returns the hexadecimal felt shortened to its first and last 4 digits, e.g. 0x49d3...4dc7.
+func shortFelt(value *felt.Felt) string {
+	if value == nil {
+		return "?"
+	}
+	hex := value.String()
+	if len(hex) <= 12 {
+		return hex
+	}
+	return hex[:6] + "..." + hex[len(hex)-4:]
+}
@@ -3,8 +3,18 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/utils"
@@ -44,7 +54,7 @@ func TestTransactionTrace(t *testing.T) {
 	type testSetType struct {
 		TransactionHash *felt.Felt
 		ExpectedResp    *InvokeTxnTrace
-		ExpectedError   *RPCError
+		ExpectedError   error
 	}
 	testSet := map[string][]testSetType{
 		"mock": {
@@ -61,10 +71,13 @@ func TestTransactionTrace(t *testing.T) {
 			testSetType{
 				TransactionHash: utils.TestHexToFelt(t, "0xf00d"),
 				ExpectedResp:    nil,
-				ExpectedError: &RPCError{
-					Code:    10,
-					Message: "No trace available for transaction",
-					Data:    "REJECTED",
+				ExpectedError: &TraceUnavailableError{
+					Status: "REJECTED",
+					Err: &RPCError{
+						Code:    10,
+						Message: "No trace available for transaction",
+						Data:    "REJECTED",
+					},
 				},
 			},
 		},
@@ -77,12 +90,50 @@ func TestTransactionTrace(t *testing.T) {
 		if err != nil {
 			require.Equal(t, test.ExpectedError, err)
 		} else {
-			invokeTrace := resp.(InvokeTxnTrace)
-			require.Equal(t, invokeTrace, *test.ExpectedResp)
+			require.Equal(t, string(TransactionType_Invoke), resp.TraceType())
+			invokeTrace, ok := resp.AsInvoke()
+			require.True(t, ok)
+			_, ok = resp.AsL1Handler()
+			require.False(t, ok)
+			require.Equal(t, *invokeTrace, *test.ExpectedResp)
 		}
 	}
 }
 
+// TestTraceTransactionWithRaw tests that TraceTransactionWithRaw returns the decoded trace along with the result
+// object sent by the node.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceTransactionWithRaw(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the raw trace is only compared with the mock trace")
+	}
+	testConfig := beforeEach(t)
+	transactionHash := utils.TestHexToFelt(t, "0x6a4a9c4f1a530f7d6dd7bba9b71f090a70d1e3bbde80998fde11a08aab8b282")
+
+	content, err := os.ReadFile("./tests/trace/sepoliaInvokeTrace_0x6a4a9c4f1a530f7d6dd7bba9b71f090a70d1e3bbde80998fde11a08aab8b282.json")
+	require.NoError(t, err)
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(content, &response))
+
+	trace, raw, err := testConfig.provider.TraceTransactionWithRaw(context.Background(), transactionHash)
+	require.NoError(t, err)
+	require.JSONEq(t, string(response.Result), string(raw))
+	expected, err := testConfig.provider.TraceTransaction(context.Background(), transactionHash)
+	require.NoError(t, err)
+	require.Equal(t, expected, trace)
+
+	_, raw, err = testConfig.provider.TraceTransactionWithRaw(context.Background(), utils.TestHexToFelt(t, "0xc0ffee"))
+	require.Equal(t, ErrHashNotFound, err)
+	require.Nil(t, raw)
+}
+
 // TestSimulateTransaction is a function that tests the SimulateTransaction function in the codebase.
 //
 // It sets up the necessary test configuration and variables, and then performs a series of tests based on the test environment.
@@ -140,6 +191,239 @@ func TestSimulateTransaction(t *testing.T) {
 	}
 }
 
+// TestSimulateTransactionFlags tests that SimulateTransactions rejects unknown simulation flags
+// before sending the request and that the known flags marshal to the spec strings.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSimulateTransactionFlags(t *testing.T) {
+	testConfig := beforeEach(t)
+
+	flags, err := json.Marshal([]SimulationFlag{SimulationFlagSkipValidate, SimulationFlagSkipFeeCharge})
+	require.NoError(t, err)
+	require.JSONEq(t, `["SKIP_VALIDATE","SKIP_FEE_CHARGE"]`, string(flags))
+
+	type testSetType struct {
+		SimulationFlags []SimulationFlag
+	}
+	testSet := map[string][]testSetType{
+		"devnet":  {},
+		"mainnet": {},
+		"testnet": {},
+		"mock": {
+			testSetType{SimulationFlags: []SimulationFlag{SKIP_EXECUTE}},
+			testSetType{SimulationFlags: []SimulationFlag{SimulationFlagSkipValidate, "skip_fee_charge"}},
+		},
+	}[testEnv]
+
+	for _, test := range testSet {
+		_, err := testConfig.provider.SimulateTransactions(context.Background(), WithBlockTag("latest"), []Transaction{}, test.SimulationFlags)
+		require.ErrorIs(t, err, ErrInvalidSimulationFlag)
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		require.Equal(t, InvalidParams, rpcErr.Code)
+		require.Contains(t, rpcErr.Data, ErrInvalidSimulationFlag.Error())
+	}
+}
+
+// TestSimulatedTransactionStateDiff tests that the state diff of a simulated transaction is taken from its
+// trace, along its fee estimate.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSimulatedTransactionStateDiff(t *testing.T) {
+	provider := NewMockProvider(map[string]json.RawMessage{
+		"starknet_simulateTransactions": json.RawMessage(`[
+			{
+				"transaction_trace": {
+					"type": "INVOKE",
+					"execute_invocation": {"contract_address": "0x1"},
+					"state_diff": {
+						"storage_diffs": [{"address": "0x1", "storage_entries": [{"key": "0x2", "value": "0x3"}]}],
+						"nonces": [{"contract_address": "0x1", "nonce": "0x5"}],
+						"deprecated_declared_classes": [], "declared_classes": [], "deployed_contracts": [], "replaced_classes": []
+					}
+				},
+				"overall_fee": "0x10",
+				"unit": "FRI"
+			},
+			{"transaction_trace": {"type": "INVOKE", "execute_invocation": {"revert_reason": "reverted"}}, "overall_fee": "0x20", "unit": "FRI"}
+		]`),
+	})
+
+	simulated, err := provider.SimulateTransactionsWithOptions(context.Background(), WithBlockTag(BlockTagLatest), []Transaction{InvokeTxnV1{}, InvokeTxnV1{}}, []SimulationFlag{SimulationFlagSkipFeeCharge}, SkipValidation())
+	require.NoError(t, err)
+	require.Len(t, simulated, 2)
+	require.NotNil(t, simulated[0].StateDiff)
+	require.Equal(t, simulated[0].TxnTrace.(InvokeTxnTrace).StateDiff, simulated[0].StateDiff)
+	require.Equal(t, []StorageEntry{{Key: utils.TestHexToFelt(t, "0x2"), Value: utils.TestHexToFelt(t, "0x3")}}, simulated[0].StateDiff.StorageDiffs[0].StorageEntries)
+	require.Equal(t, utils.TestHexToFelt(t, "0x10"), simulated[0].OverallFee)
+	require.Nil(t, simulated[1].StateDiff)
+	require.Equal(t, utils.TestHexToFelt(t, "0x20"), simulated[1].OverallFee)
+}
+
+// TestSimulateTransactionsPinned tests that SimulateTransactionsPinned simulates the transactions on the number
+// of the latest block, unless LiveLatest is given, and returns the block it simulated them on.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSimulateTransactionsPinned(t *testing.T) {
+	txns := []Transaction{InvokeTxnV1{Type: TransactionType_Invoke}}
+	simulated := func(fee string) json.RawMessage {
+		return json.RawMessage(`[{"transaction_trace": {"type": "INVOKE", "execute_invocation": {}}, "overall_fee": "` + fee + `", "unit": "WEI"}]`)
+	}
+	provider := NewMockProvider(map[string]json.RawMessage{
+		"starknet_blockNumber": json.RawMessage(`1234`),
+		MockKey("starknet_simulateTransactions", WithBlockNumber(1234), txns, []SimulationFlag(nil)):         simulated("0x1"),
+		MockKey("starknet_simulateTransactions", WithBlockTag(BlockTagLatest), txns, []SimulationFlag(nil)):  simulated("0x2"),
+		MockKey("starknet_simulateTransactions", WithBlockTag(BlockTagPending), txns, []SimulationFlag(nil)): simulated("0x3"),
+	})
+	ctx := context.Background()
+
+	result, blockID, err := provider.SimulateTransactionsPinned(ctx, WithBlockTag(BlockTagLatest), txns, nil, WithSimulateOptions(SkipValidation()))
+	require.NoError(t, err)
+	require.Equal(t, WithBlockNumber(1234), blockID)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), result[0].OverallFee)
+
+	result, blockID, err = provider.SimulateTransactionsPinned(ctx, WithBlockTag(BlockTagLatest), txns, nil, WithSimulateOptions(SkipValidation()), LiveLatest())
+	require.NoError(t, err)
+	require.Equal(t, WithBlockTag(BlockTagLatest), blockID)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2"), result[0].OverallFee)
+
+	result, blockID, err = provider.SimulateTransactionsPinned(ctx, WithBlockTag(BlockTagPending), txns, nil, WithSimulateOptions(SkipValidation()))
+	require.NoError(t, err)
+	require.Equal(t, WithBlockTag(BlockTagPending), blockID)
+	require.Equal(t, utils.TestHexToFelt(t, "0x3"), result[0].OverallFee)
+
+	failing := NewMockProvider(map[string]json.RawMessage{"starknet_simulateTransactions": simulated("0x1")})
+	_, _, err = failing.SimulateTransactionsPinned(ctx, WithBlockTag(BlockTagLatest), txns, nil, WithSimulateOptions(SkipValidation()))
+	require.Error(t, err)
+}
+
+// TestSimulateTransactionsOrder tests that the i-th simulated transaction is the simulation of the i-th
+// transaction, and that a node answer not matching the transactions is rejected.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSimulateTransactionsOrder(t *testing.T) {
+	txns := []Transaction{
+		InvokeTxnV1{Type: TransactionType_Invoke},
+		DeployAccountTxn{Type: TransactionType_DeployAccount},
+		InvokeTxnV1{Type: TransactionType_Invoke},
+	}
+	invoke := `{"transaction_trace": {"type": "INVOKE", "execute_invocation": {"revert_reason": "reverted"}}, "overall_fee": "%s", "unit": "WEI"}`
+	deployAccount := `{"transaction_trace": {"type": "DEPLOY_ACCOUNT", "constructor_invocation": {}}, "overall_fee": "%s", "unit": "WEI"}`
+
+	type testSetType struct {
+		Response      string
+		ExpectedError error
+	}
+	testSet := []testSetType{
+		{Response: "[" + fmt.Sprintf(invoke, "0x1") + "," + fmt.Sprintf(deployAccount, "0x2") + "," + fmt.Sprintf(invoke, "0x3") + "]"},
+		{Response: "[" + fmt.Sprintf(invoke, "0x1") + "," + fmt.Sprintf(deployAccount, "0x2") + "]", ExpectedError: ErrSimulationMismatch},
+		{Response: "[" + fmt.Sprintf(deployAccount, "0x2") + "," + fmt.Sprintf(invoke, "0x1") + "," + fmt.Sprintf(invoke, "0x3") + "]", ExpectedError: ErrSimulationMismatch},
+	}
+
+	for _, test := range testSet {
+		provider := NewMockProvider(map[string]json.RawMessage{"starknet_simulateTransactions": json.RawMessage(test.Response)})
+		simulated, err := provider.SimulateTransactionsWithOptions(context.Background(), WithBlockTag(BlockTagLatest), txns, nil, SkipValidation())
+		if test.ExpectedError != nil {
+			require.ErrorIs(t, err, test.ExpectedError)
+			continue
+		}
+		require.NoError(t, err)
+		require.Len(t, simulated, len(txns))
+		for i, fee := range []string{"0x1", "0x2", "0x3"} {
+			require.Equal(t, utils.TestHexToFelt(t, fee), simulated[i].OverallFee)
+			require.Equal(t, txns[i].GetType(), traceTransactionType(simulated[i].TxnTrace))
+		}
+	}
+}
+
+// TestSimulateTransactionInputValidate tests the check of the required fields of the simulated transactions,
+// and that SimulateTransactions fails before sending invalid transactions unless the validation is skipped.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSimulateTransactionInputValidate(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	one := new(felt.Felt).SetUint64(1)
+	resourceBounds := ResourceBoundsMapping{
+		L1Gas: ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x200"},
+		L2Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+	invokeV1 := InvokeTxnV1{Type: TransactionType_Invoke, Version: TransactionV1, MaxFee: one, Nonce: one, SenderAddress: one, Calldata: []*felt.Felt{one}}
+	invokeV3 := InvokeTxnV3{Type: TransactionType_Invoke, Version: TransactionV3, Nonce: one, SenderAddress: one, Calldata: []*felt.Felt{one}, ResourceBounds: resourceBounds}
+	deployAccount := DeployAccountTxn{Type: TransactionType_DeployAccount, Version: TransactionV1, MaxFee: one, Nonce: one, ClassHash: one, ContractAddressSalt: one}
+
+	valid := SimulateTransactionInput{
+		Txns:            []Transaction{invokeV1, BroadcastInvokev3Txn{InvokeTxnV3: invokeV3}, deployAccount},
+		BlockID:         WithBlockTag(BlockTagLatest),
+		SimulationFlags: []SimulationFlag{SimulationFlagSkipValidate},
+	}
+	require.NoError(t, valid.Validate())
+
+	noSender := invokeV1
+	noSender.SenderAddress = nil
+	noCalldata := invokeV1
+	noCalldata.Calldata = []*felt.Felt{}
+	noResourceBounds := invokeV3
+	noResourceBounds.ResourceBounds = ResourceBoundsMapping{}
+	noNonce := deployAccount
+	noNonce.Nonce = nil
+
+	type testSetType struct {
+		Txns          []Transaction
+		ExpectedError string
+	}
+	testSet := []testSetType{
+		{Txns: []Transaction{noSender}, ExpectedError: "txn[0]: invoke v1 missing sender_address"},
+		{Txns: []Transaction{invokeV1, noCalldata}, ExpectedError: "txn[1]: invoke v1 missing calldata"},
+		{Txns: []Transaction{invokeV1, invokeV3, BroadcastInvokev3Txn{InvokeTxnV3: noResourceBounds}}, ExpectedError: "txn[2]: invoke v3 missing resource_bounds"},
+		{Txns: []Transaction{noNonce}, ExpectedError: "txn[0]: deploy account v1 missing nonce"},
+	}
+
+	recorder := &paramsRecorderMock{}
+	provider := &Provider{c: recorder}
+	for _, test := range testSet {
+		input := SimulateTransactionInput{Txns: test.Txns, BlockID: WithBlockTag(BlockTagLatest)}
+		err := input.Validate()
+		var missing *MissingTxnFieldError
+		require.ErrorAs(t, err, &missing)
+		require.EqualError(t, err, test.ExpectedError)
+
+		_, err = provider.SimulateTransactions(context.Background(), input.BlockID, input.Txns, nil)
+		require.ErrorAs(t, err, &missing)
+		var rpcErr *RPCError
+		require.ErrorAs(t, err, &rpcErr)
+		require.Equal(t, InvalidParams, rpcErr.Code)
+		require.Equal(t, test.ExpectedError, rpcErr.Data)
+		require.Empty(t, recorder.params)
+	}
+
+	// the recorder answers with no simulated transaction
+	_, err := provider.SimulateTransactionsWithOptions(context.Background(), WithBlockTag(BlockTagLatest), []Transaction{noSender}, nil, SkipValidation())
+	require.ErrorIs(t, err, ErrSimulationMismatch)
+	require.Len(t, recorder.params, 1)
+}
+
 // TestTraceBlockTransactions tests the TraceBlockTransactions function.
 //
 // It sets up the test configuration and expected response. It then iterates
@@ -200,3 +484,1358 @@ func TestTraceBlockTransactions(t *testing.T) {
 
 	}
 }
+
+// TestTraceBlockTransactionsMixedTypes tests that the traces of a block holding invoke, declare and deploy
+// account transactions are each decoded into the trace type of their transaction, with their fields.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceBlockTransactionsMixedTypes(t *testing.T) {
+	fixture, err := os.ReadFile("./tests/trace/mixedBlockTrace.json")
+	require.NoError(t, err)
+	provider := NewMockProvider(map[string]json.RawMessage{"starknet_traceBlockTransactions": fixture})
+
+	traces, err := provider.TraceBlockTransactions(context.Background(), WithBlockNumber(1))
+	require.NoError(t, err)
+	require.Len(t, traces, 3)
+	require.Equal(t, []TransactionType{TransactionType_Invoke, TransactionType_Declare, TransactionType_DeployAccount},
+		[]TransactionType{traces[0].Type(), traces[1].Type(), traces[2].Type()})
+
+	for _, trace := range traces {
+		root, ok := trace.TraceRoot.(TransactionTrace)
+		require.True(t, ok)
+		require.Equal(t, string(trace.Type()), root.TraceType())
+	}
+	_, ok := traces[1].TraceRoot.(TransactionTrace).AsDeclare()
+	require.True(t, ok)
+	_, ok = traces[1].TraceRoot.(TransactionTrace).AsDeployAccount()
+	require.False(t, ok)
+
+	invoke, ok := traces[0].InvokeTrace()
+	require.True(t, ok)
+	require.Equal(t, utils.TestHexToFelt(t, "0x101"), traces[0].TxnHash)
+	require.Equal(t, utils.TestHexToFelt(t, "0xa1"), invoke.ExecuteInvocation.FunctionInvocation.ContractAddress)
+	require.Equal(t, []*felt.Felt{utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x2")}, invoke.ExecuteInvocation.FunctionInvocation.Calldata)
+	require.Equal(t, utils.TestHexToFelt(t, "0x6"), invoke.StateDiff.StorageDiffs[0].StorageEntries[0].Value)
+	require.Equal(t, uint64(1000), invoke.ExecutionResources.Steps)
+	_, ok = traces[0].DeclareTrace()
+	require.False(t, ok)
+
+	declare, ok := traces[1].DeclareTrace()
+	require.True(t, ok)
+	require.Equal(t, utils.TestHexToFelt(t, "0xa2"), declare.ValidateInvocation.ContractAddress)
+	require.Equal(t, []DeclaredClassesItem{{ClassHash: utils.TestHexToFelt(t, "0xdc1"), CompiledClassHash: utils.TestHexToFelt(t, "0xcc1")}}, declare.StateDiff.DeclaredClasses)
+	require.Equal(t, uint64(2000), declare.ExecutionResources.Steps)
+	require.Equal(t, uint(256), declare.ExecutionResources.DataAvailability.L1DataGas)
+
+	deployAccount, ok := traces[2].DeployAccountTrace()
+	require.True(t, ok)
+	require.Equal(t, utils.TestHexToFelt(t, "0xa3"), deployAccount.ConstructorInvocation.ContractAddress)
+	require.Equal(t, Constructor, deployAccount.ConstructorInvocation.EntryPointType)
+	require.Equal(t, []DeployedContractItem{{Address: utils.TestHexToFelt(t, "0xa3"), ClassHash: utils.TestHexToFelt(t, "0xc3")}}, deployAccount.StateDiff.DeployedContracts)
+	require.Equal(t, uint64(3000), deployAccount.ExecutionResources.Steps)
+	_, ok = traces[2].L1HandlerTrace()
+	require.False(t, ok)
+
+	// the accessors use the As methods, which also accept the trace roots set as pointers
+	l1Handler, ok := Trace{TraceRoot: &L1HandlerTxnTrace{Type: TransactionType_L1Handler}}.L1HandlerTrace()
+	require.True(t, ok)
+	require.Equal(t, TransactionType_L1Handler, l1Handler.Type)
+}
+
+// TestTraceBlockTransactionsUnknownType tests that the trace root of a transaction of an unknown type is kept as
+// its JSON object, without failing the traces of the other transactions of the block, streamed or not.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceBlockTransactionsUnknownType(t *testing.T) {
+	fixture := json.RawMessage(`[
+		{"transaction_hash": "0x1", "trace_root": {"type": "L1_HANDLER", "function_invocation": {"contract_address": "0xa", "calldata": [], "result": [], "calls": [], "events": [], "messages": [], "execution_resources": {"steps": 1}}}},
+		{"transaction_hash": "0x2", "trace_root": {"type": "FUTURE_TXN", "proof": ["0x1"]}}
+	]`)
+	provider := NewMockProvider(map[string]json.RawMessage{"starknet_traceBlockTransactions": fixture})
+
+	traces, err := provider.TraceBlockTransactions(context.Background(), WithBlockNumber(1))
+	require.NoError(t, err)
+	require.Len(t, traces, 2)
+	require.Equal(t, TransactionType_L1Handler, traces[0].Type())
+	require.Equal(t, utils.TestHexToFelt(t, "0x2"), traces[1].TxnHash)
+	require.Equal(t, map[string]any{"type": "FUTURE_TXN", "proof": []any{"0x1"}}, traces[1].TraceRoot)
+	require.Empty(t, traces[1].Type())
+
+	var streamed []Trace
+	require.NoError(t, decodeTraceStream(json.NewDecoder(strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "result": `+string(fixture)+`}`)), "", func(trace Trace) error {
+		streamed = append(streamed, trace)
+		return nil
+	}))
+	require.Equal(t, traces, streamed)
+
+	// the trace roots of the unknown type are compared as JSON objects
+	changed, _ := TracesDiffer(traces, streamed)
+	require.False(t, changed)
+
+	// a known type that cannot be decoded still fails
+	var trace Trace
+	require.Error(t, json.Unmarshal([]byte(`{"trace_root": {"type": "INVOKE", "execute_invocation": 1}}`), &trace))
+}
+
+// TestTraceBlockTransactionsTxnExecError tests that the failure of a transaction of a block to be traced is
+// returned as a BlockTraceError with the index of the transaction.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceBlockTransactionsTxnExecError(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	testConfig := beforeEach(t)
+
+	_, err := testConfig.provider.TraceBlockTransactions(context.Background(), WithBlockHash(utils.TestHexToFelt(t, "0xbad")))
+	var blockErr *BlockTraceError
+	require.ErrorAs(t, err, &blockErr)
+	require.Equal(t, 3, blockErr.Index)
+	require.ErrorIs(t, err, ErrTxnExec)
+	data, err := blockErr.Inner.TypedData()
+	require.NoError(t, err)
+	require.Equal(t, "Out of gas", data.(*TransactionExecutionErrorData).ExecutionError.Error.Message)
+
+	// the missing block is still returned as ErrBlockNotFound
+	_, err = testConfig.provider.TraceBlockTransactions(context.Background(), WithBlockHash(utils.TestHexToFelt(t, "0x0")))
+	require.Equal(t, ErrBlockNotFound, err)
+}
+
+// blockTraceServer returns a server answering every request with the traces of the Sepolia block
+// 0x42a4c6a4c3dffee2cce78f04259b499437049b0084c3296da9fbbec7eda79b2 repeated the given number of times, along
+// the traces themselves.
+//
+// Parameters:
+// - tb: the testing object for reporting failures
+// - repeat: the number of copies of the traces of the block
+// Returns:
+// - *httptest.Server: the started server
+// - []Trace: the traces served
+func blockTraceServer(tb testing.TB, repeat int) (*httptest.Server, []Trace) {
+	content, err := os.ReadFile("./tests/trace/sepoliaBlockTrace_0x42a4c6a4c3dffee2cce78f04259b499437049b0084c3296da9fbbec7eda79b2.json")
+	require.NoError(tb, err)
+	var block struct {
+		Result []json.RawMessage `json:"result"`
+	}
+	require.NoError(tb, json.Unmarshal(content, &block))
+	var result []json.RawMessage
+	for i := 0; i < repeat; i++ {
+		result = append(result, block.Result...)
+	}
+	response, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "result": result})
+	require.NoError(tb, err)
+	var traces []Trace
+	for _, raw := range result {
+		var trace Trace
+		require.NoError(tb, json.Unmarshal(raw, &trace))
+		traces = append(traces, trace)
+	}
+	txnExecError, err := os.ReadFile("./tests/trace/traceBlockTxnExecError.json")
+	require.NoError(tb, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "starknet_traceBlockTransactions" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(string(req.Params), `"0xdead"`) {
+			fmt.Fprint(w, `{"jsonrpc": "2.0", "id": 1, "error": {"code": 24, "message": "Block not found"}}`)
+			return
+		}
+		if strings.Contains(string(req.Params), `"0xbad"`) {
+			_, _ = w.Write(txnExecError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(response)
+	}))
+	tb.Cleanup(server.Close)
+	return server, traces
+}
+
+// TestTraceBlockTransactionsStream tests that the streamed traces of a block are the traces of
+// TraceBlockTransactions, that an error of the callback stops the decoding and that the errors of the node
+// are returned with the request ID of the call, a failed transaction as a BlockTraceError.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceBlockTransactionsStream(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	server, expected := blockTraceServer(t, 2)
+	provider, err := NewProvider(server.URL)
+	require.NoError(t, err)
+	blockID := WithBlockHash(utils.TestHexToFelt(t, "0x42a4c6a4c3dffee2cce78f04259b499437049b0084c3296da9fbbec7eda79b2"))
+
+	var streamed []Trace
+	err = provider.TraceBlockTransactionsStream(context.Background(), blockID, func(trace Trace) error {
+		streamed = append(streamed, trace)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, streamed)
+	buffered, err := provider.TraceBlockTransactions(context.Background(), blockID)
+	require.NoError(t, err)
+	require.Equal(t, buffered, streamed)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = provider.TraceBlockTransactionsStream(context.Background(), blockID, func(trace Trace) error {
+		calls++
+		if calls == 2 {
+			return stop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 2, calls)
+
+	err = provider.TraceBlockTransactionsStream(context.Background(), WithBlockHash(utils.TestHexToFelt(t, "0xdead")), func(Trace) error {
+		t.Fatal("unexpected trace")
+		return nil
+	})
+	require.ErrorIs(t, err, ErrBlockNotFound)
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, uint64(4), rpcErr.RequestID)
+
+	// the failed transaction is returned with its index, as by TraceBlockTransactions
+	err = provider.TraceBlockTransactionsStream(context.Background(), WithBlockHash(utils.TestHexToFelt(t, "0xbad")), func(Trace) error {
+		t.Fatal("unexpected trace")
+		return nil
+	})
+	var blockErr *BlockTraceError
+	require.ErrorAs(t, err, &blockErr)
+	require.Equal(t, 3, blockErr.Index)
+	require.ErrorIs(t, err, ErrTxnExec)
+	require.Equal(t, uint64(5), blockErr.Inner.RequestID)
+
+	// the providers of other transports hand the traces of TraceBlockTransactions
+	mockProvider := &Provider{c: &rpcMock{}}
+	streamed = nil
+	err = mockProvider.TraceBlockTransactionsStream(context.Background(), blockID, func(trace Trace) error {
+		streamed = append(streamed, trace)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected[:len(expected)/2], streamed)
+}
+
+// TestTraceBlockTransactionsStreamTimeout tests that the default timeout of WithTimeout bounds a streamed call
+// to a node that never answers, and that the deadline of the context is kept.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceBlockTransactionsStreamTimeout(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	provider, err := NewProvider(server.URL, WithTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	start := time.Now()
+	err = provider.TraceBlockTransactionsStream(context.Background(), WithBlockNumber(1), func(Trace) error {
+		t.Fatal("unexpected trace")
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+
+	// the deadline of the context overrides the default timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	err = provider.TraceBlockTransactionsStream(ctx, WithBlockNumber(1), func(Trace) error { return nil })
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+// BenchmarkTraceBlockTransactions compares the allocations of the buffered and streamed retrievals of the
+// traces of a large block.
+//
+// Parameters:
+// - b: the benchmarking object
+// Returns:
+//
+//	none
+func BenchmarkTraceBlockTransactions(b *testing.B) {
+	server, _ := blockTraceServer(b, 100)
+	provider, err := NewProvider(server.URL)
+	require.NoError(b, err)
+	blockID := WithBlockHash(utils.TestHexToFelt(b, "0x42a4c6a4c3dffee2cce78f04259b499437049b0084c3296da9fbbec7eda79b2"))
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := provider.TraceBlockTransactions(context.Background(), blockID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			err := provider.TraceBlockTransactionsStream(context.Background(), blockID, func(Trace) error { return nil })
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// TestEstimateBoundsFromTrace tests the EstimateBoundsFromTrace function.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestEstimateBoundsFromTrace(t *testing.T) {
+	resources := ExecutionResources{
+		ComputationResources: ComputationResources{
+			Steps:          72180,
+			MemoryHoles:    3866,
+			PedersenApps:   364,
+			RangeCheckApps: 2203,
+			BitwiseApps:    18,
+			ECOPApps:       3,
+		},
+		DataAvailability: DataAvailability{L1Gas: 9264},
+	}
+
+	type testSetType struct {
+		Trace          TxnTrace
+		Multiplier     float64
+		ExpectedBounds ResourceBoundsMapping
+		ExpectedErr    error
+	}
+	testSet := []testSetType{
+		{
+			// ceil(72180 * 0.0025) + 9264 = 9445 gas, * 1.5 = 14167.5
+			Trace:      InvokeTxnTrace{ExecutionResources: resources},
+			Multiplier: 1.5,
+			ExpectedBounds: ResourceBoundsMapping{
+				L1Gas: ResourceBounds{MaxAmount: "0x3758", MaxPricePerUnit: "0x0"},
+				L2Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			},
+		},
+		{
+			Trace:      &DeployAccountTxnTrace{ExecutionResources: ExecutionResources{DataAvailability: DataAvailability{L1Gas: 100, L1DataGas: 128}}},
+			Multiplier: 1,
+			ExpectedBounds: ResourceBoundsMapping{
+				L1Gas: ResourceBounds{MaxAmount: "0xe4", MaxPricePerUnit: "0x0"},
+				L2Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			},
+		},
+		{
+			// RPC 0.8 bounds the L1 gas with the l1_gas and l1_data_gas totals, (100 + 128) * 1.5 = 342
+			Trace:      InvokeTxnTrace{ExecutionResources: ExecutionResources{TotalL1Gas: 100, TotalL1DataGas: 128, L2Gas: 1000000}},
+			Multiplier: 1.5,
+			ExpectedBounds: ResourceBoundsMapping{
+				L1Gas: ResourceBounds{MaxAmount: "0x156", MaxPricePerUnit: "0x0"},
+				L2Gas: ResourceBounds{MaxAmount: "0x16e360", MaxPricePerUnit: "0x0"},
+			},
+		},
+		{
+			Trace:      InvokeTxnTrace{ExecutionResources: ExecutionResources{L2Gas: 1000000}},
+			Multiplier: 1,
+			ExpectedBounds: ResourceBoundsMapping{
+				L1Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+				L2Gas: ResourceBounds{MaxAmount: "0xf4240", MaxPricePerUnit: "0x0"},
+			},
+		},
+		{
+			Trace:       InvokeTxnTrace{},
+			Multiplier:  1,
+			ExpectedErr: ErrNoExecutionResources,
+		},
+		{
+			Trace:       L1HandlerTxnTrace{},
+			Multiplier:  1,
+			ExpectedErr: ErrNoExecutionResources,
+		},
+	}
+
+	for _, test := range testSet {
+		bounds, err := EstimateBoundsFromTrace(test.Trace, test.Multiplier)
+		if test.ExpectedErr != nil {
+			require.ErrorIs(t, err, test.ExpectedErr)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedBounds, bounds)
+	}
+
+	_, err := EstimateBoundsFromTrace(InvokeTxnTrace{ExecutionResources: resources}, 0)
+	require.Error(t, err)
+
+	// a weighted builtin count overflowing uint64 saturates instead of wrapping
+	require.Equal(t, uint64(math.MaxUint64/gasWeightScale+1), computationGas(ComputationResources{Steps: 1, ECDSAApps: math.MaxUint64 / 1000}))
+}
+
+// TestInvokeTxnTraceRevertReason tests the revert reason of the invoke traces, with the error selector parsed
+// from it, and the revert of the L1 handler traces read from the is_reverted flag of their invocation.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestInvokeTxnTraceRevertReason(t *testing.T) {
+	type testSetType struct {
+		ExecuteInvocation string
+		ExpectedReverted  bool
+		ExpectedReason    string
+		ExpectedSelector  *felt.Felt
+	}
+	testSet := []testSetType{
+		{
+			ExecuteInvocation: `{"contract_address": "0x1", "entry_point_selector": "0x2", "calldata": []}`,
+		},
+		{
+			ExecuteInvocation: `{"revert_reason": "Error in the called contract (0x0753):\nError message: Minimum receive amount not reached\n"}`,
+			ExpectedReverted:  true,
+			ExpectedReason:    "Error in the called contract (0x0753):\nError message: Minimum receive amount not reached\n",
+		},
+		{
+			ExecuteInvocation: `{"revert_reason": "0x556e617574686f72697a6564 ('Unauthorized')."}`,
+			ExpectedReverted:  true,
+			ExpectedReason:    "0x556e617574686f72697a6564 ('Unauthorized').",
+			ExpectedSelector:  utils.TestHexToFelt(t, "0x556e617574686f72697a6564"),
+		},
+	}
+
+	for _, test := range testSet {
+		var trace InvokeTxnTrace
+		require.NoError(t, json.Unmarshal([]byte(`{"type": "INVOKE", "execute_invocation": `+test.ExecuteInvocation+`}`), &trace))
+
+		reason, reverted := trace.RevertReason()
+		require.Equal(t, test.ExpectedReverted, reverted)
+		require.Equal(t, test.ExpectedReason, reason)
+		if !test.ExpectedReverted {
+			require.Nil(t, trace.Revert)
+			continue
+		}
+		require.NotNil(t, trace.Revert)
+		require.Equal(t, test.ExpectedReason, trace.Revert.Reason)
+		require.Equal(t, test.ExpectedSelector, trace.Revert.ErrorSelector)
+	}
+
+	var l1Handler L1HandlerTxnTrace
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "L1_HANDLER", "function_invocation": {"contract_address": "0x1", "entry_point_selector": "0x2", "calldata": []}}`), &l1Handler))
+	_, reverted := l1Handler.RevertReason()
+	require.False(t, reverted)
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "L1_HANDLER", "function_invocation": {"contract_address": "0x1", "entry_point_selector": "0x2", "calldata": [], "is_reverted": true, "failure_reason": "Error message: Invalid deposit"}}`), &l1Handler))
+	reason, reverted := l1Handler.RevertReason()
+	require.True(t, reverted)
+	require.Equal(t, "Error message: Invalid deposit", reason)
+}
+
+// TestTraceTransactionSpecVersion tests that the traces of the execution resources layouts of RPC 0.7 and
+// RPC 0.8 are decoded, with the spec version declared with WithSpecVersion or detected from the trace.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceTransactionSpecVersion(t *testing.T) {
+	type testSetType struct {
+		Fixture            string
+		SpecVersion        string
+		ExpectedResources  ExecutionResources
+		ExpectedInnerSteps uint64
+	}
+	v07Resources := ExecutionResources{
+		ComputationResources: ComputationResources{Steps: 1320, RangeCheckApps: 33, PedersenApps: 6},
+		DataAvailability:     DataAvailability{L1Gas: 0, L1DataGas: 128},
+	}
+	v08Resources := ExecutionResources{TotalL1Gas: 0, TotalL1DataGas: 128, L2Gas: 440000}
+	testSet := []testSetType{
+		{Fixture: "./tests/trace/specV07InvokeTrace.json", SpecVersion: "0.7.1", ExpectedResources: v07Resources, ExpectedInnerSteps: 900},
+		{Fixture: "./tests/trace/specV07InvokeTrace.json", SpecVersion: "", ExpectedResources: v07Resources, ExpectedInnerSteps: 900},
+		{Fixture: "./tests/trace/specV08InvokeTrace.json", SpecVersion: "0.8.0", ExpectedResources: v08Resources},
+		{Fixture: "./tests/trace/specV08InvokeTrace.json", SpecVersion: "", ExpectedResources: v08Resources},
+		// the declared spec version drops the resources of the other layout
+		{Fixture: "./tests/trace/specV08InvokeTrace.json", SpecVersion: "0.7.1", ExpectedResources: ExecutionResources{}},
+	}
+
+	transactionHash := utils.TestHexToFelt(t, "0x1234")
+	for _, test := range testSet {
+		content, err := os.ReadFile(test.Fixture)
+		require.NoError(t, err)
+		provider, err := NewProvider("http://localhost:6060", WithSpecVersion(test.SpecVersion))
+		require.NoError(t, err)
+		require.Equal(t, test.SpecVersion, provider.specVersion)
+		provider.c = &fixtureClient{fixtures: map[string]json.RawMessage{"starknet_traceTransaction": content}}
+
+		trace, err := provider.TraceTransaction(context.Background(), transactionHash)
+		require.NoError(t, err, test.Fixture)
+		invokeTrace, ok := trace.(InvokeTxnTrace)
+		require.True(t, ok)
+		require.Equal(t, test.ExpectedResources, invokeTrace.ExecutionResources)
+		require.Equal(t, test.ExpectedInnerSteps, invokeTrace.ExecuteInvocation.FunctionInvocation.ComputationResources.Steps)
+		require.Equal(t, utils.TestHexToFelt(t, "0x3e2375d84a97d6c9a5b4a6db8ab8b29e8bdd7d3bd3f0e85e43fd88fa2a7f0c9"), invokeTrace.ExecuteInvocation.FunctionInvocation.ContractAddress)
+	}
+}
+
+// TestCollectL1Messages tests that the messages sent to L1 by nested calls are collected in execution order.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestCollectL1Messages(t *testing.T) {
+	content, err := os.ReadFile("./tests/trace/l1MessagesInvokeTrace.json")
+	require.NoError(t, err)
+	var trace InvokeTxnTrace
+	require.NoError(t, json.Unmarshal(content, &trace))
+
+	expected := []MsgToL1{
+		// the inner call of 0x1a sends its message before 0x1a
+		{FromAddress: utils.TestHexToFelt(t, "0x1b"), ToAddress: utils.TestHexToFelt(t, "0x22"), Payload: []*felt.Felt{utils.TestHexToFelt(t, "0x2")}},
+		{FromAddress: utils.TestHexToFelt(t, "0x1a"), ToAddress: utils.TestHexToFelt(t, "0x11"), Payload: []*felt.Felt{utils.TestHexToFelt(t, "0x1")}},
+		// the sender is omitted from the trace
+		{FromAddress: utils.TestHexToFelt(t, "0x1c"), ToAddress: utils.TestHexToFelt(t, "0x33"), Payload: []*felt.Felt{utils.TestHexToFelt(t, "0x3"), utils.TestHexToFelt(t, "0x4")}},
+		{FromAddress: utils.TestHexToFelt(t, "0x1d"), ToAddress: utils.TestHexToFelt(t, "0x44"), Payload: []*felt.Felt{}},
+	}
+	require.Equal(t, expected, CollectL1Messages(trace))
+	require.Equal(t, expected, CollectL1Messages(&trace))
+	require.Empty(t, CollectL1Messages(L1HandlerTxnTrace{}))
+	require.Nil(t, CollectL1Messages("not a trace"))
+}
+
+// TestCollectEvents tests that the events of every invocation of a trace are collected in execution order, with
+// the address of the emitting contract.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestCollectEvents(t *testing.T) {
+	var trace InvokeTxnTrace
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "INVOKE",
+		"execute_invocation": {
+			"contract_address": "0xa", "calldata": [], "result": [], "messages": [], "execution_resources": {"steps": 1},
+			"events": [{"order": 1, "keys": ["0x1"], "data": ["0x64", "0x0"]}],
+			"calls": [{
+				"contract_address": "0xb", "calldata": [], "result": [], "messages": [], "calls": [], "execution_resources": {"steps": 1},
+				"events": [{"order": 0, "keys": ["0x2"], "data": ["0x1"]}]
+			}]
+		},
+		"fee_transfer_invocation": {
+			"contract_address": "0xfee", "calldata": [], "result": [], "messages": [], "calls": [], "execution_resources": {"steps": 1},
+			"events": [{"order": 0, "keys": ["0x3"], "data": []}]
+		},
+		"execution_resources": {"steps": 3, "data_availability": {"l1_gas": 0, "l1_data_gas": 0}}
+	}`), &trace))
+
+	events, err := CollectEvents(&trace)
+	require.NoError(t, err)
+	// the event of the inner call is emitted first
+	require.Equal(t, []Event{
+		{FromAddress: utils.TestHexToFelt(t, "0xb"), Keys: []*felt.Felt{utils.TestHexToFelt(t, "0x2")}, Data: []*felt.Felt{utils.TestHexToFelt(t, "0x1")}},
+		{FromAddress: utils.TestHexToFelt(t, "0xa"), Keys: []*felt.Felt{utils.TestHexToFelt(t, "0x1")}, Data: []*felt.Felt{utils.TestHexToFelt(t, "0x64"), utils.TestHexToFelt(t, "0x0")}},
+		{FromAddress: utils.TestHexToFelt(t, "0xfee"), Keys: []*felt.Felt{utils.TestHexToFelt(t, "0x3")}, Data: []*felt.Felt{}},
+	}, events)
+
+	events, err = CollectEvents(L1HandlerTxnTrace{})
+	require.NoError(t, err)
+	require.Empty(t, events)
+	_, err = CollectEvents("not a trace")
+	require.ErrorIs(t, err, ErrUnknownTraceType)
+}
+
+// TestStorageWritesFor tests that the storage writes of a contract are filtered from the state diff of a trace.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestStorageWritesFor(t *testing.T) {
+	var diff StateDiff
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"storage_diffs": [
+			{"address": "0x0000049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7", "storage_entries": [{"key": "0x1", "value": "0xa"}, {"key": "0x2", "value": "0xb"}]},
+			{"address": "0x1234", "storage_entries": [{"key": "0x3", "value": "0xc"}]},
+			{"address": "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7", "storage_entries": [{"key": "0x4", "value": "0x0"}]}
+		],
+		"deprecated_declared_classes": [], "declared_classes": [], "deployed_contracts": [], "replaced_classes": [], "nonces": []
+	}`), &diff))
+	eth := utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+
+	expected := []StorageEntry{
+		{Key: utils.TestHexToFelt(t, "0x1"), Value: utils.TestHexToFelt(t, "0xa")},
+		{Key: utils.TestHexToFelt(t, "0x2"), Value: utils.TestHexToFelt(t, "0xb")},
+		{Key: utils.TestHexToFelt(t, "0x4"), Value: utils.TestHexToFelt(t, "0x0")},
+	}
+	require.Equal(t, expected, StorageWritesFor(InvokeTxnTrace{StateDiff: &diff}, eth))
+	require.Equal(t, expected, StorageWritesFor(&L1HandlerTxnTrace{StateDiff: &diff}, eth))
+	require.Equal(t, []StorageEntry{{Key: utils.TestHexToFelt(t, "0x3"), Value: utils.TestHexToFelt(t, "0xc")}}, StorageWritesFor(DeclareTxnTrace{StateDiff: &diff}, utils.TestHexToFelt(t, "0x1234")))
+
+	// the empty results are encoded as an empty array
+	for _, writes := range [][]StorageEntry{
+		StorageWritesFor(DeployAccountTxnTrace{StateDiff: &diff}, utils.TestHexToFelt(t, "0xdead")),
+		StorageWritesFor(InvokeTxnTrace{}, eth),
+		StorageWritesFor("not a trace", eth),
+	} {
+		require.NotNil(t, writes)
+		encoded, err := json.Marshal(writes)
+		require.NoError(t, err)
+		require.Equal(t, "[]", string(encoded))
+	}
+}
+
+// TestFlattenCalls tests that the call trees of a trace are flattened in execution order along their depth.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestFlattenCalls(t *testing.T) {
+	content, err := os.ReadFile("./tests/trace/l1MessagesInvokeTrace.json")
+	require.NoError(t, err)
+	var trace InvokeTxnTrace
+	require.NoError(t, json.Unmarshal(content, &trace))
+
+	account := "0x3e2375d84a97d6c9a5b4a6db8ab8b29e8bdd7d3bd3f0e85e43fd88fa2a7f0c9"
+	expected := []struct {
+		address string
+		caller  string
+		depth   int
+	}{
+		{account, "0x0", 0},
+		{account, "0x0", 0},
+		{"0x1a", account, 1},
+		{"0x1b", "0x1a", 2},
+		{"0x1c", account, 1},
+		{"0x1d", "0x1c", 2},
+		{"0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7", account, 0},
+	}
+	calls := FlattenCalls(trace)
+	require.Len(t, calls, len(expected))
+	for i, call := range calls {
+		require.Equal(t, utils.TestHexToFelt(t, expected[i].address), call.ContractAddress)
+		require.Equal(t, utils.TestHexToFelt(t, expected[i].caller), call.CallerAddress)
+		require.Equal(t, expected[i].depth, call.Depth)
+		require.Equal(t, CallTypeCall, call.CallType)
+	}
+	require.Equal(t, utils.GetSelectorFromNameFelt("__validate__"), calls[0].EntryPointSelector)
+	require.Equal(t, utils.GetSelectorFromNameFelt("__execute__"), calls[1].EntryPointSelector)
+	require.Equal(t, calls, FlattenCalls(&trace))
+
+	// the execute invocation of a reverted transaction is missing from the trace
+	trace.ExecuteInvocation = ExecInvocation{RevertReason: "reverted"}
+	calls = FlattenCalls(trace)
+	require.Len(t, calls, 2)
+	require.Equal(t, utils.GetSelectorFromNameFelt("transfer"), calls[1].EntryPointSelector)
+
+	require.Empty(t, FlattenCalls(L1HandlerTxnTrace{}))
+	require.Nil(t, FlattenCalls("not a trace"))
+}
+
+// TestFailedCalls tests that FailedCalls returns the failed invocations of a trace with their depth, a revert
+// caught by a caller being reported as caught.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestFailedCalls(t *testing.T) {
+	content, err := os.ReadFile("./tests/trace/nestedRevertInvokeTrace.json")
+	require.NoError(t, err)
+	var trace InvokeTxnTrace
+	require.NoError(t, json.Unmarshal(content, &trace))
+	_, reverted := trace.RevertReason()
+	require.False(t, reverted)
+
+	// the swap of the router reverted in the pool, and the account went on with the transfer
+	calls := FailedCalls(trace)
+	require.Len(t, calls, 2)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1a"), calls[0].ContractAddress)
+	require.Equal(t, 1, calls[0].Depth)
+	require.Equal(t, "Swap failed", calls[0].FailureReason)
+	require.True(t, calls[0].Failed)
+	require.True(t, calls[0].Caught)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1b"), calls[1].ContractAddress)
+	require.Equal(t, 2, calls[1].Depth)
+	require.Equal(t, "Insufficient liquidity", calls[1].FailureReason)
+	require.True(t, calls[1].Caught)
+	require.Equal(t, calls, FailedCalls(&trace))
+
+	flattened := FlattenCalls(trace)
+	require.Len(t, flattened, 6)
+	require.True(t, flattened[2].Failed)
+	require.False(t, flattened[2].Caught)
+	require.False(t, flattened[4].Failed)
+
+	// a revert bubbling up to the root invocation is not caught, unlike the one under a call that did not fail
+	var l1Handler L1HandlerTxnTrace
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "L1_HANDLER",
+		"function_invocation": {"contract_address": "0x1", "is_reverted": true, "calls": [
+			{"contract_address": "0x2", "is_reverted": true},
+			{"contract_address": "0x3", "calls": [{"contract_address": "0x4", "failure_reason": "out of gas"}]}
+		]}
+	}`), &l1Handler))
+	calls = FailedCalls(l1Handler)
+	require.Len(t, calls, 3)
+	require.Equal(t, []bool{false, false, true}, []bool{calls[0].Caught, calls[1].Caught, calls[2].Caught})
+	require.Equal(t, utils.TestHexToFelt(t, "0x4"), calls[2].ContractAddress)
+	require.Equal(t, 2, calls[2].Depth)
+
+	require.Nil(t, FailedCalls(InvokeTxnTrace{}))
+	require.Nil(t, FailedCalls("not a trace"))
+}
+
+// paramsRecorderMock is a callCloser recording the JSON encoded params of the calls, and answering them with an empty list.
+type paramsRecorderMock struct {
+	params []string
+}
+
+func (m *paramsRecorderMock) Close() {}
+
+func (m *paramsRecorderMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	params, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	m.params = append(m.params, string(params))
+	return remarshal([]any{}, result)
+}
+
+// TestPendingBlockTag tests that the trace and simulate calls accept the pending block tag, and that a BlockID
+// holding both a block hash and a tag fails before the call.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestPendingBlockTag(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	recorder := &paramsRecorderMock{}
+	provider := &Provider{c: recorder}
+
+	_, err := provider.TraceBlockTransactions(context.Background(), WithBlockTag(BlockTagPending))
+	require.NoError(t, err)
+	_, err = provider.SimulateTransactions(context.Background(), BlockID{Tag: "pending"}, []Transaction{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{`["pending"]`, `["pending",[],null]`}, recorder.params)
+
+	invalid := BlockID{Hash: utils.TestHexToFelt(t, "0x1"), Tag: string(BlockTagLatest)}
+	_, err = provider.TraceBlockTransactions(context.Background(), invalid)
+	require.ErrorIs(t, err, ErrInvalidBlockID)
+	_, err = provider.SimulateTransactions(context.Background(), invalid, []Transaction{}, nil)
+	require.ErrorIs(t, err, ErrInvalidBlockID)
+	require.Len(t, recorder.params, 2)
+}
+
+// TestSumBlockResources tests the sum of the execution resources of the traces of a block, and the saturation of the sums.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSumBlockResources(t *testing.T) {
+	var rawjson struct {
+		Result []Trace `json:"result"`
+	}
+	content, err := os.ReadFile("./tests/trace/sepoliaBlockTrace_0x42a4c6a4c3dffee2cce78f04259b499437049b0084c3296da9fbbec7eda79b2.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &rawjson))
+
+	require.Equal(t, ExecutionResources{
+		ComputationResources: ComputationResources{
+			Steps:          1006519,
+			MemoryHoles:    53863,
+			RangeCheckApps: 30995,
+			PedersenApps:   5152,
+			ECOPApps:       21,
+			BitwiseApps:    309,
+		},
+		DataAvailability: DataAvailability{L1Gas: 135170},
+	}, SumBlockResources(rawjson.Result))
+
+	huge := InvokeTxnTrace{ExecutionResources: ExecutionResources{
+		ComputationResources: ComputationResources{Steps: math.MaxUint64 - 1},
+		DataAvailability:     DataAvailability{L1Gas: math.MaxUint},
+	}}
+	v08 := InvokeTxnTrace{ExecutionResources: ExecutionResources{TotalL1Gas: 20, TotalL1DataGas: 128, L2Gas: 440000}}
+	l1Handler := &L1HandlerTxnTrace{FunctionInvocation: FnInvocation{ComputationResources: ComputationResources{Steps: 10, PoseidonApps: 2}}}
+	total := SumBlockResources([]Trace{{TraceRoot: huge}, {TraceRoot: v08}, {TraceRoot: v08}, {TraceRoot: l1Handler}, {TraceRoot: "not a trace"}})
+	require.Equal(t, uint64(math.MaxUint64), total.Steps)
+	require.Equal(t, uint64(2), total.PoseidonApps)
+	require.Equal(t, uint(math.MaxUint), total.L1Gas)
+	require.Equal(t, uint(40), total.TotalL1Gas)
+	require.Equal(t, uint(256), total.TotalL1DataGas)
+	require.Equal(t, uint(880000), total.L2Gas)
+}
+
+// TestBlockGasStats tests the gas statistics of the traces and of the simulated transactions of a block, the
+// reverted transactions included, and the zero statistics of an empty block.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBlockGasStats(t *testing.T) {
+	invoke := func(l1Gas uint, revertReason string) InvokeTxnTrace {
+		return InvokeTxnTrace{
+			ExecuteInvocation:  ExecInvocation{RevertReason: revertReason},
+			ExecutionResources: ExecutionResources{TotalL1Gas: l1Gas, TotalL1DataGas: 128},
+		}
+	}
+	var traces []Trace
+	var simulated []SimulatedTransaction
+	for i := uint(1); i <= 20; i++ {
+		revertReason := ""
+		if i%10 == 0 {
+			revertReason = "Error in the called contract"
+		}
+		trace := invoke(i*100, revertReason)
+		traces = append(traces, Trace{TraceRoot: trace})
+		simulated = append(simulated, SimulatedTransaction{TxnTrace: trace, FeeEstimate: FeeEstimate{
+			OverallFee:    new(felt.Felt).SetUint64(uint64(i) * 1000),
+			L1GasConsumed: new(felt.Felt).SetUint64(uint64(i) * 100),
+		}})
+	}
+	expected := GasDistribution{Min: big.NewInt(100), Median: big.NewInt(1050), P95: big.NewInt(1900), Max: big.NewInt(2000)}
+
+	stats := BlockGasStats(traces)
+	require.Equal(t, 20, stats.Transactions)
+	require.Equal(t, 2, stats.Reverted)
+	require.Equal(t, expected, stats.L1GasConsumed)
+	require.Zero(t, stats.OverallFee.Max.Sign())
+
+	stats = SimulatedGasStats(simulated)
+	require.Equal(t, 2, stats.Reverted)
+	require.Equal(t, expected, stats.L1GasConsumed)
+	require.Equal(t, GasDistribution{Min: big.NewInt(1000), Median: big.NewInt(10500), P95: big.NewInt(19000), Max: big.NewInt(20000)}, stats.OverallFee)
+
+	odd := BlockGasStats(traces[:3])
+	require.Equal(t, big.NewInt(200), odd.L1GasConsumed.Median)
+	require.Equal(t, big.NewInt(300), odd.L1GasConsumed.P95)
+
+	// before RPC 0.8, the computation gas of 40000 steps plus the L1 gas of the data availability
+	v07 := InvokeTxnTrace{ExecutionResources: ExecutionResources{
+		ComputationResources: ComputationResources{Steps: 40000},
+		DataAvailability:     DataAvailability{L1Gas: 50, L1DataGas: 128},
+	}}
+	require.Equal(t, big.NewInt(150), BlockGasStats([]Trace{{TraceRoot: v07}}).L1GasConsumed.Max)
+
+	empty := BlockGasStats(nil)
+	require.Zero(t, empty.Transactions)
+	require.Zero(t, empty.L1GasConsumed.Median.Sign())
+	require.Zero(t, SimulatedGasStats(nil).OverallFee.P95.Sign())
+}
+
+// TestInvokeTxnTraceRoundTrip tests that the invoke traces sent by the nodes are encoded back with the same fields,
+// in the order of the spec, and that a present but empty state diff is kept apart from an absent one.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestInvokeTxnTraceRoundTrip(t *testing.T) {
+	for _, fixture := range []string{
+		"./tests/trace/sepoliaInvokeTrace_0x6a4a9c4f1a530f7d6dd7bba9b71f090a70d1e3bbde80998fde11a08aab8b282.json",
+		"./tests/trace/l1MessagesInvokeTrace.json",
+	} {
+		t.Run(fixture, func(t *testing.T) {
+			content, err := os.ReadFile(fixture)
+			require.NoError(t, err)
+			var response struct {
+				Result json.RawMessage `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal(content, &response))
+			if response.Result != nil {
+				content = response.Result
+			}
+			var trace InvokeTxnTrace
+			require.NoError(t, json.Unmarshal(content, &trace))
+			encoded, err := json.Marshal(trace)
+			require.NoError(t, err)
+			require.JSONEq(t, string(content), string(encoded))
+			require.True(t, strings.HasPrefix(string(encoded), `{"type":"INVOKE","validate_invocation":{"contract_address":`))
+		})
+	}
+
+	var absent, empty InvokeTxnTrace
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"INVOKE","execute_invocation":{"revert_reason":"failed"}}`), &absent))
+	require.Nil(t, absent.StateDiff)
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"INVOKE","execute_invocation":{"revert_reason":"failed"},"state_diff":{}}`), &empty))
+	require.NotNil(t, empty.StateDiff)
+
+	encoded, err := json.Marshal(absent)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"INVOKE","execute_invocation":{"revert_reason":"failed"}}`, string(encoded))
+	encoded, err = json.Marshal(empty)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"state_diff":{`)
+}
+
+// TestTraceFeltNormalization tests that the traces and state diffs holding the same felts formatted differently
+// by the nodes (leading zeros, letter case) are decoded into equal values.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceFeltNormalization(t *testing.T) {
+	trace := func(zero, address, value string) string {
+		return `{
+			"type": "INVOKE",
+			"execute_invocation": {
+				"contract_address": "` + address + `", "entry_point_selector": "` + zero + `", "calldata": ["` + value + `"],
+				"caller_address": "` + zero + `", "class_hash": "` + address + `", "entry_point_type": "EXTERNAL", "call_type": "CALL",
+				"result": ["` + value + `"], "calls": [],
+				"events": [{"order": 0, "keys": ["` + value + `"], "data": ["` + zero + `"]}],
+				"messages": [{"order": 0, "from_address": "` + address + `", "to_address": "` + value + `", "payload": ["` + zero + `"]}],
+				"execution_resources": {"steps": 1}
+			},
+			"state_diff": {
+				"storage_diffs": [{"address": "` + address + `", "storage_entries": [{"key": "` + zero + `", "value": "` + value + `"}]}],
+				"deprecated_declared_classes": [], "declared_classes": [], "deployed_contracts": [], "replaced_classes": [],
+				"nonces": [{"contract_address": "` + address + `", "nonce": "` + value + `"}]
+			}
+		}`
+	}
+	canonical := trace("0x0", "0x4c1d9da136846ab084ae18cf6ce7a652df7793b666a16ce46b1bf5850cc739d", "0xabc")
+	padded := trace("0x00", "0x04C1D9DA136846AB084AE18CF6CE7A652DF7793B666A16CE46B1BF5850CC739D", "0x0000ABC")
+
+	var canonicalTrace, paddedTrace Trace
+	require.NoError(t, json.Unmarshal([]byte(`{"trace_root": `+canonical+`, "transaction_hash": "0x1"}`), &canonicalTrace))
+	require.NoError(t, json.Unmarshal([]byte(`{"trace_root": `+padded+`, "transaction_hash": "0x01"}`), &paddedTrace))
+	require.IsType(t, InvokeTxnTrace{}, canonicalTrace.TraceRoot)
+	require.Equal(t, canonicalTrace, paddedTrace)
+
+	var canonicalSimulated, paddedSimulated SimulatedTransaction
+	require.NoError(t, json.Unmarshal([]byte(`{"transaction_trace": `+canonical+`, "overall_fee": "0x10", "unit": "WEI"}`), &canonicalSimulated))
+	require.NoError(t, json.Unmarshal([]byte(`{"transaction_trace": `+padded+`, "overall_fee": "0x010", "unit": "WEI"}`), &paddedSimulated))
+	require.IsType(t, InvokeTxnTrace{}, canonicalSimulated.TxnTrace)
+	require.Equal(t, canonicalSimulated, paddedSimulated)
+	require.Equal(t, "0x10", canonicalSimulated.OverallFee.String())
+}
+
+// replayMock is a transactionDetailMock also answering starknet_simulateTransactions, recording its params.
+type replayMock struct {
+	transactionDetailMock
+	simulateParams string
+}
+
+func (m *replayMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_simulateTransactions" {
+		return m.transactionDetailMock.CallContext(ctx, result, method, args...)
+	}
+	params, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	m.simulateParams = string(params)
+	return json.Unmarshal([]byte(`[{
+		"transaction_trace": {"type": "INVOKE", "execute_invocation": {"revert_reason": "reverted"}, "execution_resources": {"steps": 1}},
+		"overall_fee": "0x10", "unit": "WEI"
+	}]`), result)
+}
+
+// TestReplayTransaction tests that a transaction is simulated with SKIP_VALIDATE on the parent block of its block.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestReplayTransaction(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mock := &replayMock{}
+	provider := &Provider{c: mock}
+
+	simulated, err := provider.ReplayTransaction(context.Background(), utils.TestHexToFelt(t, "0x1234"))
+	require.NoError(t, err)
+	require.Equal(t, "0x10", simulated.OverallFee.String())
+	trace, ok := simulated.TxnTrace.(InvokeTxnTrace)
+	require.True(t, ok)
+	require.Equal(t, "reverted", trace.ExecuteInvocation.RevertReason)
+
+	// the transaction of the block 7 is simulated on the block 6
+	var params []json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(mock.simulateParams), &params))
+	require.Len(t, params, 3)
+	require.JSONEq(t, `{"block_number": 6}`, string(params[0]))
+	require.JSONEq(t, `["SKIP_VALIDATE"]`, string(params[2]))
+	var txns []map[string]any
+	require.NoError(t, json.Unmarshal(params[1], &txns))
+	require.Len(t, txns, 1)
+	require.Equal(t, "0x5", txns[0]["sender_address"])
+	require.NotContains(t, txns[0], "transaction_hash")
+}
+
+// blockRangeMock is a callCloser answering starknet_traceBlockTransactions with one trace per block after a
+// delay, failing for the block failAt, and recording the maximum number of calls in flight.
+type blockRangeMock struct {
+	mu       sync.Mutex
+	inFlight int
+	max      int
+	calls    int
+	failAt   uint64
+}
+
+func (m *blockRangeMock) Close() {}
+
+func (m *blockRangeMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	m.mu.Lock()
+	m.calls++
+	m.inFlight++
+	m.max = max(m.max, m.inFlight)
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.inFlight--
+		m.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(5 * time.Millisecond):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	number := *args[0].(BlockID).Number
+	if m.failAt != 0 && number == m.failAt {
+		return ErrBlockNotFound
+	}
+	return json.Unmarshal([]byte(fmt.Sprintf(`[{"transaction_hash": "0x%x", "trace_root": {"type": "L1_HANDLER", "function_invocation": {}}}]`, number)), result)
+}
+
+// TestTraceBlockRange tests that TraceBlockRange traces every block of the range with the bounded concurrency,
+// and stops at the first error with the partial results.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTraceBlockRange(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mock := &blockRangeMock{}
+	provider := &Provider{c: mock}
+
+	traces, err := provider.TraceBlockRange(context.Background(), 10, 49, 4)
+	require.NoError(t, err)
+	require.Len(t, traces, 40)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2a"), traces[42][0].TxnHash)
+	require.Equal(t, 40, mock.calls)
+	require.LessOrEqual(t, mock.max, 4)
+
+	mock = &blockRangeMock{failAt: 20}
+	provider = &Provider{c: mock}
+	traces, err = provider.TraceBlockRange(context.Background(), 0, 999, 2)
+	require.ErrorIs(t, err, ErrBlockNotFound)
+	require.Contains(t, err.Error(), "block 20")
+	require.NotEmpty(t, traces)
+	require.NotContains(t, traces, uint64(20))
+	require.Less(t, mock.calls, 30)
+	require.LessOrEqual(t, mock.max, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.TraceBlockRange(ctx, 0, 10, 2)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = provider.TraceBlockRange(context.Background(), 0, 10, 0)
+	require.Error(t, err)
+	_, err = provider.TraceBlockRange(context.Background(), 10, 0, 1)
+	require.Error(t, err)
+}
+
+// TestRecomputeFee tests the recomputation of the fee of a simulated transaction at other gas prices, from the
+// gas consumptions of RPC 0.8 and from the ones of RPC 0.7.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestRecomputeFee(t *testing.T) {
+	var simulated SimulatedTransaction
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"transaction_trace": null,
+		"l1_gas_consumed": "0x10",
+		"l1_gas_price": "0x2",
+		"l2_gas_consumed": "0x3e8",
+		"l2_gas_price": "0x1",
+		"l1_data_gas_consumed": "0x80",
+		"l1_data_gas_price": "0x1",
+		"overall_fee": "0x488",
+		"unit": "FRI"
+	}`), &simulated))
+	estimate := simulated.FeeEstimate
+	require.Equal(t, utils.TestHexToFelt(t, "0x3e8"), estimate.L2GasConsumed)
+
+	// the fee at the prices of the estimate is its overall fee: 16*2 + 1000*1 + 128*1
+	require.Equal(t, estimate.OverallFee, RecomputeFee(estimate, estimate.L1GasPrice, estimate.L2GasPrice, estimate.L1DataGasPrice))
+	// 16*100 + 1000*3 + 128*10
+	require.Equal(t, new(felt.Felt).SetUint64(5880), RecomputeFee(estimate, new(felt.Felt).SetUint64(100), new(felt.Felt).SetUint64(3), new(felt.Felt).SetUint64(10)))
+
+	// RPC 0.7 estimate, without L2 gas: 20*5 + 4*7
+	legacy := FeeEstimate{GasConsumed: new(felt.Felt).SetUint64(20), DataGasConsumed: new(felt.Felt).SetUint64(4)}
+	require.Equal(t, new(felt.Felt).SetUint64(128), RecomputeFee(legacy, new(felt.Felt).SetUint64(5), new(felt.Felt).SetUint64(1000), new(felt.Felt).SetUint64(7)))
+	require.Equal(t, new(felt.Felt).SetUint64(100), RecomputeFee(legacy, new(felt.Felt).SetUint64(5), nil, nil))
+}
+
+// TestFeeDelta tests the comparison of the estimated fees of simulations with the actual fees of receipts.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestFeeDelta(t *testing.T) {
+	type testSetType struct {
+		Estimated               *felt.Felt
+		Actual                  *felt.Felt
+		ExpectedDiff            *felt.Felt
+		ExpectedOverpaidPercent float64
+	}
+	testSet := []testSetType{
+		{Estimated: new(felt.Felt).SetUint64(1200), Actual: new(felt.Felt).SetUint64(1000), ExpectedDiff: new(felt.Felt).SetUint64(200), ExpectedOverpaidPercent: 100.0 / 6},
+		{Estimated: new(felt.Felt).SetUint64(800), Actual: new(felt.Felt).SetUint64(1000), ExpectedDiff: new(felt.Felt).SetUint64(200), ExpectedOverpaidPercent: -25},
+		{Estimated: new(felt.Felt), Actual: new(felt.Felt).SetUint64(1000), ExpectedDiff: new(felt.Felt).SetUint64(1000), ExpectedOverpaidPercent: 0},
+		{Estimated: nil, Actual: nil, ExpectedDiff: new(felt.Felt), ExpectedOverpaidPercent: 0},
+	}
+	for _, test := range testSet {
+		diff, percent := FeeDelta(test.Estimated, test.Actual)
+		require.Equal(t, test.ExpectedDiff, diff)
+		require.InDelta(t, test.ExpectedOverpaidPercent, percent, 1e-9)
+	}
+
+	simulated := SimulatedTransaction{FeeEstimate: FeeEstimate{OverallFee: new(felt.Felt).SetUint64(800), FeeUnit: UnitStrk}}
+	receipt := TransactionReceipt{ActualFee: FeePayment{Amount: new(felt.Felt).SetUint64(1000), Unit: UnitStrk}}
+	comparison, err := CompareSimulatedFee(simulated, receipt)
+	require.NoError(t, err)
+	require.Equal(t, FeeComparison{
+		Estimated:       simulated.OverallFee,
+		Actual:          receipt.ActualFee.Amount,
+		Unit:            UnitStrk,
+		Diff:            new(felt.Felt).SetUint64(200),
+		OverpaidPercent: -25,
+		Underestimated:  true,
+	}, *comparison)
+
+	receipt.ActualFee.Unit = UnitWei
+	_, err = CompareSimulatedFee(simulated, receipt)
+	require.ErrorIs(t, err, ErrFeeUnitMismatch)
+}
+
+// TestFormatTrace tests that FormatTrace renders the call tree of a trace with the known entry point names, the
+// revert reason of a reverted execution, the failed inner calls and the summary of its events and messages, and
+// that it prints a pathologically deep call tree.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestFormatTrace(t *testing.T) {
+	account := utils.TestHexToFelt(t, "0x4a3b00000000000000000000000000000000000000000000000000000009f21")
+	token := utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	invocation := func(address *felt.Felt, selector string, calls ...FnInvocation) FnInvocation {
+		return FnInvocation{
+			FunctionCall: FunctionCall{ContractAddress: address, EntryPointSelector: utils.GetSelectorFromNameFelt(selector)},
+			NestedCalls:  calls,
+		}
+	}
+	transfer := invocation(token, "transfer")
+	transfer.InvocationEvents = []OrderedEvent{{Order: 0, Event: Event{FromAddress: token, Keys: []*felt.Felt{utils.TestHexToFelt(t, "0x99")}, Data: []*felt.Felt{new(felt.Felt), new(felt.Felt)}}}}
+	failed := invocation(token, "approve")
+	failed.IsReverted = true
+	failed.FailureReason = "Error message: Insufficient allowance"
+	execute := invocation(account, "__execute__", invocation(token, "swap", transfer, failed))
+	execute.L1Messages = []OrderedMsg{{Order: 0, MsgToL1: MsgToL1{ToAddress: utils.TestHexToFelt(t, "0xe1"), Payload: []*felt.Felt{new(felt.Felt)}}}}
+	trace := InvokeTxnTrace{
+		ValidateInvocation:    invocation(account, "__validate__"),
+		ExecuteInvocation:     ExecInvocation{FunctionInvocation: execute},
+		FeeTransferInvocation: invocation(token, "transfer"),
+	}
+	swap := shortFelt(utils.GetSelectorFromNameFelt("swap"))
+
+	require.Equal(t, `INVOKE transaction trace
+validate: 0x4a3b...9f21 __validate__
+execute: 0x4a3b...9f21 __execute__
+  0x49d3...4dc7 `+swap+`
+    0x49d3...4dc7 transfer
+    0x49d3...4dc7 approve FAILED: Insufficient allowance
+fee transfer: 0x49d3...4dc7 transfer
+events: 1
+  #0 0x49d3...4dc7 keys [0x99] data 2 felts
+l1 messages: 1
+  #0 0x4a3b...9f21 -> 0xe1 payload 1 felts
+`, FormatTrace(&trace))
+
+	trace.ExecuteInvocation = ExecInvocation{RevertReason: "Error in the called contract (0x0753):\nError message: Minimum receive amount not reached\n"}
+	require.Equal(t, `INVOKE transaction trace
+validate: 0x4a3b...9f21 __validate__
+execute: REVERTED: Minimum receive amount not reached
+fee transfer: 0x49d3...4dc7 transfer
+events: 0
+l1 messages: 0
+`, FormatTrace(trace))
+
+	const depth = 100000
+	deep := invocation(token, "transfer")
+	for i := 0; i < depth; i++ {
+		deep = invocation(account, "__execute__", deep)
+	}
+	formatted := FormatTrace(L1HandlerTxnTrace{FunctionInvocation: deep})
+	lines := strings.Split(strings.TrimSuffix(formatted, "\n"), "\n")
+	require.Len(t, lines, depth+4)
+	require.Equal(t, strings.Repeat("  ", maxFormatIndent)+fmt.Sprintf("[depth %d] 0x49d3...4dc7 transfer", depth), lines[depth+1])
+
+	require.Equal(t, "unknown trace string", FormatTrace("not a trace"))
+}
+
+// TestTracesDiffer tests the comparison of the traces of a block traced again, ignoring the formatting of the
+// felts and the order of the JSON fields, and reporting the indices of the changed transactions.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTracesDiffer(t *testing.T) {
+	fixture, err := os.ReadFile("./tests/trace/mixedBlockTrace.json")
+	require.NoError(t, err)
+	var traces []Trace
+	require.NoError(t, json.Unmarshal(fixture, &traces))
+
+	// the same block with padded felts, its trace roots left as JSON objects with their keys reordered
+	var objects []struct {
+		TxnHash   *felt.Felt     `json:"transaction_hash"`
+		TraceRoot map[string]any `json:"trace_root"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(strings.ReplaceAll(string(fixture), `"0xa1"`, `"0x00a1"`)), &objects))
+	retraced := make([]Trace, len(objects))
+	for i, object := range objects {
+		retraced[i] = Trace{TraceRoot: object.TraceRoot, TxnHash: object.TxnHash}
+	}
+	differ, changed := TracesDiffer(traces, retraced)
+	require.False(t, differ)
+	require.Empty(t, changed)
+
+	// the nonce of the declare transaction changed
+	var reorged []Trace
+	require.NoError(t, json.Unmarshal([]byte(strings.Replace(string(fixture), `"nonce": "0x7"`, `"nonce": "0x8"`, 1)), &reorged))
+	differ, changed = TracesDiffer(traces, reorged)
+	require.True(t, differ)
+	require.Equal(t, []int{1}, changed)
+
+	differ, changed = TracesDiffer(traces, reorged[:1])
+	require.True(t, differ)
+	require.Equal(t, []int{1, 2}, changed)
+
+	reorged[0].TxnHash = utils.TestHexToFelt(t, "0x201")
+	_, changed = TracesDiffer(traces, reorged)
+	require.Equal(t, []int{0, 1}, changed)
+}
+
+// TestExecutionResourcesBuiltins tests the decoding of the builtin counters of the execution resources of the
+// committed traces, and that the counters of the builtins unknown to this version are kept in Extra.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestExecutionResourcesBuiltins(t *testing.T) {
+	content, err := os.ReadFile("./tests/trace/sepoliaInvokeTrace_0x6a4a9c4f1a530f7d6dd7bba9b71f090a70d1e3bbde80998fde11a08aab8b282.json")
+	require.NoError(t, err)
+	var response struct {
+		Result InvokeTxnTrace `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(content, &response))
+	execute := response.Result.ExecuteInvocation.FunctionInvocation.ComputationResources
+	require.Equal(t, uint64(7812), execute.Steps)
+	require.Equal(t, uint64(283), execute.MemoryHoles)
+	require.Equal(t, uint64(296), execute.RangeCheckApps)
+	require.Equal(t, uint64(18), execute.PedersenApps)
+	require.Equal(t, uint64(2), execute.BitwiseApps)
+	require.Equal(t, uint64(6), execute.ECOPApps)
+	require.Nil(t, execute.Extra())
+	require.Equal(t, uint64(3), response.Result.ValidateInvocation.ComputationResources.ECOPApps)
+
+	fixture, err := os.ReadFile("./tests/trace/mixedBlockTrace.json")
+	require.NoError(t, err)
+	var traces []Trace
+	require.NoError(t, json.Unmarshal(fixture, &traces))
+	invoke, ok := traces[0].InvokeTrace()
+	require.True(t, ok)
+	require.Equal(t, uint64(1000), invoke.ExecutionResources.Steps)
+	require.Equal(t, uint(128), invoke.ExecutionResources.L1DataGas)
+
+	resources := `{"steps": 10, "range_check_builtin_applications": 4, "add_mod_builtin_applications": 3, "data_availability": {"l1_gas": 1, "l1_data_gas": 2}, "l2_gas": 5}`
+	var decoded ExecutionResources
+	require.NoError(t, json.Unmarshal([]byte(resources), &decoded))
+	expected := ComputationResources{Steps: 10, RangeCheckApps: 4}
+	expected.SetExtra(map[string]uint64{"add_mod_builtin_applications": 3})
+	require.Equal(t, ExecutionResources{
+		ComputationResources: expected,
+		DataAvailability:     DataAvailability{L1Gas: 1, L1DataGas: 2},
+		L2Gas:                5,
+	}, decoded)
+	// the resources holding unknown builtins stay comparable
+	require.True(t, decoded.ComputationResources == expected)
+	encoded, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	require.JSONEq(t, resources, string(encoded))
+
+	total := SumBlockResources([]Trace{{TraceRoot: InvokeTxnTrace{ExecutionResources: decoded}}, {TraceRoot: InvokeTxnTrace{ExecutionResources: decoded}}})
+	require.Equal(t, map[string]uint64{"add_mod_builtin_applications": 6}, total.Extra())
+
+	require.Error(t, json.Unmarshal([]byte(`{"steps": 1, "add_mod_builtin_applications": "many"}`), &decoded))
+}
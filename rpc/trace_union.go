@@ -0,0 +1,123 @@
+package rpc
+
+// TransactionTrace is the trace of a transaction as returned by TraceTransaction: an InvokeTxnTrace,
+// DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, decoded according to the type of the
+// transaction. The As methods return the trace as one of the concrete types without a type assertion, and
+// back the InvokeTrace, DeclareTrace, DeployAccountTrace and L1HandlerTrace accessors of Trace.
+type TransactionTrace interface {
+	// TraceType returns the type of the traced transaction, e.g. "INVOKE", as a TransactionType
+	TraceType() string
+	// AsInvoke returns the trace of an invoke transaction, false for the other types
+	AsInvoke() (*InvokeTxnTrace, bool)
+	// AsDeclare returns the trace of a declare transaction, false for the other types
+	AsDeclare() (*DeclareTxnTrace, bool)
+	// AsDeployAccount returns the trace of a deploy account transaction, false for the other types
+	AsDeployAccount() (*DeployAccountTxnTrace, bool)
+	// AsL1Handler returns the trace of an L1 handler transaction, false for the other types
+	AsL1Handler() (*L1HandlerTxnTrace, bool)
+}
+
+var _ TransactionTrace = InvokeTxnTrace{}
+var _ TransactionTrace = DeclareTxnTrace{}
+var _ TransactionTrace = DeployAccountTxnTrace{}
+var _ TransactionTrace = L1HandlerTxnTrace{}
+
+// TraceType returns the type of the transaction of the trace, TransactionType_Invoke.
+func (trace InvokeTxnTrace) TraceType() string {
+	return string(TransactionType_Invoke)
+}
+
+// AsInvoke returns a copy of the trace, which is the trace of an invoke transaction.
+func (trace InvokeTxnTrace) AsInvoke() (*InvokeTxnTrace, bool) {
+	return &trace, true
+}
+
+// AsDeclare returns false, the trace being the trace of an invoke transaction.
+func (trace InvokeTxnTrace) AsDeclare() (*DeclareTxnTrace, bool) {
+	return nil, false
+}
+
+// AsDeployAccount returns false, the trace being the trace of an invoke transaction.
+func (trace InvokeTxnTrace) AsDeployAccount() (*DeployAccountTxnTrace, bool) {
+	return nil, false
+}
+
+// AsL1Handler returns false, the trace being the trace of an invoke transaction.
+func (trace InvokeTxnTrace) AsL1Handler() (*L1HandlerTxnTrace, bool) {
+	return nil, false
+}
+
+// TraceType returns the type of the transaction of the trace, TransactionType_Declare.
+func (trace DeclareTxnTrace) TraceType() string {
+	return string(TransactionType_Declare)
+}
+
+// AsInvoke returns false, the trace being the trace of a declare transaction.
+func (trace DeclareTxnTrace) AsInvoke() (*InvokeTxnTrace, bool) {
+	return nil, false
+}
+
+// AsDeclare returns a copy of the trace, which is the trace of a declare transaction.
+func (trace DeclareTxnTrace) AsDeclare() (*DeclareTxnTrace, bool) {
+	return &trace, true
+}
+
+// AsDeployAccount returns false, the trace being the trace of a declare transaction.
+func (trace DeclareTxnTrace) AsDeployAccount() (*DeployAccountTxnTrace, bool) {
+	return nil, false
+}
+
+// AsL1Handler returns false, the trace being the trace of a declare transaction.
+func (trace DeclareTxnTrace) AsL1Handler() (*L1HandlerTxnTrace, bool) {
+	return nil, false
+}
+
+// TraceType returns the type of the transaction of the trace, TransactionType_DeployAccount.
+func (trace DeployAccountTxnTrace) TraceType() string {
+	return string(TransactionType_DeployAccount)
+}
+
+// AsInvoke returns false, the trace being the trace of a deploy account transaction.
+func (trace DeployAccountTxnTrace) AsInvoke() (*InvokeTxnTrace, bool) {
+	return nil, false
+}
+
+// AsDeclare returns false, the trace being the trace of a deploy account transaction.
+func (trace DeployAccountTxnTrace) AsDeclare() (*DeclareTxnTrace, bool) {
+	return nil, false
+}
+
+// AsDeployAccount returns a copy of the trace, which is the trace of a deploy account transaction.
+func (trace DeployAccountTxnTrace) AsDeployAccount() (*DeployAccountTxnTrace, bool) {
+	return &trace, true
+}
+
+// AsL1Handler returns false, the trace being the trace of a deploy account transaction.
+func (trace DeployAccountTxnTrace) AsL1Handler() (*L1HandlerTxnTrace, bool) {
+	return nil, false
+}
+
+// TraceType returns the type of the transaction of the trace, TransactionType_L1Handler.
+func (trace L1HandlerTxnTrace) TraceType() string {
+	return string(TransactionType_L1Handler)
+}
+
+// AsInvoke returns false, the trace being the trace of an L1 handler transaction.
+func (trace L1HandlerTxnTrace) AsInvoke() (*InvokeTxnTrace, bool) {
+	return nil, false
+}
+
+// AsDeclare returns false, the trace being the trace of an L1 handler transaction.
+func (trace L1HandlerTxnTrace) AsDeclare() (*DeclareTxnTrace, bool) {
+	return nil, false
+}
+
+// AsDeployAccount returns false, the trace being the trace of an L1 handler transaction.
+func (trace L1HandlerTxnTrace) AsDeployAccount() (*DeployAccountTxnTrace, bool) {
+	return nil, false
+}
+
+// AsL1Handler returns a copy of the trace, which is the trace of an L1 handler transaction.
+func (trace L1HandlerTxnTrace) AsL1Handler() (*L1HandlerTxnTrace, bool) {
+	return &trace, true
+}
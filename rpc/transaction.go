@@ -2,6 +2,9 @@ package rpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 )
@@ -56,18 +59,123 @@ func (provider *Provider) TransactionReceipt(ctx context.Context, transactionHas
 	return &receipt, nil
 }
 
-// GetTransactionStatus gets the transaction status (possibly reflecting that the tx is still in the mempool, or dropped from it)
+// GetTransactionStatus gets the finality and execution statuses of a transaction (possibly reflecting that the
+// tx is still in the mempool, or dropped from it), without fetching its whole receipt: it is the cheaper call to
+// poll a transaction until it is accepted.
+//
 // Parameters:
 // - ctx: the context.Context object for cancellation and timeouts.
 // - transactionHash: the transaction hash as a felt
 // Returns:
-// - *GetTxnStatusResp: The transaction status
-// - error, if one arose.
-func (provider *Provider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error) {
-	var receipt TxnStatusResp
-	err := do(ctx, provider.c, "starknet_getTransactionStatus", &receipt, transactionHash)
+// - *TxnStatusResult: The finality status (RECEIVED, REJECTED, ACCEPTED_ON_L2 or ACCEPTED_ON_L1), the execution
+// status (SUCCEEDED or REVERTED, empty until the transaction is executed) and the failure reason of the transaction
+// - error: ErrHashNotFound if the transaction is unknown to the node, or another error if one arose.
+func (provider *Provider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResult, error) {
+	var status TxnStatusResult
+	err := do(ctx, provider.c, "starknet_getTransactionStatus", &status, transactionHash)
 	if err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound)
 	}
-	return &receipt, nil
+	return &status, nil
+}
+
+// TransactionFailedError is returned by WaitForTransactionReceipt for a transaction reverted during its
+// execution or rejected by the sequencer.
+type TransactionFailedError struct {
+	TransactionHash *felt.Felt
+	// The status of the transaction, TxnExecutionStatusREVERTED or TxnStatus_Rejected
+	Status string
+	// The revert reason of a reverted transaction, or the failure reason of a rejected one
+	RevertReason string
+}
+
+// Error returns the status and revert reason of the transaction.
+func (e *TransactionFailedError) Error() string {
+	if e.RevertReason == "" {
+		return fmt.Sprintf("transaction %s %s", e.TransactionHash, e.Status)
+	}
+	return fmt.Sprintf("transaction %s %s: %s", e.TransactionHash, e.Status, e.RevertReason)
+}
+
+// WaitOption configures WaitForTransactionReceipt.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	finality TxnFinalityStatus
+}
+
+// WaitForAcceptedOnL1 makes WaitForTransactionReceipt wait for the transaction to be ACCEPTED_ON_L1 instead
+// of ACCEPTED_ON_L2.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - WaitOption: the option to pass to WaitForTransactionReceipt
+func WaitForAcceptedOnL1() WaitOption {
+	return func(options *waitOptions) {
+		options.finality = TxnFinalityStatusAcceptedOnL1
+	}
+}
+
+// WaitForTransactionReceipt polls the status of a transaction every pollInterval, starting right away, until
+// the transaction is ACCEPTED_ON_L2 (or ACCEPTED_ON_L1 with WaitForAcceptedOnL1), reverted or rejected, and then
+// fetches its receipt. Only the status of the transaction is fetched by the polls, its receipt being fetched once.
+// A transaction not found yet is considered pending.
+//
+// Parameters:
+// - ctx: The context.Context object for the requests, the polling stops when it is done
+// - transactionHash: The hash of the transaction
+// - pollInterval: The duration between two polls, which must be positive
+// - opts: The WaitOption of the wait, e.g. WaitForAcceptedOnL1
+// Returns:
+// - *TransactionReceiptWithBlockInfo: the receipt of the transaction, also returned for a reverted transaction
+// - error: a *TransactionFailedError for a reverted or rejected transaction, the context error, or the error
+// of a request
+func (provider *Provider) WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration, opts ...WaitOption) (*TransactionReceiptWithBlockInfo, error) {
+	options := waitOptions{finality: TxnFinalityStatusAcceptedOnL2}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if pollInterval <= 0 {
+		return nil, Err(InvalidParams, fmt.Sprintf("invalid poll interval %v", pollInterval))
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := provider.GetTransactionStatus(ctx, transactionHash)
+		switch {
+		case errors.Is(err, ErrHashNotFound):
+		case err != nil:
+			return nil, err
+		case status.FinalityStatus == TxnStatus_Rejected:
+			return nil, &TransactionFailedError{
+				TransactionHash: transactionHash,
+				Status:          string(TxnStatus_Rejected),
+				RevertReason:    status.FailureReason,
+			}
+		case status.ExecutionStatus == TxnExecutionStatusREVERTED ||
+			status.FinalityStatus == TxnStatus_Accepted_On_L1 || string(status.FinalityStatus) == string(options.finality):
+			receipt, err := provider.TransactionReceipt(ctx, transactionHash)
+			if err != nil {
+				return nil, err
+			}
+			if receipt.ExecutionStatus == TxnExecutionStatusREVERTED {
+				return receipt, &TransactionFailedError{
+					TransactionHash: transactionHash,
+					Status:          string(TxnExecutionStatusREVERTED),
+					RevertReason:    receipt.RevertReason,
+				}
+			}
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
 }
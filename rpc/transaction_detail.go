@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TransactionDetails holds everything known about a transaction, e.g. to display it in a block explorer.
+// contracts.TransactionDetail adds the events of the receipt decoded with the ABIs of their emitters.
+type TransactionDetails struct {
+	Transaction *BlockTransaction
+	// The receipt of the transaction, nil if it is not available
+	Receipt *TransactionReceiptWithBlockInfo
+	// The trace of the transaction, nil if it is not available (e.g. the node does not trace pending transactions)
+	Trace TxnTrace
+}
+
+// TransactionDetail fetches in parallel the transaction, its receipt and its trace. Only the failure to fetch
+// the transaction is an error: the receipt and the trace are left nil when they cannot be fetched.
+// Use contracts.TransactionDetail to also decode the events of the receipt.
+//
+// Parameters:
+// - ctx: the context.Context object for the requests
+// - provider: the provider the details are fetched from
+// - txHash: the hash of the transaction
+// Returns:
+// - *TransactionDetails: the details of the transaction
+// - error: an error if the transaction cannot be fetched
+func TransactionDetail(ctx context.Context, provider RpcProvider, txHash *felt.Felt) (*TransactionDetails, error) {
+	var (
+		details TransactionDetails
+		txnErr  error
+		wg      sync.WaitGroup
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		details.Transaction, txnErr = provider.TransactionByHash(ctx, txHash)
+	}()
+	go func() {
+		defer wg.Done()
+		if receipt, err := provider.TransactionReceipt(ctx, txHash); err == nil {
+			details.Receipt = receipt
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if trace, err := provider.TraceTransaction(ctx, txHash); err == nil {
+			details.Trace = trace
+		}
+	}()
+	wg.Wait()
+	if txnErr != nil {
+		return nil, txnErr
+	}
+	return &details, nil
+}
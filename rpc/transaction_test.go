@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/utils"
@@ -203,3 +204,142 @@ func TestGetTransactionStatus(t *testing.T) {
 		require.Equal(t, *resp, test.ExpectedResp)
 	}
 }
+
+// transactionDetailMock is a callCloser answering the calls of TransactionDetail for an invoke transaction
+// emitting an event, and failing to trace it.
+type transactionDetailMock struct{}
+
+func (m *transactionDetailMock) Close() {}
+
+func (m *transactionDetailMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var content string
+	switch method {
+	case "starknet_getTransactionByHash":
+		content = `{
+			"transaction_hash": "0x1234", "type": "INVOKE", "version": "0x1", "max_fee": "0x10", "nonce": "0x2",
+			"sender_address": "0x5", "signature": ["0x6", "0x7"], "calldata": ["0x1"]
+		}`
+	case "starknet_getTransactionReceipt":
+		content = `{
+			"transaction_hash": "0x1234", "type": "INVOKE", "actual_fee": {"amount": "0x8", "unit": "WEI"},
+			"execution_status": "SUCCEEDED", "finality_status": "ACCEPTED_ON_L2", "messages_sent": [],
+			"block_hash": "0xb10c", "block_number": 7,
+			"execution_resources": {"steps": 10, "data_availability": {"l1_gas": 0, "l1_data_gas": 0}},
+			"events": [{"from_address": "0xe20", "keys": ["0x1"], "data": []}]
+		}`
+	case "starknet_traceTransaction":
+		return ErrNoTraceAvailable
+	default:
+		return errNotFound
+	}
+	return json.Unmarshal([]byte(content), result)
+}
+
+// TestTransactionDetail tests that TransactionDetail assembles the transaction and its receipt, and leaves the
+// unavailable trace nil.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestTransactionDetail(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	provider := &Provider{c: &transactionDetailMock{}}
+
+	details, err := TransactionDetail(context.Background(), provider, utils.TestHexToFelt(t, "0x1234"))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1234"), details.Transaction.Hash())
+	require.NotNil(t, details.Receipt)
+	require.Len(t, details.Receipt.Events, 1)
+	require.Nil(t, details.Trace)
+}
+
+// statusSequenceMock is a callCloser answering the successive calls to starknet_getTransactionStatus with
+// its statuses, the transaction being not found as long as the status is empty, and the calls to
+// starknet_getTransactionReceipt with receipt.
+type statusSequenceMock struct {
+	statuses []string
+	receipt  string
+	polls    int
+	receipts int
+}
+
+func (m *statusSequenceMock) Close() {}
+
+func (m *statusSequenceMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	switch method {
+	case "starknet_getTransactionStatus":
+		status := m.statuses[min(m.polls, len(m.statuses)-1)]
+		m.polls++
+		if status == "" {
+			return ErrHashNotFound
+		}
+		return json.Unmarshal([]byte(`{`+status+`}`), result)
+	case "starknet_getTransactionReceipt":
+		m.receipts++
+		return json.Unmarshal([]byte(`{
+			"transaction_hash": "0x1234", "type": "INVOKE", "actual_fee": {"amount": "0x8", "unit": "WEI"},
+			"messages_sent": [], "events": [], "block_hash": "0xb10c", "block_number": 7,
+			"execution_resources": {"steps": 10, "data_availability": {"l1_gas": 0, "l1_data_gas": 0}}, `+m.receipt+`}`), result)
+	}
+	return errNotFound
+}
+
+// TestWaitForTransactionReceipt tests that WaitForTransactionReceipt polls a pending transaction until it is
+// accepted on L2 or L1, and fails for the reverted and rejected transactions and on the context cancellation.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWaitForTransactionReceipt(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	txHash := utils.TestHexToFelt(t, "0x1234")
+	received := `"finality_status": "RECEIVED"`
+	acceptedOnL2 := `"execution_status": "SUCCEEDED", "finality_status": "ACCEPTED_ON_L2"`
+	acceptedOnL1 := `"execution_status": "SUCCEEDED", "finality_status": "ACCEPTED_ON_L1"`
+
+	mock := &statusSequenceMock{statuses: []string{"", received, acceptedOnL2, acceptedOnL1}, receipt: acceptedOnL2}
+	provider := &Provider{c: mock}
+	receipt, err := provider.WaitForTransactionReceipt(context.Background(), txHash, time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, TxnFinalityStatusAcceptedOnL2, receipt.FinalityStatus)
+	require.Equal(t, 3, mock.polls)
+	// the receipt is only fetched once the transaction is accepted
+	require.Equal(t, 1, mock.receipts)
+
+	mock = &statusSequenceMock{statuses: []string{"", received, acceptedOnL2, acceptedOnL1}, receipt: acceptedOnL1}
+	provider = &Provider{c: mock}
+	receipt, err = provider.WaitForTransactionReceipt(context.Background(), txHash, time.Millisecond, WaitForAcceptedOnL1())
+	require.NoError(t, err)
+	require.Equal(t, TxnFinalityStatusAcceptedOnL1, receipt.FinalityStatus)
+	require.Equal(t, 4, mock.polls)
+	require.Equal(t, 1, mock.receipts)
+
+	reverted := `"execution_status": "REVERTED", "finality_status": "ACCEPTED_ON_L2"`
+	provider = &Provider{c: &statusSequenceMock{statuses: []string{"", reverted}, receipt: reverted + `, "revert_reason": "Error in the called contract"`}}
+	receipt, err = provider.WaitForTransactionReceipt(context.Background(), txHash, time.Millisecond, WaitForAcceptedOnL1())
+	var failed *TransactionFailedError
+	require.ErrorAs(t, err, &failed)
+	require.Equal(t, string(TxnExecutionStatusREVERTED), failed.Status)
+	require.Equal(t, "Error in the called contract", failed.RevertReason)
+	require.NotNil(t, receipt)
+
+	provider = &Provider{c: &statusSequenceMock{statuses: []string{received, `"finality_status": "REJECTED", "failure_reason": "Invalid nonce"`}}}
+	_, err = provider.WaitForTransactionReceipt(context.Background(), txHash, time.Millisecond)
+	require.ErrorAs(t, err, &failed)
+	require.Equal(t, string(TxnStatus_Rejected), failed.Status)
+	require.Equal(t, "Invalid nonce", failed.RevertReason)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	provider = &Provider{c: &statusSequenceMock{statuses: []string{""}}}
+	_, err = provider.WaitForTransactionReceipt(ctx, txHash, time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
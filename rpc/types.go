@@ -2,10 +2,13 @@ package rpc
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
 )
 
 type ResultPageRequest struct {
@@ -205,6 +208,127 @@ type FeeEstimate struct {
 
 	// Units in which the fee is given
 	FeeUnit FeePaymentUnit `json:"unit"`
+
+	// The L1 gas consumption of the transaction, sent from RPC 0.8 on instead of GasConsumed
+	L1GasConsumed *felt.Felt `json:"l1_gas_consumed,omitempty"`
+
+	// The L1 gas price used in the cost estimation, sent from RPC 0.8 on instead of GasPrice
+	L1GasPrice *felt.Felt `json:"l1_gas_price,omitempty"`
+
+	// The L2 gas consumption of the transaction, sent from RPC 0.8 on
+	L2GasConsumed *felt.Felt `json:"l2_gas_consumed,omitempty"`
+
+	// The L2 gas price used in the cost estimation, sent from RPC 0.8 on
+	L2GasPrice *felt.Felt `json:"l2_gas_price,omitempty"`
+
+	// The L1 data gas consumption of the transaction, sent from RPC 0.8 on instead of DataGasConsumed
+	L1DataGasConsumed *felt.Felt `json:"l1_data_gas_consumed,omitempty"`
+
+	// The L1 data gas price used in the cost estimation, sent from RPC 0.8 on instead of DataGasPrice
+	L1DataGasPrice *felt.Felt `json:"l1_data_gas_price,omitempty"`
+}
+
+// RecomputeFee recomputes the overall fee of an estimate from its consumed gas amounts at other gas prices,
+// e.g. to know what a simulated transaction would cost at the prices of another block. The L1 and L1 data gas
+// consumptions of RPC 0.8 are used when present, and GasConsumed and DataGasConsumed otherwise. A missing
+// consumption or price counts as zero.
+//
+// Parameters:
+// - est: the fee estimate
+// - l1GasPrice: the price of the L1 gas
+// - l2GasPrice: the price of the L2 gas
+// - l1DataGasPrice: the price of the L1 data gas
+// Returns:
+// - *felt.Felt: the overall fee, in the unit of the prices
+func RecomputeFee(est FeeEstimate, l1GasPrice, l2GasPrice, l1DataGasPrice *felt.Felt) *felt.Felt {
+	l1Gas, l1DataGas := est.L1GasConsumed, est.L1DataGasConsumed
+	if l1Gas == nil {
+		l1Gas = est.GasConsumed
+	}
+	if l1DataGas == nil {
+		l1DataGas = est.DataGasConsumed
+	}
+
+	fee := new(felt.Felt)
+	for _, cost := range [][2]*felt.Felt{{l1Gas, l1GasPrice}, {est.L2GasConsumed, l2GasPrice}, {l1DataGas, l1DataGasPrice}} {
+		if cost[0] != nil && cost[1] != nil {
+			fee.Add(fee, new(felt.Felt).Mul(cost[0], cost[1]))
+		}
+	}
+	return fee
+}
+
+// FeeDelta compares an estimated fee with the actual fee of the transaction, e.g. to tune the multiplier
+// applied to the estimates.
+//
+// Parameters:
+// - estimated: the estimated fee, such as the overall fee of a simulation
+// - actual: the actual fee of the receipt, in the same unit
+// Returns:
+// - diff: the difference between the estimated and the actual fee, as an absolute value
+// - overpaidPercent: the difference as a percentage of the estimated fee, negative if the fee was
+// underestimated, 0 if the estimated fee is zero or nil
+func FeeDelta(estimated, actual *felt.Felt) (diff *felt.Felt, overpaidPercent float64) {
+	estimatedInt, actualInt := feeAmount(estimated), feeAmount(actual)
+	delta := new(big.Int).Sub(estimatedInt, actualInt)
+	diff = utils.BigIntToFelt(new(big.Int).Abs(delta))
+	if estimatedInt.Sign() == 0 {
+		return diff, 0
+	}
+	percent, _ := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(delta, big.NewInt(100))), new(big.Float).SetInt(estimatedInt)).Float64()
+	return diff, percent
+}
+
+var ErrFeeUnitMismatch = errors.New("the estimated and actual fees are not in the same unit")
+
+// FeeComparison is the comparison of the fee of a simulated transaction with the actual fee of its receipt.
+type FeeComparison struct {
+	Estimated *felt.Felt
+	Actual    *felt.Felt
+	Unit      FeePaymentUnit
+	// The difference between the estimated and the actual fee, as an absolute value
+	Diff *felt.Felt
+	// The difference as a percentage of the estimated fee, negative if the fee was underestimated
+	OverpaidPercent float64
+	// True if the actual fee is higher than the estimated fee
+	Underestimated bool
+}
+
+// CompareSimulatedFee compares the overall fee of a simulated transaction with the actual fee of the receipt
+// of the transaction once it is on-chain, as FeeDelta does.
+//
+// Parameters:
+// - simulated: the simulated transaction, as returned by SimulateTransactions
+// - receipt: the receipt of the transaction
+// Returns:
+// - *FeeComparison: the fees and their difference
+// - error: ErrFeeUnitMismatch if the fees are not in the same unit, e.g. the simulation of a v3 transaction,
+// paid in FRI, compared with the receipt of a v1 transaction, paid in WEI
+func CompareSimulatedFee(simulated SimulatedTransaction, receipt TransactionReceipt) (*FeeComparison, error) {
+	if simulated.FeeUnit != "" && receipt.ActualFee.Unit != "" && simulated.FeeUnit != receipt.ActualFee.Unit {
+		return nil, fmt.Errorf("%w: estimated in %s, paid in %s", ErrFeeUnitMismatch, simulated.FeeUnit, receipt.ActualFee.Unit)
+	}
+	unit := simulated.FeeUnit
+	if unit == "" {
+		unit = receipt.ActualFee.Unit
+	}
+	diff, percent := FeeDelta(simulated.OverallFee, receipt.ActualFee.Amount)
+	return &FeeComparison{
+		Estimated:       simulated.OverallFee,
+		Actual:          receipt.ActualFee.Amount,
+		Unit:            unit,
+		Diff:            diff,
+		OverpaidPercent: percent,
+		Underestimated:  feeAmount(receipt.ActualFee.Amount).Cmp(feeAmount(simulated.OverallFee)) > 0,
+	}, nil
+}
+
+// feeAmount returns a fee as a big.Int, a nil fee being zero.
+func feeAmount(fee *felt.Felt) *big.Int {
+	if fee == nil {
+		return new(big.Int)
+	}
+	return utils.FeltToBigInt(fee)
 }
 
 type TxnExecutionStatus string
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/big"
 	"strconv"
 	"strings"
 
@@ -19,18 +18,66 @@ type BlockHashAndNumberOutput struct {
 	BlockHash   *felt.Felt `json:"block_hash,omitempty"`
 }
 
+// BlockTag is a tag referencing a block relative to the head of the chain, the value of the Tag of a BlockID.
+type BlockTag string
+
+const (
+	// BlockTagLatest references the latest accepted block
+	BlockTagLatest BlockTag = "latest"
+	// BlockTagPending references the pending block, built on top of the latest accepted block
+	BlockTagPending BlockTag = "pending"
+	// BlockTagPreConfirmed references the pre-confirmed block, the block being built by the sequencer, which
+	// some nodes serve alongside or instead of the pending block
+	BlockTagPreConfirmed BlockTag = "pre_confirmed"
+)
+
 // BlockID is a struct that is used to choose between different
 // search types.
 type BlockID struct {
 	Number *uint64    `json:"block_number,omitempty"`
 	Hash   *felt.Felt `json:"block_hash,omitempty"`
-	Tag    string     `json:"block_tag,omitempty"`
+	// The tag of the block, one of the BlockTag constants
+	Tag string `json:"block_tag,omitempty"`
+}
+
+// Validate checks that the BlockID references a block by exactly one of a block number, a block hash
+// or a known tag: BlockTagLatest, BlockTagPending or BlockTagPreConfirmed.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error wrapping ErrInvalidBlockID if the BlockID is invalid
+func (b BlockID) Validate() error {
+	set := 0
+	if b.Number != nil {
+		set++
+	}
+	if b.Hash != nil {
+		set++
+	}
+	if b.Tag != "" {
+		set++
+	}
+	switch {
+	case set == 0:
+		return fmt.Errorf("%w: no block number, hash or tag", ErrInvalidBlockID)
+	case set > 1:
+		return fmt.Errorf("%w: only one of a block number, hash or tag can be set", ErrInvalidBlockID)
+	}
+
+	if tag := BlockTag(b.Tag); tag != "" && tag != BlockTagLatest && tag != BlockTagPending && tag != BlockTagPreConfirmed {
+		return fmt.Errorf("%w: unknown tag %q", ErrInvalidBlockID, b.Tag)
+	}
+	return nil
 }
 
 // MarshalJSON marshals the BlockID to JSON format.
 //
 // It returns a byte slice and an error. The byte slice contains the JSON representation of the BlockID,
 // while the error indicates any error that occurred during the marshaling process.
+// The BlockID is validated first, so that an invalid BlockID fails the request before it is sent.
 //
 // Parameters:
 //
@@ -40,24 +87,22 @@ type BlockID struct {
 // - []byte: the JSON representation of the BlockID
 // - error: any error that occurred during the marshaling process
 func (b BlockID) MarshalJSON() ([]byte, error) {
-	if b.Tag == "pending" || b.Tag == "latest" {
-		return []byte(strconv.Quote(b.Tag)), nil
+	if err := b.Validate(); err != nil {
+		return nil, err
 	}
 
 	if b.Tag != "" {
-		return nil, ErrInvalidBlockID
+		return []byte(strconv.Quote(b.Tag)), nil
 	}
 
 	if b.Number != nil {
 		return []byte(fmt.Sprintf(`{"block_number":%d}`, *b.Number)), nil
 	}
 
-	if b.Hash.BigInt(big.NewInt(0)).BitLen() != 0 {
-		return []byte(fmt.Sprintf(`{"block_hash":"%s"}`, b.Hash.String())), nil
+	if b.Hash.IsZero() {
+		return nil, ErrInvalidBlockID
 	}
-
-	return nil, ErrInvalidBlockID
-
+	return []byte(fmt.Sprintf(`{"block_hash":"%s"}`, b.Hash.String())), nil
 }
 
 type BlockStatus string
@@ -120,6 +165,29 @@ type Block struct {
 	Transactions BlockTransactions `json:"transactions"`
 }
 
+// UnmarshalJSON decodes the block, its header as BlockHeader.UnmarshalJSON does, as the method of the embedded
+// header would otherwise decode the header alone.
+//
+// Parameters:
+// - data: the JSON block
+// Returns:
+// - error: an error if the block cannot be decoded
+func (block *Block) UnmarshalJSON(data []byte) error {
+	var header BlockHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	var body struct {
+		Status       BlockStatus       `json:"status"`
+		Transactions BlockTransactions `json:"transactions"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	*block = Block{BlockHeader: header, Status: body.Status, Transactions: body.Transactions}
+	return nil
+}
+
 type PendingBlock struct {
 	PendingBlockHeader
 	BlockTransactions
@@ -132,6 +200,28 @@ type BlockWithReceipts struct {
 	BlockBodyWithReceipts
 }
 
+// UnmarshalJSON decodes the block, its header as BlockHeader.UnmarshalJSON does.
+//
+// Parameters:
+// - data: the JSON block
+// Returns:
+// - error: an error if the block cannot be decoded
+func (block *BlockWithReceipts) UnmarshalJSON(data []byte) error {
+	var header BlockHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	var body struct {
+		Status BlockStatus `json:"status"`
+		BlockBodyWithReceipts
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	*block = BlockWithReceipts{BlockHeader: header, Status: body.Status, BlockBodyWithReceipts: body.BlockBodyWithReceipts}
+	return nil
+}
+
 type BlockBodyWithReceipts struct {
 	Transactions []TransactionWithReceipt `json:"transactions"`
 }
@@ -154,6 +244,28 @@ type BlockTxHashes struct {
 	Transactions []*felt.Felt `json:"transactions"`
 }
 
+// UnmarshalJSON decodes the block, its header as BlockHeader.UnmarshalJSON does.
+//
+// Parameters:
+// - data: the JSON block
+// Returns:
+// - error: an error if the block cannot be decoded
+func (block *BlockTxHashes) UnmarshalJSON(data []byte) error {
+	var header BlockHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	var body struct {
+		Status       BlockStatus  `json:"status"`
+		Transactions []*felt.Felt `json:"transactions"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	*block = BlockTxHashes{BlockHeader: header, Status: body.Status, Transactions: body.Transactions}
+	return nil
+}
+
 type PendingBlockTxHashes struct {
 	PendingBlockHeader
 	Transactions []*felt.Felt `json:"transactions"`
@@ -176,12 +288,71 @@ type BlockHeader struct {
 	L1GasPrice ResourcePrice `json:"l1_gas_price"`
 	// The price of l1 data gas in the block
 	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block, only sent from RPC 0.8 on
+	L2GasPrice *ResourcePrice `json:"l2_gas_price,omitempty"`
 	// Specifies whether the data of this block is published via blob data or calldata
 	L1DAMode L1DAMode `json:"l1_da_mode"`
 	// Semver of the current Starknet protocol
 	StarknetVersion string `json:"starknet_version"`
 }
 
+// UnmarshalJSON decodes the header, its felts and numbers sent either as hex strings or as JSON integers, a
+// value that is not a felt, or a block number or timestamp that is not a uint64, being rejected with an error
+// naming its field.
+//
+// Parameters:
+// - data: the JSON block header
+// Returns:
+// - error: an error if a field cannot be decoded
+func (header *BlockHeader) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHash        json.RawMessage `json:"block_hash"`
+		ParentHash       json.RawMessage `json:"parent_hash"`
+		BlockNumber      json.RawMessage `json:"block_number"`
+		NewRoot          json.RawMessage `json:"new_root"`
+		Timestamp        json.RawMessage `json:"timestamp"`
+		SequencerAddress json.RawMessage `json:"sequencer_address"`
+		L1GasPrice       ResourcePrice   `json:"l1_gas_price"`
+		L1DataGasPrice   ResourcePrice   `json:"l1_data_gas_price"`
+		L2GasPrice       *ResourcePrice  `json:"l2_gas_price"`
+		L1DAMode         L1DAMode        `json:"l1_da_mode"`
+		StarknetVersion  string          `json:"starknet_version"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	decoded := BlockHeader{
+		L1GasPrice:      raw.L1GasPrice,
+		L1DataGasPrice:  raw.L1DataGasPrice,
+		L2GasPrice:      raw.L2GasPrice,
+		L1DAMode:        raw.L1DAMode,
+		StarknetVersion: raw.StarknetVersion,
+	}
+	var err error
+	for _, field := range []struct {
+		name  string
+		raw   json.RawMessage
+		value **felt.Felt
+	}{
+		{"block_hash", raw.BlockHash, &decoded.BlockHash},
+		{"parent_hash", raw.ParentHash, &decoded.ParentHash},
+		{"new_root", raw.NewRoot, &decoded.NewRoot},
+		{"sequencer_address", raw.SequencerAddress, &decoded.SequencerAddress},
+	} {
+		if *field.value, err = decodeFeltField(field.name, field.raw); err != nil {
+			return err
+		}
+	}
+	if decoded.BlockNumber, err = decodeUint64Field("block_number", raw.BlockNumber); err != nil {
+		return err
+	}
+	if decoded.Timestamp, err = decodeUint64Field("timestamp", raw.Timestamp); err != nil {
+		return err
+	}
+	*header = decoded
+	return nil
+}
+
 type L1DAMode int
 
 const (
@@ -229,6 +400,8 @@ type PendingBlockHeader struct {
 	StarknetVersion string `json:"starknet_version"`
 	// The price of l1 data gas in the block
 	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block, only sent from RPC 0.8 on
+	L2GasPrice *ResourcePrice `json:"l2_gas_price,omitempty"`
 	// Specifies whether the data of this block is published via blob data or calldata
 	L1DAMode L1DAMode `json:"l1_da_mode"`
 }
@@ -239,3 +412,30 @@ type ResourcePrice struct {
 	// The price of one unit of the given resource, denominated in wei
 	PriceInWei *felt.Felt `json:"price_in_wei"`
 }
+
+// UnmarshalJSON decodes the prices sent either as hex strings or as JSON integers, a price that is not a felt
+// being rejected with an error naming its field.
+//
+// Parameters:
+// - data: the JSON resource price
+// Returns:
+// - error: an error if a price is not a felt
+func (price *ResourcePrice) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		PriceInFRI json.RawMessage `json:"price_in_fri"`
+		PriceInWei json.RawMessage `json:"price_in_wei"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	fri, err := decodeFeltField("price_in_fri", raw.PriceInFRI)
+	if err != nil {
+		return err
+	}
+	wei, err := decodeFeltField("price_in_wei", raw.PriceInWei)
+	if err != nil {
+		return err
+	}
+	*price = ResourcePrice{PriceInFRI: fri, PriceInWei: wei}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
@@ -19,8 +20,8 @@ import (
 //
 // The function tests the MarshalJSON method of the BlockID struct by providing
 // different scenarios and verifying the output against the expected values.
-// The scenarios include testing the serialization of the "latest" and
-// "pending" tags, testing an invalid tag, testing the serialization of a
+// The scenarios include testing the serialization of the "latest",
+// "pending" and "pre_confirmed" tags, testing an invalid tag, testing the serialization of a
 // block number, and testing the serialization of a block hash.
 // The function uses the testing.T parameter to report any errors that occur
 // during the execution of the test cases.
@@ -46,6 +47,9 @@ func TestBlockID_Marshal(t *testing.T) {
 			Tag: "pending",
 		},
 		want: `"pending"`,
+	}, {
+		id:   WithBlockTag(BlockTagPreConfirmed),
+		want: `"pre_confirmed"`,
 	}, {
 		id: BlockID{
 			Tag: "bad tag",
@@ -64,6 +68,24 @@ func TestBlockID_Marshal(t *testing.T) {
 			}
 		}(),
 		want: `{"block_hash":"0xdead"}`,
+	}, {
+		id: BlockID{
+			Tag:    string(BlockTagPending),
+			Number: &blockNumber,
+		},
+		wantErr: ErrInvalidBlockID,
+	}, {
+		id: func() BlockID {
+			h, _ := new(felt.Felt).SetString("0xdead")
+			return BlockID{
+				Tag:  string(BlockTagPreConfirmed),
+				Hash: h,
+			}
+		}(),
+		wantErr: ErrInvalidBlockID,
+	}, {
+		id:      BlockID{},
+		wantErr: ErrInvalidBlockID,
 	}} {
 		b, err := tc.id.MarshalJSON()
 		if err != nil && tc.wantErr == nil {
@@ -78,6 +100,35 @@ func TestBlockID_Marshal(t *testing.T) {
 	}
 }
 
+// TestBlockTagPreConfirmed tests that Call, Nonce and StorageAt send the pre_confirmed tag as the block ID.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBlockTagPreConfirmed(t *testing.T) {
+	provider := NewMockProvider(map[string]json.RawMessage{
+		`starknet_call[{"contract_address":"0x1","entry_point_selector":"0x2","calldata":[]},"pre_confirmed"]`:             json.RawMessage(`["0x3"]`),
+		`starknet_getNonce["pre_confirmed","0x1"]`:                                                                         json.RawMessage(`"0x4"`),
+		`starknet_getStorageAt["0x1","` + fmt.Sprintf("0x%x", utils.GetSelectorFromName("balance")) + `","pre_confirmed"]`: json.RawMessage(`"0x5"`),
+	})
+	address := new(felt.Felt).SetUint64(1)
+	blockID := WithBlockTag(BlockTagPreConfirmed)
+
+	result, err := provider.Call(context.Background(), FunctionCall{ContractAddress: address, EntryPointSelector: new(felt.Felt).SetUint64(2)}, blockID)
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(3)}, result)
+
+	nonce, err := provider.Nonce(context.Background(), blockID, address)
+	require.NoError(t, err)
+	require.Equal(t, new(felt.Felt).SetUint64(4), nonce)
+
+	value, err := provider.StorageAt(context.Background(), address, "balance", blockID)
+	require.NoError(t, err)
+	require.Equal(t, "0x5", value)
+}
+
 // TestBlockStatus is a unit test for the BlockStatus function.
 //
 // The test checks the behavior of the BlockStatus function by iterating through a list of test cases.
@@ -277,3 +328,77 @@ func TestBlockWithReceipts(t *testing.T) {
 		}
 	}
 }
+
+// TestBlockHeader_UnmarshalMixedNumbers tests the decoding of a block header whose felts, numbers and prices are
+// sent as hex strings or as JSON integers, and the errors naming the field of a value exceeding the felt modulus.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBlockHeader_UnmarshalMixedNumbers(t *testing.T) {
+	header := `{
+		"block_hash": "0x1a2b",
+		"parent_hash": 6699,
+		"block_number": "0x2a",
+		"new_root": "0x3",
+		"timestamp": 1700000000,
+		"sequencer_address": "0x4",
+		"l1_gas_price": {"price_in_fri": "0x64", "price_in_wei": 100},
+		"l1_data_gas_price": {"price_in_fri": 7, "price_in_wei": "0x7"},
+		"l1_da_mode": "BLOB",
+		"starknet_version": "0.13.2"
+	}`
+	var block BlockHeader
+	require.NoError(t, json.Unmarshal([]byte(header), &block))
+	require.Equal(t, "0x1a2b", block.BlockHash.String())
+	require.Equal(t, "0x1a2b", block.ParentHash.String())
+	require.Equal(t, uint64(42), block.BlockNumber)
+	require.Equal(t, "0x64", block.L1GasPrice.PriceInFRI.String())
+	require.Equal(t, "0x64", block.L1GasPrice.PriceInWei.String())
+	require.Equal(t, "0x7", block.L1DataGasPrice.PriceInFRI.String())
+	require.Equal(t, "0x7", block.L1DataGasPrice.PriceInWei.String())
+
+	// the blocks embedding the header decode their other fields too
+	var full Block
+	require.NoError(t, json.Unmarshal([]byte(strings.Replace(header, "{", `{"status": "ACCEPTED_ON_L2", "transactions": [],`, 1)), &full))
+	require.Equal(t, block, full.BlockHeader)
+	require.Equal(t, BlockStatus_AcceptedOnL2, full.Status)
+	require.Empty(t, full.Transactions)
+
+	err := json.Unmarshal([]byte(strings.Replace(header, `"0x3"`, `"0x800000000000011000000000000000000000000000000000000000000000001"`, 1)), &block)
+	require.ErrorContains(t, err, "new_root")
+	require.ErrorContains(t, err, "exceeds the felt modulus")
+	err = json.Unmarshal([]byte(strings.Replace(header, `"0x2a"`, `"0x10000000000000000"`, 1)), &block)
+	require.ErrorContains(t, err, "block_number")
+
+	var price ResourcePrice
+	err = json.Unmarshal([]byte(`{"price_in_fri": "0x1", "price_in_wei": "0x800000000000011000000000000000000000000000000000000000000000001"}`), &price)
+	require.ErrorContains(t, err, "price_in_wei")
+	require.ErrorContains(t, err, "exceeds the felt modulus")
+
+	err = json.Unmarshal([]byte(`{"price_in_fri": "wei", "price_in_wei": "0x1"}`), &price)
+	require.ErrorContains(t, err, "price_in_fri")
+
+	var numbers struct {
+		Hex     NumAsHex `json:"hex"`
+		Integer NumAsHex `json:"integer"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"hex": "0x2a", "integer": 42}`), &numbers))
+	require.Equal(t, NumAsHex("0x2a"), numbers.Hex)
+	require.Equal(t, NumAsHex("0x2a"), numbers.Integer)
+	require.Error(t, json.Unmarshal([]byte(`{"integer": -1}`), &numbers))
+	require.Error(t, json.Unmarshal([]byte(`{"integer": 1.5}`), &numbers))
+
+	// a string without the 0x prefix is decimal, the other Go integer literals are rejected
+	for raw, expected := range map[string]int64{`"0x2A"`: 42, `"017"`: 17, `17`: 17} {
+		value, err := decodeJSONInteger(json.RawMessage(raw))
+		require.NoError(t, err, raw)
+		require.Equal(t, big.NewInt(expected), value, raw)
+	}
+	for _, raw := range []string{`"0o17"`, `"0b1"`, `"1_000"`, `"0x1_0"`, `"0x"`} {
+		_, err := decodeJSONInteger(json.RawMessage(raw))
+		require.Error(t, err, raw)
+	}
+}
@@ -2,7 +2,6 @@ package rpc
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -28,6 +27,7 @@ var _ IBlockTransaction = BlockDeclareTxnV2{}
 var _ IBlockTransaction = BlockDeclareTxnV3{}
 var _ IBlockTransaction = BlockDeployTxn{}
 var _ IBlockTransaction = BlockDeployAccountTxn{}
+var _ IBlockTransaction = BlockDeployAccountTxnV3{}
 var _ IBlockTransaction = BlockL1HandlerTxn{}
 
 // Hash returns the transaction hash of the BlockInvokeTxnV0.
@@ -75,6 +75,11 @@ func (tx BlockDeployAccountTxn) Hash() *felt.Felt {
 	return tx.TransactionHash
 }
 
+// Hash returns the transaction hash of the BlockDeployAccountTxnV3.
+func (tx BlockDeployAccountTxnV3) Hash() *felt.Felt {
+	return tx.TransactionHash
+}
+
 // Hash returns the hash of the BlockL1HandlerTxn.
 func (tx BlockL1HandlerTxn) Hash() *felt.Felt {
 	return tx.TransactionHash
@@ -130,6 +135,11 @@ type BlockDeployAccountTxn struct {
 	DeployAccountTxn
 }
 
+type BlockDeployAccountTxnV3 struct {
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+	DeployAccountTxnV3
+}
+
 // UnmarshalJSON unmarshals the data into a BlockTransactions object.
 //
 // It takes a byte slice as the parameter, representing the JSON data to be unmarshalled.
@@ -198,50 +208,65 @@ func (txn *BlockTransaction) UnmarshalJSON(data []byte) error {
 func unmarshalBlockTxn(t interface{}) (IBlockTransaction, error) {
 	switch casted := t.(type) {
 	case map[string]interface{}:
-		switch TransactionType(casted["type"].(string)) {
+		txnType, version, err := txnTypeAndVersion(casted)
+		if err != nil {
+			return nil, err
+		}
+		switch txnType {
 		case TransactionType_Declare:
-
-			switch TransactionType(casted["version"].(string)) {
-			case "0x0":
+			switch version {
+			case TransactionV0:
 				var txn BlockDeclareTxnV0
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x1":
+			case TransactionV1:
 				var txn BlockDeclareTxnV1
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x2":
+			case TransactionV2:
 				var txn BlockDeclareTxnV2
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x3":
+			case TransactionV3:
 				var txn BlockDeclareTxnV3
 				err := remarshal(casted, &txn)
 				return txn, err
 			default:
-				return nil, errors.New("internal error with Declare transaction version and unmarshalTxn()")
+				return nil, fmt.Errorf("internal unmarshalBlockTxn() error, unknown Declare transaction version %s", version)
 			}
 		case TransactionType_Deploy:
 			var txn BlockDeployTxn
 			err := remarshal(casted, &txn)
 			return txn, err
 		case TransactionType_DeployAccount:
-			var txn BlockDeployAccountTxn
-			err := remarshal(casted, &txn)
-			return txn, err
+			switch version {
+			case TransactionV1:
+				var txn BlockDeployAccountTxn
+				err := remarshal(casted, &txn)
+				return txn, err
+			case TransactionV3:
+				var txn BlockDeployAccountTxnV3
+				err := remarshal(casted, &txn)
+				return txn, err
+			default:
+				return nil, fmt.Errorf("internal unmarshalBlockTxn() error, unknown DeployAccount transaction version %s", version)
+			}
 		case TransactionType_Invoke:
-			if casted["version"].(string) == "0x0" {
+			switch version {
+			case TransactionV0:
 				var txn BlockInvokeTxnV0
 				err := remarshal(casted, &txn)
 				return txn, err
-			} else if casted["version"].(string) == "0x1" {
+			case TransactionV1:
 				var txn BlockInvokeTxnV1
 				err := remarshal(casted, &txn)
 				return txn, err
-			} else {
+			case TransactionV3:
 				var txn BlockInvokeTxnV3
 				err := remarshal(casted, &txn)
 				return txn, err
+			default:
+				return nil, fmt.Errorf("internal unmarshalBlockTxn() error, unknown Invoke transaction version %s", version)
 			}
 		case TransactionType_L1Handler:
 			var txn BlockL1HandlerTxn
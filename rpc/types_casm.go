@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// CasmCompiledContractClass is the CASM a Sierra class is compiled to by the node, as returned by GetCompiledCasm.
+type CasmCompiledContractClass struct {
+	EntryPointsByType CasmEntryPointsByType `json:"entry_points_by_type"`
+	Bytecode          []*felt.Felt          `json:"bytecode"`
+	Prime             string                `json:"prime"`
+	CompilerVersion   string                `json:"compiler_version"`
+	// The hints of the bytecode, by offset of the instruction they are run before
+	Hints []CasmHints `json:"hints"`
+	// The lengths of the bytecode segments, nil if the class is compiled to a single segment
+	BytecodeSegmentLengths []int `json:"bytecode_segment_lengths,omitempty"`
+}
+
+// CasmEntryPointsByType are the entry points of a CASM class by type.
+type CasmEntryPointsByType struct {
+	Constructor []CasmEntryPoint `json:"CONSTRUCTOR"`
+	External    []CasmEntryPoint `json:"EXTERNAL"`
+	L1Handler   []CasmEntryPoint `json:"L1_HANDLER"`
+}
+
+// CasmEntryPoint is an entry point of a CASM class.
+type CasmEntryPoint struct {
+	// The selector of the entry point
+	Selector *felt.Felt `json:"selector"`
+	// The offset of the entry point in the bytecode
+	Offset int `json:"offset"`
+	// The builtins used by the entry point
+	Builtins []string `json:"builtins"`
+}
+
+// CasmHints are the hints run before the instruction at an offset of the bytecode, encoded as the
+// [offset, [hint, ...]] pair of the spec.
+type CasmHints struct {
+	// The offset of the instruction in the bytecode
+	PC int
+	// The hints, left encoded as they are free-form Cairo hints
+	Hints []json.RawMessage
+}
+
+// UnmarshalJSON unmarshals the [offset, [hint, ...]] pair into the CasmHints.
+//
+// Parameters:
+// - data: The JSON pair
+// Returns:
+// - error: an error if the data is not a pair of an offset and an array of hints
+func (h *CasmHints) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("invalid casm hints: expected [offset, hints], got %d elements", len(pair))
+	}
+	if err := json.Unmarshal(pair[0], &h.PC); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &h.Hints)
+}
+
+// MarshalJSON marshals the CasmHints into the [offset, [hint, ...]] pair of the spec.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: The JSON pair
+// - error: an error if any
+func (h CasmHints) MarshalJSON() ([]byte, error) {
+	hints := h.Hints
+	if hints == nil {
+		hints = []json.RawMessage{}
+	}
+	return json.Marshal([]interface{}{h.PC, hints})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,34 @@ import (
 // An integer number in hex format (0x...)
 type NumAsHex string
 
+// UnmarshalJSON decodes a NumAsHex sent either as a hex string or, as some nodes send the numeric fields, as a
+// JSON integer, converted to hex. The values are not bounded by the felt modulus, as the L1 message hashes
+// sent as NumAsHex are 256-bit hashes.
+//
+// Parameters:
+// - data: the JSON value
+// Returns:
+// - error: an error if a JSON number is not a non-negative integer
+func (n *NumAsHex) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*n = NumAsHex(s)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	value, ok := new(big.Int).SetString(string(data), 10)
+	if !ok || value.Sign() < 0 {
+		return fmt.Errorf("invalid NumAsHex %s, expected a hex string or a non-negative integer", data)
+	}
+	*n = NumAsHex("0x" + value.Text(16))
+	return nil
+}
+
 // 64 bit integers, represented by hex string of length at most 16
 type U64 string
 
@@ -84,8 +113,7 @@ type ContractClass struct {
 // The value is unmarshaled into the `DeprecatedEntryPointsByType` field in the struct.
 // Finally, the function processes the `abi` field in the JSON object.
 // If it is missing, the function returns nil.
-// Otherwise, it unmarshals the value into a slice of interfaces.
-// For each element in the slice, it checks the type and assigns it to the appropriate field in the `ABI` field in the struct.
+// Otherwise, it unmarshals the value into the `ABI` field in the struct (see ABI.UnmarshalJSON).
 //
 // Parameters:
 // - content: byte array
@@ -129,14 +157,31 @@ func (c *DeprecatedContractClass) UnmarshalJSON(content []byte) error {
 		return nil
 	}
 
+	abi := ABI{}
+	if err := json.Unmarshal(data, &abi); err != nil {
+		return err
+	}
+
+	c.ABI = &abi
+	return nil
+}
+
+// UnmarshalJSON unmarshals the JSON content into the ABI, decoding each entry into the type matching its 'type' field.
+// Cairo 1 classes hold their ABI as a JSON string, which can be decoded with json.Unmarshal([]byte(class.ABI), &abi).
+//
+// Parameters:
+// - content: byte array
+// Returns:
+// - error: error if there is any
+func (abi *ABI) UnmarshalJSON(content []byte) error {
 	abis := []interface{}{}
-	if err := json.Unmarshal(data, &abis); err != nil {
+	if err := json.Unmarshal(content, &abis); err != nil {
 		return err
 	}
 
 	abiPointer := ABI{}
-	for _, abi := range abis {
-		if checkABI, ok := abi.(map[string]interface{}); ok {
+	for _, entry := range abis {
+		if checkABI, ok := entry.(map[string]interface{}); ok {
 			var ab ABIEntry
 			abiType, ok := checkABI["type"].(string)
 			if !ok {
@@ -149,6 +194,12 @@ func (c *DeprecatedContractClass) UnmarshalJSON(content []byte) error {
 				ab = &StructABIEntry{}
 			case string(ABITypeEvent):
 				ab = &EventABIEntry{}
+			case string(ABITypeEnum):
+				ab = &EnumABIEntry{}
+			case string(ABITypeInterface):
+				ab = &InterfaceABIEntry{}
+			case string(ABITypeImpl):
+				ab = &ImplABIEntry{}
 			default:
 				return fmt.Errorf("unknown ABI type %v", checkABI["type"])
 			}
@@ -164,10 +215,70 @@ func (c *DeprecatedContractClass) UnmarshalJSON(content []byte) error {
 		}
 	}
 
-	c.ABI = &abiPointer
+	*abi = abiPointer
 	return nil
 }
 
+// Function returns the function, constructor or L1 handler with the given name, whether it is
+// declared at the top level of the ABI or inside one of its interfaces.
+//
+// Parameters:
+// - name: the name of the function
+// Returns:
+// - *FunctionABIEntry: the function entry
+// - bool: false if the ABI has no function with this name
+func (abi ABI) Function(name string) (*FunctionABIEntry, bool) {
+	for _, entry := range abi {
+		switch entry := entry.(type) {
+		case *FunctionABIEntry:
+			if entry.Name == name {
+				return entry, true
+			}
+		case *InterfaceABIEntry:
+			if function, ok := entry.Function(name); ok {
+				return function, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Interfaces returns the interfaces declared in the ABI, which group the functions of Cairo 2 contracts.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*InterfaceABIEntry: the interface entries, in the order of the ABI
+func (abi ABI) Interfaces() []*InterfaceABIEntry {
+	var interfaces []*InterfaceABIEntry
+	for _, entry := range abi {
+		if entry, ok := entry.(*InterfaceABIEntry); ok {
+			interfaces = append(interfaces, entry)
+		}
+	}
+	return interfaces
+}
+
+// Impls returns the impls declared in the ABI, each naming the interface it implements.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*ImplABIEntry: the impl entries, in the order of the ABI
+func (abi ABI) Impls() []*ImplABIEntry {
+	var impls []*ImplABIEntry
+	for _, entry := range abi {
+		if entry, ok := entry.(*ImplABIEntry); ok {
+			impls = append(impls, entry)
+		}
+	}
+	return impls
+}
+
 type SierraEntryPoint struct {
 	// The index of the function in the program
 	FunctionIdx int `json:"function_idx"`
@@ -193,6 +304,9 @@ const (
 	ABITypeL1Handler   ABIType = "l1_handler"
 	ABITypeEvent       ABIType = "event"
 	ABITypeStruct      ABIType = "struct"
+	ABITypeEnum        ABIType = "enum"
+	ABITypeInterface   ABIType = "interface"
+	ABITypeImpl        ABIType = "impl"
 )
 
 type StructABIEntry struct {
@@ -223,6 +337,23 @@ type EventABIEntry struct {
 	Keys []TypedParameter `json:"keys"`
 
 	Data []TypedParameter `json:"data"`
+
+	// The kind of a Cairo 1 event, "struct" or "enum"
+	Kind string `json:"kind,omitempty"`
+
+	// The members of a Cairo 1 struct event
+	Members []EventMember `json:"members,omitempty"`
+
+	// The variants of a Cairo 1 enum event, of kind "nested" or "flat"
+	Variants []EventMember `json:"variants,omitempty"`
+}
+
+// EventMember is a member of a Cairo 1 struct event, or a variant of a Cairo 1 enum event.
+type EventMember struct {
+	TypedParameter
+
+	// Where the member is emitted, "key" or "data", or how the variant is emitted, "nested" or "flat"
+	Kind string `json:"kind"`
 }
 
 type FunctionStateMutability string
@@ -245,6 +376,38 @@ type FunctionABIEntry struct {
 	Outputs []TypedParameter `json:"outputs"`
 }
 
+type EnumABIEntry struct {
+	// The enum type
+	Type ABIType `json:"type"`
+
+	// The enum name
+	Name string `json:"name"`
+
+	Variants []TypedParameter `json:"variants"`
+}
+
+type InterfaceABIEntry struct {
+	// The interface type
+	Type ABIType `json:"type"`
+
+	// The interface name
+	Name string `json:"name"`
+
+	// The functions of the interface
+	Items []*FunctionABIEntry `json:"items"`
+}
+
+type ImplABIEntry struct {
+	// The impl type
+	Type ABIType `json:"type"`
+
+	// The impl name
+	Name string `json:"name"`
+
+	// The name of the implemented interface
+	InterfaceName string `json:"interface_name"`
+}
+
 // IsType returns the ABIType of the StructABIEntry.
 //
 // Parameters:
@@ -281,6 +444,58 @@ func (f *FunctionABIEntry) IsType() ABIType {
 	return f.Type
 }
 
+// IsType returns the ABIType of the EnumABIEntry.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - ABIType: the ABIType
+func (e *EnumABIEntry) IsType() ABIType {
+	return e.Type
+}
+
+// IsType returns the ABIType of the InterfaceABIEntry.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - ABIType: the ABIType
+func (i *InterfaceABIEntry) IsType() ABIType {
+	return i.Type
+}
+
+// Function returns the function of the interface with the given name.
+//
+// Parameters:
+// - name: the name of the function
+// Returns:
+// - *FunctionABIEntry: the function entry
+// - bool: false if the interface has no function with this name
+func (i *InterfaceABIEntry) Function(name string) (*FunctionABIEntry, bool) {
+	for _, function := range i.Items {
+		if function.Name == name {
+			return function, true
+		}
+	}
+	return nil, false
+}
+
+// IsType returns the ABIType of the ImplABIEntry.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - ABIType: the ABIType
+func (i *ImplABIEntry) IsType() ABIType {
+	return i.Type
+}
+
 type TypedParameter struct {
 	// The parameter's name
 	Name string `json:"name"`
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -59,3 +61,53 @@ func TestContractClass_UnmarshalValidJSON_Successful(t *testing.T) {
 		t.Fatal("should be able unmarshall Class", err)
 	}
 }
+
+// TestABI_InterfaceFunctions tests that the functions grouped under the interfaces of a Cairo 2 ABI
+// are found by ABI.Function, and that the interfaces and impls are exposed.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestABI_InterfaceFunctions(t *testing.T) {
+	content, err := os.ReadFile(validContractCompiledPath)
+	require.NoError(t, err)
+
+	contractClass := ContractClass{}
+	require.NoError(t, json.Unmarshal(content, &contractClass))
+
+	var abi ABI
+	require.NoError(t, json.Unmarshal([]byte(contractClass.ABI), &abi))
+
+	// declared inside the IOracle interface
+	function, ok := abi.Function("get_seconds_per_liquidity_inside")
+	require.True(t, ok)
+	require.Equal(t, ABITypeFunction, function.Type)
+	require.Equal(t, "pool_key", function.Inputs[0].Name)
+
+	// declared inside the IExtension interface
+	_, ok = abi.Function("before_initialize_pool")
+	require.True(t, ok)
+
+	// top-level constructor
+	constructor, ok := abi.Function("constructor")
+	require.True(t, ok)
+	require.Equal(t, ABITypeConstructor, constructor.Type)
+
+	_, ok = abi.Function("not_a_function")
+	require.False(t, ok)
+
+	interfaces := abi.Interfaces()
+	require.Len(t, interfaces, 2)
+	require.Equal(t, "ekubo_oracle_extension::oracle::IOracle", interfaces[0].Name)
+	_, ok = interfaces[0].Function("get_seconds_per_liquidity_inside")
+	require.True(t, ok)
+	_, ok = interfaces[0].Function("before_initialize_pool")
+	require.False(t, ok)
+
+	impls := abi.Impls()
+	require.Len(t, impls, 2)
+	require.Equal(t, "OracleImpl", impls[0].Name)
+	require.Equal(t, interfaces[0].Name, impls[0].InterfaceName)
+}
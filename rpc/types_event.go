@@ -1,6 +1,12 @@
 package rpc
 
-import "github.com/NethermindEth/juno/core/felt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
 
 type OrderedEvent struct {
 	// The order of the event within the transaction
@@ -8,6 +14,38 @@ type OrderedEvent struct {
 	Event Event
 }
 
+// orderedEventJSON is the JSON encoding of an ordered event: its order and the keys and data of the event.
+type orderedEventJSON struct {
+	Order int          `json:"order"`
+	Keys  []*felt.Felt `json:"keys"`
+	Data  []*felt.Felt `json:"data"`
+}
+
+// UnmarshalJSON decodes an ordered event, whose keys and data are at the same level as its order.
+//
+// Parameters:
+// - data: the JSON data to unmarshal
+// Returns:
+// - error: an error if the unmarshaling fails
+func (event *OrderedEvent) UnmarshalJSON(data []byte) error {
+	var aux orderedEventJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*event = OrderedEvent{Order: aux.Order, Event: Event{Keys: aux.Keys, Data: aux.Data}}
+	return nil
+}
+
+// MarshalJSON encodes an ordered event as its order and the keys and data of the event.
+// The emitting contract is the contract of the invocation holding the event, so it is not encoded.
+//
+// Returns:
+// - []byte: the JSON encoding of the ordered event
+// - error: an error if the marshaling fails
+func (event OrderedEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(orderedEventJSON{Order: event.Order, Keys: event.Event.Keys, Data: event.Event.Data})
+}
+
 type Event struct {
 	FromAddress *felt.Felt   `json:"from_address"`
 	Keys        []*felt.Felt `json:"keys"`
@@ -37,11 +75,69 @@ type EventFilter struct {
 	ToBlock BlockID `json:"to_block,omitempty"`
 	// Address from contract
 	Address *felt.Felt `json:"address,omitempty"`
-	// Keys the values used to filter the events
+	// Addresses the contracts the events are emitted from, to match the events of several contracts instead of
+	// Address. The node filters a single address only, so the events of several addresses are requested without
+	// address and filtered by Events, whose pages may then hold fewer events than the chunk size
+	Addresses []*felt.Felt `json:"-"`
+	// Keys the values used to filter the events: Keys[i] lists the accepted values of the i-th key of an event,
+	// any of them matching, an empty list matching any value
 	Keys [][]*felt.Felt `json:"keys,omitempty"`
 }
 
+// MaxEventsChunkSize is the largest chunk size accepted by Events, the usual page size limit of the nodes.
+const MaxEventsChunkSize = 1024
+
+var ErrInvalidEventsInput = errors.New("invalid events input")
+
 type EventsInput struct {
 	EventFilter
 	ResultPageRequest
 }
+
+// Validate checks that the block range of the input is coherent, that its addresses are given either with
+// Address or with Addresses, and that its chunk size does not exceed MaxEventsChunkSize.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: ErrInvalidEventsInput wrapping the first problem found, nil otherwise
+func (input EventsInput) Validate() error {
+	from, to := input.FromBlock, input.ToBlock
+	if from.Number != nil && to.Number != nil && *to.Number < *from.Number {
+		return fmt.Errorf("%w: to_block %d before from_block %d", ErrInvalidEventsInput, *to.Number, *from.Number)
+	}
+	// the pending and pre-confirmed blocks are ahead of the other blocks
+	if isHeadTag(BlockTag(from.Tag)) && to != (BlockID{}) && !isHeadTag(BlockTag(to.Tag)) {
+		return fmt.Errorf("%w: to_block before the %s from_block", ErrInvalidEventsInput, from.Tag)
+	}
+	if input.Address != nil && len(input.Addresses) > 0 {
+		return fmt.Errorf("%w: both address and addresses are set", ErrInvalidEventsInput)
+	}
+	if input.ChunkSize > MaxEventsChunkSize {
+		return fmt.Errorf("%w: chunk_size %d above %d", ErrInvalidEventsInput, input.ChunkSize, MaxEventsChunkSize)
+	}
+	return nil
+}
+
+// isHeadTag reports whether the tag references a block ahead of the latest accepted block.
+func isHeadTag(tag BlockTag) bool {
+	return tag == BlockTagPending || tag == BlockTagPreConfirmed
+}
+
+// matchesAddresses reports whether the event is emitted by one of the addresses.
+//
+// Parameters:
+// - event: the event
+// - addresses: the addresses of the contracts
+// Returns:
+// - bool: true if the event is emitted by one of the addresses
+func matchesAddresses(event EmittedEvent, addresses []*felt.Felt) bool {
+	for _, address := range addresses {
+		if event.FromAddress != nil && event.FromAddress.Equal(address) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// StorageProofInput is the input of GetStorageProof: the block of the proofs and the classes, contracts and
+// storage keys to prove.
+type StorageProofInput struct {
+	// The block of the state the proofs are given against
+	BlockID BlockID `json:"block_id"`
+	// The classes to prove the membership of in the classes tree, optional
+	ClassHashes []*felt.Felt `json:"class_hashes,omitempty"`
+	// The contracts to prove the membership of in the contracts tree, optional
+	ContractAddresses []*felt.Felt `json:"contract_addresses,omitempty"`
+	// The storage keys to prove the values of in the storage tree of each contract, optional
+	ContractsStorageKeys []ContractStorageKeys `json:"contracts_storage_keys,omitempty"`
+}
+
+// ContractStorageKeys are storage keys of a contract.
+type ContractStorageKeys struct {
+	ContractAddress *felt.Felt   `json:"contract_address"`
+	StorageKeys     []*felt.Felt `json:"storage_keys"`
+}
+
+// StorageProofResult is the result of GetStorageProof: the Merkle proofs of the requested classes, contracts
+// and storage keys, and the roots of the trees they are given against.
+type StorageProofResult struct {
+	ClassesProof   []NodeHashToNode `json:"classes_proof"`
+	ContractsProof ContractsProof   `json:"contracts_proof"`
+	// The proofs of the storage keys, one per contract of StorageProofInput.ContractsStorageKeys, in order
+	ContractsStorageProofs [][]NodeHashToNode `json:"contracts_storage_proofs"`
+	GlobalRoots            GlobalRoots        `json:"global_roots"`
+}
+
+// ContractsProof is the proof of the contracts in the contracts tree, along with the leaves of the contracts.
+type ContractsProof struct {
+	// The nodes of the union of the paths from the contracts tree root to the requested leaves
+	Nodes []NodeHashToNode `json:"nodes"`
+	// The data of the leaves of the requested contracts, in the order of StorageProofInput.ContractAddresses
+	ContractLeavesData []ContractLeafData `json:"contract_leaves_data"`
+}
+
+// ContractLeafData is the data of the leaf of a contract in the contracts tree.
+type ContractLeafData struct {
+	Nonce     *felt.Felt `json:"nonce"`
+	ClassHash *felt.Felt `json:"class_hash"`
+	// The root of the storage tree of the contract, nil if not sent by the node
+	StorageRoot *felt.Felt `json:"storage_root,omitempty"`
+}
+
+// GlobalRoots are the roots of the state trees of the block the proofs are given against.
+type GlobalRoots struct {
+	ContractsTreeRoot *felt.Felt `json:"contracts_tree_root"`
+	ClassesTreeRoot   *felt.Felt `json:"classes_tree_root"`
+	// The hash of the block whose state the roots belong to
+	BlockHash *felt.Felt `json:"block_hash"`
+}
+
+// MerkleNode is a node of a Merkle-Patricia tree: a *BinaryNode or an *EdgeNode.
+type MerkleNode interface {
+	merkleNode()
+}
+
+// BinaryNode is an internal node of a Merkle-Patricia tree with two children.
+type BinaryNode struct {
+	// The hash of the left child
+	Left *felt.Felt `json:"left"`
+	// The hash of the right child
+	Right *felt.Felt `json:"right"`
+}
+
+// EdgeNode is a node of a Merkle-Patricia tree compressing a path of nodes with a single child.
+type EdgeNode struct {
+	// The path to the child, an integer whose binary representation is the path from the node
+	Path *felt.Felt `json:"path"`
+	// The length of the path, in bits
+	Length int `json:"length"`
+	// The hash of the child
+	Child *felt.Felt `json:"child"`
+}
+
+func (*BinaryNode) merkleNode() {}
+func (*EdgeNode) merkleNode()   {}
+
+// NodeHashToNode is a node of a proof along with its hash.
+type NodeHashToNode struct {
+	NodeHash *felt.Felt `json:"node_hash"`
+	Node     MerkleNode `json:"node"`
+}
+
+// UnmarshalJSON decodes a node of a proof, the node being a *BinaryNode or an *EdgeNode depending on its fields.
+//
+// Parameters:
+// - data: the JSON data to unmarshal
+// Returns:
+// - error: an error if the unmarshaling fails or the node is neither a binary nor an edge node
+func (n *NodeHashToNode) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		NodeHash *felt.Felt                 `json:"node_hash"`
+		Node     map[string]json.RawMessage `json:"node"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	node, err := json.Marshal(aux.Node)
+	if err != nil {
+		return err
+	}
+
+	var merkleNode MerkleNode
+	_, hasLeft := aux.Node["left"]
+	_, hasPath := aux.Node["path"]
+	switch {
+	case hasLeft && !hasPath:
+		merkleNode = &BinaryNode{}
+	case hasPath && !hasLeft:
+		merkleNode = &EdgeNode{}
+	default:
+		return fmt.Errorf("unknown merkle node %s", node)
+	}
+	if err := json.Unmarshal(node, merkleNode); err != nil {
+		return err
+	}
+	*n = NodeHashToNode{NodeHash: aux.NodeHash, Node: merkleNode}
+	return nil
+}
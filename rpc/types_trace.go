@@ -1,6 +1,16 @@
 package rpc
 
-import "github.com/NethermindEth/juno/core/felt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
 
 type SimulateTransactionInput struct {
 	//a sequence of transactions to simulate, running each transaction on the state resulting from applying all the previous ones
@@ -11,13 +21,41 @@ type SimulateTransactionInput struct {
 
 type SimulationFlag string
 
+// Flags that indicate how to simulate a given transaction. By default, the sequencer behavior is replicated locally
 const (
-	SKIP_FEE_CHARGE SimulationFlag = "SKIP_FEE_CHARGE"
-	SKIP_EXECUTE    SimulationFlag = "SKIP_EXECUTE"
-	// Flags that indicate how to simulate a given transaction. By default, the sequencer behavior is replicated locally
-	SKIP_VALIDATE SimulationFlag = "SKIP_VALIDATE"
+	SimulationFlagSkipFeeCharge SimulationFlag = "SKIP_FEE_CHARGE"
+	SimulationFlagSkipValidate  SimulationFlag = "SKIP_VALIDATE"
+
+	// Deprecated: use SimulationFlagSkipFeeCharge instead
+	SKIP_FEE_CHARGE = SimulationFlagSkipFeeCharge
+	// Deprecated: use SimulationFlagSkipValidate instead
+	SKIP_VALIDATE = SimulationFlagSkipValidate
+	// Deprecated: SKIP_EXECUTE is not a simulation flag of the spec, and is rejected by SimulateTransactions
+	SKIP_EXECUTE SimulationFlag = "SKIP_EXECUTE"
 )
 
+var (
+	ErrInvalidSimulationFlag = errors.New("invalid simulation flag")
+	ErrUnknownTraceType      = errors.New("unknown trace type")
+)
+
+// validateSimulationFlags checks that every flag is one of the flags defined by the spec.
+//
+// Parameters:
+// - flags: the simulation flags to validate
+// Returns:
+// - error: ErrInvalidSimulationFlag wrapping the first unknown flag, nil otherwise
+func validateSimulationFlags(flags []SimulationFlag) error {
+	for _, flag := range flags {
+		switch flag {
+		case SimulationFlagSkipFeeCharge, SimulationFlagSkipValidate:
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidSimulationFlag, flag)
+		}
+	}
+	return nil
+}
+
 // The execution trace and consumed resources of the required transactions
 type SimulateTransactionOutput struct {
 	Txns []SimulatedTransaction `json:"result"`
@@ -26,6 +64,8 @@ type SimulateTransactionOutput struct {
 type SimulatedTransaction struct {
 	TxnTrace `json:"transaction_trace"`
 	FeeEstimate
+	// The state diff of the simulated transaction, taken from its trace when decoding it, nil if the node did not send it
+	StateDiff *StateDiff `json:"-"`
 }
 
 type TxnTrace interface{}
@@ -39,39 +79,167 @@ var _ TxnTrace = L1HandlerTxnTrace{}
 type InvokeTxnTrace struct {
 	ValidateInvocation FnInvocation `json:"validate_invocation"`
 	//the trace of the __execute__ call or constructor call, depending on the transaction type (none for declare transactions)
-	ExecuteInvocation     ExecInvocation     `json:"execute_invocation"`
-	FeeTransferInvocation FnInvocation       `json:"fee_transfer_invocation"`
-	StateDiff             StateDiff          `json:"state_diff"`
-	Type                  TransactionType    `json:"type"`
-	ExecutionResources    ExecutionResources `json:"execution_resources"`
+	ExecuteInvocation     ExecInvocation `json:"execute_invocation"`
+	FeeTransferInvocation FnInvocation   `json:"fee_transfer_invocation"`
+	// The state diff of the transaction, nil if the node did not send it
+	StateDiff          *StateDiff         `json:"state_diff,omitempty"`
+	Type               TransactionType    `json:"type"`
+	ExecutionResources ExecutionResources `json:"execution_resources"`
+	// Revert is set when the __execute__ call reverted, it is derived from ExecuteInvocation when decoding the trace
+	Revert *ExecutionRevert `json:"-"`
+}
+
+// UnmarshalJSON decodes an invoke transaction trace and populates its Revert field.
+//
+// Parameters:
+// - data: the JSON data to unmarshal
+// Returns:
+// - error: an error if the unmarshaling fails
+func (trace *InvokeTxnTrace) UnmarshalJSON(data []byte) error {
+	type alias InvokeTxnTrace
+	var aux alias
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*trace = InvokeTxnTrace(aux)
+	trace.Revert = parseExecutionRevert(trace.ExecuteInvocation.RevertReason)
+	return nil
+}
+
+// MarshalJSON encodes an invoke transaction trace with the fields in the order of the spec, so that the
+// trace sent by a node is encoded back as it was received. The optional invocations and execution resources
+// are omitted when they are empty, as the node omits them (e.g. the validate invocation of a simulation
+// skipping the validation), and the state diff when it is nil.
+//
+// Returns:
+// - []byte: the JSON encoding of the trace
+// - error: an error if the marshaling fails
+func (trace InvokeTxnTrace) MarshalJSON() ([]byte, error) {
+	var resources *ExecutionResources
+	if !reflect.ValueOf(trace.ExecutionResources).IsZero() {
+		resources = &trace.ExecutionResources
+	}
+	return json.Marshal(struct {
+		Type                  TransactionType     `json:"type"`
+		ValidateInvocation    *FnInvocation       `json:"validate_invocation,omitempty"`
+		ExecuteInvocation     ExecInvocation      `json:"execute_invocation"`
+		FeeTransferInvocation *FnInvocation       `json:"fee_transfer_invocation,omitempty"`
+		StateDiff             *StateDiff          `json:"state_diff,omitempty"`
+		ExecutionResources    *ExecutionResources `json:"execution_resources,omitempty"`
+	}{
+		Type:                  trace.Type,
+		ValidateInvocation:    optionalInvocation(trace.ValidateInvocation),
+		ExecuteInvocation:     trace.ExecuteInvocation,
+		FeeTransferInvocation: optionalInvocation(trace.FeeTransferInvocation),
+		StateDiff:             trace.StateDiff,
+		ExecutionResources:    resources,
+	})
+}
+
+// optionalInvocation returns nil for an empty invocation, i.e. absent from the trace, and the invocation otherwise.
+func optionalInvocation(invocation FnInvocation) *FnInvocation {
+	if reflect.ValueOf(invocation).IsZero() {
+		return nil
+	}
+	return &invocation
+}
+
+// RevertReason returns the revert reason of the __execute__ call.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the revert reason
+// - bool: true if the execution reverted, false otherwise
+func (trace InvokeTxnTrace) RevertReason() (string, bool) {
+	return trace.ExecuteInvocation.RevertReason, trace.ExecuteInvocation.RevertReason != ""
 }
 
 // the execution trace of a declare transaction
 type DeclareTxnTrace struct {
-	ValidateInvocation    FnInvocation       `json:"validate_invocation"`
-	FeeTransferInvocation FnInvocation       `json:"fee_transfer_invocation"`
-	StateDiff             StateDiff          `json:"state_diff"`
-	Type                  TransactionType    `json:"type"`
-	ExecutionResources    ExecutionResources `json:"execution_resources"`
+	ValidateInvocation    FnInvocation `json:"validate_invocation"`
+	FeeTransferInvocation FnInvocation `json:"fee_transfer_invocation"`
+	// The state diff of the transaction, nil if the node did not send it
+	StateDiff          *StateDiff         `json:"state_diff,omitempty"`
+	Type               TransactionType    `json:"type"`
+	ExecutionResources ExecutionResources `json:"execution_resources"`
+}
+
+// RevertReason always reports a successful execution, declare transactions cannot be included as reverted.
+func (trace DeclareTxnTrace) RevertReason() (string, bool) {
+	return "", false
 }
 
 // the execution trace of a deploy account transaction
 type DeployAccountTxnTrace struct {
 	ValidateInvocation FnInvocation `json:"validate_invocation"`
 	//the trace of the __execute__ call or constructor call, depending on the transaction type (none for declare transactions)
-	ConstructorInvocation FnInvocation       `json:"constructor_invocation"`
-	FeeTransferInvocation FnInvocation       `json:"fee_transfer_invocation"`
-	StateDiff             StateDiff          `json:"state_diff"`
-	Type                  TransactionType    `json:"type"`
-	ExecutionResources    ExecutionResources `json:"execution_resources"`
+	ConstructorInvocation FnInvocation `json:"constructor_invocation"`
+	FeeTransferInvocation FnInvocation `json:"fee_transfer_invocation"`
+	// The state diff of the transaction, nil if the node did not send it
+	StateDiff          *StateDiff         `json:"state_diff,omitempty"`
+	Type               TransactionType    `json:"type"`
+	ExecutionResources ExecutionResources `json:"execution_resources"`
+}
+
+// RevertReason always reports a successful execution, deploy account transactions cannot be included as reverted.
+func (trace DeployAccountTxnTrace) RevertReason() (string, bool) {
+	return "", false
 }
 
 // the execution trace of an L1 handler transaction
 type L1HandlerTxnTrace struct {
 	//the trace of the __execute__ call or constructor call, depending on the transaction type (none for declare transactions)
-	FunctionInvocation FnInvocation    `json:"function_invocation"`
-	StateDiff          StateDiff       `json:"state_diff"`
-	Type               TransactionType `json:"type"`
+	FunctionInvocation FnInvocation `json:"function_invocation"`
+	// The state diff of the transaction, nil if the node did not send it
+	StateDiff *StateDiff      `json:"state_diff,omitempty"`
+	Type      TransactionType `json:"type"`
+}
+
+// RevertReason returns the failure reason of the l1_handler call, which reverted if its invocation is marked
+// with is_reverted, as sent from RPC 0.8 on.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the failure reason, empty if the node did not send one
+// - bool: true if the l1_handler call reverted, false otherwise
+func (trace L1HandlerTxnTrace) RevertReason() (string, bool) {
+	return trace.FunctionInvocation.FailureReason, trace.FunctionInvocation.IsReverted
+}
+
+// ExecutionRevert is the revert reason of a reverted execution
+type ExecutionRevert struct {
+	Reason string
+	// The Cairo error selector found at the start of the revert reason (e.g. "0x4661696c6564 ('Failed')"), nil if absent
+	ErrorSelector *felt.Felt
+}
+
+// parseExecutionRevert parses a revert reason, extracting the leading Cairo error selector if any.
+//
+// Parameters:
+// - reason: the revert reason returned by the node
+// Returns:
+// - *ExecutionRevert: the parsed revert, nil if the reason is empty
+func parseExecutionRevert(reason string) *ExecutionRevert {
+	if reason == "" {
+		return nil
+	}
+	revert := &ExecutionRevert{Reason: reason}
+	token := strings.TrimSpace(reason)
+	if end := strings.IndexAny(token, " \t\n:,.()'"); end >= 0 {
+		token = token[:end]
+	}
+	if strings.HasPrefix(token, "0x") && len(token) > 2 {
+		if selector, err := new(felt.Felt).SetString(token); err == nil {
+			revert.ErrorSelector = selector
+		}
+	}
+	return revert
 }
 
 type EntryPointType string
@@ -118,6 +286,13 @@ type FnInvocation struct {
 	// Resources consumed by the internal call
 	// https://github.com/starkware-libs/starknet-specs/blob/v0.7.0-rc0/api/starknet_trace_api_openrpc.json#L374C1-L374C29
 	ComputationResources ComputationResources `json:"execution_resources"`
+
+	// Whether the invocation reverted, sent from RPC 0.8 on. The revert of an inner call may be caught by its
+	// caller, which then does not revert
+	IsReverted bool `json:"is_reverted,omitempty"`
+
+	// The reason of the failure of the invocation, sent by some nodes along is_reverted
+	FailureReason string `json:"failure_reason,omitempty"`
 }
 
 // A single pair of transaction hash and corresponding trace
@@ -126,7 +301,600 @@ type Trace struct {
 	TxnHash   *felt.Felt `json:"transaction_hash,omitempty"`
 }
 
+// UnmarshalJSON decodes the trace root into the trace type of its transaction type, as TraceTransaction does,
+// so that its felts are decoded as *felt.Felt whatever their formatting by the node. The trace root of an
+// unknown transaction type is kept as the JSON object sent by the node, a map[string]any, so that a single
+// trace does not fail the block.
+//
+// Parameters:
+// - data: the JSON trace
+// Returns:
+// - error: an error if the trace cannot be decoded
+func (trace *Trace) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TraceRoot json.RawMessage `json:"trace_root"`
+		TxnHash   *felt.Felt      `json:"transaction_hash"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	root, err := decodeTraceRoot(raw.TraceRoot)
+	if err != nil {
+		return err
+	}
+	*trace = Trace{TraceRoot: root, TxnHash: raw.TxnHash}
+	return nil
+}
+
+// decodeTraceRoot decodes the trace root of a block trace with decodeOptionalTxnTrace, the trace root of an
+// unknown transaction type being decoded as a map[string]any.
+//
+// Parameters:
+// - rawTrace: the JSON trace root
+// Returns:
+// - TxnTrace: the typed trace root, or the JSON object of an unknown transaction type
+// - error: an error if the trace root cannot be decoded
+func decodeTraceRoot(rawTrace json.RawMessage) (TxnTrace, error) {
+	root, err := decodeOptionalTxnTrace(rawTrace)
+	if err == nil {
+		return root, nil
+	}
+	// the type is read as a string, TransactionType rejecting the unknown types
+	var header struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(rawTrace, &header) != nil {
+		return nil, err
+	}
+	switch TransactionType(header.Type) {
+	case TransactionType_Invoke, TransactionType_Declare, TransactionType_DeployAccount, TransactionType_L1Handler:
+		return nil, err
+	}
+	var fallback map[string]any
+	if json.Unmarshal(rawTrace, &fallback) != nil {
+		return nil, err
+	}
+	return fallback, nil
+}
+
+// Type returns the type of the transaction of the trace root.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - TransactionType: the type of the transaction, empty if the trace root is nil
+func (trace Trace) Type() TransactionType {
+	return traceTransactionType(trace.TraceRoot)
+}
+
+// InvokeTrace returns the trace root of an invoke transaction, as TransactionTrace.AsInvoke does.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - InvokeTxnTrace: the trace root
+// - bool: false if the trace root is not the trace of an invoke transaction
+func (trace Trace) InvokeTrace() (InvokeTxnTrace, bool) {
+	if root, ok := trace.TraceRoot.(TransactionTrace); ok {
+		if typed, ok := root.AsInvoke(); ok {
+			return *typed, true
+		}
+	}
+	return InvokeTxnTrace{}, false
+}
+
+// DeclareTrace returns the trace root of a declare transaction, as TransactionTrace.AsDeclare does.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - DeclareTxnTrace: the trace root
+// - bool: false if the trace root is not the trace of a declare transaction
+func (trace Trace) DeclareTrace() (DeclareTxnTrace, bool) {
+	if root, ok := trace.TraceRoot.(TransactionTrace); ok {
+		if typed, ok := root.AsDeclare(); ok {
+			return *typed, true
+		}
+	}
+	return DeclareTxnTrace{}, false
+}
+
+// DeployAccountTrace returns the trace root of a deploy account transaction, as TransactionTrace.AsDeployAccount
+// does.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - DeployAccountTxnTrace: the trace root
+// - bool: false if the trace root is not the trace of a deploy account transaction
+func (trace Trace) DeployAccountTrace() (DeployAccountTxnTrace, bool) {
+	if root, ok := trace.TraceRoot.(TransactionTrace); ok {
+		if typed, ok := root.AsDeployAccount(); ok {
+			return *typed, true
+		}
+	}
+	return DeployAccountTxnTrace{}, false
+}
+
+// L1HandlerTrace returns the trace root of an L1 handler transaction, as TransactionTrace.AsL1Handler does.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - L1HandlerTxnTrace: the trace root
+// - bool: false if the trace root is not the trace of an L1 handler transaction
+func (trace Trace) L1HandlerTrace() (L1HandlerTxnTrace, bool) {
+	if root, ok := trace.TraceRoot.(TransactionTrace); ok {
+		if typed, ok := root.AsL1Handler(); ok {
+			return *typed, true
+		}
+	}
+	return L1HandlerTxnTrace{}, false
+}
+
+// UnmarshalJSON decodes the transaction trace into the trace type of its transaction type, along the fee
+// estimate of the simulated transaction.
+//
+// Parameters:
+// - data: the JSON simulated transaction
+// Returns:
+// - error: an error if the simulated transaction cannot be decoded
+func (txn *SimulatedTransaction) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TxnTrace json.RawMessage `json:"transaction_trace"`
+		FeeEstimate
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	trace, err := decodeOptionalTxnTrace(raw.TxnTrace)
+	if err != nil {
+		return err
+	}
+	*txn = SimulatedTransaction{TxnTrace: trace, FeeEstimate: raw.FeeEstimate, StateDiff: traceStateDiff(trace)}
+	return nil
+}
+
+// decodeOptionalTxnTrace decodes a transaction trace with decodeTxnTrace, a missing or null trace being nil.
+//
+// Parameters:
+// - rawTrace: the JSON trace
+// Returns:
+// - TxnTrace: the typed trace, nil if there is no trace
+// - error: an error if the trace cannot be decoded
+func decodeOptionalTxnTrace(rawTrace json.RawMessage) (TxnTrace, error) {
+	if len(rawTrace) == 0 || string(rawTrace) == "null" {
+		return nil, nil
+	}
+	return decodeTxnTrace(rawTrace, "")
+}
+
+// ExecInvocation is the execute invocation of an invoke transaction: the function invocation of the
+// __execute__ call, or its revert reason if it reverted.
 type ExecInvocation struct {
 	FunctionInvocation FnInvocation `json:"function_invocation,omitempty"`
 	RevertReason       string       `json:"revert_reason,omitempty"`
 }
+
+// UnmarshalJSON decodes the execute invocation, which the nodes send either as a function invocation
+// or as an object holding the revert reason.
+//
+// Parameters:
+// - data: the JSON data to unmarshal
+// Returns:
+// - error: an error if the unmarshaling fails
+func (exec *ExecInvocation) UnmarshalJSON(data []byte) error {
+	var revert struct {
+		RevertReason *string `json:"revert_reason"`
+	}
+	if err := json.Unmarshal(data, &revert); err != nil {
+		return err
+	}
+	if revert.RevertReason != nil {
+		*exec = ExecInvocation{RevertReason: *revert.RevertReason}
+		return nil
+	}
+	*exec = ExecInvocation{}
+	return json.Unmarshal(data, &exec.FunctionInvocation)
+}
+
+// MarshalJSON encodes the execute invocation as the revert reason if it reverted, or as the function invocation.
+//
+// Returns:
+// - []byte: the JSON encoding of the execute invocation
+// - error: an error if the marshaling fails
+func (exec ExecInvocation) MarshalJSON() ([]byte, error) {
+	if exec.RevertReason != "" {
+		return json.Marshal(struct {
+			RevertReason string `json:"revert_reason"`
+		}{exec.RevertReason})
+	}
+	return json.Marshal(exec.FunctionInvocation)
+}
+
+// CollectL1Messages returns the messages sent to L1 by every invocation of the trace, including the inner
+// calls, in execution order: the invocations in the order they are run (validation, execution and fee
+// transfer), and the messages of each call tree by their order.
+// The sender of a message defaults to the address of the invocation when the trace omits it.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - []MsgToL1: the messages sent to L1, nil for unknown trace types
+func CollectL1Messages(trace TxnTrace) []MsgToL1 {
+	roots, ok := traceRootInvocations(trace)
+	if !ok {
+		return nil
+	}
+
+	var messages []MsgToL1
+	for _, root := range roots {
+		var ordered []OrderedMsg
+		collectOrderedMessages(root, &ordered)
+		sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+		for _, msg := range ordered {
+			messages = append(messages, msg.MsgToL1)
+		}
+	}
+	return messages
+}
+
+// CollectEvents returns the events emitted by every invocation of the trace, including the inner calls, in
+// execution order, as CollectL1Messages returns the messages.
+// The address of the emitting contract defaults to the address of the invocation when the trace omits it.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - []Event: the events emitted, empty if there are none
+// - error: ErrUnknownTraceType if the trace is not a transaction trace
+func CollectEvents(trace TxnTrace) ([]Event, error) {
+	roots, ok := traceRootInvocations(trace)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnknownTraceType, trace)
+	}
+
+	events := []Event{}
+	for _, root := range roots {
+		var ordered []OrderedEvent
+		collectOrderedEvents(root, &ordered)
+		sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+		for _, event := range ordered {
+			events = append(events, event.Event)
+		}
+	}
+	return events, nil
+}
+
+// collectOrderedEvents appends the events of the invocation and its inner calls, depth first, with the address
+// of the emitting contract.
+func collectOrderedEvents(invocation FnInvocation, events *[]OrderedEvent) {
+	for _, event := range invocation.InvocationEvents {
+		if event.Event.FromAddress == nil {
+			event.Event.FromAddress = invocation.ContractAddress
+		}
+		*events = append(*events, event)
+	}
+	for _, call := range invocation.NestedCalls {
+		collectOrderedEvents(call, events)
+	}
+}
+
+// traceRootInvocations returns the root invocations of a trace in the order they are run.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - []FnInvocation: the validation, execution and fee transfer invocations, or the constructor, validation and
+// fee transfer invocations of a deploy account transaction, empty when they are missing from the trace
+// - bool: false for unknown trace types
+func traceRootInvocations(trace TxnTrace) ([]FnInvocation, bool) {
+	switch trace := trace.(type) {
+	case InvokeTxnTrace:
+		return []FnInvocation{trace.ValidateInvocation, trace.ExecuteInvocation.FunctionInvocation, trace.FeeTransferInvocation}, true
+	case *InvokeTxnTrace:
+		return traceRootInvocations(*trace)
+	case DeclareTxnTrace:
+		return []FnInvocation{trace.ValidateInvocation, trace.FeeTransferInvocation}, true
+	case *DeclareTxnTrace:
+		return traceRootInvocations(*trace)
+	case DeployAccountTxnTrace:
+		return []FnInvocation{trace.ConstructorInvocation, trace.ValidateInvocation, trace.FeeTransferInvocation}, true
+	case *DeployAccountTxnTrace:
+		return traceRootInvocations(*trace)
+	case L1HandlerTxnTrace:
+		return []FnInvocation{trace.FunctionInvocation}, true
+	case *L1HandlerTxnTrace:
+		return traceRootInvocations(*trace)
+	}
+	return nil, false
+}
+
+// collectOrderedMessages appends the messages of the invocation and its inner calls, depth first.
+func collectOrderedMessages(invocation FnInvocation, messages *[]OrderedMsg) {
+	for _, msg := range invocation.L1Messages {
+		if msg.FromAddress == nil {
+			msg.FromAddress = invocation.ContractAddress
+		}
+		*messages = append(*messages, msg)
+	}
+	for _, call := range invocation.NestedCalls {
+		collectOrderedMessages(call, messages)
+	}
+}
+
+// StorageWritesFor returns the storage entries written by a contract during a transaction, from the storage
+// diffs of the state diff of its trace.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// - contract: the address of the contract
+// Returns:
+// - []StorageEntry: the keys and values written by the contract, empty (not nil) if it wrote none or the trace
+// has no state diff
+func StorageWritesFor(trace TxnTrace, contract *felt.Felt) []StorageEntry {
+	diff := traceStateDiff(trace)
+	writes := []StorageEntry{}
+	if diff == nil || contract == nil {
+		return writes
+	}
+	for _, item := range diff.StorageDiffs {
+		if item.Address != nil && item.Address.Equal(contract) {
+			writes = append(writes, item.StorageEntries...)
+		}
+	}
+	return writes
+}
+
+// traceStateDiff returns the state diff of a trace.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - *StateDiff: the state diff of the trace, nil if the trace has none or its type is unknown
+func traceStateDiff(trace TxnTrace) *StateDiff {
+	switch trace := trace.(type) {
+	case InvokeTxnTrace:
+		return trace.StateDiff
+	case *InvokeTxnTrace:
+		return trace.StateDiff
+	case DeclareTxnTrace:
+		return trace.StateDiff
+	case *DeclareTxnTrace:
+		return trace.StateDiff
+	case DeployAccountTxnTrace:
+		return trace.StateDiff
+	case *DeployAccountTxnTrace:
+		return trace.StateDiff
+	case L1HandlerTxnTrace:
+		return trace.StateDiff
+	case *L1HandlerTxnTrace:
+		return trace.StateDiff
+	}
+	return nil
+}
+
+// FlatCall is an invocation of a call tree flattened by FlattenCalls or FailedCalls, without its nested calls.
+type FlatCall struct {
+	ContractAddress    *felt.Felt
+	EntryPointSelector *felt.Felt
+	// The address of the invoking contract, 0 for a root invocation
+	CallerAddress  *felt.Felt
+	ClassHash      *felt.Felt
+	EntryPointType EntryPointType
+	CallType       CallType
+	// The depth of the call in its call tree, 0 for a root invocation
+	Depth int
+	// Whether the call failed, i.e. its is_reverted flag or its failure reason is set
+	Failed        bool
+	FailureReason string
+	// Whether the failure of the call was caught by one of its callers rather than reverting its root
+	// invocation, only set by FailedCalls
+	Caught bool
+}
+
+// FlattenCalls returns the invocations of the call trees of the trace in execution order: the root invocations
+// in the order they are run (validation, execution and fee transfer, or constructor and validation for a
+// deploy account transaction), and the calls of each tree in pre-order, every call before its nested calls.
+// The invocations missing from the trace (e.g. the execution of a reverted transaction) are skipped.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - []FlatCall: the flattened calls, nil for unknown trace types
+func FlattenCalls(trace TxnTrace) []FlatCall {
+	roots, ok := traceRootInvocations(trace)
+	if !ok {
+		return nil
+	}
+
+	var calls []FlatCall
+	for _, root := range roots {
+		if optionalInvocation(root) != nil {
+			flattenInvocation(root, 0, &calls)
+		}
+	}
+	return calls
+}
+
+// flattenInvocation appends the invocation and its nested calls, in pre-order.
+func flattenInvocation(invocation FnInvocation, depth int, calls *[]FlatCall) {
+	*calls = append(*calls, flatCall(invocation, depth))
+	for _, call := range invocation.NestedCalls {
+		flattenInvocation(call, depth+1, calls)
+	}
+}
+
+// flatCall returns the invocation without its nested calls.
+func flatCall(invocation FnInvocation, depth int) FlatCall {
+	return FlatCall{
+		ContractAddress:    invocation.ContractAddress,
+		EntryPointSelector: invocation.EntryPointSelector,
+		CallerAddress:      invocation.CallerAddress,
+		ClassHash:          invocation.ClassHash,
+		EntryPointType:     invocation.EntryPointType,
+		CallType:           invocation.CallType,
+		Depth:              depth,
+		Failed:             invocationFailed(invocation),
+		FailureReason:      invocation.FailureReason,
+	}
+}
+
+// invocationFailed reports whether the invocation failed, from its is_reverted flag or its failure reason.
+func invocationFailed(invocation FnInvocation) bool {
+	return invocation.IsReverted || invocation.FailureReason != ""
+}
+
+// FailedCalls returns the failed invocations of the call trees of the trace, i.e. those whose is_reverted flag
+// or failure reason is set, in the order of FlattenCalls and with their depth, to find where the execution
+// failed. A failed call whose callers all failed up to its root invocation reverted the root; a failed call
+// with a caller that did not fail was caught and handled by that caller, and is reported with Caught set.
+// The traces of the nodes before RPC 0.8 carry no revert flag on the invocations, so no call is reported for
+// them, only the revert reason of the transaction telling that it reverted.
+//
+// Parameters:
+// - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or L1HandlerTxnTrace, or a pointer to one
+// Returns:
+// - []FlatCall: the failed calls, nil if no call failed or for unknown trace types
+func FailedCalls(trace TxnTrace) []FlatCall {
+	roots, ok := traceRootInvocations(trace)
+	if !ok {
+		return nil
+	}
+
+	var calls []FlatCall
+	for _, root := range roots {
+		if optionalInvocation(root) != nil {
+			collectFailedCalls(root, 0, true, &calls)
+		}
+	}
+	return calls
+}
+
+// collectFailedCalls appends the failed calls of the invocation and its nested calls, in pre-order.
+// callersFailed is true when all the callers of the invocation failed, or for a root invocation.
+func collectFailedCalls(invocation FnInvocation, depth int, callersFailed bool, calls *[]FlatCall) {
+	failed := invocationFailed(invocation)
+	if failed {
+		call := flatCall(invocation, depth)
+		call.Caught = !callersFailed
+		*calls = append(*calls, call)
+	}
+	for _, nested := range invocation.NestedCalls {
+		collectFailedCalls(nested, depth+1, callersFailed && failed, calls)
+	}
+}
+
+// SumBlockResources sums the execution resources of the transaction traces of a block, e.g. as returned by
+// TraceBlockTransactions. The execution resources of a transaction already account for its nested invocations,
+// so the nested invocations are only summed for the L1 handler traces, from the resources of their root
+// invocation. The reverted transactions are counted with the resources they consumed. The sums saturate at
+// the maximum value of their type instead of wrapping.
+//
+// Parameters:
+// - traces: the transaction traces of the block
+// Returns:
+// - ExecutionResources: the total execution resources of the traces
+func SumBlockResources(traces []Trace) ExecutionResources {
+	var total ExecutionResources
+	for _, trace := range traces {
+		resources, ok := blockTraceResources(trace.TraceRoot)
+		if !ok {
+			continue
+		}
+		total.Steps = saturatingAdd(total.Steps, resources.Steps)
+		total.MemoryHoles = saturatingAdd(total.MemoryHoles, resources.MemoryHoles)
+		total.RangeCheckApps = saturatingAdd(total.RangeCheckApps, resources.RangeCheckApps)
+		total.PedersenApps = saturatingAdd(total.PedersenApps, resources.PedersenApps)
+		total.PoseidonApps = saturatingAdd(total.PoseidonApps, resources.PoseidonApps)
+		total.ECOPApps = saturatingAdd(total.ECOPApps, resources.ECOPApps)
+		total.ECDSAApps = saturatingAdd(total.ECDSAApps, resources.ECDSAApps)
+		total.BitwiseApps = saturatingAdd(total.BitwiseApps, resources.BitwiseApps)
+		total.KeccakApps = saturatingAdd(total.KeccakApps, resources.KeccakApps)
+		total.SegmentArenaBuiltin = saturatingAdd(total.SegmentArenaBuiltin, resources.SegmentArenaBuiltin)
+		total.L1Gas = saturatingAddUint(total.L1Gas, resources.L1Gas)
+		total.L1DataGas = saturatingAddUint(total.L1DataGas, resources.L1DataGas)
+		total.TotalL1Gas = saturatingAddUint(total.TotalL1Gas, resources.TotalL1Gas)
+		total.TotalL1DataGas = saturatingAddUint(total.TotalL1DataGas, resources.TotalL1DataGas)
+		total.L2Gas = saturatingAddUint(total.L2Gas, resources.L2Gas)
+		if extra := resources.Extra(); extra != nil {
+			totalExtra := total.Extra()
+			if totalExtra == nil {
+				totalExtra = make(map[string]uint64, len(extra))
+			}
+			for key, count := range extra {
+				totalExtra[key] = saturatingAdd(totalExtra[key], count)
+			}
+			total.SetExtra(totalExtra)
+		}
+	}
+	return total
+}
+
+// blockTraceResources returns the execution resources of a transaction trace of a block, either typed (as in
+// the traces of TraceBlockTransactions) or decoded as a JSON object.
+func blockTraceResources(trace TxnTrace) (ExecutionResources, bool) {
+	switch trace := trace.(type) {
+	case L1HandlerTxnTrace:
+		return ExecutionResources{ComputationResources: trace.FunctionInvocation.ComputationResources}, true
+	case *L1HandlerTxnTrace:
+		return ExecutionResources{ComputationResources: trace.FunctionInvocation.ComputationResources}, true
+	case map[string]any:
+		content, err := json.Marshal(trace)
+		if err != nil {
+			return ExecutionResources{}, false
+		}
+		var resources struct {
+			ExecutionResources *ExecutionResources `json:"execution_resources"`
+			FunctionInvocation *struct {
+				ComputationResources ComputationResources `json:"execution_resources"`
+			} `json:"function_invocation"`
+		}
+		if err := json.Unmarshal(content, &resources); err != nil {
+			return ExecutionResources{}, false
+		}
+		switch {
+		case resources.ExecutionResources != nil:
+			return *resources.ExecutionResources, true
+		case resources.FunctionInvocation != nil:
+			return ExecutionResources{ComputationResources: resources.FunctionInvocation.ComputationResources}, true
+		}
+		return ExecutionResources{}, false
+	}
+	resources, err := traceExecutionResources(trace)
+	return resources, err == nil
+}
+
+// saturatingAdd adds two counters, returning math.MaxUint64 on overflow.
+func saturatingAdd(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
+
+// saturatingMul multiplies two counters, returning math.MaxUint64 on overflow.
+func saturatingMul(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
+
+// saturatingAddUint adds two amounts, returning math.MaxUint on overflow.
+func saturatingAddUint(a, b uint) uint {
+	if a > math.MaxUint-b {
+		return math.MaxUint
+	}
+	return a + b
+}
@@ -286,53 +286,65 @@ func (txn *UnknownTransaction) UnmarshalJSON(data []byte) error {
 func unmarshalTxn(t interface{}) (Transaction, error) {
 	switch casted := t.(type) {
 	case map[string]interface{}:
-		switch TransactionType(casted["type"].(string)) {
+		txnType, version, err := txnTypeAndVersion(casted)
+		if err != nil {
+			return nil, err
+		}
+		switch txnType {
 		case TransactionType_Declare:
-
-			switch TransactionType(casted["version"].(string)) {
-			case "0x0":
+			switch version {
+			case TransactionV0:
 				var txn DeclareTxnV0
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x1":
+			case TransactionV1:
 				var txn DeclareTxnV1
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x2":
+			case TransactionV2:
 				var txn DeclareTxnV2
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x3":
+			case TransactionV3:
 				var txn DeclareTxnV3
 				err := remarshal(casted, &txn)
 				return txn, err
 			default:
-				return nil, errors.New("internal unmarshalTxn() error, unknown Declare transaction version")
+				return nil, fmt.Errorf("internal unmarshalTxn() error, unknown Declare transaction version %s", version)
 			}
 		case TransactionType_Deploy:
 			var txn DeployTxn
 			err := remarshal(casted, &txn)
 			return txn, err
 		case TransactionType_DeployAccount:
-			var txn DeployAccountTxn
-			err := remarshal(casted, &txn)
-			return txn, err
+			switch version {
+			case TransactionV1:
+				var txn DeployAccountTxn
+				err := remarshal(casted, &txn)
+				return txn, err
+			case TransactionV3:
+				var txn DeployAccountTxnV3
+				err := remarshal(casted, &txn)
+				return txn, err
+			default:
+				return nil, fmt.Errorf("internal unmarshalTxn() error, unknown DeployAccount transaction version %s", version)
+			}
 		case TransactionType_Invoke:
-			switch TransactionType(casted["version"].(string)) {
-			case "0x0":
+			switch version {
+			case TransactionV0:
 				var txn InvokeTxnV0
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x1":
+			case TransactionV1:
 				var txn InvokeTxnV1
 				err := remarshal(casted, &txn)
 				return txn, err
-			case "0x3":
+			case TransactionV3:
 				var txn InvokeTxnV3
 				err := remarshal(casted, &txn)
 				return txn, err
 			default:
-				return nil, errors.New("internal unmarshalTxn() error, unknown Invoke transaction version")
+				return nil, fmt.Errorf("internal unmarshalTxn() error, unknown Invoke transaction version %s", version)
 			}
 		case TransactionType_L1Handler:
 			var txn L1HandlerTxn
@@ -344,6 +356,41 @@ func unmarshalTxn(t interface{}) (Transaction, error) {
 	return nil, fmt.Errorf("unknown transaction type: %v", t)
 }
 
+// txnTypeAndVersion reads the type and version of a decoded transaction. The version of deploy and
+// L1 handler transactions is optional, and transactions sent with the query bit are reported with their base version.
+//
+// Parameters:
+// - txn: the decoded transaction
+// Returns:
+// - TransactionType: the type of the transaction
+// - TransactionVersion: the version of the transaction
+// - error: an error if the type or version is missing or not a string
+func txnTypeAndVersion(txn map[string]interface{}) (TransactionType, TransactionVersion, error) {
+	txnType, ok := txn["type"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("unknown transaction type: %v", txn["type"])
+	}
+	version, ok := txn["version"].(string)
+	if !ok {
+		switch TransactionType(txnType) {
+		case TransactionType_Deploy, TransactionType_L1Handler:
+			return TransactionType(txnType), "", nil
+		}
+		return "", "", fmt.Errorf("unknown %s transaction version: %v", txnType, txn["version"])
+	}
+	switch TransactionVersion(version) {
+	case TransactionV0WithQueryBit:
+		version = string(TransactionV0)
+	case TransactionV1WithQueryBit:
+		version = string(TransactionV1)
+	case TransactionV2WithQueryBit:
+		version = string(TransactionV2)
+	case TransactionV3WithQueryBit:
+		version = string(TransactionV3)
+	}
+	return TransactionType(txnType), TransactionVersion(version), nil
+}
+
 // remarshal is a function that takes in an interface{} value 'v' and an interface{} value 'dst'.
 // It marshals the 'v' value to JSON using the json.Marshal function and then unmarshals the JSON data to 'dst' using the json.Unmarshal function.
 //
@@ -13,6 +13,7 @@ type Transaction interface {
 var _ Transaction = InvokeTxnV0{}
 var _ Transaction = InvokeTxnV1{}
 var _ Transaction = InvokeTxnV3{}
+var _ Transaction = DeclareTxnV0{}
 var _ Transaction = DeclareTxnV1{}
 var _ Transaction = DeclareTxnV2{}
 var _ Transaction = DeclareTxnV3{}
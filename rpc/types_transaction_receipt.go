@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/NethermindEth/juno/core/felt"
 )
@@ -30,8 +31,23 @@ type MsgFromL1 struct {
 
 type OrderedMsg struct {
 	// The order of the message within the transaction
-	Order   int `json:"order"`
-	MsgToL1 MsgToL1
+	Order int `json:"order"`
+	MsgToL1
+}
+
+// MarshalJSON encodes an ordered message, omitting the sending contract when it is not set,
+// as the nodes omitting it in the messages of a trace do.
+//
+// Returns:
+// - []byte: the JSON encoding of the ordered message
+// - error: an error if the marshaling fails
+func (msg OrderedMsg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Order       int          `json:"order"`
+		FromAddress *felt.Felt   `json:"from_address,omitempty"`
+		ToAddress   *felt.Felt   `json:"to_address"`
+		Payload     []*felt.Felt `json:"payload"`
+	}{msg.Order, msg.FromAddress, msg.ToAddress, msg.Payload})
 }
 
 type FeePayment struct {
@@ -124,25 +140,129 @@ func (tt TransactionType) MarshalJSON() ([]byte, error) {
 
 type ComputationResources struct {
 	// The number of Cairo steps used
-	Steps int `json:"steps"`
+	Steps uint64 `json:"steps"`
 	// The number of unused memory cells (each cell is roughly equivalent to a step)
-	MemoryHoles int `json:"memory_holes,omitempty"`
+	MemoryHoles uint64 `json:"memory_holes,omitempty"`
 	// The number of RANGE_CHECK builtin instances
-	RangeCheckApps int `json:"range_check_builtin_applications,omitempty"`
+	RangeCheckApps uint64 `json:"range_check_builtin_applications,omitempty"`
 	// The number of Pedersen builtin instances
-	PedersenApps int `json:"pedersen_builtin_applications,omitempty"`
+	PedersenApps uint64 `json:"pedersen_builtin_applications,omitempty"`
 	// The number of Poseidon builtin instances
-	PoseidonApps int `json:"poseidon_builtin_applications,omitempty"`
+	PoseidonApps uint64 `json:"poseidon_builtin_applications,omitempty"`
 	// The number of EC_OP builtin instances
-	ECOPApps int `json:"ec_op_builtin_applications,omitempty"`
+	ECOPApps uint64 `json:"ec_op_builtin_applications,omitempty"`
 	// The number of ECDSA builtin instances
-	ECDSAApps int `json:"ecdsa_builtin_applications,omitempty"`
+	ECDSAApps uint64 `json:"ecdsa_builtin_applications,omitempty"`
 	// The number of BITWISE builtin instances
-	BitwiseApps int `json:"bitwise_builtin_applications,omitempty"`
+	BitwiseApps uint64 `json:"bitwise_builtin_applications,omitempty"`
 	// The number of KECCAK builtin instances
-	KeccakApps int `json:"keccak_builtin_applications,omitempty"`
+	KeccakApps uint64 `json:"keccak_builtin_applications,omitempty"`
 	// The number of accesses to the segment arena
-	SegmentArenaBuiltin int `json:"segment_arena_builtin,omitempty"`
+	SegmentArenaBuiltin uint64 `json:"segment_arena_builtin,omitempty"`
+	// extra is the JSON object of the counters of the builtins unknown to this version (see Extra), empty if
+	// there are none. It is kept encoded so that ComputationResources stays comparable
+	extra string
+}
+
+// knownBuiltins are the JSON keys of the builtin counters of ComputationResources.
+var knownBuiltins = map[string]bool{
+	"range_check_builtin_applications": true,
+	"pedersen_builtin_applications":    true,
+	"poseidon_builtin_applications":    true,
+	"ec_op_builtin_applications":       true,
+	"ecdsa_builtin_applications":       true,
+	"bitwise_builtin_applications":     true,
+	"keccak_builtin_applications":      true,
+	"segment_arena_builtin":            true,
+}
+
+// Extra returns the counters of the builtins unknown to this version, by JSON key (e.g.
+// "add_mod_builtin_applications"), which are kept rather than dropped when decoding the resources.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - map[string]uint64: a copy of the counters, nil if there are none
+func (resources ComputationResources) Extra() map[string]uint64 {
+	if resources.extra == "" {
+		return nil
+	}
+	var extra map[string]uint64
+	if err := json.Unmarshal([]byte(resources.extra), &extra); err != nil {
+		return nil
+	}
+	return extra
+}
+
+// SetExtra sets the counters of the builtins unknown to this version, e.g. to encode resources holding them.
+//
+// Parameters:
+// - extra: the counters by JSON key, nil or empty to remove them
+// Returns:
+//
+//	none
+func (resources *ComputationResources) SetExtra(extra map[string]uint64) {
+	resources.extra = ""
+	if len(extra) == 0 {
+		return
+	}
+	// the keys of a map are encoded in order, so equal counters are encoded equal
+	encoded, err := json.Marshal(extra)
+	if err == nil {
+		resources.extra = string(encoded)
+	}
+}
+
+// UnmarshalJSON decodes the computation resources, the counters of the builtins unknown to this version (the
+// keys holding "builtin" without a field) being kept in Extra rather than dropped.
+//
+// Parameters:
+// - data: the JSON computation resources
+// Returns:
+// - error: an error if the resources or the counter of an unknown builtin cannot be decoded
+func (resources *ComputationResources) UnmarshalJSON(data []byte) error {
+	type computationResources ComputationResources
+	var aux computationResources
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var extra map[string]uint64
+	for key, value := range fields {
+		if !strings.Contains(key, "builtin") || knownBuiltins[key] {
+			continue
+		}
+		var count uint64
+		if err := json.Unmarshal(value, &count); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if extra == nil {
+			extra = make(map[string]uint64)
+		}
+		extra[key] = count
+	}
+	*resources = ComputationResources(aux)
+	resources.SetExtra(extra)
+	return nil
+}
+
+// MarshalJSON encodes the computation resources, with the counters of Extra after the known ones.
+//
+// Returns:
+// - []byte: the JSON encoding of the resources
+// - error: an error if the marshaling fails
+func (resources ComputationResources) MarshalJSON() ([]byte, error) {
+	type computationResources ComputationResources
+	data, err := json.Marshal(computationResources(resources))
+	if err != nil || resources.extra == "" {
+		return data, err
+	}
+	return mergeJSONObjects(data, []byte(resources.extra)), nil
 }
 
 func (er *ComputationResources) Validate() bool {
@@ -156,7 +276,98 @@ func (er *ComputationResources) Validate() bool {
 
 type ExecutionResources struct {
 	ComputationResources
+	// the gas consumed by this transaction's data, only sent before RPC 0.8
 	DataAvailability `json:"data_availability"`
+	// the L1 gas consumed by this transaction, only sent from RPC 0.8 on, which sends the gas totals instead of
+	// the computation resources and data availability
+	TotalL1Gas uint `json:"l1_gas,omitempty"`
+	// the L1 data gas consumed by this transaction, only sent from RPC 0.8 on
+	TotalL1DataGas uint `json:"l1_data_gas,omitempty"`
+	// the L2 gas consumed by this transaction, only sent from RPC 0.8 on
+	L2Gas uint `json:"l2_gas,omitempty"`
+}
+
+// HasGasTotals reports whether the execution resources hold the gas totals of RPC 0.8.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if one of the totals is set
+func (resources ExecutionResources) HasGasTotals() bool {
+	return resources.TotalL1Gas != 0 || resources.TotalL1DataGas != 0 || resources.L2Gas != 0
+}
+
+// executionGas are the fields of ExecutionResources besides its computation resources.
+type executionGas struct {
+	DataAvailability *DataAvailability `json:"data_availability,omitempty"`
+	TotalL1Gas       uint              `json:"l1_gas,omitempty"`
+	TotalL1DataGas   uint              `json:"l1_data_gas,omitempty"`
+	L2Gas            uint              `json:"l2_gas,omitempty"`
+}
+
+// UnmarshalJSON decodes the execution resources, their computation resources being decoded by
+// ComputationResources.UnmarshalJSON, whose promotion would otherwise drop the gas.
+//
+// Parameters:
+// - data: the JSON execution resources
+// Returns:
+// - error: an error if the resources cannot be decoded
+func (resources *ExecutionResources) UnmarshalJSON(data []byte) error {
+	var computation ComputationResources
+	if err := json.Unmarshal(data, &computation); err != nil {
+		return err
+	}
+	var gas executionGas
+	if err := json.Unmarshal(data, &gas); err != nil {
+		return err
+	}
+	*resources = ExecutionResources{
+		ComputationResources: computation,
+		TotalL1Gas:           gas.TotalL1Gas,
+		TotalL1DataGas:       gas.TotalL1DataGas,
+		L2Gas:                gas.L2Gas,
+	}
+	if gas.DataAvailability != nil {
+		resources.DataAvailability = *gas.DataAvailability
+	}
+	return nil
+}
+
+// MarshalJSON encodes the execution resources, the computation resources followed by the gas. The data
+// availability is omitted when it is zero and the resources hold the gas totals of RPC 0.8.
+//
+// Returns:
+// - []byte: the JSON encoding of the resources
+// - error: an error if the marshaling fails
+func (resources ExecutionResources) MarshalJSON() ([]byte, error) {
+	computation, err := json.Marshal(resources.ComputationResources)
+	if err != nil {
+		return nil, err
+	}
+	gas := executionGas{TotalL1Gas: resources.TotalL1Gas, TotalL1DataGas: resources.TotalL1DataGas, L2Gas: resources.L2Gas}
+	if resources.DataAvailability != (DataAvailability{}) || !resources.HasGasTotals() {
+		gas.DataAvailability = &resources.DataAvailability
+	}
+	encoded, err := json.Marshal(gas)
+	if err != nil {
+		return nil, err
+	}
+	return mergeJSONObjects(computation, encoded), nil
+}
+
+// mergeJSONObjects returns the JSON object holding the fields of both objects, those of a first.
+func mergeJSONObjects(a, b []byte) []byte {
+	switch {
+	case string(a) == "{}":
+		return b
+	case string(b) == "{}":
+		return a
+	}
+	merged := append([]byte(nil), a[:len(a)-1]...)
+	merged = append(merged, ',')
+	return append(merged, b[1:]...)
 }
 
 type DataAvailability struct {
@@ -178,8 +389,13 @@ const (
 type TxnStatusResp struct {
 	ExecutionStatus TxnExecutionStatus `json:"execution_status,omitempty"`
 	FinalityStatus  TxnStatus          `json:"finality_status"`
+	// The failure reason of a reverted or rejected transaction, only sent by the nodes from RPC 0.8 on
+	FailureReason string `json:"failure_reason,omitempty"`
 }
 
+// TxnStatusResult is the result of GetTransactionStatus.
+type TxnStatusResult = TxnStatusResp
+
 type TransactionReceiptWithBlockInfo struct {
 	TransactionReceipt
 	BlockHash   *felt.Felt `json:"block_hash,omitempty"`
@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnmarshalHistoricalTxns tests that every transaction version is decoded into its version-specific type,
+// both as a block transaction and as a standalone transaction.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestUnmarshalHistoricalTxns(t *testing.T) {
+	data, err := os.ReadFile("./tests/transactions/historicalTxns.json")
+	require.NoError(t, err)
+
+	var rawTxns []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &rawTxns))
+
+	var blockTxns BlockTransactions
+	require.NoError(t, json.Unmarshal(data, &blockTxns))
+
+	expectedTypes := []struct {
+		BlockTxn IBlockTransaction
+		Txn      Transaction
+	}{
+		{BlockInvokeTxnV0{}, InvokeTxnV0{}},
+		{BlockInvokeTxnV1{}, InvokeTxnV1{}},
+		{BlockInvokeTxnV3{}, InvokeTxnV3{}},
+		{BlockDeclareTxnV0{}, DeclareTxnV0{}},
+		{BlockDeclareTxnV1{}, DeclareTxnV1{}},
+		{BlockDeclareTxnV2{}, DeclareTxnV2{}},
+		{BlockDeclareTxnV3{}, DeclareTxnV3{}},
+		{BlockDeployAccountTxn{}, DeployAccountTxn{}},
+		{BlockDeployAccountTxnV3{}, DeployAccountTxnV3{}},
+	}
+	require.Len(t, blockTxns, len(expectedTypes))
+	require.Len(t, rawTxns, len(expectedTypes))
+
+	for i, expected := range expectedTypes {
+		require.IsType(t, expected.BlockTxn, blockTxns[i])
+		require.NotNil(t, blockTxns[i].Hash())
+
+		var txn UnknownTransaction
+		require.NoError(t, json.Unmarshal(rawTxns[i], &txn))
+		require.IsType(t, expected.Txn, txn.Transaction)
+	}
+
+	// version-specific fields
+	require.NotNil(t, blockTxns[1].(BlockInvokeTxnV1).MaxFee)
+	require.Equal(t, U64("0xc0e"), blockTxns[2].(BlockInvokeTxnV3).ResourceBounds.L1Gas.MaxAmount)
+	require.NotNil(t, blockTxns[5].(BlockDeclareTxnV2).CompiledClassHash)
+	require.Equal(t, U64("0x186a0"), blockTxns[6].(BlockDeclareTxnV3).ResourceBounds.L1Gas.MaxAmount)
+	require.NotNil(t, blockTxns[7].(BlockDeployAccountTxn).MaxFee)
+	require.Equal(t, U64("0x125f"), blockTxns[8].(BlockDeployAccountTxnV3).ResourceBounds.L1Gas.MaxAmount)
+
+	// unknown versions are rejected instead of being decoded as another version
+	var txn UnknownTransaction
+	require.Error(t, json.Unmarshal([]byte(`{"type": "INVOKE", "version": "0x2"}`), &txn))
+	require.Error(t, json.Unmarshal([]byte(`{"type": "DEPLOY_ACCOUNT"}`), &txn))
+	var blockTxn BlockTransaction
+	require.Error(t, json.Unmarshal([]byte(`{"type": "INVOKE", "version": "0x2", "transaction_hash": "0x1"}`), &blockTxn))
+}
@@ -1,15 +1,195 @@
 package rpc
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
-// SpecVersion returns the version of the Starknet JSON-RPC specification being used
-// Parameters: None
-// Returns: String of the Starknet JSON-RPC specification
+// SpecVersion returns the version of the Starknet JSON-RPC specification being used.
+// The version is cached after the first successful call for the lifetime of the provider, as the chain ID is,
+// and is then used to decode the traces of TraceTransaction when no version is declared with WithSpecVersion.
+// The cache is not cleared when the connection to the node is re-established, so a provider whose URL may be
+// served by nodes of different versions, e.g. behind a load balancer, should declare the version with
+// WithSpecVersion, or be replaced by a new provider when the node changes.
+//
+// Parameters:
+// - ctx: The context.Context object for the function
+// Returns:
+// - string: The version of the Starknet JSON-RPC specification, e.g. "0.7.1"
+// - error: An error if any occurred during the execution
 func (provider *Provider) SpecVersion(ctx context.Context) (string, error) {
+	provider.specVersionMu.RLock()
+	version := provider.nodeSpecVersion
+	provider.specVersionMu.RUnlock()
+	if version != "" {
+		return version, nil
+	}
 	var result string
 	err := do(ctx, provider.c, "starknet_specVersion", &result)
 	if err != nil {
-		return "", Err(InternalError, err)
+		if ctxErr := contextError(err); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", internalCallErr(err)
 	}
+	provider.specVersionMu.Lock()
+	provider.nodeSpecVersion = result
+	provider.specVersionMu.Unlock()
 	return result, nil
 }
+
+// knownSpecVersion returns the spec version of the node as far as the provider knows it, e.g. to decode the
+// traces: the version declared with WithSpecVersion, or else the version cached by SpecVersion.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the spec version, empty if it is neither declared nor cached
+func (provider *Provider) knownSpecVersion() string {
+	if provider.specVersion != "" {
+		return provider.specVersion
+	}
+	provider.specVersionMu.RLock()
+	defer provider.specVersionMu.RUnlock()
+	return provider.nodeSpecVersion
+}
+
+var ErrMethodUnsupported = errors.New("method not supported by the node")
+
+// methodSpecVersions are the minimum spec versions of the methods not available since RPC 0.6.
+var methodSpecVersions = map[string]string{
+	"starknet_getBlockWithReceipts": "0.7.0",
+	"starknet_getMessagesStatus":    "0.7.0",
+	"starknet_getStorageProof":      "0.8.0",
+	"starknet_getCompiledCasm":      "0.8.0",
+	"starknet_subscribeNewHeads":    "0.8.0",
+	"starknet_subscribeEvents":      "0.8.0",
+}
+
+// MethodUnsupportedError is the error of a call to a method the node does not support, with the spec version
+// the method requires and the version of the node. It matches ErrMethodUnsupported with errors.Is.
+type MethodUnsupportedError struct {
+	Method string
+	// The minimum spec version of the method, empty if it is not known
+	Required string
+	// The spec version of the node, empty if it is not known
+	Actual string
+}
+
+// Error returns the method and the versions.
+func (e *MethodUnsupportedError) Error() string {
+	required, actual := e.Required, e.Actual
+	if required == "" {
+		required = "unknown"
+	}
+	if actual == "" {
+		actual = "unknown"
+	}
+	return fmt.Sprintf("%s: %s requires RPC %s, the node runs RPC %s", ErrMethodUnsupported, e.Method, required, actual)
+}
+
+// Is makes the error match ErrMethodUnsupported.
+func (e *MethodUnsupportedError) Is(target error) bool {
+	return target == ErrMethodUnsupported
+}
+
+// SupportsMethod reports whether the node supports a method, from the minimum spec version of the method and
+// the version of the node declared with WithSpecVersion, or else detected with SpecVersion. A method is assumed
+// to be supported if the version of the node cannot be detected.
+//
+// Parameters:
+// - name: the method, with or without its "starknet_" prefix, e.g. "starknet_getStorageProof"
+// Returns:
+// - bool: false if the version of the node is older than the minimum version of the method
+func (provider *Provider) SupportsMethod(name string) bool {
+	return provider.checkMethod(context.Background(), name) == nil
+}
+
+// checkMethod returns a *MethodUnsupportedError if the spec version of the node is older than the minimum
+// version of the method. The version of the node is detected with SpecVersion when it is neither declared nor
+// cached, and only for the methods that have a minimum version.
+//
+// Parameters:
+// - ctx: the context.Context of the detection of the version
+// - name: the method, with or without its "starknet_" prefix
+// Returns:
+// - error: the *MethodUnsupportedError, nil if the method is supported or the version of the node cannot be
+// detected
+func (provider *Provider) checkMethod(ctx context.Context, name string) error {
+	method := "starknet_" + strings.TrimPrefix(name, "starknet_")
+	required, ok := methodSpecVersions[method]
+	if !ok {
+		return nil
+	}
+	actual := provider.knownSpecVersion()
+	if actual == "" {
+		// the method is assumed to be supported by a node whose version cannot be fetched
+		actual, _ = provider.SpecVersion(ctx)
+	}
+	if actual == "" || compareSpecVersions(actual, required) >= 0 {
+		return nil
+	}
+	return &MethodUnsupportedError{Method: method, Required: required, Actual: actual}
+}
+
+// unsupportedMethodErr turns the MethodNotFound error of the node for a method into a *MethodUnsupportedError.
+//
+// Parameters:
+// - method: the method of the call
+// - err: the error of the call
+// Returns:
+// - error: the *MethodUnsupportedError if err is a MethodNotFound error, or else err
+func (provider *Provider) unsupportedMethodErr(method string, err error) error {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != MethodNotFound {
+		return err
+	}
+	return &MethodUnsupportedError{Method: method, Required: methodSpecVersions[method], Actual: provider.knownSpecVersion()}
+}
+
+// compareSpecVersions compares two spec versions such as "0.7.1" or "v0.8.0-rc.1" by their numbers, the
+// missing numbers being 0 and the pre-release suffixes being ignored.
+//
+// Parameters:
+// - a: the first version
+// - b: the second version
+// Returns:
+// - int: -1 if a is older than b, 1 if it is newer, 0 if they are the same
+func compareSpecVersions(a, b string) int {
+	partsA, partsB := specVersionNumbers(a), specVersionNumbers(b)
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var numberA, numberB int
+		if i < len(partsA) {
+			numberA = partsA[i]
+		}
+		if i < len(partsB) {
+			numberB = partsB[i]
+		}
+		if numberA != numberB {
+			if numberA < numberB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// specVersionNumbers returns the numbers of a spec version, e.g. [0 8 0] for "v0.8.0-rc.1".
+func specVersionNumbers(version string) []int {
+	version, _, _ = strings.Cut(strings.TrimPrefix(strings.TrimSpace(version), "v"), "-")
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
@@ -2,8 +2,10 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
+	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,3 +32,93 @@ func TestSpecVersion(t *testing.T) {
 		require.Equal(t, test.ExpectedResp, resp)
 	}
 }
+
+// specVersionMock is a callCloser answering starknet_specVersion and counting its calls.
+type specVersionMock struct {
+	calls int
+}
+
+func (m *specVersionMock) Close() {}
+
+func (m *specVersionMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method != "starknet_specVersion" {
+		return errNotFound
+	}
+	m.calls++
+	return json.Unmarshal([]byte(`"0.8.0"`), result)
+}
+
+// TestSpecVersionCache tests that the spec version is only fetched from the node by the first call to
+// SpecVersion, and is then used to decode the traces.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSpecVersionCache(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("Skipping test as it requires a mock environment")
+	}
+	mock := &specVersionMock{}
+	provider := &Provider{c: mock}
+	require.Empty(t, provider.knownSpecVersion())
+
+	for i := 0; i < 2; i++ {
+		version, err := provider.SpecVersion(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "0.8.0", version)
+	}
+	require.Equal(t, 1, mock.calls)
+	require.Equal(t, "0.8.0", provider.knownSpecVersion())
+
+	// a declared version takes precedence over the version of the node
+	provider.specVersion = "0.7.1"
+	require.Equal(t, "0.7.1", provider.knownSpecVersion())
+}
+
+// TestSupportsMethod tests the methods supported by the spec version of the node, declared or detected, and the
+// MethodUnsupportedError of the calls of the unsupported methods.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSupportsMethod(t *testing.T) {
+	require.Equal(t, -1, compareSpecVersions("0.7.1", "0.8.0"))
+	require.Equal(t, 0, compareSpecVersions("v0.8.0-rc.1", "0.8"))
+	require.Equal(t, 1, compareSpecVersions("0.10.0", "0.8.0"))
+
+	provider := NewMockProvider(map[string]json.RawMessage{})
+	// the version of the node cannot be detected
+	require.True(t, provider.SupportsMethod("starknet_getStorageProof"))
+
+	provider.specVersion = "0.7.1"
+	require.True(t, provider.SupportsMethod("getBlockWithReceipts"))
+	require.True(t, provider.SupportsMethod("starknet_getNonce"))
+	require.False(t, provider.SupportsMethod("starknet_getStorageProof"))
+	require.False(t, provider.SupportsMethod("getCompiledCasm"))
+
+	_, err := provider.GetStorageProof(context.Background(), StorageProofInput{BlockID: WithBlockTag(BlockTagLatest)})
+	require.ErrorIs(t, err, ErrMethodUnsupported)
+	var unsupported *MethodUnsupportedError
+	require.ErrorAs(t, err, &unsupported)
+	require.Equal(t, MethodUnsupportedError{Method: "starknet_getStorageProof", Required: "0.8.0", Actual: "0.7.1"}, *unsupported)
+	require.EqualError(t, err, "method not supported by the node: starknet_getStorageProof requires RPC 0.8.0, the node runs RPC 0.7.1")
+
+	// the MethodNotFound error of a node whose version is not known
+	provider.specVersion = ""
+	_, err = provider.GetCompiledCasm(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.ErrorAs(t, err, &unsupported)
+	require.Equal(t, MethodUnsupportedError{Method: "starknet_getCompiledCasm", Required: "0.8.0"}, *unsupported)
+
+	// the version of the node is detected with starknet_specVersion
+	provider = NewMockProvider(map[string]json.RawMessage{"starknet_specVersion": json.RawMessage(`"0.7.1"`)})
+	require.True(t, provider.SupportsMethod("starknet_getMessagesStatus"))
+	require.False(t, provider.SupportsMethod("starknet_subscribeNewHeads"))
+	require.Equal(t, "0.7.1", provider.knownSpecVersion())
+	_, err = provider.GetCompiledCasm(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.ErrorAs(t, err, &unsupported)
+	require.Equal(t, MethodUnsupportedError{Method: "starknet_getCompiledCasm", Required: "0.8.0", Actual: "0.7.1"}, *unsupported)
+}
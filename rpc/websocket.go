@@ -0,0 +1,668 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// ErrSubscriptionReconnected is sent on the Status channel of a subscription after the websocket
+	// connection dropped and the subscription was re-established. It is not fatal, the subscription
+	// keeps delivering on the same channel, but notifications sent while disconnected are lost.
+	ErrSubscriptionReconnected = errors.New("websocket reconnected, notifications may have been missed")
+	ErrWsProviderClosed        = errors.New("websocket provider closed")
+)
+
+const (
+	wsReconnectBaseDelay = 500 * time.Millisecond
+	wsReconnectMaxDelay  = 30 * time.Second
+	wsUnsubscribeTimeout = 5 * time.Second
+	// wsNotificationBuffer is the default number of notifications buffered per subscription
+	wsNotificationBuffer = 64
+)
+
+// Subscription is an active subscription of a WsProvider.
+type Subscription interface {
+	// Unsubscribe cancels the subscription and closes its Err channel.
+	Unsubscribe()
+	// Err returns the channel on which the errors of the subscription, such as a notification that cannot be
+	// decoded, are sent. It is closed by Unsubscribe or when the provider is closed.
+	Err() <-chan error
+	// Status returns the channel on which ErrSubscriptionReconnected is sent after each reconnection, once the
+	// notifications received on the previous connection have been delivered. No signal is dropped, they are
+	// queued until read. It is closed once the subscription is closed.
+	Status() <-chan error
+}
+
+// WsProvider provides the subscriptions of the Starknet websocket API. The connection is
+// re-established automatically when it drops, and the active subscriptions are re-issued.
+// The notifications of each subscription are buffered, so that a slow consumer does not block the
+// notifications of the other subscriptions: when the buffer of a subscription is full, its notifications
+// are dropped with a warning logged.
+type WsProvider struct {
+	url    string
+	dialer *websocket.Dialer
+
+	reconnectBaseDelay time.Duration
+	reconnectMaxDelay  time.Duration
+	noReconnect        bool
+	bufferSize         int
+	logger             *slog.Logger
+
+	writeMu sync.Mutex
+
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	nextID uint64
+	calls  map[uint64]wsCall
+	subs   map[string]*wsSubscription
+	closed bool
+	done   chan struct{}
+
+	observer MetricsObserver
+}
+
+// wsCall is a pending call of the provider.
+type wsCall struct {
+	resp chan wsResponse
+	// onResult, if set, is run by the read loop with ws.mu held when the call succeeds, before the next
+	// message is read, e.g. to register a subscription before its first notification is dispatched
+	onResult func(result json.RawMessage)
+}
+
+type wsRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// wsResponse is either a response to a request (ID set) or a subscription notification (Method set).
+type wsResponse struct {
+	ID     *uint64         `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+	Method string          `json:"method"`
+	Params struct {
+		SubscriptionID json.RawMessage `json:"subscription_id"`
+		Result         json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+// WsOption configures a WsProvider created with NewWsProvider.
+type WsOption func(*WsProvider)
+
+// WithReconnectBackoff sets the delays between the attempts to re-establish a dropped connection: the first
+// attempt waits baseDelay, and each failed attempt doubles the delay up to maxDelay. The defaults are 500ms and 30s.
+//
+// Parameters:
+// - baseDelay: the delay before the first attempt, the default if zero or negative
+// - maxDelay: the maximum delay between two attempts, at least baseDelay
+// Returns:
+// - WsOption: the option to pass to NewWsProvider
+func WithReconnectBackoff(baseDelay, maxDelay time.Duration) WsOption {
+	return func(ws *WsProvider) {
+		if baseDelay > 0 {
+			ws.reconnectBaseDelay = baseDelay
+		}
+		ws.reconnectMaxDelay = maxDelay
+	}
+}
+
+// WithoutReconnect disables the reconnection of the provider: when the connection drops, the error of the
+// connection is sent on the Err channel of each subscription, which is then closed, and the calls fail.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - WsOption: the option to pass to NewWsProvider
+func WithoutReconnect() WsOption {
+	return func(ws *WsProvider) {
+		ws.noReconnect = true
+	}
+}
+
+// WithNotificationBuffer sets the number of notifications buffered per subscription while the consumer is busy,
+// 64 by default. The notifications received while the buffer is full are dropped with a warning logged.
+//
+// Parameters:
+// - size: the number of buffered notifications, at least 1
+// Returns:
+// - WsOption: the option to pass to NewWsProvider
+func WithNotificationBuffer(size int) WsOption {
+	return func(ws *WsProvider) {
+		ws.bufferSize = max(size, 1)
+	}
+}
+
+// WithWsLogger sets the logger of the warnings of the provider, such as the dropped notifications,
+// slog.Default() by default.
+//
+// Parameters:
+// - logger: the logger of the warnings
+// Returns:
+// - WsOption: the option to pass to NewWsProvider
+func WithWsLogger(logger *slog.Logger) WsOption {
+	return func(ws *WsProvider) {
+		ws.logger = logger
+	}
+}
+
+// NewWsProvider connects to the websocket endpoint of a Starknet node.
+//
+// Parameters:
+// - url: the websocket URL of the node (ws:// or wss://)
+// - options: the WsOption applied to the provider, e.g. WithReconnectBackoff or WithNotificationBuffer
+// Returns:
+// - *WsProvider: a new WsProvider
+// - error: an error if the connection cannot be established
+func NewWsProvider(url string, options ...WsOption) (*WsProvider, error) {
+	ws := &WsProvider{
+		url:                url,
+		dialer:             websocket.DefaultDialer,
+		reconnectBaseDelay: wsReconnectBaseDelay,
+		reconnectMaxDelay:  wsReconnectMaxDelay,
+		bufferSize:         wsNotificationBuffer,
+		logger:             slog.Default(),
+		calls:              map[uint64]wsCall{},
+		subs:               map[string]*wsSubscription{},
+		done:               make(chan struct{}),
+	}
+	for _, option := range options {
+		option(ws)
+	}
+	ws.reconnectMaxDelay = max(ws.reconnectMaxDelay, ws.reconnectBaseDelay)
+	if ws.logger == nil {
+		ws.logger = slog.Default()
+	}
+	conn, _, err := ws.dialer.Dial(url, nil)
+	if err != nil {
+		return nil, err
+	}
+	ws.conn = conn
+	go ws.readLoop(conn)
+	return ws, nil
+}
+
+// Close closes the connection and all the subscriptions of the provider.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (ws *WsProvider) Close() {
+	ws.mu.Lock()
+	if ws.closed {
+		ws.mu.Unlock()
+		return
+	}
+	ws.closed = true
+	close(ws.done)
+	conn := ws.conn
+	ws.conn = nil
+	subs := ws.subs
+	ws.subs = map[string]*wsSubscription{}
+	ws.failCalls(ErrWsProviderClosed)
+	ws.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+	for _, sub := range subs {
+		sub.close()
+	}
+}
+
+// SubscribeNewHeads subscribes to the headers of the new blocks accepted on L2.
+// The headers are delivered on the given channel until the subscription is cancelled.
+// The lag of each header behind the wall-clock time is recorded by the metrics observer, if any.
+//
+// Parameters:
+// - ctx: the context.Context for the subscription request
+// - headers: the channel on which the new block headers are delivered
+// Returns:
+// - Subscription: the subscription
+// - error: an error if the subscription cannot be created
+func (ws *WsProvider) SubscribeNewHeads(ctx context.Context, headers chan<- BlockHeader) (Subscription, error) {
+	return ws.subscribe(ctx, "starknet_subscribeNewHeads", map[string]interface{}{}, func(sub *wsSubscription, result json.RawMessage) error {
+		var header BlockHeader
+		if err := json.Unmarshal(result, &header); err != nil {
+			return err
+		}
+		ws.observeBlockLag(sub.method, header.Timestamp)
+		select {
+		case headers <- header:
+		case <-sub.quit:
+		}
+		return nil
+	})
+}
+
+// wsSubscription is a subscription of a WsProvider, re-issued with the same method and params after a reconnection.
+type wsSubscription struct {
+	ws      *WsProvider
+	method  string
+	params  interface{}
+	deliver func(sub *wsSubscription, result json.RawMessage) error
+
+	// id is the subscription id of the current connection, guarded by ws.mu
+	id json.RawMessage
+	// buffer holds the notifications received and not yet delivered, across the reconnections
+	buffer chan json.RawMessage
+
+	// status is the channel of the reconnection signals, sent by run from the signals queued in pending
+	status    chan error
+	statusMu  sync.Mutex
+	pending   []pendingStatus
+	statusSet chan struct{}
+
+	quit      chan struct{}
+	errMu     sync.Mutex
+	err       chan error
+	closeOnce sync.Once
+}
+
+// pendingStatus is a status signal waiting for the notifications buffered before it to be delivered.
+type pendingStatus struct {
+	err error
+	// after is the number of notifications still to deliver before the signal
+	after int
+}
+
+var _ Subscription = &wsSubscription{}
+
+// Err returns the channel of the non-fatal notifications of the subscription.
+func (sub *wsSubscription) Err() <-chan error {
+	return sub.err
+}
+
+// Status returns the channel of the reconnection signals of the subscription.
+func (sub *wsSubscription) Status() <-chan error {
+	return sub.status
+}
+
+// Unsubscribe cancels the subscription on the node and closes its Err channel.
+func (sub *wsSubscription) Unsubscribe() {
+	// closed first, so that a resubscription in progress does not register it again
+	sub.close()
+	sub.ws.dropRegistration(sub)
+}
+
+// unsubscribe cancels a subscription id on the node, best effort as the subscription is dropped locally anyway.
+func (ws *WsProvider) unsubscribe(id json.RawMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), wsUnsubscribeTimeout)
+	defer cancel()
+	_, _ = ws.call(ctx, "starknet_unsubscribe", map[string]interface{}{"subscription_id": id})
+}
+
+// register returns the hook registering the subscription under the id returned by the node, run by the read
+// loop before the first notification of the subscription is read. A subscription closed meanwhile keeps the
+// id to cancel, but is not registered.
+func (ws *WsProvider) register(sub *wsSubscription, onRegistered func()) func(json.RawMessage) {
+	return func(id json.RawMessage) {
+		sub.id = id
+		select {
+		case <-sub.quit:
+			return
+		default:
+		}
+		ws.subs[subscriptionKey(id)] = sub
+		if onRegistered != nil {
+			onRegistered()
+		}
+	}
+}
+
+// dropRegistration removes the registration of a subscription that was closed or not handed out, and cancels
+// its id on the node, e.g. when Unsubscribe ran while the subscription was re-issued. The id is cancelled once,
+// by the first caller.
+func (ws *WsProvider) dropRegistration(sub *wsSubscription) {
+	ws.mu.Lock()
+	id := sub.id
+	sub.id = nil
+	if existing, ok := ws.subs[subscriptionKey(id)]; ok && existing == sub {
+		delete(ws.subs, subscriptionKey(id))
+	}
+	ws.mu.Unlock()
+	if id != nil {
+		ws.unsubscribe(id)
+	}
+}
+
+// notify sends a non-fatal notification on the Err channel, dropping it if the previous one was not read.
+func (sub *wsSubscription) notify(err error) {
+	sub.errMu.Lock()
+	defer sub.errMu.Unlock()
+	select {
+	case <-sub.quit:
+		return
+	default:
+	}
+	select {
+	case sub.err <- err:
+	default:
+	}
+}
+
+// queueStatus queues a status signal, sent once the notifications currently buffered have been delivered.
+// ws.mu must be held, so that no notification is buffered concurrently.
+func (sub *wsSubscription) queueStatus(err error) {
+	sub.statusMu.Lock()
+	sub.pending = append(sub.pending, pendingStatus{err: err, after: len(sub.buffer)})
+	sub.statusMu.Unlock()
+	select {
+	case sub.statusSet <- struct{}{}:
+	default:
+	}
+}
+
+// nextStatus returns the Status channel and the signal to send next, a nil channel if no signal is ready.
+func (sub *wsSubscription) nextStatus() (chan error, error) {
+	sub.statusMu.Lock()
+	defer sub.statusMu.Unlock()
+	if len(sub.pending) == 0 || sub.pending[0].after > 0 {
+		return nil, nil
+	}
+	return sub.status, sub.pending[0].err
+}
+
+// statusSent removes the signal sent from the queue.
+func (sub *wsSubscription) statusSent() {
+	sub.statusMu.Lock()
+	defer sub.statusMu.Unlock()
+	sub.pending = sub.pending[1:]
+}
+
+// notificationTaken counts a notification taken from the buffer for the signals waiting for it.
+func (sub *wsSubscription) notificationTaken() {
+	sub.statusMu.Lock()
+	defer sub.statusMu.Unlock()
+	for i := range sub.pending {
+		if sub.pending[i].after > 0 {
+			sub.pending[i].after--
+		}
+	}
+}
+
+// run delivers the buffered notifications and the status signals of the subscription until it is closed.
+func (sub *wsSubscription) run() {
+	defer close(sub.status)
+	for {
+		status, signal := sub.nextStatus()
+		select {
+		case <-sub.quit:
+			return
+		case status <- signal:
+			sub.statusSent()
+		case <-sub.statusSet:
+		case result := <-sub.buffer:
+			sub.notificationTaken()
+			if err := sub.deliver(sub, result); err != nil {
+				sub.notify(fmt.Errorf("%s notification: %w", sub.method, err))
+			}
+		}
+	}
+}
+
+func (sub *wsSubscription) close() {
+	sub.closeOnce.Do(func() {
+		sub.errMu.Lock()
+		defer sub.errMu.Unlock()
+		close(sub.quit)
+		close(sub.err)
+	})
+}
+
+// subscribe issues the subscription request and registers the subscription under the returned id.
+func (ws *WsProvider) subscribe(ctx context.Context, method string, params interface{}, deliver func(*wsSubscription, json.RawMessage) error) (Subscription, error) {
+	sub := &wsSubscription{
+		ws:        ws,
+		method:    method,
+		params:    params,
+		deliver:   deliver,
+		buffer:    make(chan json.RawMessage, ws.bufferSize),
+		quit:      make(chan struct{}),
+		err:       make(chan error, 1),
+		status:    make(chan error),
+		statusSet: make(chan struct{}, 1),
+	}
+	if _, err := ws.callThen(ctx, method, params, ws.register(sub, nil)); err != nil {
+		// the node may have answered after the context was done
+		ws.dropRegistration(sub)
+		return nil, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.closed {
+		return nil, ErrWsProviderClosed
+	}
+	go sub.run()
+	return sub, nil
+}
+
+// call sends a request on the current connection and waits for its response.
+func (ws *WsProvider) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	return ws.callThen(ctx, method, params, nil)
+}
+
+// callThen is call running onResult, if not nil, in the read loop when the call succeeds (see wsCall).
+func (ws *WsProvider) callThen(ctx context.Context, method string, params interface{}, onResult func(json.RawMessage)) (json.RawMessage, error) {
+	ws.mu.Lock()
+	if ws.closed {
+		ws.mu.Unlock()
+		return nil, ErrWsProviderClosed
+	}
+	conn := ws.conn
+	if conn == nil {
+		ws.mu.Unlock()
+		return nil, Err(InternalError, "websocket disconnected")
+	}
+	ws.nextID++
+	id := ws.nextID
+	respCh := make(chan wsResponse, 1)
+	ws.calls[id] = wsCall{resp: respCh, onResult: onResult}
+	ws.mu.Unlock()
+
+	defer func() {
+		ws.mu.Lock()
+		delete(ws.calls, id)
+		ws.mu.Unlock()
+	}()
+
+	ws.writeMu.Lock()
+	err := conn.WriteJSON(wsRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	ws.writeMu.Unlock()
+	if err != nil {
+		return nil, Err(InternalError, err.Error())
+	}
+
+	select {
+	case resp := <-respCh:
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// readLoop dispatches the messages received on the connection until it fails.
+func (ws *WsProvider) readLoop(conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			ws.connectionLost(conn, err)
+			return
+		}
+		var msg wsResponse
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.ID != nil {
+			ws.mu.Lock()
+			call, ok := ws.calls[*msg.ID]
+			if ok {
+				delete(ws.calls, *msg.ID)
+				if msg.Error == nil && call.onResult != nil {
+					call.onResult(msg.Result)
+				}
+			}
+			ws.mu.Unlock()
+			if ok {
+				select {
+				case call.resp <- msg:
+				default:
+				}
+			}
+			continue
+		}
+		if msg.Method == "" {
+			continue
+		}
+		ws.mu.Lock()
+		sub, ok := ws.subs[subscriptionKey(msg.Params.SubscriptionID)]
+		ws.mu.Unlock()
+		if !ok {
+			continue
+		}
+		select {
+		case sub.buffer <- msg.Params.Result:
+		default:
+			ws.logger.Warn("starknet websocket notification dropped, the subscription buffer is full",
+				"method", sub.method, "subscription_id", subscriptionKey(msg.Params.SubscriptionID), "buffer", cap(sub.buffer))
+		}
+	}
+}
+
+// failCalls answers every pending call with the error, ws.mu must be held.
+func (ws *WsProvider) failCalls(err error) {
+	for id, call := range ws.calls {
+		select {
+		case call.resp <- wsResponse{Error: Err(InternalError, err.Error())}:
+		default:
+		}
+		delete(ws.calls, id)
+	}
+}
+
+// connectionLost drops the failed connection and starts reconnecting, unless the provider is closed.
+// Without reconnection (see WithoutReconnect), the subscriptions are notified of the error and closed.
+func (ws *WsProvider) connectionLost(conn *websocket.Conn, err error) {
+	ws.mu.Lock()
+	if ws.closed || ws.conn != conn {
+		ws.mu.Unlock()
+		return
+	}
+	ws.conn = nil
+	ws.failCalls(err)
+	var subs map[string]*wsSubscription
+	if ws.noReconnect {
+		subs = ws.subs
+		ws.subs = map[string]*wsSubscription{}
+	}
+	ws.mu.Unlock()
+
+	conn.Close()
+	if ws.noReconnect {
+		for _, sub := range subs {
+			sub.notify(fmt.Errorf("%s: websocket disconnected: %w", sub.method, err))
+			sub.close()
+		}
+		return
+	}
+	go ws.reconnect()
+}
+
+// reconnect dials the node with an exponential backoff, then re-issues the active subscriptions.
+func (ws *WsProvider) reconnect() {
+	delay := ws.reconnectBaseDelay
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ws.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		conn, _, err := ws.dialer.Dial(ws.url, nil)
+		if err != nil {
+			delay = min(2*delay, ws.reconnectMaxDelay)
+			continue
+		}
+
+		ws.mu.Lock()
+		if ws.closed {
+			ws.mu.Unlock()
+			conn.Close()
+			return
+		}
+		ws.conn = conn
+		subs := make([]*wsSubscription, 0, len(ws.subs))
+		for _, sub := range ws.subs {
+			subs = append(subs, sub)
+		}
+		ws.subs = map[string]*wsSubscription{}
+		ws.mu.Unlock()
+
+		go ws.readLoop(conn)
+		if observer := ws.metricsObserver(); observer != nil {
+			observer.Reconnected()
+		}
+		for _, sub := range subs {
+			ws.resubscribe(sub)
+		}
+		return
+	}
+}
+
+// resubscribe re-issues a subscription on the new connection and queues the reconnection signal on its
+// Status channel, after the notifications still buffered from the previous connection.
+func (ws *WsProvider) resubscribe(sub *wsSubscription) {
+	select {
+	case <-sub.quit:
+		return
+	default:
+	}
+	// the id of the previous connection is not valid on the new one
+	ws.mu.Lock()
+	sub.id = nil
+	ws.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), wsUnsubscribeTimeout)
+	defer cancel()
+	// the notifications of the new connection are buffered only once the subscription is registered
+	_, err := ws.callThen(ctx, sub.method, sub.params, ws.register(sub, func() { sub.queueStatus(ErrSubscriptionReconnected) }))
+	select {
+	case <-sub.quit:
+		// unsubscribed while reconnecting, the new subscription must not stay live on the node
+		ws.dropRegistration(sub)
+		return
+	default:
+	}
+	if err != nil {
+		// the subscription is lost, the consumer has to subscribe again
+		ws.dropRegistration(sub)
+		sub.notify(fmt.Errorf("%s resubscription: %w", sub.method, err))
+		sub.close()
+	}
+}
+
+// subscriptionKey normalises a subscription id, which nodes send either as a string or a number.
+func subscriptionKey(id json.RawMessage) string {
+	return strings.Trim(string(id), `"`)
+}
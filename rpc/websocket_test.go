@@ -0,0 +1,478 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// wsNodeMock is a websocket server answering the subscription requests of a WsProvider.
+// Every connection gets a new subscription id and its connections can be dropped on demand.
+type wsNodeMock struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	conns    []*websocket.Conn
+	subIDs   int
+	subs     chan string
+	unsubbed chan string
+	// beforeAnswer, if set, is called before a subscription request is answered with the subscription id
+	beforeAnswer func(subID string)
+	// afterAnswer, if set, is called right after a subscription request is answered on the connection
+	afterAnswer func(conn *websocket.Conn, subID string)
+}
+
+func newWsNodeMock(t *testing.T) *wsNodeMock {
+	node := &wsNodeMock{subs: make(chan string, 10), unsubbed: make(chan string, 10)}
+	upgrader := websocket.Upgrader{}
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		node.mu.Lock()
+		node.conns = append(node.conns, conn)
+		node.mu.Unlock()
+		for {
+			var req struct {
+				ID     uint64                     `json:"id"`
+				Method string                     `json:"method"`
+				Params map[string]json.RawMessage `json:"params"`
+			}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			switch req.Method {
+			case "starknet_subscribeNewHeads":
+				node.mu.Lock()
+				node.subIDs++
+				id := strconv.Itoa(node.subIDs)
+				beforeAnswer, afterAnswer := node.beforeAnswer, node.afterAnswer
+				node.mu.Unlock()
+				if beforeAnswer != nil {
+					beforeAnswer(id)
+				}
+				node.send(conn, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": id})
+				if afterAnswer != nil {
+					afterAnswer(conn, id)
+				}
+				node.subs <- id
+			case "starknet_unsubscribe":
+				node.send(conn, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": true})
+				node.unsubbed <- strings.Trim(string(req.Params["subscription_id"]), `"`)
+			default:
+				node.send(conn, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "error": ErrUnexpectedError})
+			}
+		}
+	}))
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+func (node *wsNodeMock) url() string {
+	return "ws" + strings.TrimPrefix(node.server.URL, "http")
+}
+
+func (node *wsNodeMock) send(conn *websocket.Conn, msg interface{}) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	_ = conn.WriteJSON(msg)
+}
+
+// publishHead sends a new head notification on the latest connection.
+func (node *wsNodeMock) publishHead(subID string, blockNumber, timestamp uint64) {
+	node.mu.Lock()
+	conn := node.conns[len(node.conns)-1]
+	node.mu.Unlock()
+	node.publishHeadOn(conn, subID, blockNumber, timestamp)
+}
+
+// publishHeadOn sends a new head notification on the connection.
+func (node *wsNodeMock) publishHeadOn(conn *websocket.Conn, subID string, blockNumber, timestamp uint64) {
+	node.send(conn, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "starknet_subscriptionNewHeads",
+		"params": map[string]interface{}{
+			"subscription_id": subID,
+			"result":          map[string]interface{}{"block_number": blockNumber, "block_hash": "0x1", "timestamp": timestamp},
+		},
+	})
+}
+
+// dropConnections closes the server side of every connection.
+func (node *wsNodeMock) dropConnections() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	for _, conn := range node.conns {
+		conn.Close()
+	}
+}
+
+// TestSubscribeNewHeads tests that the new heads are delivered on the same channel across a reconnection.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestSubscribeNewHeads(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("SubscribeNewHeads is only tested against a local websocket server")
+	}
+	node := newWsNodeMock(t)
+	ws, err := NewWsProvider(node.url())
+	require.NoError(t, err)
+	defer ws.Close()
+
+	headers := make(chan BlockHeader)
+	sub, err := ws.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+	subID := <-node.subs
+
+	node.publishHead(subID, 100, 1)
+	require.Equal(t, uint64(100), (<-headers).BlockNumber)
+
+	// the subscription is re-issued after the connection drops
+	node.dropConnections()
+	var newSubID string
+	select {
+	case newSubID = <-node.subs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription was not re-issued after the connection dropped")
+	}
+	require.NotEqual(t, subID, newSubID)
+	select {
+	case err := <-sub.Status():
+		require.ErrorIs(t, err, ErrSubscriptionReconnected)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the reconnection was not notified")
+	}
+
+	node.publishHead(newSubID, 101, 1)
+	require.Equal(t, uint64(101), (<-headers).BlockNumber)
+
+	sub.Unsubscribe()
+	require.Equal(t, newSubID, <-node.unsubbed)
+	_, ok := <-sub.Err()
+	require.False(t, ok, "the Err channel is closed after Unsubscribe")
+}
+
+// TestWsProviderRegistration tests that a notification sent right after the subscription response is
+// delivered, on subscription and on resubscription, and that a subscription cancelled with Unsubscribe while
+// it is re-issued is cancelled on the node once re-issued.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWsProviderRegistration(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the subscription registration is only tested against a local websocket server")
+	}
+	node := newWsNodeMock(t)
+	node.afterAnswer = func(conn *websocket.Conn, subID string) {
+		block, _ := strconv.ParseUint(subID, 10, 64)
+		node.publishHeadOn(conn, subID, block, 1)
+	}
+	ws, err := NewWsProvider(node.url(), WithReconnectBackoff(10*time.Millisecond, 20*time.Millisecond))
+	require.NoError(t, err)
+	defer ws.Close()
+
+	headers := make(chan BlockHeader)
+	sub, err := ws.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+	<-node.subs
+	require.Equal(t, uint64(1), (<-headers).BlockNumber)
+
+	node.dropConnections()
+	select {
+	case header := <-headers:
+		require.Equal(t, uint64(2), header.BlockNumber)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first notification after the resubscription was not delivered")
+	}
+	<-node.subs
+
+	// the resubscription is answered only after Unsubscribe
+	held, release := make(chan string, 1), make(chan struct{})
+	node.mu.Lock()
+	node.afterAnswer = nil
+	node.beforeAnswer = func(subID string) {
+		held <- subID
+		<-release
+	}
+	node.mu.Unlock()
+	node.dropConnections()
+	var heldID string
+	select {
+	case heldID = <-held:
+	case <-time.After(5 * time.Second):
+		close(release)
+		t.Fatal("the subscription was not re-issued after the connection dropped")
+	}
+	sub.Unsubscribe()
+	close(release)
+	select {
+	case unsubbed := <-node.unsubbed:
+		require.Equal(t, heldID, unsubbed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription re-issued during Unsubscribe was left live on the node")
+	}
+}
+
+// TestWsProviderStatus tests that the reconnection signal is sent on the Status channel after the notifications
+// buffered from the previous connection, and that it is not dropped while an error of the Err channel is unread.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWsProviderStatus(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the status signals are only tested against a local websocket server")
+	}
+	node := newWsNodeMock(t)
+	ws, err := NewWsProvider(node.url(), WithNotificationBuffer(4), WithReconnectBackoff(10*time.Millisecond, 20*time.Millisecond))
+	require.NoError(t, err)
+	defer ws.Close()
+
+	headers := make(chan BlockHeader)
+	sub, err := ws.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	subID := <-node.subs
+
+	// a notification that cannot be decoded leaves an unread error on the Err channel
+	node.mu.Lock()
+	conn := node.conns[0]
+	node.mu.Unlock()
+	node.send(conn, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "starknet_subscriptionNewHeads",
+		"params":  map[string]interface{}{"subscription_id": subID, "result": "not a header"},
+	})
+	for block := uint64(100); block < 103; block++ {
+		node.publishHead(subID, block, 1)
+	}
+	require.Eventually(t, func() bool { return len(sub.Err()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	node.dropConnections()
+	select {
+	case <-node.subs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription was not re-issued after the connection dropped")
+	}
+	select {
+	case err := <-sub.Status():
+		t.Fatalf("the reconnection was signalled before the buffered notifications: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	for block := uint64(100); block < 103; block++ {
+		require.Equal(t, block, (<-headers).BlockNumber)
+	}
+	select {
+	case err := <-sub.Status():
+		require.ErrorIs(t, err, ErrSubscriptionReconnected)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the reconnection was not signalled")
+	}
+	require.ErrorContains(t, <-sub.Err(), "starknet_subscribeNewHeads notification")
+}
+
+// metricsRecorder is a MetricsObserver recording the metrics it receives.
+type metricsRecorder struct {
+	mu         sync.Mutex
+	lags       map[string]time.Duration
+	reconnects int
+}
+
+func (r *metricsRecorder) SubscriptionLag(method string, lag time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lags[method] = lag
+}
+
+func (r *metricsRecorder) Reconnected() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reconnects++
+}
+
+// TestWsProviderMetrics tests that the lag of the new heads and the reconnections are reported to the metrics observer.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWsProviderMetrics(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the metrics are only tested against a local websocket server")
+	}
+	node := newWsNodeMock(t)
+	ws, err := NewWsProvider(node.url())
+	require.NoError(t, err)
+	defer ws.Close()
+	recorder := &metricsRecorder{lags: map[string]time.Duration{}}
+	ws.SetMetricsObserver(recorder)
+
+	headers := make(chan BlockHeader)
+	sub, err := ws.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	subID := <-node.subs
+
+	// a block produced a minute ago
+	node.publishHead(subID, 100, uint64(time.Now().Add(-time.Minute).Unix()))
+	<-headers
+	recorder.mu.Lock()
+	lag := recorder.lags["starknet_subscribeNewHeads"]
+	recorder.mu.Unlock()
+	require.GreaterOrEqual(t, lag, time.Minute-time.Second)
+	require.Less(t, lag, 2*time.Minute)
+
+	node.dropConnections()
+	select {
+	case <-node.subs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscription was not re-issued after the connection dropped")
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Equal(t, 1, recorder.reconnects)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, as the output of a logger.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestWsProviderNotificationBuffer tests that a slow consumer does not block the read loop: the notifications
+// beyond the buffer are dropped with a warning, and the calls are still answered.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWsProviderNotificationBuffer(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the buffering is only tested against a local websocket server")
+	}
+	node := newWsNodeMock(t)
+	logs := &syncBuffer{}
+	ws, err := NewWsProvider(node.url(), WithNotificationBuffer(2), WithWsLogger(slog.New(slog.NewTextHandler(logs, nil))))
+	require.NoError(t, err)
+	defer ws.Close()
+
+	headers := make(chan BlockHeader)
+	sub, err := ws.SubscribeNewHeads(context.Background(), headers)
+	require.NoError(t, err)
+	subID := <-node.subs
+
+	// the first head waits for the consumer, at most two more are buffered and the others dropped
+	for block := uint64(100); block < 106; block++ {
+		node.publishHead(subID, block, 1)
+	}
+	require.Eventually(t, func() bool {
+		return strings.Count(logs.String(), "notification dropped") >= 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Contains(t, logs.String(), "method=starknet_subscribeNewHeads")
+
+	var delivered []uint64
+	for {
+		select {
+		case header := <-headers:
+			delivered = append(delivered, header.BlockNumber)
+			continue
+		case <-time.After(100 * time.Millisecond):
+		}
+		break
+	}
+	require.Equal(t, uint64(100), delivered[0])
+	require.LessOrEqual(t, len(delivered), 3)
+	require.IsIncreasing(t, delivered)
+	require.Equal(t, 6, len(delivered)+strings.Count(logs.String(), "notification dropped"))
+
+	sub.Unsubscribe()
+	require.Equal(t, subID, <-node.unsubbed)
+}
+
+// TestWsProviderReconnectOptions tests the reconnection backoff of WithReconnectBackoff, and that the
+// subscriptions of a provider created WithoutReconnect are closed with the error of the dropped connection.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestWsProviderReconnectOptions(t *testing.T) {
+	if testEnv != "mock" {
+		t.Skip("the reconnection is only tested against a local websocket server")
+	}
+	node := newWsNodeMock(t)
+	ws, err := NewWsProvider(node.url(), WithReconnectBackoff(10*time.Millisecond, 20*time.Millisecond))
+	require.NoError(t, err)
+	defer ws.Close()
+	sub, err := ws.SubscribeNewHeads(context.Background(), make(chan BlockHeader))
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	<-node.subs
+
+	node.dropConnections()
+	select {
+	case <-node.subs:
+	case <-time.After(wsReconnectBaseDelay):
+		t.Fatal("the subscription was not re-issued within the configured backoff")
+	}
+
+	node = newWsNodeMock(t)
+	ws, err = NewWsProvider(node.url(), WithoutReconnect())
+	require.NoError(t, err)
+	defer ws.Close()
+	sub, err = ws.SubscribeNewHeads(context.Background(), make(chan BlockHeader))
+	require.NoError(t, err)
+	<-node.subs
+
+	node.dropConnections()
+	select {
+	case err := <-sub.Err():
+		require.ErrorContains(t, err, "websocket disconnected")
+		require.NotErrorIs(t, err, ErrSubscriptionReconnected)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dropped connection was not notified")
+	}
+	_, ok := <-sub.Err()
+	require.False(t, ok, "the Err channel is closed after the connection dropped")
+	select {
+	case <-node.subs:
+		t.Fatal("the subscription was re-issued without reconnection")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,398 @@
+package typeddata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// The domain type of revision 1 typed data (ref: https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-12.md)
+const domainType = "StarknetDomain"
+
+// The only revision supported by this package, revision 0 messages can be hashed with the typed package
+const revision = "1"
+
+var (
+	ErrInvalidTypedData = errors.New("invalid typed data")
+	ErrUnknownType      = errors.New("unknown type")
+	ErrInvalidValue     = errors.New("invalid value")
+)
+
+var maxU128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// basicTypes are the revision 1 types that are encoded as a single felt
+var basicTypes = map[string]bool{
+	"felt":            true,
+	"shortstring":     true,
+	"bool":            true,
+	"selector":        true,
+	"u128":            true,
+	"ContractAddress": true,
+	"ClassHash":       true,
+	"timestamp":       true,
+}
+
+// TypedData is a SNIP-12 revision 1 typed data message, as defined by the standard JSON representation.
+type TypedData struct {
+	Types       map[string][]TypeParameter `json:"types"`
+	PrimaryType string                     `json:"primaryType"`
+	Domain      Domain                     `json:"domain"`
+	Message     map[string]any             `json:"message"`
+}
+
+// TypeParameter is a member of a struct type.
+type TypeParameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Domain is the StarknetDomain of a typed data message. Its values accept both JSON strings and numbers.
+type Domain struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	ChainId  string `json:"chainId"`
+	Revision string `json:"revision"`
+}
+
+// UnmarshalJSON decodes a domain whose values are either JSON strings or numbers.
+//
+// Parameters:
+// - data: the JSON data to unmarshal
+// Returns:
+// - error: an error if a value is neither a string nor a number
+func (dm *Domain) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	fields := map[string]*string{
+		"name":     &dm.Name,
+		"version":  &dm.Version,
+		"chainId":  &dm.ChainId,
+		"revision": &dm.Revision,
+	}
+	for name, field := range fields {
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+		var number json.Number
+		if err := json.Unmarshal(value, field); err == nil {
+			continue
+		}
+		if err := json.Unmarshal(value, &number); err != nil {
+			return fmt.Errorf("domain %s: %w", name, err)
+		}
+		*field = number.String()
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a typed data message from its standard JSON representation and validates it.
+// Numbers in the message are kept as json.Number so that no precision is lost.
+//
+// Parameters:
+// - data: the JSON data to unmarshal
+// Returns:
+// - error: an error if the unmarshaling fails or the typed data is invalid
+func (td *TypedData) UnmarshalJSON(data []byte) error {
+	type alias TypedData
+	var aux alias
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&aux); err != nil {
+		return err
+	}
+	*td = TypedData(aux)
+	return td.Validate()
+}
+
+// Validate checks that the typed data is a revision 1 message whose primary type, domain type
+// and every referenced member type are defined.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: ErrInvalidTypedData or ErrUnknownType describing the first issue found, nil otherwise
+func (td TypedData) Validate() error {
+	if td.Domain.Revision != revision {
+		return fmt.Errorf("%w: unsupported revision %q", ErrInvalidTypedData, td.Domain.Revision)
+	}
+	if _, ok := td.Types[domainType]; !ok {
+		return fmt.Errorf("%w: %s is not defined", ErrUnknownType, domainType)
+	}
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return fmt.Errorf("%w: primary type %s is not defined", ErrUnknownType, td.PrimaryType)
+	}
+	for name, params := range td.Types {
+		if basicTypes[name] {
+			return fmt.Errorf("%w: type %s redefines a basic type", ErrInvalidTypedData, name)
+		}
+		for _, param := range params {
+			member := strings.TrimSuffix(param.Type, "*")
+			if basicTypes[member] {
+				continue
+			}
+			if _, ok := td.Types[member]; !ok {
+				return fmt.Errorf("%w: %s referenced by %s.%s", ErrUnknownType, member, name, param.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// GetMessageHash computes the revision 1 hash of the message signed by the given account,
+// poseidon("StarkNet Message", domain hash, account address, message hash).
+//
+// Parameters:
+// - accountAddress: the address of the account signing the message
+// Returns:
+// - *felt.Felt: the message hash
+// - error: an error if the typed data is invalid or the message does not match its type
+func (td TypedData) GetMessageHash(accountAddress *felt.Felt) (*felt.Felt, error) {
+	if err := td.Validate(); err != nil {
+		return nil, err
+	}
+	domainHash, err := td.GetStructHash(domainType, map[string]any{
+		"name":     td.Domain.Name,
+		"version":  td.Domain.Version,
+		"chainId":  td.Domain.ChainId,
+		"revision": td.Domain.Revision,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("domain: %w", err)
+	}
+	messageHash, err := td.GetStructHash(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := encodeShortString("StarkNet Message")
+	if err != nil {
+		return nil, err
+	}
+	return curve.Curve.PoseidonArray(prefix, domainHash, accountAddress, messageHash), nil
+}
+
+// GetStructHash computes the hash of a value of a struct type, poseidon(type hash, encoded members...).
+//
+// Parameters:
+// - typeName: the struct type of the value
+// - value: the members of the value, keyed by name
+// Returns:
+// - *felt.Felt: the struct hash
+// - error: an error if the type is unknown or a member cannot be encoded
+func (td TypedData) GetStructHash(typeName string, value map[string]any) (*felt.Felt, error) {
+	params, ok := td.Types[typeName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, typeName)
+	}
+	typeHash, err := td.GetTypeHash(typeName)
+	if err != nil {
+		return nil, err
+	}
+	elements := []*felt.Felt{typeHash}
+	for _, param := range params {
+		member, ok := value[param.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s.%s", ErrInvalidValue, typeName, param.Name)
+		}
+		enc, err := td.encodeValue(param.Type, member)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, param.Name, err)
+		}
+		elements = append(elements, enc)
+	}
+	return curve.Curve.PoseidonArray(elements...), nil
+}
+
+// GetTypeHash returns the starknet keccak of the encoded type.
+//
+// Parameters:
+// - typeName: the struct type to hash
+// Returns:
+// - *felt.Felt: the type hash
+// - error: an error if the type or one of its dependencies is unknown
+func (td TypedData) GetTypeHash(typeName string) (*felt.Felt, error) {
+	enc, err := td.EncodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return utils.GetSelectorFromNameFelt(enc), nil
+}
+
+// EncodeType encodes a struct type as `"Name"("member":"type",...)`, followed by the
+// encoding of the struct types it depends on sorted alphabetically.
+//
+// Parameters:
+// - typeName: the struct type to encode
+// Returns:
+// - string: the encoded type
+// - error: an error if the type or one of its dependencies is unknown
+func (td TypedData) EncodeType(typeName string) (string, error) {
+	deps := map[string]bool{}
+	if err := td.collectDependencies(typeName, deps); err != nil {
+		return "", err
+	}
+	delete(deps, typeName)
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+
+	var buf bytes.Buffer
+	for _, name := range append([]string{typeName}, sorted...) {
+		buf.WriteString(fmt.Sprintf("%q(", name))
+		for i, param := range td.Types[name] {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(fmt.Sprintf("%q:%q", param.Name, param.Type))
+		}
+		buf.WriteString(")")
+	}
+	return buf.String(), nil
+}
+
+// collectDependencies adds the type and the struct types it references, recursively, to deps.
+func (td TypedData) collectDependencies(typeName string, deps map[string]bool) error {
+	if deps[typeName] {
+		return nil
+	}
+	params, ok := td.Types[typeName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, typeName)
+	}
+	deps[typeName] = true
+	for _, param := range params {
+		member := strings.TrimSuffix(param.Type, "*")
+		if basicTypes[member] {
+			continue
+		}
+		if err := td.collectDependencies(member, deps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeValue encodes a member value of the given type into a single felt.
+// Arrays are hashed as poseidon(encoded elements...) and structs as their struct hash.
+func (td TypedData) encodeValue(typeName string, value any) (*felt.Felt, error) {
+	if base, isArray := strings.CutSuffix(typeName, "*"); isArray {
+		elements, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected an array of %s, got %T", ErrInvalidValue, base, value)
+		}
+		encoded := make([]*felt.Felt, len(elements))
+		for i, element := range elements {
+			enc, err := td.encodeValue(base, element)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			encoded[i] = enc
+		}
+		return curve.Curve.PoseidonArray(encoded...), nil
+	}
+
+	if _, isStruct := td.Types[typeName]; isStruct {
+		members, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a %s struct, got %T", ErrInvalidValue, typeName, value)
+		}
+		return td.GetStructHash(typeName, members)
+	}
+
+	switch typeName {
+	case "felt", "ContractAddress", "ClassHash", "timestamp":
+		return encodeFelt(value)
+	case "shortstring":
+		if str, ok := value.(string); ok && !isNumeric(str) {
+			return encodeShortString(str)
+		}
+		return encodeFelt(value)
+	case "u128":
+		f, err := encodeFelt(value)
+		if err != nil {
+			return nil, err
+		}
+		if utils.FeltToBigInt(f).Cmp(maxU128) > 0 {
+			return nil, fmt.Errorf("%w: %s overflows u128", ErrInvalidValue, f)
+		}
+		return f, nil
+	case "bool":
+		switch value {
+		case true, "true":
+			return new(felt.Felt).SetUint64(1), nil
+		case false, "false":
+			return new(felt.Felt), nil
+		}
+		return nil, fmt.Errorf("%w: expected a bool, got %v", ErrInvalidValue, value)
+	case "selector":
+		if str, ok := value.(string); ok && !isNumeric(str) {
+			return utils.GetSelectorFromNameFelt(str), nil
+		}
+		return encodeFelt(value)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownType, typeName)
+}
+
+// encodeFelt encodes a JSON number, a numeric string (decimal or hexadecimal) or a short string into a felt.
+func encodeFelt(value any) (*felt.Felt, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return encodeNumeric(v.String())
+	case float64:
+		return encodeNumeric(big.NewFloat(v).Text('f', 0))
+	case string:
+		if isNumeric(v) {
+			return encodeNumeric(v)
+		}
+		return encodeShortString(v)
+	}
+	return nil, fmt.Errorf("%w: expected a felt, got %T", ErrInvalidValue, value)
+}
+
+// encodeNumeric parses a decimal or hexadecimal string into a felt.
+func encodeNumeric(str string) (*felt.Felt, error) {
+	base := 10
+	if strings.HasPrefix(str, "0x") {
+		base, str = 16, str[2:]
+	}
+	num, ok := new(big.Int).SetString(str, base)
+	if !ok || num.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %q is not a felt", ErrInvalidValue, str)
+	}
+	f, err := new(felt.Felt).SetString("0x" + num.Text(16))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	return f, nil
+}
+
+// encodeShortString encodes an ASCII string of at most 31 characters into a felt.
+func encodeShortString(str string) (*felt.Felt, error) {
+	if len(str) > 31 {
+		return nil, fmt.Errorf("%w: short string %q is longer than 31 characters", ErrInvalidValue, str)
+	}
+	return new(felt.Felt).SetBytes([]byte(str)), nil
+}
+
+// isNumeric reports whether the string is a decimal or 0x-prefixed hexadecimal number.
+func isNumeric(str string) bool {
+	if hexStr, isHex := strings.CutPrefix(str, "0x"); isHex {
+		_, ok := new(big.Int).SetString(hexStr, 16)
+		return ok
+	}
+	return str != "" && strings.Trim(str, "0123456789") == ""
+}
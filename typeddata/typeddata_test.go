@@ -0,0 +1,176 @@
+package typeddata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const mailTypedData = `{
+	"types": {
+		"StarknetDomain": [
+			{ "name": "name", "type": "shortstring" },
+			{ "name": "version", "type": "shortstring" },
+			{ "name": "chainId", "type": "shortstring" },
+			{ "name": "revision", "type": "shortstring" }
+		],
+		"Person": [
+			{ "name": "name", "type": "felt" },
+			{ "name": "wallet", "type": "ContractAddress" }
+		],
+		"Mail": [
+			{ "name": "from", "type": "Person" },
+			{ "name": "to", "type": "Person*" },
+			{ "name": "amount", "type": "u128" },
+			{ "name": "tags", "type": "felt*" }
+		]
+	},
+	"primaryType": "Mail",
+	"domain": { "name": "StarkNet Mail", "version": "1", "chainId": "SN_SEPOLIA", "revision": 1 },
+	"message": {
+		"from": { "name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826" },
+		"to": [{ "name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB" }],
+		"amount": 1000,
+		"tags": ["0x1", "urgent"]
+	}
+}`
+
+func mockTypedData(t *testing.T) TypedData {
+	var td TypedData
+	require.NoError(t, json.Unmarshal([]byte(mailTypedData), &td))
+	return td
+}
+
+func TestEncodeType(t *testing.T) {
+	td := mockTypedData(t)
+
+	enc, err := td.EncodeType("Mail")
+	require.NoError(t, err)
+	require.Equal(t, `"Mail"("from":"Person","to":"Person*","amount":"u128","tags":"felt*")"Person"("name":"felt","wallet":"ContractAddress")`, enc)
+
+	enc, err = td.EncodeType("StarknetDomain")
+	require.NoError(t, err)
+	require.Equal(t, `"StarknetDomain"("name":"shortstring","version":"shortstring","chainId":"shortstring","revision":"shortstring")`, enc)
+}
+
+// The revision 1 type hashes of the mail typed data. The StarknetDomain one is the STARKNET_DOMAIN_TYPE_HASH
+// published with SNIP-12 (and the OpenZeppelin Cairo contracts), the others are the starknet_keccak of the
+// encodings checked by TestEncodeType.
+const (
+	starknetDomainTypeHash = "0x1ff2f602e42168014d405a94f75e8a93d640751d71d16311266e140d8b0a210"
+	personTypeHash         = "0x2d69999cedc355b2a755f05aee3bfb3541b9c3721323ec496c02dcf449e451b"
+	mailTypeHash           = "0x2eedaf250e5e828687b67f3c43b15b5a3c3c31faa8c423db13e9bb8f7b9f057"
+)
+
+func TestGetTypeHash(t *testing.T) {
+	td := mockTypedData(t)
+
+	for name, expected := range map[string]string{
+		"StarknetDomain": starknetDomainTypeHash,
+		"Person":         personTypeHash,
+		"Mail":           mailTypeHash,
+	} {
+		typeHash, err := td.GetTypeHash(name)
+		require.NoError(t, err)
+		require.Equal(t, utils.TestHexToFelt(t, expected), typeHash, name)
+	}
+}
+
+func TestGetMessageHash(t *testing.T) {
+	td := mockTypedData(t)
+	account := utils.TestHexToFelt(t, "0x5b5e9f6f6fb7d2647d81a8b2c2b99cbc9cc9d03d705576d7061812324dca5c0")
+
+	hash, err := td.GetMessageHash(account)
+	require.NoError(t, err)
+
+	short := func(s string) *felt.Felt { return new(felt.Felt).SetBytes([]byte(s)) }
+	poseidon := curve.Curve.PoseidonArray
+	person := func(name, wallet string) *felt.Felt {
+		return poseidon(utils.TestHexToFelt(t, personTypeHash), short(name), utils.TestHexToFelt(t, wallet))
+	}
+	domainHash := poseidon(utils.TestHexToFelt(t, starknetDomainTypeHash), short("StarkNet Mail"), new(felt.Felt).SetUint64(1), short("SN_SEPOLIA"), new(felt.Felt).SetUint64(1))
+	mailHash := poseidon(
+		utils.TestHexToFelt(t, mailTypeHash),
+		person("Cow", "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"),
+		poseidon(person("Bob", "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")),
+		new(felt.Felt).SetUint64(1000),
+		poseidon(new(felt.Felt).SetUint64(1), short("urgent")),
+	)
+	expected := poseidon(short("StarkNet Message"), domainHash, account, mailHash)
+	require.Equal(t, expected, hash)
+
+	// the hash depends on the signing account
+	other, err := td.GetMessageHash(utils.TestHexToFelt(t, "0x1"))
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+}
+
+func TestTypedDataValidation(t *testing.T) {
+	type testSetType struct {
+		Types       string
+		PrimaryType string
+		Revision    string
+		ExpectedErr error
+	}
+	domain := `"StarknetDomain": [{ "name": "name", "type": "shortstring" }]`
+	testSet := []testSetType{
+		{
+			Types:       domain + `, "Mail": [{ "name": "from", "type": "Person" }]`,
+			PrimaryType: "Mail",
+			Revision:    "1",
+			ExpectedErr: ErrUnknownType,
+		},
+		{
+			Types:       domain + `, "Mail": [{ "name": "to", "type": "Person*" }]`,
+			PrimaryType: "Mail",
+			Revision:    "1",
+			ExpectedErr: ErrUnknownType,
+		},
+		{
+			Types:       domain + `, "Mail": [{ "name": "contents", "type": "felt" }]`,
+			PrimaryType: "Letter",
+			Revision:    "1",
+			ExpectedErr: ErrUnknownType,
+		},
+		{
+			Types:       `"Mail": [{ "name": "contents", "type": "felt" }]`,
+			PrimaryType: "Mail",
+			Revision:    "1",
+			ExpectedErr: ErrUnknownType,
+		},
+		{
+			Types:       domain + `, "Mail": [{ "name": "contents", "type": "felt" }]`,
+			PrimaryType: "Mail",
+			Revision:    "0",
+			ExpectedErr: ErrInvalidTypedData,
+		},
+	}
+
+	for _, test := range testSet {
+		data := `{"types": {` + test.Types + `}, "primaryType": "` + test.PrimaryType + `", "domain": {"revision": "` + test.Revision + `"}, "message": {}}`
+		var td TypedData
+		require.ErrorIs(t, json.Unmarshal([]byte(data), &td), test.ExpectedErr)
+	}
+}
+
+func TestEncodeValueErrors(t *testing.T) {
+	td := mockTypedData(t)
+
+	td.Message["amount"] = "0x100000000000000000000000000000000"
+	_, err := td.GetMessageHash(utils.TestHexToFelt(t, "0x1"))
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	td = mockTypedData(t)
+	delete(td.Message, "tags")
+	_, err = td.GetMessageHash(utils.TestHexToFelt(t, "0x1"))
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	td = mockTypedData(t)
+	td.Message["to"] = "Bob"
+	_, err = td.GetMessageHash(utils.TestHexToFelt(t, "0x1"))
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"regexp"
@@ -10,6 +12,15 @@ import (
 	"github.com/NethermindEth/juno/core/felt"
 )
 
+// shortStringMaxLength is the maximum number of characters of a short string, which must fit in a felt
+const shortStringMaxLength = 31
+
+var (
+	ErrShortStringTooLong  = fmt.Errorf("short string longer than %d characters", shortStringMaxLength)
+	ErrShortStringNotASCII = errors.New("short string with non-ASCII characters")
+	ErrFeltInverseOfZero   = errors.New("zero has no inverse in the Stark field")
+)
+
 // Uint64ToFelt generates a new *felt.Felt from a given uint64 number.
 //
 // Parameters:
@@ -92,6 +103,38 @@ func FeltArrToBigIntArr(f []*felt.Felt) []*big.Int {
 	return bigArr
 }
 
+// StrToFelt encodes an ASCII string of at most 31 characters as a Cairo short string, i.e. the felt of the
+// big-endian bytes of the string, as used for the identifiers like the chain IDs or the token symbols.
+//
+// Parameters:
+// - s: the short string, the empty string being encoded as zero
+// Returns:
+// - *felt.Felt: the encoded string
+// - error: ErrShortStringTooLong or ErrShortStringNotASCII if the string cannot be encoded
+func StrToFelt(s string) (*felt.Felt, error) {
+	if len(s) > shortStringMaxLength {
+		return nil, fmt.Errorf("%w: %q", ErrShortStringTooLong, s)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] > 0x7f {
+			return nil, fmt.Errorf("%w: %q", ErrShortStringNotASCII, s)
+		}
+	}
+	return new(felt.Felt).SetBytes([]byte(s)), nil
+}
+
+// FeltToStr decodes a Cairo short string, i.e. the felt of the big-endian bytes of a string of at most
+// 31 characters.
+//
+// Parameters:
+// - f: the encoded string
+// Returns:
+// - string: the short string, empty for zero
+func FeltToStr(f *felt.Felt) string {
+	b := f.Bytes()
+	return string(bytes.TrimLeft(b[:], "\x00"))
+}
+
 // StringToByteArrFelt converts string to array of Felt objects.
 // The returned array of felts will be of the format
 //
@@ -216,3 +259,52 @@ func BigIntArrToFeltArr(bigArr []*big.Int) []*felt.Felt {
 	}
 	return feltArr
 }
+
+// FeltAdd adds two felts modulo the Stark prime.
+//
+// Parameters:
+// - x: the first felt
+// - y: the second felt
+// Returns:
+// - *felt.Felt: x + y mod P, a new felt
+func FeltAdd(x, y *felt.Felt) *felt.Felt {
+	return new(felt.Felt).Add(x, y)
+}
+
+// FeltSub subtracts two felts modulo the Stark prime, a negative difference wrapping around the modulus.
+//
+// Parameters:
+// - x: the felt to subtract from
+// - y: the felt to subtract
+// Returns:
+// - *felt.Felt: x - y mod P, a new felt
+func FeltSub(x, y *felt.Felt) *felt.Felt {
+	return new(felt.Felt).Sub(x, y)
+}
+
+// FeltMul multiplies two felts modulo the Stark prime.
+//
+// Parameters:
+// - x: the first felt
+// - y: the second felt
+// Returns:
+// - *felt.Felt: x * y mod P, a new felt
+func FeltMul(x, y *felt.Felt) *felt.Felt {
+	return new(felt.Felt).Mul(x, y)
+}
+
+// FeltInv returns the multiplicative inverse of a felt modulo the Stark prime.
+//
+// Parameters:
+// - x: the felt to invert
+// Returns:
+// - *felt.Felt: the felt y such that x * y = 1 mod P, a new felt
+// - error: ErrFeltInverseOfZero if x is zero
+func FeltInv(x *felt.Felt) (*felt.Felt, error) {
+	if x.IsZero() {
+		return nil, ErrFeltInverseOfZero
+	}
+	inverse := new(felt.Felt)
+	inverse.Impl().Inverse(x.Impl())
+	return inverse, nil
+}
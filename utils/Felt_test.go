@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -95,3 +96,64 @@ func TestByteArrFeltToString(t *testing.T) {
 		require.Equal(t, tc.out, res, "invalid conversion: output does not match")
 	}
 }
+
+func TestShortString(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{in: "SN_MAIN", out: "0x534e5f4d41494e"},
+		{in: "SN_SEPOLIA", out: "0x534e5f5345504f4c4941"},
+		{in: "", out: "0x0"},
+		{in: "1234567890123456789012345678901", out: "0x31323334353637383930313233343536373839303132333435363738393031"},
+	}
+
+	for _, tc := range tests {
+		f, err := StrToFelt(tc.in)
+		require.NoError(t, err)
+		require.Equal(t, tc.out, f.String())
+		require.Equal(t, tc.in, FeltToStr(f))
+	}
+
+	_, err := StrToFelt("12345678901234567890123456789012")
+	require.ErrorIs(t, err, ErrShortStringTooLong)
+	_, err = StrToFelt("é")
+	require.ErrorIs(t, err, ErrShortStringNotASCII)
+}
+
+func TestFeltArithmetic(t *testing.T) {
+	prime, ok := new(big.Int).SetString("0x800000000000011000000000000000000000000000000000000000000000001", 0)
+	require.True(t, ok)
+	values := []string{"0x0", "0x1", "0x2", "0x1234567890abcdef", "0x800000000000011000000000000000000000000000000000000000000000000", "0x7ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"}
+
+	for _, a := range values {
+		for _, b := range values {
+			x, y := TestHexToFelt(t, a), TestHexToFelt(t, b)
+			bigX, bigY := FeltToBigInt(x), FeltToBigInt(y)
+
+			sum := new(big.Int).Mod(new(big.Int).Add(bigX, bigY), prime)
+			require.Equal(t, BigIntToFelt(sum), FeltAdd(x, y))
+			diff := new(big.Int).Mod(new(big.Int).Sub(bigX, bigY), prime)
+			require.Equal(t, BigIntToFelt(diff), FeltSub(x, y))
+			product := new(big.Int).Mod(new(big.Int).Mul(bigX, bigY), prime)
+			require.Equal(t, BigIntToFelt(product), FeltMul(x, y))
+		}
+	}
+	// the operands are left unchanged
+	x := TestHexToFelt(t, "0x2")
+	FeltAdd(x, x)
+	require.Equal(t, TestHexToFelt(t, "0x2"), x)
+
+	// 1 - 2 wraps around the modulus to P - 1
+	require.Equal(t, TestHexToFelt(t, "0x800000000000011000000000000000000000000000000000000000000000000"), FeltSub(TestHexToFelt(t, "0x1"), TestHexToFelt(t, "0x2")))
+
+	for _, v := range values[1:] {
+		x := TestHexToFelt(t, v)
+		inverse, err := FeltInv(x)
+		require.NoError(t, err)
+		require.Equal(t, BigIntToFelt(new(big.Int).ModInverse(FeltToBigInt(x), prime)), inverse)
+		require.Equal(t, TestHexToFelt(t, "0x1"), FeltMul(x, inverse))
+	}
+	_, err := FeltInv(TestHexToFelt(t, "0x0"))
+	require.ErrorIs(t, err, ErrFeltInverseOfZero)
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	junoCrypto "github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// ComputeHashOnElements computes the Pedersen hash of a slice of elements, as cairo-lang's
+// compute_hash_on_elements: the elements are folded with Pedersen starting from 0, and the result is
+// hashed with the number of elements. It is the hash of the calldata of the legacy transaction hashes
+// and of the payload of the messages from L1.
+// NOTE: This function just wraps the Juno implementation
+// (ref: https://github.com/NethermindEth/juno/blob/main/core/crypto/pedersen_hash.go)
+//
+// Parameters:
+// - elems: the elements to hash
+// Returns:
+// - *felt.Felt: the hash of the elements
+func ComputeHashOnElements(elems []*felt.Felt) *felt.Felt {
+	return junoCrypto.PedersenArray(elems...)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestComputeHashOnElements checks the hashes against vectors of cairo-lang's compute_hash_on_elements.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestComputeHashOnElements(t *testing.T) {
+	felts := func(values ...uint64) []*felt.Felt {
+		elems := make([]*felt.Felt, len(values))
+		for i, v := range values {
+			elems[i] = new(felt.Felt).SetUint64(v)
+		}
+		return elems
+	}
+	var tests = []struct {
+		in  []*felt.Felt
+		out string
+	}{
+		{
+			in:  felts(),
+			out: "0x49ee3eba8c1600700ee1b87eb599f16716b0b1022947733551fde4050ca6804",
+		},
+		{
+			in:  felts(1),
+			out: "0x78d74f61aeaa8286418fd34b3a12a610445eba11d00ecc82ecac2542d55f7a4",
+		},
+		{
+			in:  felts(1, 2),
+			out: "0x501a3a8e6cd4f5241c639c74052aaa34557aafa84dd4ba983d6443c590ab7df",
+		},
+		{
+			in:  felts(1, 2, 3, 4),
+			out: "0x66bd4335902683054d08a0572747ea78ebd9e531536fb43125424ca9f902084",
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.out, ComputeHashOnElements(test.in).String())
+	}
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	junoCrypto "github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Poseidon computes the Poseidon hash of the given elements, as cairo-lang's poseidon_hash_many.
+//
+// Parameters:
+// - elems: the elements to hash
+// Returns:
+// - *felt.Felt: the hash of the elements
+func Poseidon(elems ...*felt.Felt) *felt.Felt {
+	return PoseidonArray(elems)
+}
+
+// PoseidonArray computes the Poseidon hash of a slice of elements, as cairo-lang's poseidon_hash_many.
+// The elements are absorbed two at a time into the sponge, after appending 1 to them (the
+// Starknet padding rule), so slices of any length, including empty ones, can be hashed.
+// NOTE: This function just wraps the Juno implementation
+// (ref: https://github.com/NethermindEth/juno/blob/main/core/crypto/poseidon_hash.go)
+//
+// Parameters:
+// - elems: the elements to hash
+// Returns:
+// - *felt.Felt: the hash of the elements
+func PoseidonArray(elems []*felt.Felt) *felt.Felt {
+	return junoCrypto.PoseidonArray(elems...)
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPoseidonArray checks the hashes against vectors of cairo-lang's poseidon_hash_many.
+func TestPoseidonArray(t *testing.T) {
+	felts := func(values ...uint64) []*felt.Felt {
+		elems := make([]*felt.Felt, len(values))
+		for i, v := range values {
+			elems[i] = new(felt.Felt).SetUint64(v)
+		}
+		return elems
+	}
+	var tests = []struct {
+		in  []*felt.Felt
+		out string
+	}{
+		{
+			in:  felts(),
+			out: "0x2272be0f580fd156823304800919530eaa97430e972d7213ee13f4fbf7a5dbc",
+		},
+		{
+			in:  felts(1),
+			out: "0x579e8877c7755365d5ec1ec7d3a94a457eff5d1f40482bbe9729c064cdead2",
+		},
+		{
+			in:  felts(0, 1, 2),
+			out: "0x7a01142da8aecae3782ba66fc3285fd02fcd2c55aa868fe50fd95c089068d16",
+		},
+		{
+			in:  felts(0, 1, 2, 3),
+			out: "0x7b8f30ac298ea12d170c0873f1fa631a18c00756c6e7d1fd273b9a239d0d413",
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.out, PoseidonArray(test.in).String())
+		require.Equal(t, test.out, Poseidon(test.in...).String())
+	}
+}
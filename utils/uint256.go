@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var ErrInvalidUint256 = errors.New("invalid uint256")
+
+var uint128Mask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// Uint256ToFelts splits a 256 bits value into the low and high 128 bits limbs of a Cairo u256.
+//
+// Parameters:
+// - v: the value to split
+// Returns:
+// - low: the low 128 bits of the value
+// - high: the high 128 bits of the value
+// - err: an error wrapping ErrInvalidUint256 if the value is negative or exceeds 256 bits
+func Uint256ToFelts(v *big.Int) (low, high *felt.Felt, err error) {
+	if v.Sign() < 0 || v.BitLen() > 256 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidUint256, v.String())
+	}
+	return BigIntToFelt(new(big.Int).And(v, uint128Mask)), BigIntToFelt(new(big.Int).Rsh(v, 128)), nil
+}
+
+// FeltsToUint256 joins the low and high 128 bits limbs of a Cairo u256.
+//
+// Parameters:
+// - low: the low 128 bits of the value
+// - high: the high 128 bits of the value
+// Returns:
+// - *big.Int: the value, high * 2^128 + low
+// - error: an error wrapping ErrInvalidUint256 if a limb exceeds 128 bits
+func FeltsToUint256(low, high *felt.Felt) (*big.Int, error) {
+	for _, limb := range []*felt.Felt{low, high} {
+		if err := checkUint256Limb(limb); err != nil {
+			return nil, err
+		}
+	}
+	return joinUint256(low, high), nil
+}
+
+// checkUint256Limb returns an error wrapping ErrInvalidUint256 if the limb of a u256 exceeds 128 bits.
+func checkUint256Limb(limb *felt.Felt) error {
+	if FeltToBigInt(limb).BitLen() > 128 {
+		return fmt.Errorf("%w: limb %s exceeds 128 bits", ErrInvalidUint256, limb)
+	}
+	return nil
+}
+
+// joinUint256 returns high * 2^128 + low.
+func joinUint256(low, high *felt.Felt) *big.Int {
+	value := new(big.Int).Lsh(FeltToBigInt(high), 128)
+	return value.Add(value, FeltToBigInt(low))
+}
+
+// Uint256 is a Cairo u256, serialized in the calldata as its low and high 128 bits limbs.
+type Uint256 struct {
+	Low  *felt.Felt
+	High *felt.Felt
+}
+
+// NewUint256 returns the Cairo u256 of a 256 bits value.
+//
+// Parameters:
+// - v: the value
+// Returns:
+// - Uint256: the limbs of the value
+// - error: an error wrapping ErrInvalidUint256 if the value is negative or exceeds 256 bits
+func NewUint256(v *big.Int) (Uint256, error) {
+	low, high, err := Uint256ToFelts(v)
+	if err != nil {
+		return Uint256{}, err
+	}
+	return Uint256{Low: low, High: high}, nil
+}
+
+// BigInt returns the value of the u256.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *big.Int: the value, high * 2^128 + low
+func (u Uint256) BigInt() *big.Int {
+	low, high := u.Low, u.High
+	if low == nil {
+		low = new(felt.Felt)
+	}
+	if high == nil {
+		high = new(felt.Felt)
+	}
+	return joinUint256(low, high)
+}
+
+// Calldata returns the limbs of the u256 in calldata order, low then high.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the low and high limbs
+func (u Uint256) Calldata() []*felt.Felt {
+	low, high, _ := Uint256ToFelts(u.BigInt())
+	return []*felt.Felt{low, high}
+}
+
+// MarshalJSON marshals the u256 as its calldata, e.g. ["0x1","0x0"].
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON array of the low and high limbs
+// - error: an error if any
+func (u Uint256) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.Calldata())
+}
+
+// UnmarshalJSON unmarshals a u256 from its calldata, e.g. ["0x1","0x0"].
+//
+// Parameters:
+// - data: the JSON array of the low and high limbs
+// Returns:
+// - error: an error wrapping ErrInvalidUint256 if the array does not hold two limbs of at most 128 bits
+func (u *Uint256) UnmarshalJSON(data []byte) error {
+	var limbs []*felt.Felt
+	if err := json.Unmarshal(data, &limbs); err != nil {
+		return err
+	}
+	if len(limbs) != 2 || limbs[0] == nil || limbs[1] == nil {
+		return fmt.Errorf("%w: expected the low and high limbs, got %s", ErrInvalidUint256, data)
+	}
+	for _, limb := range limbs {
+		if err := checkUint256Limb(limb); err != nil {
+			return err
+		}
+	}
+	u.Low, u.High = limbs[0], limbs[1]
+	return nil
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUint256 tests the splitting of the u256 values into their limbs and their JSON round-trip.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestUint256(t *testing.T) {
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	type testSetType struct {
+		Value   *big.Int
+		Low     string
+		High    string
+		JSON    string
+		WantErr bool
+	}
+	testSet := []testSetType{
+		{Value: big.NewInt(0), Low: "0x0", High: "0x0", JSON: `["0x0","0x0"]`},
+		{Value: maxUint128, Low: "0xffffffffffffffffffffffffffffffff", High: "0x0", JSON: `["0xffffffffffffffffffffffffffffffff","0x0"]`},
+		// the low limb overflows into the high limb
+		{Value: new(big.Int).Add(maxUint128, big.NewInt(1)), Low: "0x0", High: "0x1", JSON: `["0x0","0x1"]`},
+		// only the high limb is set
+		{Value: new(big.Int).Lsh(big.NewInt(0x1234), 128), Low: "0x0", High: "0x1234", JSON: `["0x0","0x1234"]`},
+		{Value: maxUint256, Low: "0xffffffffffffffffffffffffffffffff", High: "0xffffffffffffffffffffffffffffffff",
+			JSON: `["0xffffffffffffffffffffffffffffffff","0xffffffffffffffffffffffffffffffff"]`},
+		{Value: new(big.Int).Add(maxUint256, big.NewInt(1)), WantErr: true},
+		{Value: big.NewInt(-1), WantErr: true},
+	}
+
+	for _, test := range testSet {
+		low, high, err := Uint256ToFelts(test.Value)
+		if test.WantErr {
+			require.ErrorIs(t, err, ErrInvalidUint256)
+			_, err = NewUint256(test.Value)
+			require.ErrorIs(t, err, ErrInvalidUint256)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.Low, low.String())
+		require.Equal(t, test.High, high.String())
+		joined, err := FeltsToUint256(low, high)
+		require.NoError(t, err)
+		require.Equal(t, test.Value, joined)
+
+		u, err := NewUint256(test.Value)
+		require.NoError(t, err)
+		encoded, err := json.Marshal(u)
+		require.NoError(t, err)
+		require.Equal(t, test.JSON, string(encoded))
+
+		var decoded Uint256
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		require.Equal(t, test.Value, decoded.BigInt())
+	}
+
+	// the limbs of 2^128 or more are rejected instead of overlapping
+	overflow := new(felt.Felt).SetBytes(new(big.Int).Lsh(big.NewInt(1), 128).Bytes())
+	_, err := FeltsToUint256(overflow, new(felt.Felt))
+	require.ErrorIs(t, err, ErrInvalidUint256)
+	_, err = FeltsToUint256(new(felt.Felt), overflow)
+	require.ErrorIs(t, err, ErrInvalidUint256)
+
+	var decoded Uint256
+	require.ErrorIs(t, json.Unmarshal([]byte(`["0x1"]`), &decoded), ErrInvalidUint256)
+	require.ErrorIs(t, json.Unmarshal([]byte(`["0x100000000000000000000000000000000","0x0"]`), &decoded), ErrInvalidUint256)
+	require.Equal(t, []*felt.Felt{new(felt.Felt), new(felt.Felt)}, Uint256{}.Calldata())
+}